@@ -0,0 +1,151 @@
+// main.go - Entry point for replaying historical candles through a strategy.
+// Loads a bot config (same YAML as the live bot), a CSV of OHLCV candles,
+// runs the backtest engine and prints the trade summary.
+// With -import it instead stores the CSV candles in the SQLite candle store.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"rsi-bot/pkg/backtest"
+	"rsi-bot/pkg/config"
+	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/indicators"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+func main() {
+	configPath := flag.String("config", "configs/config.yaml", "bot config file")
+	dataPath := flag.String("data", "", "CSV file with timestamp,open,high,low,close,volume candles")
+	dbPath := flag.String("db", "", "SQLite database to read stored candles from (instead of -data)")
+	interval := flag.String("interval", "1h", "candle interval to read from -db")
+	days := flag.Int("days", 90, "number of days of history to read from -db")
+	balance := flag.Float64("balance", 10000, "initial quote balance")
+	fee := flag.Float64("fee", 0.1, "fee per fill in percent")
+	slippage := flag.Float64("slippage", 0.05, "slippage per fill in percent")
+	jsonOut := flag.String("json", "", "optional path to write the full result as JSON")
+	importOnly := flag.Bool("import", false, "store the -data candles in -db (as the config symbol and -interval) and exit")
+	flag.Parse()
+
+	if *dataPath == "" && *dbPath == "" {
+		log.Fatal("either -data or -db is required")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	if *importOnly {
+		if *dataPath == "" || *dbPath == "" {
+			log.Fatal("-import requires both -data and -db")
+		}
+		db, err := database.New(*dbPath)
+		if err != nil {
+			log.Fatalf("Failed to open database: %v", err)
+		}
+		n, err := backtest.ImportCSV(db, *dataPath, cfg.Symbol, *interval)
+		db.Close()
+		if err != nil {
+			log.Fatalf("Failed to import candles: %v", err)
+		}
+		log.Printf("Imported %d %s %s candles into %s", n, cfg.Symbol, *interval, *dbPath)
+		return
+	}
+
+	strat, err := createStrategy(cfg.Strategy, cfg.RSIPeriod)
+	if err != nil {
+		log.Fatalf("Failed to create strategy: %v", err)
+	}
+
+	var candles []strategy.OHLCV
+	if *dataPath != "" {
+		candles, err = backtest.LoadCSV(*dataPath)
+		if err != nil {
+			log.Fatalf("Failed to load candles: %v", err)
+		}
+		log.Printf("Loaded %d candles from %s", len(candles), *dataPath)
+	} else {
+		db, err := database.New(*dbPath)
+		if err != nil {
+			log.Fatalf("Failed to open database: %v", err)
+		}
+		end := time.Now()
+		start := end.AddDate(0, 0, -*days)
+		candles, err = backtest.LoadFromDB(db, cfg.Symbol, *interval, start, end)
+		db.Close()
+		if err != nil {
+			log.Fatalf("Failed to load candles: %v", err)
+		}
+		log.Printf("Loaded %d %s candles from %s", len(candles), *interval, *dbPath)
+	}
+
+	engine, err := backtest.NewEngine(strat, backtest.Config{
+		Symbol:          cfg.Symbol,
+		InitialBalance:  *balance,
+		Quantity:        cfg.Quantity,
+		FeePercent:      *fee,
+		SlippagePercent: *slippage,
+		AllowShort:      cfg.Signals.AllowShort,
+		MinStrength:     cfg.Signals.MinStrength,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create backtest engine: %v", err)
+	}
+
+	result, err := engine.Run(candles)
+	if err != nil {
+		log.Fatalf("Backtest failed: %v", err)
+	}
+
+	s := result.Summary
+	fmt.Printf("\n=== Backtest: %s on %s ===\n", strat.Name(), cfg.Symbol)
+	fmt.Printf("Candles:        %d\n", result.CandlesProcessed)
+	fmt.Printf("Trades:         %d (%d buys, %d sells)\n", s.TotalTrades, s.TotalBuys, s.TotalSells)
+	fmt.Printf("Win rate:       %.2f%%\n", s.WinRate)
+	fmt.Printf("Total P/L:      %.2f\n", s.TotalProfitLoss)
+	fmt.Printf("Avg P/L:        %.2f\n", s.AverageProfitLoss)
+	fmt.Printf("Largest win:    %.2f\n", s.LargestWin)
+	fmt.Printf("Largest loss:   %.2f\n", s.LargestLoss)
+	fmt.Printf("Fees paid:      %.2f\n", result.TotalFees)
+	fmt.Printf("Final equity:   %.2f (%.2f%%)\n", result.FinalEquity, result.ReturnPercent)
+	fmt.Printf("Max drawdown:   %.2f%%\n", result.MaxDrawdownPercent)
+
+	if *jsonOut != "" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to encode result: %v", err)
+		}
+		if err := os.WriteFile(*jsonOut, data, 0644); err != nil {
+			log.Fatalf("Failed to write result: %v", err)
+		}
+		log.Printf("Wrote result to %s", *jsonOut)
+	}
+}
+
+// createStrategy builds a strategy from config, falling back to legacy RSI settings
+func createStrategy(config models.StrategyConfig, rsiPeriod int) (strategy.Strategy, error) {
+	factory := strategy.NewFactory()
+
+	stratConfig := strategy.ConfigFromModel(config)
+	if stratConfig.Type == "" {
+		stratConfig.Type = "rsi"
+		stratConfig.IndicatorConfig = indicators.IndicatorConfig{
+			Type:   "rsi",
+			Params: map[string]interface{}{"period": rsiPeriod},
+		}
+	}
+
+	if err := factory.ValidateConfig(stratConfig); err != nil {
+		return nil, fmt.Errorf("invalid strategy configuration: %w", err)
+	}
+
+	return factory.Create(stratConfig)
+}
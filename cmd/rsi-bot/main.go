@@ -71,5 +71,8 @@ func main() {
 
 	// Give bot time to cleanup
 	time.Sleep(2 * time.Second)
+	if err := bot.CloseDatabase(); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
 	log.Println("Bot stopped.")
 }
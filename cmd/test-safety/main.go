@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"rsi-bot/pkg/exchange"
 	"rsi-bot/pkg/safety"
 	"time"
 
@@ -30,6 +31,7 @@ func main() {
 
 	client := binance.NewClient(apiKey, apiSecret)
 	client.BaseURL = "https://api.binance.us"
+	ex := exchange.NewBinanceExchange(client)
 
 	// Test 1: Circuit Breaker
 	testCircuitBreaker()
@@ -38,16 +40,16 @@ func main() {
 	testRateLimiter()
 
 	// Test 3: Liquidity Checker
-	testLiquidityChecker(client)
+	testLiquidityChecker(ex)
 
 	// Test 4: Position Limits
-	testPositionLimits(client)
+	testPositionLimits(ex)
 
 	// Test 5: Recovery Manager
 	testRecoveryManager()
 
 	// Test 6: Safety Manager (Integration)
-	testSafetyManager(client)
+	testSafetyManager(ex)
 
 	log.Println("\n✅ All safety feature tests completed!")
 }
@@ -146,7 +148,7 @@ func testRateLimiter() {
 	log.Printf("  ✅ Tokens refilled: %d", tokens)
 }
 
-func testLiquidityChecker(client *binance.Client) {
+func testLiquidityChecker(ex exchange.Exchange) {
 	log.Println("\n--- Test 3: Liquidity Checker ---")
 
 	config := safety.LiquidityConfig{
@@ -156,7 +158,7 @@ func testLiquidityChecker(client *binance.Client) {
 		MinVolumeMultiplier: 0.1,
 	}
 
-	lc := safety.NewLiquidityChecker(client, config)
+	lc := safety.NewLiquidityChecker(ex, config)
 
 	// Test with RVNUSD (your actual holding)
 	symbol := "RVNUSD"
@@ -181,7 +183,7 @@ func testLiquidityChecker(client *binance.Client) {
 	}
 }
 
-func testPositionLimits(client *binance.Client) {
+func testPositionLimits(ex exchange.Exchange) {
 	log.Println("\n--- Test 4: Position Limits ---")
 
 	config := safety.PositionLimitsConfig{
@@ -191,7 +193,7 @@ func testPositionLimits(client *binance.Client) {
 		MaxTotalPositions:   3,
 	}
 
-	pl := safety.NewPositionLimits(client, config)
+	pl := safety.NewPositionLimits(ex, config)
 
 	// Test position size check
 	symbol := "RVNUSD"
@@ -285,7 +287,7 @@ func testRecoveryManager() {
 	}
 }
 
-func testSafetyManager(client *binance.Client) {
+func testSafetyManager(ex exchange.Exchange) {
 	log.Println("\n--- Test 6: Safety Manager (Integration) ---")
 
 	config := safety.Config{
@@ -318,14 +320,14 @@ func testSafetyManager(client *binance.Client) {
 		},
 	}
 
-	sm, err := safety.NewSafetyManager(client, config)
+	sm, err := safety.NewSafetyManager(ex, config, nil)
 	if err != nil {
 		log.Fatalf("  ❌ Failed to create safety manager: %v", err)
 	}
 
 	// Test CheckTradeAllowed
 	log.Println("  Testing integrated trade checks...")
-	err = sm.CheckTradeAllowed(context.Background(), "RVNUSD", 1000.0, 0.01, "BUY")
+	err = sm.CheckTradeAllowed(context.Background(), "RVNUSD", 1000.0, 0.01, "BUY", exchange.OrderTypeMarket)
 	if err != nil {
 		log.Printf("  ⚠️  Trade check: %v", err)
 	} else {
@@ -0,0 +1,103 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"rsi-bot/pkg/strategy"
+)
+
+// LoadCSV reads OHLCV candles from a CSV file.
+// See ReadCSV for the accepted format.
+func LoadCSV(path string) ([]strategy.OHLCV, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open candle file: %w", err)
+	}
+	defer f.Close()
+
+	return ReadCSV(f)
+}
+
+// ReadCSV parses candles with columns: timestamp, open, high, low, close, volume.
+// Extra columns are ignored, so Binance kline exports can be used as-is.
+// The timestamp may be Unix milliseconds, Unix seconds or RFC3339.
+// A header row is skipped automatically and candles are returned oldest first.
+func ReadCSV(r io.Reader) ([]strategy.OHLCV, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var candles []strategy.OHLCV
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV line %d: %w", line+1, err)
+		}
+		line++
+
+		if len(record) < 6 {
+			return nil, fmt.Errorf("line %d: expected at least 6 columns, got %d", line, len(record))
+		}
+
+		timestamp, err := parseTimestamp(record[0])
+		if err != nil {
+			if line == 1 {
+				continue // Header row
+			}
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		var values [5]float64
+		for i := 0; i < 5; i++ {
+			values[i], err = strconv.ParseFloat(strings.TrimSpace(record[i+1]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid number %q: %w", line, record[i+1], err)
+			}
+		}
+
+		candles = append(candles, strategy.OHLCV{
+			Timestamp: timestamp,
+			Open:      values[0],
+			High:      values[1],
+			Low:       values[2],
+			Close:     values[3],
+			Volume:    values[4],
+		})
+	}
+
+	sort.SliceStable(candles, func(i, j int) bool {
+		return candles[i].Timestamp.Before(candles[j].Timestamp)
+	})
+
+	return candles, nil
+}
+
+// parseTimestamp accepts Unix milliseconds, Unix seconds or RFC3339
+func parseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		// Anything past the year 2286 in seconds is really milliseconds
+		if n > 9999999999 {
+			return time.UnixMilli(n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,84 @@
+package backtest
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCSV(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		input string
+		want  []time.Time // Candle timestamps, oldest first
+	}{
+		{
+			name:  "unix milliseconds with a header",
+			input: "open_time,open,high,low,close,volume\n1704067200000,100,101,99,100.5,10\n1704067260000,100.5,102,100,101,12\n",
+			want:  []time.Time{start, start.Add(time.Minute)},
+		},
+		{
+			name:  "unix seconds without a header",
+			input: "1704067200,100,101,99,100.5,10\n1704067260,100.5,102,100,101,12\n",
+			want:  []time.Time{start, start.Add(time.Minute)},
+		},
+		{
+			name:  "RFC3339",
+			input: "2024-01-01T00:00:00Z,100,101,99,100.5,10\n2024-01-01T01:00:00+01:00,100.5,102,100,101,12\n",
+			want:  []time.Time{start, start},
+		},
+		{
+			name:  "out of order rows are sorted",
+			input: "1704067320000,1,1,1,3,1\n1704067200000,1,1,1,1,1\n1704067260000,1,1,1,2,1\n",
+			want:  []time.Time{start, start.Add(time.Minute), start.Add(2 * time.Minute)},
+		},
+		{
+			name:  "extra columns and spaces",
+			input: "1704067200000, 100, 101, 99, 100.5, 10, 1704067259999, 1005, 42\n",
+			want:  []time.Time{start},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candles, err := ReadCSV(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(candles) != len(tt.want) {
+				t.Fatalf("got %d candles, want %d", len(candles), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !candles[i].Timestamp.Equal(want) {
+					t.Errorf("candle %d at %s, want %s", i, candles[i].Timestamp.UTC(), want)
+				}
+			}
+		})
+	}
+
+	candles, err := ReadCSV(strings.NewReader("1704067260000,1,1,1,2,1\n1704067200000,100,101,99,100.5,10\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := candles[0]; c.Open != 100 || c.High != 101 || c.Low != 99 || c.Close != 100.5 || c.Volume != 10 {
+		t.Errorf("first candle = %+v, want the row moved up with its values", c)
+	}
+}
+
+func TestReadCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"too few columns", "1704067200000,100,101,99,100.5\n", "line 1: expected at least 6 columns, got 5"},
+		{"invalid number", "1704067200000,100,101,abc,100.5,10\n", `line 1: invalid number "abc"`},
+		{"invalid timestamp after the header", "time,open,high,low,close,volume\nyesterday,100,101,99,100.5,10\n", `line 2: invalid timestamp "yesterday"`},
+	}
+	for _, tt := range tests {
+		if _, err := ReadCSV(strings.NewReader(tt.input)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ReadCSV = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
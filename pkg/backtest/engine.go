@@ -0,0 +1,395 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+// Config defines how the simulated account trades
+type Config struct {
+	Symbol          string
+	InitialBalance  float64 // Starting quote balance (e.g., USDT)
+	Quantity        float64 // Base quantity per entry, scaled by the signal's size (0 = invest all available cash)
+	FeePercent      float64 // Taker fee per fill, e.g. 0.1 = 0.1%
+	SlippagePercent float64 // Adverse price movement per fill, e.g. 0.05 = 0.05%
+	AllowShort      bool    // Act on short signals (no margin or borrow fees are simulated)
+	MinStrength     float64 // Skip entries whose signal strength (0-1) is below this
+}
+
+// DefaultConfig returns Binance spot-like defaults
+func DefaultConfig() Config {
+	return Config{
+		InitialBalance:  10000.0,
+		FeePercent:      0.1,
+		SlippagePercent: 0.05,
+	}
+}
+
+// EquityPoint is the account value after a candle has been processed
+type EquityPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Price     float64   `json:"price"`
+	Cash      float64   `json:"cash"`
+	Holdings  float64   `json:"holdings"` // Value of the position (negative for a short)
+	Equity    float64   `json:"equity"`
+}
+
+// Result holds the outcome of a backtest run
+type Result struct {
+	Trades             []database.Trade      `json:"trades"`
+	EquityCurve        []EquityPoint         `json:"equity_curve"`
+	Summary            database.TradeSummary `json:"summary"`
+	InitialBalance     float64               `json:"initial_balance"`
+	FinalEquity        float64               `json:"final_equity"`
+	ReturnPercent      float64               `json:"return_percent"`
+	MaxDrawdownPercent float64               `json:"max_drawdown_percent"`
+	TotalFees          float64               `json:"total_fees"`
+	CandlesProcessed   int                   `json:"candles_processed"`
+}
+
+// Engine replays historical candles through a strategy and simulates fills
+type Engine struct {
+	strategy strategy.Strategy
+	config   Config
+
+	cash       float64
+	position   *models.Position
+	entryValue float64 // Quote spent on (long) or received for (short) the open position, net of fees
+	entryID    int64   // First trade of the open position
+	nextID     int64
+	result     *Result
+}
+
+// NewEngine creates a backtest engine for a strategy
+func NewEngine(strat strategy.Strategy, config Config) (*Engine, error) {
+	if strat == nil {
+		return nil, fmt.Errorf("strategy cannot be nil")
+	}
+	if config.InitialBalance <= 0 {
+		return nil, fmt.Errorf("initial balance must be positive, got %.2f", config.InitialBalance)
+	}
+	if config.Quantity < 0 {
+		return nil, fmt.Errorf("quantity cannot be negative, got %.8f", config.Quantity)
+	}
+	if config.FeePercent < 0 || config.SlippagePercent < 0 {
+		return nil, fmt.Errorf("fee and slippage cannot be negative")
+	}
+	if config.MinStrength < 0 || config.MinStrength > 1 {
+		return nil, fmt.Errorf("minimum signal strength must be between 0 and 1, got %.2f", config.MinStrength)
+	}
+
+	return &Engine{
+		strategy: strat,
+		config:   config,
+	}, nil
+}
+
+// Run feeds candles (oldest first) through the strategy and returns the simulated result.
+// The strategy and its indicators are reset before the run so an Engine can be reused.
+func (e *Engine) Run(candles []strategy.OHLCV) (*Result, error) {
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no candles to backtest")
+	}
+
+	e.strategy.Reset()
+	e.cash = e.config.InitialBalance
+	e.position = &models.Position{}
+	e.entryValue = 0
+	e.entryID = 0
+	e.nextID = 1
+	e.result = &Result{
+		InitialBalance: e.config.InitialBalance,
+		EquityCurve:    make([]EquityPoint, 0, len(candles)),
+	}
+
+	for i, candle := range candles {
+		if i > 0 && candle.Timestamp.Before(candles[i-1].Timestamp) {
+			return nil, fmt.Errorf("candles out of order at index %d (%s before %s)",
+				i, candle.Timestamp.Format(time.RFC3339), candles[i-1].Timestamp.Format(time.RFC3339))
+		}
+
+		if err := e.step(candle); err != nil {
+			return nil, fmt.Errorf("candle %d (%s): %w", i, candle.Timestamp.Format(time.RFC3339), err)
+		}
+		e.result.CandlesProcessed++
+	}
+
+	e.finish()
+	return e.result, nil
+}
+
+// step processes a single candle the same way bot.handleMessage does
+func (e *Engine) step(candle strategy.OHLCV) error {
+	if err := strategy.UpdateWithCandle(e.strategy, candle); err != nil {
+		return fmt.Errorf("failed to update strategy: %w", err)
+	}
+
+	if e.strategy.IsReady() {
+		values := make(map[string]float64)
+		ready := true
+		if indicator := e.strategy.GetIndicator(); indicator != nil {
+			values, ready = indicator.GetValue()
+		}
+
+		if ready {
+			ctx := strategy.SignalContext{
+				CurrentPrice:  candle.Close,
+				Position:      e.position,
+				IndicatorData: values,
+				Timestamp:     candle.Timestamp,
+			}
+
+			signal := e.strategy.GenerateSignal(ctx)
+			reason := e.strategy.GetSignalReason()
+			e.execute(signal, strategy.DetailOf(e.strategy, signal), candle, values, reason)
+		}
+	}
+
+	holdings := e.position.Quantity * candle.Close
+	if e.position.IsShort() {
+		holdings = -holdings
+	}
+	e.result.EquityCurve = append(e.result.EquityCurve, EquityPoint{
+		Timestamp: candle.Timestamp,
+		Price:     candle.Close,
+		Cash:      e.cash,
+		Holdings:  holdings,
+		Equity:    e.cash + holdings,
+	})
+
+	return nil
+}
+
+// execute applies a signal the way bot.executeSignal does: entries open or add to a
+// position, exits close all or part of it, and signals that do not fit the position are ignored
+func (e *Engine) execute(signal strategy.Signal, detail strategy.SignalDetail, candle strategy.OHLCV, values map[string]float64, reason string) {
+	short := signal.Side() == models.PositionShort
+	switch {
+	case signal.IsEntry():
+		if (e.position.InPosition && e.position.IsShort() != short) || (short && !e.config.AllowShort) {
+			return
+		}
+		if detail.Strength < e.config.MinStrength {
+			return
+		}
+		e.open(signal, detail, candle, values, reason)
+	case signal.IsExit():
+		if !e.position.InPosition || e.position.IsShort() != short {
+			return
+		}
+		e.close(detail.Size, candle, values, reason)
+	}
+}
+
+// open simulates the market order of an entry at the candle close with adverse slippage:
+// a BUY for a long, a SELL for a short. A position on the same side is averaged into.
+func (e *Engine) open(signal strategy.Signal, detail strategy.SignalDetail, candle strategy.OHLCV, values map[string]float64, reason string) {
+	if e.cash <= 0 {
+		return
+	}
+
+	side := signal.Side()
+	fillPrice := candle.Close * (1 + e.config.SlippagePercent/100.0)
+	if side == models.PositionShort {
+		fillPrice = candle.Close * (1 - e.config.SlippagePercent/100.0)
+	}
+	feeRate := e.config.FeePercent / 100.0
+
+	quantity := e.config.Quantity
+	if detail.Size > 0 {
+		quantity *= detail.Size
+	}
+	if quantity == 0 || quantity*fillPrice*(1+feeRate) > e.cash {
+		// Size down to what the account can afford (shorts are limited to the same value)
+		quantity = e.cash / (fillPrice * (1 + feeRate))
+	}
+	if quantity <= 0 {
+		return
+	}
+
+	notional := quantity * fillPrice
+	fee := notional * feeRate
+	e.result.TotalFees += fee
+
+	tradeSide := "BUY"
+	if side == models.PositionShort {
+		tradeSide = "SELL"
+		e.cash += notional - fee
+		e.entryValue += notional - fee
+	} else {
+		e.cash -= notional + fee
+		e.entryValue += notional + fee
+	}
+
+	// Average into an existing position (DCA buys repeatedly)
+	totalQty := e.position.Quantity + quantity
+	e.position.EntryPrice = (e.position.EntryPrice*e.position.Quantity + fillPrice*quantity) / totalQty
+	e.position.Quantity = totalQty
+	e.position.InPosition = true
+	e.position.Side = side
+	e.position.LastUpdate = candle.Timestamp
+
+	trade := database.Trade{
+		ID:              e.nextID,
+		Symbol:          e.config.Symbol,
+		Side:            tradeSide,
+		Quantity:        quantity,
+		Price:           fillPrice,
+		Total:           notional,
+		Strategy:        e.strategy.Name(),
+		IndicatorValues: database.SerializeIndicatorValues(values),
+		SignalReason:    reason,
+		PaperTrade:      true,
+		Timestamp:       candle.Timestamp,
+		PositionSide:    string(side),
+		SignalStrength:  detail.Strength,
+	}
+	if e.entryID == 0 {
+		e.entryID = trade.ID
+	}
+	e.nextID++
+	e.result.Trades = append(e.result.Trades, trade)
+}
+
+// close simulates the market order closing fraction of the position (0 = all of it)
+// at the candle close with adverse slippage: a SELL for a long, a BUY for a short
+func (e *Engine) close(fraction float64, candle strategy.OHLCV, values map[string]float64, reason string) {
+	if !e.position.InPosition || e.position.Quantity <= 0 {
+		return
+	}
+
+	quantity := e.position.Quantity
+	if fraction > 0 && fraction < 1 {
+		quantity *= fraction
+	}
+	// The closed part carries its share of what the position was entered for
+	entryValue := e.entryValue * quantity / e.position.Quantity
+
+	short := e.position.IsShort()
+	feeRate := e.config.FeePercent / 100.0
+
+	// P/L is net of fees on both legs
+	var fillPrice, notional, fee, profitLoss float64
+	tradeSide := "SELL"
+	if short {
+		tradeSide = "BUY"
+		fillPrice = candle.Close * (1 + e.config.SlippagePercent/100.0)
+		notional = quantity * fillPrice
+		fee = notional * feeRate
+		e.cash -= notional + fee
+		profitLoss = entryValue - notional - fee
+	} else {
+		fillPrice = candle.Close * (1 - e.config.SlippagePercent/100.0)
+		notional = quantity * fillPrice
+		fee = notional * feeRate
+		e.cash += notional - fee
+		profitLoss = notional - fee - entryValue
+	}
+	e.result.TotalFees += fee
+	profitPercent := (profitLoss / entryValue) * 100
+
+	e.result.Trades = append(e.result.Trades, database.Trade{
+		ID:                e.nextID,
+		Symbol:            e.config.Symbol,
+		Side:              tradeSide,
+		Quantity:          quantity,
+		Price:             fillPrice,
+		Total:             notional,
+		Strategy:          e.strategy.Name(),
+		IndicatorValues:   database.SerializeIndicatorValues(values),
+		SignalReason:      reason,
+		PaperTrade:        true,
+		Timestamp:         candle.Timestamp,
+		ProfitLoss:        profitLoss,
+		ProfitLossPercent: profitPercent,
+		RelatedBuyID:      e.entryID,
+		PositionSide:      string(e.position.Side),
+	})
+	e.nextID++
+
+	e.position.LastUpdate = candle.Timestamp
+	if quantity < e.position.Quantity {
+		e.position.Quantity -= quantity
+		e.entryValue -= entryValue
+		return
+	}
+
+	e.position.InPosition = false
+	e.position.Side = ""
+	e.position.Quantity = 0
+	e.position.EntryPrice = 0
+	e.entryValue = 0
+	e.entryID = 0
+}
+
+// finish computes the summary statistics once all candles are processed
+func (e *Engine) finish() {
+	r := e.result
+
+	if n := len(r.EquityCurve); n > 0 {
+		r.FinalEquity = r.EquityCurve[n-1].Equity
+	} else {
+		r.FinalEquity = r.InitialBalance
+	}
+	r.ReturnPercent = ((r.FinalEquity - r.InitialBalance) / r.InitialBalance) * 100
+
+	peak := 0.0
+	for _, point := range r.EquityCurve {
+		peak = math.Max(peak, point.Equity)
+		if peak > 0 {
+			drawdown := ((peak - point.Equity) / peak) * 100
+			r.MaxDrawdownPercent = math.Max(r.MaxDrawdownPercent, drawdown)
+		}
+	}
+
+	r.Summary = Summarize(r.Trades)
+}
+
+// Summarize computes the same aggregates as database.GetTradeSummary for an in-memory trade list
+func Summarize(trades []database.Trade) database.TradeSummary {
+	var summary database.TradeSummary
+	wins, closes := 0, 0
+
+	for i, t := range trades {
+		summary.TotalTrades++
+		if i == 0 || t.Timestamp.Before(summary.StartDate) {
+			summary.StartDate = t.Timestamp
+		}
+		if t.Timestamp.After(summary.EndDate) {
+			summary.EndDate = t.Timestamp
+		}
+
+		if t.Side == "BUY" {
+			summary.TotalBuys++
+		} else {
+			summary.TotalSells++
+		}
+		if !t.Closes() {
+			continue
+		}
+
+		if closes == 0 {
+			summary.LargestWin = t.ProfitLoss
+			summary.LargestLoss = t.ProfitLoss
+		}
+		closes++
+		summary.TotalProfitLoss += t.ProfitLoss
+		summary.LargestWin = math.Max(summary.LargestWin, t.ProfitLoss)
+		summary.LargestLoss = math.Min(summary.LargestLoss, t.ProfitLoss)
+		if t.ProfitLoss > 0 {
+			wins++
+		}
+	}
+
+	if closes > 0 {
+		summary.AverageProfitLoss = summary.TotalProfitLoss / float64(closes)
+		summary.WinRate = (float64(wins) / float64(closes)) * 100
+	}
+
+	return summary
+}
@@ -0,0 +1,429 @@
+package backtest
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/indicators"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+// scriptedStrategy gives the signal scripted for each bar and records the position it saw
+type scriptedStrategy struct {
+	signals []strategy.Signal
+	sizes   []float64 // Exit fractions per bar (nil = whole position)
+	bar     int
+	seen    []models.Position
+}
+
+func (s *scriptedStrategy) Name() string                       { return "scripted" }
+func (s *scriptedStrategy) GetIndicator() indicators.Indicator { return nil }
+func (s *scriptedStrategy) IsReady() bool                      { return true }
+func (s *scriptedStrategy) GetSignalReason() string            { return "scripted" }
+func (s *scriptedStrategy) Reset()                             { s.bar, s.seen = 0, nil }
+
+func (s *scriptedStrategy) Update(price, volume float64, timestamp time.Time) error {
+	s.bar++
+	return nil
+}
+
+func (s *scriptedStrategy) GenerateSignal(ctx strategy.SignalContext) strategy.Signal {
+	s.seen = append(s.seen, *ctx.Position)
+	if s.bar > len(s.signals) {
+		return strategy.SignalNone
+	}
+	return s.signals[s.bar-1]
+}
+
+func (s *scriptedStrategy) SignalDetail() strategy.SignalDetail {
+	if s.bar > len(s.sizes) {
+		return strategy.SignalDetail{}
+	}
+	return strategy.SignalDetail{Size: s.sizes[s.bar-1]}
+}
+
+// candles returns one-minute candles closing at closes
+func candles(closes ...float64) []strategy.OHLCV {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]strategy.OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = strategy.OHLCV{Timestamp: start.Add(time.Duration(i) * time.Minute), Open: c, High: c, Low: c, Close: c}
+	}
+	return bars
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEngine_Short(t *testing.T) {
+	const (
+		none  = strategy.SignalNone
+		short = strategy.SignalOpenShort
+		cover = strategy.SignalCloseShort
+	)
+
+	tests := []struct {
+		name       string
+		feePercent float64
+		closes     []float64
+		signals    []strategy.Signal
+		sizes      []float64
+		wantPnL    []float64 // P/L of each cover
+		wantEquity float64
+	}{
+		{
+			name:       "cover after a fall",
+			closes:     []float64{100, 95, 90},
+			signals:    []strategy.Signal{short, none, cover},
+			wantPnL:    []float64{10},
+			wantEquity: 1010,
+		},
+		{
+			name:       "fees on both legs",
+			feePercent: 0.1,
+			closes:     []float64{100, 95, 90},
+			signals:    []strategy.Signal{short, none, cover},
+			wantPnL:    []float64{(100 - 0.1) - (90 + 0.09)},
+			wantEquity: 1000 + (100 - 0.1) - (90 + 0.09),
+		},
+		{
+			name:       "cover after a rise loses",
+			closes:     []float64{100, 110},
+			signals:    []strategy.Signal{short, cover},
+			wantPnL:    []float64{-10},
+			wantEquity: 990,
+		},
+		{
+			name:       "partial cover",
+			closes:     []float64{100, 90, 80},
+			signals:    []strategy.Signal{short, cover, cover},
+			sizes:      []float64{0, 0.4, 0},
+			wantPnL:    []float64{0.4 * 10, 0.6 * 20},
+			wantEquity: 1016,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := &scriptedStrategy{signals: tt.signals, sizes: tt.sizes}
+			engine, err := NewEngine(strat, Config{Symbol: "BTCUSDT", InitialBalance: 1000, Quantity: 1, FeePercent: tt.feePercent, AllowShort: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := engine.Run(candles(tt.closes...))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Trades) != 1+len(tt.wantPnL) {
+				t.Fatalf("got %d trades, want %d: %+v", len(result.Trades), 1+len(tt.wantPnL), result.Trades)
+			}
+			open := result.Trades[0]
+			if open.Side != "SELL" || open.PositionSide != string(models.PositionShort) || open.Quantity != 1 || open.Price != 100 {
+				t.Errorf("opening trade = %s %s %.4f @ %.4f, want a short SELL of 1 @ 100", open.PositionSide, open.Side, open.Quantity, open.Price)
+			}
+			for i, want := range tt.wantPnL {
+				trade := result.Trades[i+1]
+				if trade.Side != "BUY" || trade.PositionSide != string(models.PositionShort) || trade.RelatedBuyID != open.ID {
+					t.Errorf("cover %d = %s %s related to %d, want a short BUY related to %d", i+1, trade.PositionSide, trade.Side, trade.RelatedBuyID, open.ID)
+				}
+				if !almostEqual(trade.ProfitLoss, want) {
+					t.Errorf("cover %d P/L = %.8f, want %.8f", i+1, trade.ProfitLoss, want)
+				}
+			}
+			if !almostEqual(result.FinalEquity, tt.wantEquity) {
+				t.Errorf("final equity = %.8f, want %.8f", result.FinalEquity, tt.wantEquity)
+			}
+			if last := strat.seen[len(strat.seen)-1]; last.Side != models.PositionShort || !last.InPosition {
+				t.Errorf("position before the last cover = %+v, want a short", last)
+			}
+		})
+	}
+}
+
+func TestEngine_PartialCoverLeavesRemainder(t *testing.T) {
+	strat := &scriptedStrategy{
+		signals: []strategy.Signal{strategy.SignalOpenShort, strategy.SignalCloseShort, strategy.SignalNone},
+		sizes:   []float64{0, 0.25, 0},
+	}
+	engine, err := NewEngine(strat, Config{Symbol: "BTCUSDT", InitialBalance: 1000, Quantity: 2, AllowShort: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := engine.Run(candles(100, 90, 85))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The bar after the partial cover sees the rest of the short at the original entry price
+	rest := strat.seen[2]
+	if !rest.InPosition || !rest.IsShort() || !almostEqual(rest.Quantity, 1.5) || rest.EntryPrice != 100 {
+		t.Errorf("position after covering 25%% = %+v, want short 1.5 @ 100", rest)
+	}
+	if cover := result.Trades[1]; !almostEqual(cover.Quantity, 0.5) || !almostEqual(cover.ProfitLoss, 5) {
+		t.Errorf("cover = %.8f with P/L %.8f, want 0.5 with 5", cover.Quantity, cover.ProfitLoss)
+	}
+
+	// Cash holds the proceeds of the whole short less the cover; the open rest is valued at the close
+	last := result.EquityCurve[2]
+	if !almostEqual(last.Cash, 1000+200-45) || !almostEqual(last.Holdings, -1.5*85) || !almostEqual(last.Equity, 1000+5+1.5*15) {
+		t.Errorf("last equity point = %+v", last)
+	}
+}
+
+func TestEngine_ShortsDisabled(t *testing.T) {
+	strat := &scriptedStrategy{signals: []strategy.Signal{strategy.SignalOpenShort, strategy.SignalCloseShort}}
+	engine, err := NewEngine(strat, Config{Symbol: "BTCUSDT", InitialBalance: 1000, Quantity: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := engine.Run(candles(100, 90))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Trades) != 0 || result.FinalEquity != 1000 {
+		t.Errorf("got %d trades and equity %.2f without allow_short, want none and 1000", len(result.Trades), result.FinalEquity)
+	}
+}
+
+func TestEngine_Long(t *testing.T) {
+	const (
+		none = strategy.SignalNone
+		buy  = strategy.SignalBuy
+		sell = strategy.SignalSell
+	)
+
+	tests := []struct {
+		name         string
+		feePercent   float64
+		slippage     float64
+		closes       []float64
+		signals      []strategy.Signal
+		wantPrices   []float64 // Fill price of each trade
+		wantPnL      float64   // P/L of the closing trade
+		wantFees     float64
+		wantEquity   float64
+		wantReturn   float64
+		wantDrawdown float64
+	}{
+		{
+			name:       "sell after a rise",
+			closes:     []float64{100, 105, 110},
+			signals:    []strategy.Signal{buy, none, sell},
+			wantPrices: []float64{100, 110},
+			wantPnL:    10,
+			wantEquity: 1010,
+			wantReturn: 1,
+		},
+		{
+			name:       "fees and slippage on both legs",
+			feePercent: 0.1,
+			slippage:   0.5,
+			closes:     []float64{100, 110},
+			signals:    []strategy.Signal{buy, sell},
+			wantPrices: []float64{100.5, 109.45},
+			wantPnL:    (109.45 - 0.10945) - (100.5 + 0.1005),
+			wantFees:   0.1005 + 0.10945,
+			wantEquity: 1000 + (109.45 - 0.10945) - (100.5 + 0.1005),
+			wantReturn: ((109.45 - 0.10945) - (100.5 + 0.1005)) / 10,
+		},
+		{
+			name:       "scale in averages the entry",
+			closes:     []float64{100, 120, 130},
+			signals:    []strategy.Signal{buy, buy, sell},
+			wantPrices: []float64{100, 120, 130},
+			wantPnL:    40,
+			wantEquity: 1040,
+			wantReturn: 4,
+		},
+		{
+			name:         "sell after a fall loses",
+			closes:       []float64{100, 120, 90},
+			signals:      []strategy.Signal{buy, none, sell},
+			wantPrices:   []float64{100, 90},
+			wantPnL:      -10,
+			wantEquity:   990,
+			wantReturn:   -1,
+			wantDrawdown: 30.0 / 1020 * 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := &scriptedStrategy{signals: tt.signals}
+			engine, err := NewEngine(strat, Config{Symbol: "BTCUSDT", InitialBalance: 1000, Quantity: 1, FeePercent: tt.feePercent, SlippagePercent: tt.slippage})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := engine.Run(candles(tt.closes...))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Trades) != len(tt.wantPrices) {
+				t.Fatalf("got %d trades, want %d: %+v", len(result.Trades), len(tt.wantPrices), result.Trades)
+			}
+			for i, want := range tt.wantPrices {
+				if trade := result.Trades[i]; !almostEqual(trade.Price, want) || trade.PositionSide != string(models.PositionLong) {
+					t.Errorf("trade %d = %s %s @ %.8f, want a long @ %.8f", i+1, trade.PositionSide, trade.Side, trade.Price, want)
+				}
+			}
+			last := result.Trades[len(result.Trades)-1]
+			if last.Side != "SELL" || last.RelatedBuyID != result.Trades[0].ID || !almostEqual(last.ProfitLoss, tt.wantPnL) {
+				t.Errorf("closing trade = %s related to %d with P/L %.8f, want a SELL related to %d with P/L %.8f",
+					last.Side, last.RelatedBuyID, last.ProfitLoss, result.Trades[0].ID, tt.wantPnL)
+			}
+
+			if !almostEqual(result.TotalFees, tt.wantFees) {
+				t.Errorf("total fees = %.8f, want %.8f", result.TotalFees, tt.wantFees)
+			}
+			if !almostEqual(result.FinalEquity, tt.wantEquity) || !almostEqual(result.ReturnPercent, tt.wantReturn) {
+				t.Errorf("final equity = %.8f (%.4f%%), want %.8f (%.4f%%)", result.FinalEquity, result.ReturnPercent, tt.wantEquity, tt.wantReturn)
+			}
+			if !almostEqual(result.MaxDrawdownPercent, tt.wantDrawdown) {
+				t.Errorf("max drawdown = %.6f%%, want %.6f%%", result.MaxDrawdownPercent, tt.wantDrawdown)
+			}
+			if result.CandlesProcessed != len(tt.closes) || len(result.EquityCurve) != len(tt.closes) {
+				t.Errorf("processed %d candles with %d equity points, want %d", result.CandlesProcessed, len(result.EquityCurve), len(tt.closes))
+			}
+		})
+	}
+}
+
+func TestEngine_EquityCurve(t *testing.T) {
+	strat := &scriptedStrategy{signals: []strategy.Signal{strategy.SignalBuy, strategy.SignalNone, strategy.SignalNone, strategy.SignalSell}}
+	engine, err := NewEngine(strat, Config{Symbol: "BTCUSDT", InitialBalance: 1000, Quantity: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := engine.Run(candles(100, 120, 90, 110))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []EquityPoint{
+		{Price: 100, Cash: 800, Holdings: 200, Equity: 1000},
+		{Price: 120, Cash: 800, Holdings: 240, Equity: 1040},
+		{Price: 90, Cash: 800, Holdings: 180, Equity: 980},
+		{Price: 110, Cash: 1020, Holdings: 0, Equity: 1020},
+	}
+	for i, point := range result.EquityCurve {
+		want[i].Timestamp = point.Timestamp
+		if point != want[i] {
+			t.Errorf("equity point %d = %+v, want %+v", i, point, want[i])
+		}
+	}
+	// The drawdown runs from the 1040 peak, not from the initial balance
+	if wantDrawdown := 60.0 / 1040 * 100; !almostEqual(result.MaxDrawdownPercent, wantDrawdown) {
+		t.Errorf("max drawdown = %.6f%%, want %.6f%%", result.MaxDrawdownPercent, wantDrawdown)
+	}
+}
+
+func TestEngine_Reuse(t *testing.T) {
+	rsi, err := indicators.NewRSI(14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	strat, err := strategy.NewRSIStrategy(rsi, 70, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := NewEngine(strat, Config{Symbol: "BTCUSDT", InitialBalance: 1000, Quantity: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closes := make([]float64, 120)
+	for i := range closes {
+		closes[i] = 100 + 20*math.Sin(float64(i)/6)
+	}
+	bars := candles(closes...)
+
+	first, err := engine.Run(bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Trades) == 0 {
+		t.Fatal("got no trades, want the RSI to cross its levels")
+	}
+	second, err := engine.Run(bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second run starts from a fresh indicator, so it trades exactly like the first
+	if !reflect.DeepEqual(second.Trades, first.Trades) || second.FinalEquity != first.FinalEquity {
+		t.Errorf("second run made %d trades ending at %.8f, want %d ending at %.8f",
+			len(second.Trades), second.FinalEquity, len(first.Trades), first.FinalEquity)
+	}
+}
+
+func TestSummarize_MatchesDatabase(t *testing.T) {
+	const (
+		none  = strategy.SignalNone
+		buy   = strategy.SignalBuy
+		sell  = strategy.SignalSell
+		short = strategy.SignalOpenShort
+		cover = strategy.SignalCloseShort
+	)
+	// Two winning and two losing closes, one of them partial
+	strat := &scriptedStrategy{
+		signals: []strategy.Signal{buy, sell, short, cover, none, cover, buy, sell},
+		sizes:   []float64{0, 0, 0, 0.5, 0, 0, 0, 0},
+	}
+	engine, err := NewEngine(strat, Config{Symbol: "BTCUSDT", InitialBalance: 1000, Quantity: 1, FeePercent: 0.1, AllowShort: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := engine.Run(candles(100, 110, 120, 100, 110, 130, 95, 90))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := database.New(filepath.Join(t.TempDir(), "trading_bot.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for i := range result.Trades {
+		if _, err := db.InsertTrade(&result.Trades[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stored, err := db.GetTradeSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := result.Summary
+	if got.TotalTrades != stored.TotalTrades || got.TotalBuys != stored.TotalBuys || got.TotalSells != stored.TotalSells {
+		t.Errorf("counts = %d trades, %d buys, %d sells; database has %d, %d, %d",
+			got.TotalTrades, got.TotalBuys, got.TotalSells, stored.TotalTrades, stored.TotalBuys, stored.TotalSells)
+	}
+	for _, v := range []struct {
+		name       string
+		got, store float64
+	}{
+		{"total P/L", got.TotalProfitLoss, stored.TotalProfitLoss},
+		{"average P/L", got.AverageProfitLoss, stored.AverageProfitLoss},
+		{"largest win", got.LargestWin, stored.LargestWin},
+		{"largest loss", got.LargestLoss, stored.LargestLoss},
+		{"win rate", got.WinRate, stored.WinRate},
+	} {
+		if !almostEqual(v.got, v.store) {
+			t.Errorf("%s = %.8f, database has %.8f", v.name, v.got, v.store)
+		}
+	}
+	if !got.StartDate.Equal(stored.StartDate) || !got.EndDate.Equal(stored.EndDate) {
+		t.Errorf("dates = %s to %s, database has %s to %s", got.StartDate, got.EndDate, stored.StartDate, stored.EndDate)
+	}
+	if got.WinRate == 0 || got.WinRate == 100 {
+		t.Errorf("win rate = %.2f%%, want a mix of wins and losses", got.WinRate)
+	}
+}
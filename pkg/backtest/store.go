@@ -0,0 +1,78 @@
+package backtest
+
+import (
+	"fmt"
+	"time"
+
+	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/strategy"
+)
+
+// importBatchSize is the number of candles ImportCandles stores per transaction
+const importBatchSize = 5000
+
+// ImportCSV stores the candles of a CSV file (see ReadCSV) under symbol and interval,
+// replacing candles already stored at the same open times. It returns the number stored.
+func ImportCSV(db *database.DB, path, symbol, interval string) (int, error) {
+	candles, err := LoadCSV(path)
+	if err != nil {
+		return 0, err
+	}
+	return ImportCandles(db, candles, symbol, interval)
+}
+
+// ImportCandles stores candles under symbol and interval in batches
+func ImportCandles(db *database.DB, candles []strategy.OHLCV, symbol, interval string) (int, error) {
+	stored := 0
+	for start := 0; start < len(candles); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(candles) {
+			end = len(candles)
+		}
+
+		batch := make([]database.Candle, 0, end-start)
+		for _, c := range candles[start:end] {
+			batch = append(batch, database.Candle{
+				Symbol:   symbol,
+				Interval: interval,
+				OpenTime: c.Timestamp,
+				Open:     c.Open,
+				High:     c.High,
+				Low:      c.Low,
+				Close:    c.Close,
+				Volume:   c.Volume,
+			})
+		}
+
+		if err := db.InsertCandles(batch); err != nil {
+			return stored, fmt.Errorf("failed to import candles %d-%d: %w", start, end-1, err)
+		}
+		stored += len(batch)
+	}
+	return stored, nil
+}
+
+// LoadFromDB reads candles for a symbol and interval from the SQLite store, oldest first
+func LoadFromDB(db *database.DB, symbol, interval string, start, end time.Time) ([]strategy.OHLCV, error) {
+	stored, err := db.GetCandles(symbol, interval, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(stored) == 0 {
+		return nil, fmt.Errorf("no %s %s candles stored between %s and %s",
+			symbol, interval, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	candles := make([]strategy.OHLCV, len(stored))
+	for i, c := range stored {
+		candles[i] = strategy.OHLCV{
+			Timestamp: c.OpenTime,
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		}
+	}
+	return candles, nil
+}
@@ -0,0 +1,67 @@
+package backtest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/strategy"
+)
+
+func TestImportCandles(t *testing.T) {
+	db, err := database.New(filepath.Join(t.TempDir(), "trading_bot.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// More than two batches
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]strategy.OHLCV, 2*importBatchSize+10)
+	for i := range candles {
+		price := 100 + float64(i%50)
+		candles[i] = strategy.OHLCV{Timestamp: start.Add(time.Duration(i) * time.Minute), Open: price, High: price + 1, Low: price - 1, Close: price, Volume: 1}
+	}
+	end := candles[len(candles)-1].Timestamp
+
+	stored, err := ImportCandles(db, candles, "BTCUSDT", "1m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored != len(candles) {
+		t.Errorf("stored %d, want %d", stored, len(candles))
+	}
+
+	// Importing overlapping candles replaces them instead of duplicating
+	overlap := append([]strategy.OHLCV(nil), candles[len(candles)-5:]...)
+	for i := range overlap {
+		overlap[i].Close = 1
+	}
+	if _, err := ImportCandles(db, overlap, "BTCUSDT", "1m"); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadFromDB(db, "BTCUSDT", "1m", start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(candles) {
+		t.Fatalf("loaded %d candles, want %d", len(loaded), len(candles))
+	}
+	for i, c := range loaded {
+		want := candles[i]
+		if i >= len(candles)-5 {
+			want.Close = 1
+		}
+		if !c.Timestamp.Equal(want.Timestamp) || c.Open != want.Open || c.High != want.High || c.Low != want.Low || c.Close != want.Close || c.Volume != want.Volume {
+			t.Fatalf("candle %d = %+v, want %+v", i, c, want)
+		}
+	}
+
+	// Other symbols and intervals are kept apart
+	if _, err := LoadFromDB(db, "BTCUSDT", "5m", start, end); err == nil || !strings.Contains(err.Error(), "no BTCUSDT 5m candles stored") {
+		t.Errorf("LoadFromDB(5m) = %v, want no candles stored", err)
+	}
+}
@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"rsi-bot/pkg/strategy"
+)
+
+// backfillPageSize is the most klines requested per REST call while backfilling (Binance's maximum)
+const backfillPageSize = 1000
+
+// backfill feeds every market the closed candles it missed since its last one,
+// so indicators and timeframe aggregators see no gap after a reconnect
+func (b *Bot) backfill(ctx context.Context) {
+	for _, symbol := range b.symbols {
+		m := b.markets[symbol]
+
+		m.mu.Lock()
+		b.fillGap(ctx, m, time.Now())
+		m.mu.Unlock()
+	}
+}
+
+// fillGap feeds m the closed candles opening after its last candle and before until,
+// fetched over REST. The caller holds m.mu. Backfilled candles update the strategy
+// and ATR like warm-up candles do: they are history, so they generate no signals or exits.
+func (b *Bot) fillGap(ctx context.Context, m *market, until time.Time) {
+	// Nothing processed yet: warm-up or the first live candle sets the starting point
+	if m.lastCandle.IsZero() {
+		return
+	}
+
+	interval := b.config.KlineInterval()
+	from := m.lastCandle
+	fed := 0
+	var lastClose float64
+
+pages:
+	for {
+		klines, err := b.exchange.GetKlinesSince(ctx, m.symbol, interval, m.lastCandle.Add(time.Millisecond), backfillPageSize)
+		if err != nil {
+			log.Printf("⚠️  %s: Failed to backfill %s candles after %s: %v", m.symbol, interval, m.lastCandle.Format(time.RFC3339), err)
+			break pages
+		}
+		b.cacheKlines(m.symbol, interval, klines)
+
+		now := time.Now()
+		progressed := false
+		for _, k := range klines {
+			if !k.IsClosed(now) || !k.OpenTime.Before(until) || !k.OpenTime.After(m.lastCandle) {
+				continue
+			}
+			if err := strategy.UpdateWithCandle(m.strategy, klineCandle(k)); err != nil {
+				log.Printf("⚠️  %s: Failed to update strategy with backfilled candle: %v", m.symbol, err)
+				break pages
+			}
+			m.updateVolatility(k.High, k.Low, k.Close)
+			m.lastCandle = k.OpenTime
+			lastClose = k.Close
+			fed++
+			progressed = true
+		}
+
+		if !progressed || len(klines) < backfillPageSize {
+			break
+		}
+	}
+
+	if fed == 0 {
+		return
+	}
+
+	if b.paper != nil {
+		b.paper.UpdatePrice(m.symbol, lastClose)
+	}
+
+	log.Printf("🩹 %s: Backfilled %d missed %s candles (%s to %s)", m.symbol, fed, interval, from.Format(time.RFC3339), m.lastCandle.Format(time.RFC3339))
+	b.emit("bot:status", fmt.Sprintf("%s: Backfilled %d missed candles", m.symbol, fed), map[string]interface{}{
+		"symbol":  m.symbol,
+		"candles": fed,
+		"from":    from,
+		"to":      m.lastCandle,
+	})
+}
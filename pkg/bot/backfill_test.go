@@ -0,0 +1,129 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+)
+
+func TestFillGap(t *testing.T) {
+	// More closed klines than one REST page, then the open one
+	klines := testKlines(time.Minute, 2*backfillPageSize+500)
+	lastClosed := len(klines) - 2
+
+	tests := []struct {
+		name     string
+		from     int // Index of the market's last candle
+		until    time.Time
+		err      error
+		wantLast int // Index of the last candle fed
+	}{
+		{"pages to the last closed kline", 0, time.Now().Add(time.Hour), nil, lastClosed},
+		{"stops before until", 0, klines[1500].OpenTime, nil, 1499},
+		{"nothing missed", lastClosed, time.Now().Add(time.Hour), nil, lastClosed},
+		{"REST error", 100, time.Now().Add(time.Hour), errors.New("exchange unreachable"), 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeExchange()
+			fake.SetKlines("BTCUSDT", "1m", klines)
+			fake.Err = tt.err
+			b, m, counting := newWarmUpBot(t, fake)
+			m.lastCandle = klines[tt.from].OpenTime
+
+			b.fillGap(context.Background(), m, tt.until)
+
+			if want := tt.wantLast - tt.from; len(counting.fed) != want {
+				t.Fatalf("fed %d candles, want %d", len(counting.fed), want)
+			}
+			for i, fed := range counting.fed {
+				if want := klines[tt.from+1+i].OpenTime; !fed.Equal(want) {
+					t.Fatalf("candle %d opened at %s, want %s", i, fed, want)
+				}
+			}
+			if !m.lastCandle.Equal(klines[tt.wantLast].OpenTime) {
+				t.Errorf("last candle = %s, want %s", m.lastCandle, klines[tt.wantLast].OpenTime)
+			}
+		})
+	}
+}
+
+func TestNextCandleOpen(t *testing.T) {
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		interval string
+		open     time.Time
+		want     time.Time
+	}{
+		{"1m", jan, jan.Add(time.Minute)},
+		{"1w", jan, jan.AddDate(0, 0, 7)},
+		{"1M", jan, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"1M", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"1M", time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// Open times read from the stream are local
+		{"1M", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC).Local(), time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := nextCandleOpen(tt.interval, tt.open); !got.Equal(tt.want) {
+			t.Errorf("nextCandleOpen(%s, %s) = %s, want %s", tt.interval, tt.open, got, tt.want)
+		}
+	}
+}
+
+// backfillCounter counts the backfill requests made to a fake exchange
+type backfillCounter struct {
+	*exchange.FakeExchange
+	calls int
+}
+
+func (c *backfillCounter) GetKlinesSince(ctx context.Context, symbol, interval string, since time.Time, limit int) ([]exchange.Kline, error) {
+	c.calls++
+	return c.FakeExchange.GetKlinesSince(ctx, symbol, interval, since, limit)
+}
+
+func TestHandleMessage_MonthlyGap(t *testing.T) {
+	month := func(m time.Month) time.Time { return time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC) }
+	var klines []exchange.Kline
+	for m := time.January; m <= time.May; m++ {
+		klines = append(klines, exchange.Kline{OpenTime: month(m), CloseTime: month(m + 1).Add(-time.Millisecond), Open: 100, High: 100, Low: 100, Close: 100})
+	}
+	fake := &backfillCounter{FakeExchange: newFakeExchange()}
+	fake.SetKlines("BTCUSDT", "1M", klines)
+	b, m, _ := newTestBot(t, &models.Config{Interval: "1M"}, fake)
+	counting := &countingStrategy{}
+	m.strategy = counting
+	m.lastCandle = month(time.January)
+
+	// January has 31 days, February 29 (2024), but neither month follows a gap
+	for _, next := range []time.Month{time.February, time.March} {
+		if err := b.handleMessage(klineMessage("BTCUSDT", month(next), 100)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fake.calls != 0 {
+		t.Errorf("backfilled %d time(s) between consecutive months, want none", fake.calls)
+	}
+
+	// April was missed, so it is backfilled before May
+	if err := b.handleMessage(klineMessage("BTCUSDT", month(time.May), 100)); err != nil {
+		t.Fatal(err)
+	}
+	if fake.calls != 1 {
+		t.Errorf("backfilled %d time(s), want once for April", fake.calls)
+	}
+
+	want := []time.Time{month(time.February), month(time.March), month(time.April), month(time.May)}
+	if len(counting.fed) != len(want) {
+		t.Fatalf("fed %v, want %v", counting.fed, want)
+	}
+	for i := range want {
+		if !counting.fed[i].Equal(want[i]) {
+			t.Errorf("candle %d opened at %s, want %s", i, counting.fed[i], want[i])
+		}
+	}
+}
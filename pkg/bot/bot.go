@@ -3,11 +3,13 @@ package bot
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/exchange"
 	"rsi-bot/pkg/indicators"
 	"rsi-bot/pkg/models"
 	"rsi-bot/pkg/safety"
@@ -15,6 +17,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
@@ -23,6 +26,21 @@ import (
 	"github.com/joho/godotenv"
 )
 
+const (
+	// streamStableAfter is how long a connection must hold before its endpoint's failure streak and the reconnect backoff reset
+	streamStableAfter = time.Minute
+
+	// Closed candles are stored in batches of candleBatchSize, or every candleFlushInterval
+	candleBatchSize     = 50
+	candleFlushInterval = 30 * time.Second
+
+	// Strategy state is saved this often, after every signal and on shutdown
+	stateSaveInterval = 5 * time.Minute
+)
+
+// errStaleStream ends a connection that stopped delivering klines
+var errStaleStream = errors.New("stale stream")
+
 // Helper function for safe string slicing
 func min(a, b int) int {
 	if a < b {
@@ -31,24 +49,78 @@ func min(a, b int) int {
 	return b
 }
 
-type Bot struct {
-	config   *models.Config
+// market holds the trading state of one symbol; each symbol gets its own strategy instance
+type market struct {
+	symbol   string
 	strategy strategy.Strategy
 	position *models.Position
-	conn     *websocket.Conn
-	connMu   sync.Mutex // Protects conn field
-	client   *binance.Client
-	db       *database.DB
-	logs     []string
 
 	// Track current position in database
 	currentPositionID int64
 
+	// P/L realized and quantity closed by partial closes of the open position
+	realizedPnL    float64
+	closedQuantity float64
+
+	// Open time of the last candle fed to the strategy (skips replays after warm-up or reconnect)
+	lastCandle time.Time
+
+	// Strategy state was restored from the database; warm-up only feeds the candles since
+	restored bool
+
+	// Stop-loss / take-profit of the open position (nil when flat or risk exits are disabled)
+	exits *positionExits
+
+	// Last close and ATR of the bot interval (atr is nil unless sizing needs it)
+	lastPrice float64
+	atr       *strategy.ATRCalculator
+
+	// Resting limit order being tracked (nil when none); mu guards the market
+	// against the goroutine that completes it
+	pending *exchange.Order
+	mu      sync.Mutex
+}
+
+type Bot struct {
+	config   *models.Config
+	markets  map[string]*market // Keyed by upper-case symbol
+	symbols  []string           // Configured symbols, in config order
+	conn     *websocket.Conn
+	connMu   sync.Mutex // Protects conn field
+	exchange exchange.Exchange
+	paper    *exchange.PaperExchange // Set when exchange is a paper-trading simulator
+	db       *database.DB // nil only on the minimal bot New returns without API credentials
+	logs     []string
+
 	// Event callback for real-time updates to UI
 	eventCallback func(eventType string, message string, data map[string]interface{})
 
 	// Safety & Resilience (Phase 7.5)
 	safety *safety.SafetyManager
+
+	// Risk exits enforced on open positions (nil when disabled)
+	risk *strategy.RiskManager
+
+	// Sizes risk_per_trade orders (nil for other sizing modes)
+	sizing *strategy.RiskManager
+
+	// LOT_SIZE / PRICE_FILTER / MIN_NOTIONAL rules of the traded symbols
+	filters *exchange.FilterCache
+
+	// Health of the market data WebSocket endpoints, used to pick where to reconnect
+	endpoints *endpointPool
+
+	// Stores every closed candle of the stream (nil when New had no API credentials)
+	candles *database.CandleWriter
+
+	// Context of the running bot: resting orders stop being tracked when it ends
+	runCtx   context.Context
+	tracking sync.WaitGroup // Resting orders still being tracked
+
+	// Account state pushed by the user data stream (live trading only)
+	accountMu  sync.Mutex
+	balances   map[string]exchange.Balance // nil until the stream connects
+	openOrders map[int64]*exchange.Order   // With the fills seen so far
 }
 
 func New(config *models.Config) *Bot {
@@ -74,27 +146,149 @@ func New(config *models.Config) *Bot {
 		log.Println("⚠️  BINANCE_API_KEY and BINANCE_API_SECRET must be set")
 		// Return a minimal bot that will fail gracefully when started
 		return &Bot{
-			config:   config,
-			position: &models.Position{},
+			config:  config,
+			markets: make(map[string]*market),
 		}
 	}
 	//creating binance client below
 	client := binance.NewClient(config.APIKey, config.APISecret)
 	client.BaseURL = "https://testnet.binance.vision"
+	binanceExchange := exchange.NewBinanceExchange(client)
 
 	// Synchronize time with Binance server to prevent timestamp errors (-1021)
-	serverTime, timeErr := client.NewServerTimeService().Do(context.Background())
+	offset, timeErr := binanceExchange.SyncTime(context.Background())
 	if timeErr != nil {
 		log.Printf("⚠️  Warning: Failed to sync time with Binance server: %v", timeErr)
 		log.Println("   Continuing without time sync - may encounter timestamp errors")
 	} else {
-		localTime := time.Now().UnixMilli()
-		timeOffset := serverTime - localTime
-		// Set offset with 1 second safety buffer to ensure we're always behind server time
-		client.TimeOffset = timeOffset - 1000
-		log.Printf("⏰ Time synchronized with Binance: offset=%dms (with 1s buffer)", client.TimeOffset)
+		log.Printf("⏰ Time synchronized with Binance: offset=%dms (with 1s buffer)", offset)
+	}
+
+	// Initialize database
+	db, err := database.New("trading_bot.db")
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	log.Println("✅ Database initialized")
+
+	// Paper trading routes orders through a local simulator backed by live market data
+	var ex exchange.Exchange = binanceExchange
+	if !config.TradingEnabled {
+		log.Println("📝 Paper trading: orders will be simulated against the live order book")
+		// The simulated account can short whenever the bot may
+		paperConfig := config.Paper
+		paperConfig.AllowShort = paperConfig.AllowShort || config.Signals.AllowShort
+		ex = exchange.NewPaperExchange(binanceExchange, paperConfig)
 	}
 
+	b, err := NewWithExchange(config, ex, db)
+	if err != nil {
+		log.Fatalf("Failed to create bot: %v", err)
+	}
+	return b
+}
+
+// NewWithExchange creates a bot that trades through the given exchange and stores its
+// trades, positions and candles in db. New uses this with a Binance adapter and
+// trading_bot.db; tests pass an exchange.FakeExchange and a temporary database.
+// Every configured symbol gets its own strategy instance and position. The database
+// is required: the bot restores its state from it and records every trade in it.
+func NewWithExchange(config *models.Config, ex exchange.Exchange, db *database.DB) (*Bot, error) {
+	if db == nil {
+		return nil, fmt.Errorf("bot needs a database")
+	}
+
+	paper, _ := ex.(*exchange.PaperExchange)
+	risk := newRiskManager(config)
+
+	b := &Bot{
+		config:    config,
+		exchange:  ex,
+		paper:     paper,
+		db:        db,
+		risk:      risk,
+		sizing:    newSizingManager(config),
+		filters:   exchange.NewFilterCache(ex, exchange.DefaultFilterCacheTTL),
+		endpoints: newEndpointPool(config.Stream.StreamEndpoints()),
+		candles:   db.NewCandleWriter(candleBatchSize, candleFlushInterval, func(err error) {
+			log.Printf("⚠️  Failed to store candles: %v", err)
+		}),
+	}
+
+	symbols := config.TradingSymbols()
+	markets := make(map[string]*market, len(symbols))
+	for _, symbol := range symbols {
+		m := &market{
+			symbol:   symbol,
+			strategy: newStrategy(config),
+			atr:      newVolatility(config),
+			position: &models.Position{
+				InPosition: false,
+				Quantity:   0,
+				EntryPrice: 0,
+				LastUpdate: time.Now(),
+			},
+		}
+
+		// Resume the strategy from its saved state instead of re-warming
+		b.restoreState(m)
+
+		// Restore position from database if exists
+		dbPosition, err := db.GetOpenPosition(symbol)
+		if err != nil {
+			log.Printf("⚠️  Error checking for open %s position: %v", symbol, err)
+		}
+		if dbPosition != nil {
+			m.position.InPosition = true
+			m.position.Side = models.PositionSide(dbPosition.PositionSide())
+			m.position.Quantity = dbPosition.Quantity
+			m.position.EntryPrice = dbPosition.EntryPrice
+			m.position.LastUpdate = dbPosition.EntryTime
+			m.currentPositionID = dbPosition.ID
+			m.realizedPnL = dbPosition.RealizedPnL
+			m.closedQuantity = dbPosition.ClosedQuantity
+			m.exits = b.restoreExits(dbPosition)
+			log.Printf("📍 Restored open %s %s position from database: %.0f @ %.8f", symbol, m.position.Side, m.position.Quantity, m.position.EntryPrice)
+			if m.exits != nil {
+				log.Printf("   🛡️  Stop-loss %.8f, take-profit %.8f", m.exits.stop.StopLossPrice, m.exits.takeProfit)
+			}
+
+			// A restored position must exist in the simulated account too, or it could never be
+			// closed: a long holds the base asset, a short owes it
+			if paper != nil {
+				if filters, err := paper.GetSymbolFilters(context.Background(), symbol); err == nil {
+					if m.position.IsShort() {
+						paper.Deposit(filters.BaseAsset, -m.position.Quantity)
+					} else {
+						paper.Deposit(filters.BaseAsset, m.position.Quantity)
+					}
+				}
+			}
+		}
+
+		markets[symbol] = m
+	}
+
+	// Initialize Safety Manager (Phase 7.5)
+	safetyMgr, err := safety.NewSafetyManager(ex, config.Safety, b.filters)
+	if err != nil {
+		log.Printf("⚠️  Failed to initialize safety manager: %v", err)
+		safetyMgr = nil
+	}
+
+	b.markets = markets
+	b.symbols = symbols
+	b.safety = safetyMgr
+
+	if config.Signals.AllowShort && !b.shortsAllowed() {
+		log.Printf("⚠️  signals.allow_short is set but the exchange cannot short: short signals will be ignored")
+	}
+	return b, nil
+}
+
+// newStrategy creates a strategy instance from config
+func newStrategy(config *models.Config) strategy.Strategy {
 	// Create strategy based on config
 	var strat strategy.Strategy
 	var err error
@@ -102,16 +296,8 @@ func New(config *models.Config) *Bot {
 
 	// Check if new strategy config is specified
 	if config.Strategy.Type != "" {
-		// Use new strategy config
-		stratConfig := strategy.StrategyConfig{
-			Type:            config.Strategy.Type,
-			IndicatorConfig: indicators.IndicatorConfig{
-				Type:   config.Strategy.Indicator.Type,
-				Params: config.Strategy.Indicator.Params,
-			},
-			OverboughtLevel: config.Strategy.OverboughtLevel,
-			OversoldLevel:   config.Strategy.OversoldLevel,
-		}
+		// Use new strategy config (composite strategies bring their children)
+		stratConfig := strategy.ConfigFromModel(config.Strategy)
 
 		// Validate config
 		if err := stratFactory.ValidateConfig(stratConfig); err != nil {
@@ -123,7 +309,11 @@ func New(config *models.Config) *Bot {
 			log.Fatalf("Failed to create strategy: %v", err)
 		}
 
-		log.Printf("✅ Created %s strategy with indicator: %s", config.Strategy.Type, config.Strategy.Indicator.Type)
+		if len(config.Strategy.Children) > 0 {
+			log.Printf("✅ Created %s strategy with %d child strategies", config.Strategy.Type, len(config.Strategy.Children))
+		} else {
+			log.Printf("✅ Created %s strategy with indicator: %s", config.Strategy.Type, config.Strategy.Indicator.Type)
+		}
 	} else if config.Indicator.Type != "" {
 		// Fallback to legacy indicator config (create RSI strategy)
 		log.Println("⚠️  Using legacy 'indicator' config. Consider using 'strategy' config instead.")
@@ -166,62 +356,15 @@ func New(config *models.Config) *Bot {
 		log.Printf("✅ Created RSI strategy with period: %d", config.RSIPeriod)
 	}
 
-	// Initialize database
-	db, err := database.New("trading_bot.db")
-	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
-	}
-
-	log.Println("✅ Database initialized")
-
-	// Check for existing open position in database
-	dbPosition, err := db.GetOpenPosition(config.Symbol)
-	if err != nil {
-		log.Printf("⚠️  Error checking for open position: %v", err)
-	}
-
-	position := &models.Position{
-		InPosition: false,
-		Quantity:   0,
-		EntryPrice: 0,
-		LastUpdate: time.Now(),
-	}
-
-	var currentPosID int64 = 0
-
-	// Restore position from database if exists
-	if dbPosition != nil {
-		position.InPosition = true
-		position.Quantity = dbPosition.Quantity
-		position.EntryPrice = dbPosition.EntryPrice
-		position.LastUpdate = dbPosition.EntryTime
-		currentPosID = dbPosition.ID
-		log.Printf("📍 Restored open position from database: %.0f @ %.8f", position.Quantity, position.EntryPrice)
-	}
-
-	// Initialize Safety Manager (Phase 7.5)
-	safetyMgr, err := safety.NewSafetyManager(client, config.Safety)
-	if err != nil {
-		log.Printf("⚠️  Failed to initialize safety manager: %v", err)
-		safetyMgr = nil
-	}
-
-	return &Bot{
-		config:            config,
-		strategy:          strat,
-		position:          position,
-		client:            client,
-		db:                db,
-		currentPositionID: currentPosID,
-		safety:            safetyMgr,
-	}
+	return strat
 }
 
 func (b *Bot) Start(ctx context.Context) error {
 	// Check if bot was initialized properly
-	if b.client == nil {
+	if b.exchange == nil {
 		return fmt.Errorf("bot not properly initialized: missing API credentials")
 	}
+	b.runCtx = ctx
 
 	// Safely log API key (first 8 chars only if long enough)
 	if len(b.config.APIKey) >= 16 {
@@ -232,53 +375,102 @@ func (b *Bot) Start(ctx context.Context) error {
 		log.Printf("🔑 API Key loaded: %s...", b.config.APIKey[:min(8, len(b.config.APIKey))])
 	}
 
-	// Try multiple WebSocket endpoints
-	wsURLs := []string{
-		fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@kline_1m", strings.ToLower(b.config.Symbol)),
-		fmt.Sprintf("wss://stream.binance.com/ws/%s@kline_1m", strings.ToLower(b.config.Symbol)),
-		fmt.Sprintf("wss://data-stream.binance.vision/ws/%s@kline_1m", strings.ToLower(b.config.Symbol)),
+	// Load trading rules once so orders are rounded without an exchangeInfo call each
+	if err := b.filters.Load(ctx, b.symbols...); err != nil {
+		log.Printf("⚠️  Failed to preload symbol filters, loading on first order: %v", err)
 	}
 
+	// Import fills the database missed and flag positions the account no longer holds
+	b.reconcile(ctx)
+
+	// Follow fills, cancellations and balance changes, including those made outside the bot
+	b.streamUserData(ctx)
+
+	// Pre-feed strategies with recent history so they are ready immediately
+	b.warmUp(ctx)
+
+	// Save strategy state periodically so a restart resumes where the bot stopped
+	go b.runStateSaver(ctx)
+
+	// One combined stream carries the klines of every configured symbol
+	streams := make([]string, len(b.symbols))
+	for i, symbol := range b.symbols {
+		streams[i] = fmt.Sprintf("%s@kline_%s", strings.ToLower(symbol), b.config.KlineInterval())
+	}
+	streamPath := "/stream?streams=" + strings.Join(streams, "/")
+	log.Printf("📡 Streaming %s klines for %d symbol(s): %s", b.config.KlineInterval(), len(b.symbols), strings.Join(b.symbols, ", "))
+
+	// Connect to the healthiest endpoint, backing off while connections keep failing
+	recovery := safety.NewRecoveryManager(b.config.Stream.RecoveryConfig())
+	attempt := 0
+	var disconnectedAt time.Time // Zero until the first connection drops
+
 	for {
-		select {
-		case <-ctx.Done():
+		if ctx.Err() != nil {
 			return nil
-		default:
-			log.Println("Attempting to connect to Binance WebSocket...")
-
-			var lastErr error
-			for _, wsURL := range wsURLs {
-				if err := b.connectAndRun(ctx, wsURL); err != nil {
-					log.Printf("Failed to connect to %s: %v", wsURL, err)
-					lastErr = err
-					continue
-				}
+		}
+
+		endpoint := b.endpoints.best()
+		dialStart := time.Now()
+		conn, err := b.dialStream(endpoint + streamPath)
+		if err != nil {
+			log.Printf("❌ Failed to connect to %s: %v", endpoint, err)
+			b.endpoints.recordFailure(endpoint, err)
+		} else {
+			b.endpoints.recordConnect(endpoint, time.Since(dialStart))
+			if !disconnectedAt.IsZero() {
+				downtime := time.Since(disconnectedAt)
+				log.Printf("🔌 Reconnected to %s after %s", endpoint, downtime.Round(time.Millisecond))
+				b.emit("bot:reconnected", fmt.Sprintf("Reconnected to %s after %s", endpoint, downtime.Round(time.Millisecond)), map[string]interface{}{
+					"url":             endpoint,
+					"downtime":        downtime.Round(time.Millisecond).String(),
+					"downtimeSeconds": downtime.Seconds(),
+				})
+				disconnectedAt = time.Time{}
+
+				// Feed the candles that closed while disconnected before resuming live processing
+				b.backfill(ctx)
 			}
 
-			if lastErr != nil {
-				log.Printf("All connection attempts failed. Last error: %v", lastErr)
-				log.Println("Retrying in 5 seconds...")
+			connectedAt := time.Now()
+			err = b.runStream(ctx, conn, endpoint)
+			if ctx.Err() != nil {
+				return nil
+			}
+			uptime := time.Since(connectedAt)
 
-				select {
-				case <-ctx.Done():
-					return nil
-				case <-time.After(5 * time.Second):
-					continue
-				}
+			// A connection that held counts as healthy even if the server closed it (Binance
+			// drops every stream after 24h); one that dropped early or went stale does not
+			if uptime >= streamStableAfter && !errors.Is(err, errStaleStream) {
+				b.endpoints.recordStable(endpoint)
+				attempt = 0
+			} else {
+				b.endpoints.recordFailure(endpoint, err)
 			}
+
+			disconnectedAt = time.Now()
+			log.Printf("⚠️  Disconnected from %s after %s: %v", endpoint, uptime.Round(time.Second), err)
+			b.emit("bot:disconnected", fmt.Sprintf("Disconnected from %s: %v", endpoint, err), map[string]interface{}{
+				"url":    endpoint,
+				"reason": fmt.Sprint(err),
+				"stale":  errors.Is(err, errStaleStream),
+				"uptime": uptime.Round(time.Second).String(),
+			})
 		}
-	}
-}
 
-func (b *Bot) connectAndRun(ctx context.Context, wsURL string) error {
-	// Check if context is already cancelled before connecting
-	select {
-	case <-ctx.Done():
-		log.Println("Context cancelled before connecting")
-		return nil
-	default:
+		delay := recovery.Delay(attempt)
+		attempt++
+		log.Printf("🔄 Reconnecting in %s (attempt %d)", delay, attempt)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
 	}
+}
 
+// dialStream opens the kline WebSocket
+func (b *Bot) dialStream(wsURL string) (*websocket.Conn, error) {
 	// Create dialer with timeout and proper headers
 	dialer := websocket.Dialer{
 		HandshakeTimeout: 45 * time.Second,
@@ -298,8 +490,13 @@ func (b *Bot) connectAndRun(ctx context.Context, wsURL string) error {
 			log.Printf("❌ HTTP Response Status: %s", resp.Status)
 			log.Printf("❌ Response Headers: %v", resp.Header)
 		}
-		return fmt.Errorf("websocket dial failed: %w", err)
+		return nil, fmt.Errorf("websocket dial failed: %w", err)
 	}
+	return conn, nil
+}
+
+// runStream reads klines from an open connection until it fails, goes stale or ctx is cancelled
+func (b *Bot) runStream(ctx context.Context, conn *websocket.Conn, endpoint string) error {
 	defer conn.Close()
 
 	// Set connection timeouts
@@ -314,15 +511,27 @@ func (b *Bot) connectAndRun(ctx context.Context, wsURL string) error {
 	b.conn = conn
 	b.connMu.Unlock()
 
-	log.Printf("✅ Connected to %s", wsURL)
-	b.emit("bot:connected", fmt.Sprintf("Connected to %s", wsURL), map[string]interface{}{
-		"url": wsURL,
+	log.Printf("✅ Connected to %s", endpoint)
+	b.emit("bot:connected", fmt.Sprintf("Connected to %s", endpoint), map[string]interface{}{
+		"url": endpoint,
 	})
 
 	// Start ping routine to keep connection alive
 	pingTicker := time.NewTicker(30 * time.Second)
 	defer pingTicker.Stop()
 
+	// Pings keep the socket open even when no klines flow, so watch for silence separately
+	var lastMessage atomic.Int64
+	var stale atomic.Bool
+	lastMessage.Store(time.Now().UnixNano())
+	staleAfter := time.Duration(b.config.Stream.StaleIntervals) * intervalDuration(b.config.KlineInterval())
+	var staleCheck <-chan time.Time // Never fires when stale detection is off
+	if staleAfter > 0 {
+		staleTicker := time.NewTicker(staleCheckInterval(staleAfter))
+		defer staleTicker.Stop()
+		staleCheck = staleTicker.C
+	}
+
 	// Channel to signal goroutine completion
 	done := make(chan struct{})
 	defer close(done)
@@ -340,15 +549,25 @@ func (b *Bot) connectAndRun(ctx context.Context, wsURL string) error {
 					log.Printf("Ping failed: %v", err)
 					return
 				}
+			case <-staleCheck:
+				if silent := time.Since(time.Unix(0, lastMessage.Load())); silent > staleAfter {
+					log.Printf("🥶 No kline from %s for %s, reconnecting", endpoint, silent.Round(time.Second))
+					stale.Store(true)
+					conn.Close() // Unblocks ReadMessage
+					return
+				}
 			}
 		}
 	}()
 
 	// Start goroutine to close connection when context is cancelled
 	go func() {
-		<-ctx.Done()
-		log.Println("Context cancelled, closing WebSocket connection")
-		conn.Close() // This will cause ReadMessage() to return immediately
+		select {
+		case <-ctx.Done():
+			log.Println("Context cancelled, closing WebSocket connection")
+			conn.Close() // This will cause ReadMessage() to return immediately
+		case <-done:
+		}
 	}()
 
 	for {
@@ -360,12 +579,16 @@ func (b *Bot) connectAndRun(ctx context.Context, wsURL string) error {
 				log.Println("WebSocket closed due to context cancellation")
 				return nil
 			default:
+				if stale.Load() {
+					return fmt.Errorf("%w: no kline for %s", errStaleStream, staleAfter)
+				}
 				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					return fmt.Errorf("websocket unexpected close: %w", err)
 				}
 				return fmt.Errorf("websocket read error: %w", err)
 			}
 		}
+		lastMessage.Store(time.Now().UnixNano())
 
 		// Check if context is cancelled before processing message
 		select {
@@ -382,17 +605,52 @@ func (b *Bot) connectAndRun(ctx context.Context, wsURL string) error {
 	}
 }
 
+// staleCheckInterval is how often a stream is checked for silence: a third of the
+// stale threshold, between one second and the 30s ping period
+func staleCheckInterval(staleAfter time.Duration) time.Duration {
+	interval := staleAfter / 3
+	if interval < time.Second {
+		return time.Second
+	}
+	if interval > 30*time.Second {
+		return 30 * time.Second
+	}
+	return interval
+}
+
+// EndpointHealth returns the connection record of each market data endpoint, in config order
+func (b *Bot) EndpointHealth() []EndpointHealth {
+	if b.endpoints == nil {
+		return nil
+	}
+	return b.endpoints.snapshot()
+}
+
 func (b *Bot) handleMessage(message []byte) error {
-	var event models.KlineEvent
-	if err := json.Unmarshal(message, &event); err != nil {
+	// Combined streams wrap each event as {"stream": ..., "data": {...}}
+	var envelope models.CombinedStreamEvent
+	if err := json.Unmarshal(message, &envelope); err != nil {
 		return fmt.Errorf("failed to unmarshal kline event: %w", err)
 	}
+	event := envelope.Data
+	if envelope.Stream == "" {
+		if err := json.Unmarshal(message, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal kline event: %w", err)
+		}
+	}
 
 	// Only process closed candles
 	if !event.Kline.IsClosed {
 		return nil
 	}
 
+	m, ok := b.markets[strings.ToUpper(event.Kline.Symbol)]
+	if !ok {
+		return fmt.Errorf("received kline for unconfigured symbol %q", event.Kline.Symbol)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	closePrice, err := strconv.ParseFloat(event.Kline.Close, 64)
 	if err != nil {
 		return fmt.Errorf("failed to parse close price: %w", err)
@@ -401,37 +659,105 @@ func (b *Bot) handleMessage(message []byte) error {
 	volume, _ := strconv.ParseFloat(event.Kline.Volume, 64)
 	timestamp := time.Unix(event.Kline.OpenTime/1000, 0)
 
-	// Update strategy with new price data (this handles both single-indicator and multi-timeframe strategies)
-	if err := b.strategy.Update(closePrice, volume, timestamp); err != nil {
+	// Already fed during warm-up
+	if !timestamp.After(m.lastCandle) {
+		return nil
+	}
+
+	// Candles missed since the last one (dropped connection, skipped message) go in first
+	if !m.lastCandle.IsZero() && timestamp.After(nextCandleOpen(b.config.KlineInterval(), m.lastCandle)) {
+		log.Printf("🕳️  %s: Gap in klines from %s to %s, backfilling", m.symbol, m.lastCandle.Format(time.RFC3339), timestamp.Format(time.RFC3339))
+		b.fillGap(context.Background(), m, timestamp)
+	}
+	m.lastCandle = timestamp
+	open, _ := strconv.ParseFloat(event.Kline.Open, 64)
+	high, _ := strconv.ParseFloat(event.Kline.High, 64)
+	low, _ := strconv.ParseFloat(event.Kline.Low, 64)
+	m.updateVolatility(high, low, closePrice)
+
+	// Keep the candle for warm starts, backtests and charts
+	if b.candles != nil {
+		b.candles.Add(database.Candle{
+			Symbol:   m.symbol,
+			Interval: b.config.KlineInterval(),
+			OpenTime: timestamp,
+			Open:     open,
+			High:     high,
+			Low:      low,
+			Close:    closePrice,
+			Volume:   volume,
+		})
+	}
+
+	// Keep the simulator's price current so synthetic books and resting orders track the market
+	if b.paper != nil {
+		b.paper.UpdatePrice(m.symbol, closePrice)
+	}
+
+	// Update strategy with the whole bar (this handles both single-indicator and multi-timeframe strategies)
+	takerBuyVolume, _ := strconv.ParseFloat(event.Kline.TakerBuyVolume, 64)
+	candle := strategy.OHLCV{
+		Timestamp:      timestamp,
+		Open:           open,
+		High:           high,
+		Low:            low,
+		Close:          closePrice,
+		Volume:         volume,
+		Trades:         event.Kline.Trades,
+		TakerBuyVolume: takerBuyVolume,
+	}
+	if err := strategy.UpdateWithCandle(m.strategy, candle); err != nil {
 		return fmt.Errorf("failed to update strategy: %w", err)
 	}
 
-	log.Printf("📊 Candle closed: %s = %.8f", b.config.Symbol, closePrice)
-	b.emit("bot:candle", fmt.Sprintf("Candle closed: %s = %.8f", b.config.Symbol, closePrice), map[string]interface{}{
-		"symbol": b.config.Symbol,
+	log.Printf("📊 Candle closed: %s = %.8f", m.symbol, closePrice)
+	b.emit("bot:candle", fmt.Sprintf("Candle closed: %s = %.8f", m.symbol, closePrice), map[string]interface{}{
+		"symbol": m.symbol,
 		"price":  closePrice,
 	})
 
+	// A resting limit order decides the position; no exits or new signals until it completes
+	if m.pending != nil {
+		log.Printf("⏳ %s: %s order %d pending", m.symbol, m.pending.Side, m.pending.OrderID)
+		return nil
+	}
+
+	// Risk exits are enforced on every closed candle, even before the strategy is ready
+	if exit, reason := b.checkExits(m, closePrice); exit {
+		log.Printf("🛡️  %s: %s", m.symbol, reason)
+		values := make(map[string]float64)
+		if indicator := m.strategy.GetIndicator(); indicator != nil {
+			if v, ok := indicator.GetValue(); ok {
+				values = v
+			}
+		}
+		b.executeSignal(m, strategy.CloseSignal(m.position.Side), strategy.SignalDetail{}, reason, values, closePrice)
+		return nil
+	}
+
 	// Check if strategy is ready (uses Strategy.IsReady() which handles multi-timeframe properly)
-	if !b.strategy.IsReady() {
+	if !m.strategy.IsReady() {
 		// For single indicator strategies, show data count
-		indicator := b.strategy.GetIndicator()
+		indicator := m.strategy.GetIndicator()
 		if indicator != nil {
-			log.Printf("⏳ Waiting for indicator to initialize (current data: %d)", indicator.GetDataCount())
-			b.emit("bot:status", fmt.Sprintf("Waiting for indicator (%d data points)", indicator.GetDataCount()), map[string]interface{}{
+			log.Printf("⏳ %s: Waiting for indicator to initialize (current data: %d)", m.symbol, indicator.GetDataCount())
+			b.emit("bot:status", fmt.Sprintf("%s: Waiting for indicator (%d data points)", m.symbol, indicator.GetDataCount()), map[string]interface{}{
+				"symbol":     m.symbol,
 				"dataPoints": indicator.GetDataCount(),
 			})
 		} else {
 			// Multi-timeframe or non-indicator strategy (like DCA)
-			log.Printf("⏳ Waiting for strategy to initialize")
-			b.emit("bot:status", "Waiting for strategy to initialize", map[string]interface{}{})
+			log.Printf("⏳ %s: Waiting for strategy to initialize", m.symbol)
+			b.emit("bot:status", fmt.Sprintf("%s: Waiting for strategy to initialize", m.symbol), map[string]interface{}{
+				"symbol": m.symbol,
+			})
 		}
 		return nil
 	}
 
 	// Get indicator values (if strategy uses indicators)
 	var values map[string]float64
-	indicator := b.strategy.GetIndicator()
+	indicator := m.strategy.GetIndicator()
 	if indicator != nil {
 		// Strategy uses indicators (RSI, MACD, Multi-timeframe)
 		var isValid bool
@@ -442,303 +768,569 @@ func (b *Bot) handleMessage(message []byte) error {
 		}
 
 		// Log indicator values
-		log.Printf("📈 %s: %v", b.strategy.Name(), values)
-		b.emit("bot:indicator", fmt.Sprintf("%s: %v", b.strategy.Name(), values), map[string]interface{}{
-			"strategy": b.strategy.Name(),
+		log.Printf("📈 %s %s: %v", m.symbol, m.strategy.Name(), values)
+		b.emit("bot:indicator", fmt.Sprintf("%s %s: %v", m.symbol, m.strategy.Name(), values), map[string]interface{}{
+			"symbol":   m.symbol,
+			"strategy": m.strategy.Name(),
 			"values":   values,
 		})
 	} else {
 		// Strategy doesn't use indicators (DCA)
 		values = make(map[string]float64)
-		log.Printf("📈 %s strategy (no indicators)", b.strategy.Name())
+		log.Printf("📈 %s strategy (no indicators)", m.strategy.Name())
 	}
 
 	// Generate trading signal using strategy
-	b.processSignal(values, closePrice)
+	b.processSignal(m, values, closePrice)
 
 	return nil
 }
 
-func (b *Bot) processSignal(indicatorValues map[string]float64, currentPrice float64) {
-	now := time.Now()
-
+func (b *Bot) processSignal(m *market, indicatorValues map[string]float64, currentPrice float64) {
 	// Create signal context
 	ctx := strategy.SignalContext{
 		CurrentPrice:  currentPrice,
-		Position:      b.position,
+		Position:      m.position,
 		IndicatorData: indicatorValues,
 	}
 
 	// Generate signal from strategy
-	signal := b.strategy.GenerateSignal(ctx)
-	reason := b.strategy.GetSignalReason()
-
-	// Process signal
-	switch signal {
-	case strategy.SignalBuy:
-		log.Printf("🟢 BUY SIGNAL: %s", reason)
-		log.Printf("   💵 Quantity: %.0f @ %.8f", b.config.Quantity, currentPrice)
-		b.emit("bot:trade", fmt.Sprintf("BUY Signal: %s", reason), map[string]interface{}{
-			"side":     "BUY",
-			"price":    currentPrice,
-			"quantity": b.config.Quantity,
-			"reason":   reason,
-		})
+	signal := m.strategy.GenerateSignal(ctx)
+	reason := m.strategy.GetSignalReason()
+	detail := strategy.DetailOf(m.strategy, signal)
+
+	// Save right away so a restart cannot repeat the signal (e.g. a scheduled DCA buy)
+	if signal != strategy.SignalNone {
+		b.saveState(m)
+	}
 
-		var binanceOrderID string
+	b.executeSignal(m, signal, detail, reason, indicatorValues, currentPrice)
+}
+
+// executeSignal acts on a signal: entries open or add to a position, exits close all or
+// part of it. Signals that do not fit the open position (e.g. a SELL while short) are ignored.
+// Risk exits call it directly with a forced close.
+func (b *Bot) executeSignal(m *market, signal strategy.Signal, detail strategy.SignalDetail, reason string, indicatorValues map[string]float64, currentPrice float64) {
+	switch {
+	case signal.IsEntry():
+		if m.position.InPosition && !fits(m.position, signal) {
+			log.Printf("⚠️  %s %s signal ignored: a %s position is open", m.symbol, signal, m.position.Side)
+			return
+		}
+		if signal.Side() == models.PositionShort && !b.shortsAllowed() {
+			log.Printf("⚠️  %s %s signal ignored: short selling is disabled (signals.allow_short)", m.symbol, signal)
+			return
+		}
+		if detail.Strength < b.config.Signals.MinStrength {
+			log.Printf("⌛ %s %s signal skipped: strength %.2f below %.2f: %s", m.symbol, signal, detail.Strength, b.config.Signals.MinStrength, reason)
+			return
+		}
+		b.openPosition(m, signal, detail, reason, indicatorValues, currentPrice)
+
+	case signal.IsExit():
+		if !m.position.InPosition || !fits(m.position, signal) {
+			log.Printf("⚠️  %s %s signal ignored: no %s position is open", m.symbol, signal, signal.Side())
+			return
+		}
+		b.closePosition(m, signal, detail.Size, reason, indicatorValues, currentPrice)
+
+	default:
+		// No signal - just log status
+		log.Printf("⌛ %s", reason)
+	}
+}
+
+// fits reports whether a signal applies to the side of a position
+func fits(position *models.Position, signal strategy.Signal) bool {
+	return position.IsShort() == (signal.Side() == models.PositionShort)
+}
+
+// shortsAllowed reports whether short signals are acted on: they must be enabled,
+// and an exchange receiving the orders must be able to short
+func (b *Bot) shortsAllowed() bool {
+	if !b.config.Signals.AllowShort {
+		return false
+	}
+	if !b.placesOrders() {
+		return true
+	}
+	seller, ok := b.exchange.(exchange.ShortSeller)
+	return ok && seller.CanShort()
+}
+
+// orderSide returns the side of the order executing a signal: longs are opened
+// by buying and closed by selling, shorts the other way round
+func orderSide(signal strategy.Signal) exchange.OrderSide {
+	if signal == strategy.SignalBuy || signal == strategy.SignalCloseShort {
+		return exchange.SideBuy
+	}
+	return exchange.SideSell
+}
+
+// openPosition places the order for an entry signal and records the trade and position
+func (b *Bot) openPosition(m *market, signal strategy.Signal, detail strategy.SignalDetail, reason string, indicatorValues map[string]float64, currentPrice float64) {
+	log.Printf("🟢 %s %s SIGNAL: %s", m.symbol, signal, reason)
+	quantity, err := b.orderQuantity(context.Background(), m, currentPrice, detail.Size)
+	if err != nil {
+		log.Printf("   ❌ Cannot size %s order: %v", signal, err)
+		return
+	}
+	if m.position.InPosition {
+		log.Printf("   ➕ Adding to the %s position: %.8f @ %.8f", m.position.Side, m.position.Quantity, m.position.EntryPrice)
+	}
+	log.Printf("   💵 Quantity: %.8f @ %.8f (%s, strength %.2f)", quantity, currentPrice, b.config.SizingMode(), detail.Strength)
+	b.emit("bot:trade", fmt.Sprintf("%s %s Signal: %s", m.symbol, signal, reason), map[string]interface{}{
+		"symbol":   m.symbol,
+		"side":     string(orderSide(signal)),
+		"signal":   signal.String(),
+		"price":    currentPrice,
+		"quantity": quantity,
+		"strength": detail.Strength,
+		"reason":   reason,
+	})
+
+	side := signal.Side()
+	b.submit(m, signal, quantity, currentPrice, !m.position.InPosition, func(order *exchange.Order) {
+		b.recordOpen(m, side, order, detail.Strength, reason, indicatorValues)
+	})
+}
+
+// closePosition places the order for an exit signal closing fraction of the position
+// (0 = all of it) and records the trade and position
+func (b *Bot) closePosition(m *market, signal strategy.Signal, fraction float64, reason string, indicatorValues map[string]float64, currentPrice float64) {
+	quantity := b.closeQuantity(m, fraction, currentPrice)
+	profitPercent := m.position.ProfitPercent(currentPrice)
+	profitLoss := m.position.EntryPrice * quantity * profitPercent / 100
+	log.Printf("🔴 %s %s SIGNAL: %s", m.symbol, signal, reason)
+	log.Printf("   📍 Position: %s %.8f @ %.8f", m.position.Side, m.position.Quantity, m.position.EntryPrice)
+	if quantity < m.position.Quantity {
+		log.Printf("   ✂️  Closing %.8f of it", quantity)
+	}
+	log.Printf("   💰 Current: %.8f (%.2f%% profit, $%.2f)", currentPrice, profitPercent, profitLoss)
+	b.emit("bot:trade", fmt.Sprintf("%s %s Signal: %s", m.symbol, signal, reason), map[string]interface{}{
+		"symbol":        m.symbol,
+		"side":          string(orderSide(signal)),
+		"signal":        signal.String(),
+		"price":         currentPrice,
+		"quantity":      quantity,
+		"reason":        reason,
+		"profitLoss":    profitLoss,
+		"profitPercent": profitPercent,
+	})
+
+	b.submit(m, signal, quantity, currentPrice, false, func(order *exchange.Order) {
+		b.recordClose(m, order, reason, indicatorValues)
+	})
+}
+
+// closeQuantity returns how much of the position an exit closes: fraction of it rounded
+// down to the lot step, or all of it when fraction is 0 or at least 1, or when either
+// part would be left as unsellable dust
+func (b *Bot) closeQuantity(m *market, fraction, price float64) float64 {
+	total := m.position.Quantity
+	if fraction <= 0 || fraction >= 1 {
+		return total
+	}
+
+	filters, err := b.symbolFilters(context.Background(), m.symbol)
+	if err != nil {
+		return total
+	}
+	part := filters.RoundQuantity(total * fraction)
+	if !b.sellable(m.symbol, part, price) || !b.sellable(m.symbol, total-part, price) {
+		log.Printf("   ⚠️  Closing %.0f%% of the position would leave dust, closing all of it", fraction*100)
+		return total
+	}
+	return part
+}
+
+// submit executes the order for a signal and passes the completed order to record.
+// Without an exchange the order is assumed to fill at the signal price; a resting limit
+// order is tracked in the background and recorded once it completes. opening is true
+// when the order opens a new position (not when it adds to one).
+func (b *Bot) submit(m *market, signal strategy.Signal, quantity, currentPrice float64, opening bool, record func(*exchange.Order)) {
+	cfg := b.orderConfig(m, signal)
+	side := orderSide(signal)
+
+	order := &exchange.Order{
+		Symbol:           m.symbol,
+		Side:             side,
+		Status:           exchange.OrderStatusFilled,
+		ExecutedQuantity: quantity,
+		QuoteQuantity:    quantity * currentPrice,
+	}
+	if b.placesOrders() {
 		if b.config.TradingEnabled {
-			log.Println("   🚨 EXECUTING BUY ORDER")
-			orderID, err := b.executeBuyOrder(currentPrice)
-			if err != nil {
-				log.Printf("   ❌ BUY ORDER FAILED: %v", err)
-				return
-			}
-			binanceOrderID = orderID
-			log.Println("   ✅ Order executed")
+			log.Printf("   🚨 EXECUTING %s ORDER", side)
 		} else {
-			log.Println("   📝 PAPER TRADE: Trading disabled")
+			log.Printf("   📝 PAPER TRADE: Simulating %s order", side)
+		}
+		placed, err := b.executeOrder(m, side, quantity, currentPrice, cfg, opening)
+		if err != nil {
+			log.Printf("   ❌ %s ORDER FAILED: %v", side, err)
+			if signal.IsExit() && exchange.IsFilterError(err, exchange.FilterMinNotional) {
+				log.Printf("   ⚠️  %s position is below the exchange's minimum order value and cannot be closed at this price", m.symbol)
+			}
+			return
+		}
+		order = placed
+		if order.IsOpen() {
+			b.trackOrder(b.runContext(), m, order, cfg, record)
+			return
+		}
+	} else {
+		log.Println("   📝 PAPER TRADE: Trading disabled")
+	}
+
+	record(order)
+}
+
+// recordOpen opens the position, or adds to it, from a completed entry order and logs both
+// to the database. A long holds what was bought at the quote spent; a short owes what was
+// sold at the quote received.
+func (b *Bot) recordOpen(m *market, side models.PositionSide, order *exchange.Order, strength float64, reason string, indicatorValues map[string]float64) {
+	now := executionTime(order)
+	opening := !m.position.InPosition
+	if order.ExecutedQuantity == 0 {
+		log.Printf("   ⚠️  %s %s order %d %s without fills", m.symbol, order.Side, order.OrderID, order.Status)
+		if opening && b.safety != nil && b.placesOrders() {
+			b.safety.ClosePosition()
 		}
+		return
+	}
+
+	fillPrice, fillQty, total := order.AveragePrice(), order.ExecutedQuantity, order.QuoteQuantity
+	var positionQty, entryPrice float64
+	if side == models.PositionShort {
+		// Commission is taken from the quote received
+		positionQty = fillQty
+		entryPrice = (total - b.quoteCommission(m.symbol, order)) / positionQty
+		log.Printf("   ✅ Order executed: %.8f @ %.8f (owing %.8f)", fillQty, fillPrice, positionQty)
+	} else {
+		// Commission is taken from the asset received, so we hold less than we bought
+		positionQty = fillQty - b.baseCommission(m.symbol, order)
+		entryPrice = total / positionQty
+		log.Printf("   ✅ Order executed: %.8f @ %.8f (holding %.8f)", fillQty, fillPrice, positionQty)
+	}
+	if fee, asset := b.otherCommission(m.symbol, order); fee > 0 {
+		log.Printf("   ⚠️  %.8f %s commission is not included in the entry price", fee, asset)
+	}
+
+	// Log trade to database
+	trade := b.tradeRecord(m, order, reason, indicatorValues)
+	trade.PositionSide = string(side)
+	trade.SignalStrength = strength
+
+	tradeID, err := b.db.InsertTrade(trade)
+	if err != nil {
+		log.Printf("   ⚠️  Failed to log trade to database: %v", err)
+	} else {
+		log.Printf("   💾 Trade logged (ID: %d)", tradeID)
+	}
+
+	if !opening {
+		b.addToPosition(m, positionQty, entryPrice, now)
+		return
+	}
 
-		// Log trade to database
-		trade := &database.Trade{
-			Symbol:          b.config.Symbol,
-			Side:            "BUY",
-			Quantity:        b.config.Quantity,
-			Price:           currentPrice,
-			Total:           b.config.Quantity * currentPrice,
-			Strategy:        b.strategy.Name(),
-			IndicatorValues: database.SerializeIndicatorValues(indicatorValues),
-			SignalReason:    reason,
-			PaperTrade:      !b.config.TradingEnabled,
-			Timestamp:       now,
-			BinanceOrderID:  binanceOrderID,
+	// Risk exits are based on the real entry price
+	exits := b.newExits(side, entryPrice, m.volatility())
+
+	if err == nil {
+		// Create new position in database
+		dbPos := &database.Position{
+			Symbol:      m.symbol,
+			Side:        string(side),
+			Quantity:    positionQty,
+			EntryPrice:  entryPrice,
+			EntryTime:   now,
+			Strategy:    m.strategy.Name(),
+			IsOpen:      true,
+			BuyTradeID:  tradeID,
+		}
+		if exits != nil {
+			dbPos.StopLossPrice = exits.stop.StopLossPrice
+			dbPos.TakeProfitPrice = exits.takeProfit
+			dbPos.HighestPrice = exits.stop.HighestPrice
 		}
 
-		tradeID, err := b.db.InsertTrade(trade)
+		posID, err := b.db.InsertPosition(dbPos)
 		if err != nil {
-			log.Printf("   ⚠️  Failed to log trade to database: %v", err)
+			log.Printf("   ⚠️  Failed to log position to database: %v", err)
 		} else {
-			log.Printf("   💾 Trade logged (ID: %d)", tradeID)
-
-			// Create new position in database
-			dbPos := &database.Position{
-				Symbol:      b.config.Symbol,
-				Quantity:    b.config.Quantity,
-				EntryPrice:  currentPrice,
-				EntryTime:   now,
-				Strategy:    b.strategy.Name(),
-				IsOpen:      true,
-				BuyTradeID:  tradeID,
-			}
+			m.currentPositionID = posID
+			log.Printf("   💾 Position logged (ID: %d)", posID)
+		}
+	}
 
-			posID, err := b.db.InsertPosition(dbPos)
-			if err != nil {
-				log.Printf("   ⚠️  Failed to log position to database: %v", err)
-			} else {
-				b.currentPositionID = posID
-				log.Printf("   💾 Position logged (ID: %d)", posID)
-			}
+	// Update in-memory position
+	m.position.InPosition = true
+	m.position.Side = side
+	m.position.Quantity = positionQty
+	m.position.EntryPrice = entryPrice
+	m.position.LastUpdate = now
+	m.realizedPnL, m.closedQuantity = 0, 0
+	m.exits = exits
+	if exits != nil {
+		log.Printf("   🛡️  Stop-loss %.8f, take-profit %.8f", exits.stop.StopLossPrice, exits.takeProfit)
+	}
+}
+
+// addToPosition averages a scale-in into the open position and moves its exits to the new entry price
+func (b *Bot) addToPosition(m *market, quantity, entryPrice float64, now time.Time) {
+	total := m.position.Quantity + quantity
+	m.position.EntryPrice = (m.position.EntryPrice*m.position.Quantity + entryPrice*quantity) / total
+	m.position.Quantity = total
+	m.position.LastUpdate = now
+	log.Printf("   ➕ Position now %.8f @ %.8f", total, m.position.EntryPrice)
+
+	if m.currentPositionID > 0 {
+		if err := b.db.UpdatePositionEntry(m.currentPositionID, total, m.position.EntryPrice); err != nil {
+			log.Printf("   ⚠️  Failed to update position in database: %v", err)
 		}
+	}
 
-		// Update in-memory position
-		b.position.InPosition = true
-		b.position.Quantity = b.config.Quantity
-		b.position.EntryPrice = currentPrice
-		b.position.LastUpdate = now
-
-	case strategy.SignalSell:
-		profitLoss := (currentPrice - b.position.EntryPrice) * b.position.Quantity
-		profitPercent := ((currentPrice - b.position.EntryPrice) / b.position.EntryPrice) * 100
-		log.Printf("🔴 SELL SIGNAL: %s", reason)
-		log.Printf("   📍 Position: %.0f @ %.8f", b.position.Quantity, b.position.EntryPrice)
-		log.Printf("   💰 Current: %.8f (%.2f%% profit, $%.2f)", currentPrice, profitPercent, profitLoss)
-		b.emit("bot:trade", fmt.Sprintf("SELL Signal: %s", reason), map[string]interface{}{
-			"side":          "SELL",
-			"price":         currentPrice,
-			"quantity":      b.position.Quantity,
-			"reason":        reason,
-			"profitLoss":    profitLoss,
-			"profitPercent": profitPercent,
-		})
+	m.exits = b.newExits(m.position.Side, m.position.EntryPrice, m.volatility())
+	if m.exits != nil {
+		b.persistExits(m)
+		log.Printf("   🛡️  Stop-loss %.8f, take-profit %.8f", m.exits.stop.StopLossPrice, m.exits.takeProfit)
+	}
+}
 
-		var binanceOrderID string
-		if b.config.TradingEnabled {
-			log.Println("   🚨 EXECUTING SELL ORDER")
-			orderID, err := b.executeSellOrder(currentPrice)
+// recordClose closes (or, after a partial close or fill, reduces) the position from a
+// completed exit order and logs both to the database
+func (b *Bot) recordClose(m *market, order *exchange.Order, reason string, indicatorValues map[string]float64) {
+	now := executionTime(order)
+	if order.ExecutedQuantity == 0 {
+		log.Printf("   ⚠️  %s %s order %d %s without fills, position stays open", m.symbol, order.Side, order.OrderID, order.Status)
+		return
+	}
+
+	fillPrice, fillQty, total := order.AveragePrice(), order.ExecutedQuantity, order.QuoteQuantity
+	// P/L on what was actually received or paid, net of fees on both legs
+	var closedQty, profitLoss float64
+	if m.position.IsShort() {
+		// Buying back is charged in the asset received, so a little less of the debt is repaid
+		closedQty = fillQty - b.baseCommission(m.symbol, order)
+		profitLoss = m.position.EntryPrice*closedQty - total
+	} else {
+		closedQty = fillQty
+		profitLoss = total - b.quoteCommission(m.symbol, order) - m.position.EntryPrice*fillQty
+	}
+	cost := m.position.EntryPrice * closedQty
+	profitPercent := (profitLoss / cost) * 100
+	log.Printf("   ✅ Order executed: %.8f @ %.8f (P/L $%.2f)", fillQty, fillPrice, profitLoss)
+	if fee, asset := b.otherCommission(m.symbol, order); fee > 0 {
+		log.Printf("   ⚠️  %.8f %s commission is not included in P/L", fee, asset)
+	}
+
+	// A partial close or fill leaves the rest of the position open, unless the rest is unsellable dust
+	remaining := m.position.Quantity - closedQty
+	partial := remaining > 0 && b.sellable(m.symbol, remaining, fillPrice)
+
+	if b.safety != nil && b.placesOrders() {
+		b.safety.RecordTrade(profitLoss, profitLoss > 0)
+		if !partial {
+			b.safety.ClosePosition()
+		}
+	}
+
+	// Log trade to database
+	trade := b.tradeRecord(m, order, reason, indicatorValues)
+	trade.ProfitLoss = profitLoss
+	trade.ProfitLossPercent = profitPercent
+	trade.PositionSide = string(m.position.Side)
+
+	tradeID, err := b.db.InsertTrade(trade)
+	if err != nil {
+		log.Printf("   ⚠️  Failed to log trade to database: %v", err)
+	} else {
+		log.Printf("   💾 Trade logged (ID: %d)", tradeID)
+
+		// Update position in database; its P/L includes what earlier partial closes realized
+		if m.currentPositionID > 0 && !partial {
+			positionPnL := m.realizedPnL + profitLoss
+			err := b.db.UpdatePosition(
+				m.currentPositionID,
+				fillPrice,
+				now,
+				positionPnL,
+				positionPnL/(m.position.EntryPrice*(m.closedQuantity+closedQty))*100,
+				tradeID,
+			)
 			if err != nil {
-				log.Printf("   ❌ SELL ORDER FAILED: %v", err)
-				return
-			}
-			binanceOrderID = orderID
-			log.Println("   ✅ Order executed")
-		} else {
-			log.Println("   📝 PAPER TRADE: Trading disabled")
-		}
-
-		// Log trade to database
-		trade := &database.Trade{
-			Symbol:            b.config.Symbol,
-			Side:              "SELL",
-			Quantity:          b.position.Quantity,
-			Price:             currentPrice,
-			Total:             b.position.Quantity * currentPrice,
-			Strategy:          b.strategy.Name(),
-			IndicatorValues:   database.SerializeIndicatorValues(indicatorValues),
-			SignalReason:      reason,
-			PaperTrade:        !b.config.TradingEnabled,
-			Timestamp:         now,
-			BinanceOrderID:    binanceOrderID,
-			ProfitLoss:        profitLoss,
-			ProfitLossPercent: profitPercent,
-		}
-
-		tradeID, err := b.db.InsertTrade(trade)
-		if err != nil {
-			log.Printf("   ⚠️  Failed to log trade to database: %v", err)
-		} else {
-			log.Printf("   💾 Trade logged (ID: %d)", tradeID)
-
-			// Update position in database
-			if b.currentPositionID > 0 {
-				err := b.db.UpdatePosition(
-					b.currentPositionID,
-					currentPrice,
-					now,
-					profitLoss,
-					profitPercent,
-					tradeID,
-				)
-				if err != nil {
-					log.Printf("   ⚠️  Failed to update position in database: %v", err)
-				} else {
-					log.Printf("   💾 Position closed (ID: %d)", b.currentPositionID)
-				}
+				log.Printf("   ⚠️  Failed to update position in database: %v", err)
+			} else {
+				log.Printf("   💾 Position closed (ID: %d)", m.currentPositionID)
 			}
 		}
+	}
 
-		// Update in-memory position
-		b.position.InPosition = false
-		b.position.Quantity = 0
-		b.position.EntryPrice = 0
-		b.position.LastUpdate = now
-		b.currentPositionID = 0
-
-	default:
-		// No signal - just log status
-		log.Printf("⌛ %s", reason)
+	if partial {
+		log.Printf("   ✂️  %.8f of the position remains open", remaining)
+		m.realizedPnL += profitLoss
+		m.closedQuantity += closedQty
+		if m.currentPositionID > 0 {
+			if err := b.db.ReducePosition(m.currentPositionID, remaining, m.realizedPnL, m.closedQuantity); err != nil {
+				log.Printf("   ⚠️  Failed to update position in database: %v", err)
+			}
+		}
+		m.position.Quantity = remaining
+		m.position.LastUpdate = now
+		return
 	}
+
+	// Update in-memory position
+	m.position.InPosition = false
+	m.position.Side = ""
+	m.position.Quantity = 0
+	m.position.EntryPrice = 0
+	m.position.LastUpdate = now
+	m.currentPositionID = 0
+	m.realizedPnL, m.closedQuantity = 0, 0
+	m.exits = nil
 }
 
 // TODO: buy and sell orders below need to be tested rigoursly
-func (b *Bot) executeBuyOrder(price float64) (string, error) {
-	log.Printf("🚀 Executing BUY order: %.0f @ %.8f", b.config.Quantity, price)
+
+// executeOrder places an order for a market through the safety checks. opening counts a
+// new position towards the safety manager's limit; profit/loss is reported to it by
+// recordClose once a closing order has filled.
+func (b *Bot) executeOrder(m *market, side exchange.OrderSide, quantity, price float64, cfg models.OrderConfig, opening bool) (*exchange.Order, error) {
+	// Fees leave a position off the lot step; the remainder stays as dust
+	req, err := b.orderRequest(m.symbol, side, quantity, price, cfg)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🚀 Executing %s %s order: %.8f @ %.8f", side, req.Type, req.Quantity, orderPrice(req, price))
 
 	// Safety checks (Phase 7.5)
 	if b.safety != nil {
 		// Check if trade is allowed
 		if err := b.safety.CheckTradeAllowed(
 			context.Background(),
-			b.config.Symbol,
-			b.config.Quantity,
-			price,
-			"BUY",
+			m.symbol,
+			req.Quantity,
+			orderPrice(req, price),
+			string(side),
+			req.Type,
 		); err != nil {
 			log.Printf("🛑 Trade blocked by safety checks: %v", err)
-			return "", fmt.Errorf("safety check failed: %w", err)
+			return nil, fmt.Errorf("safety check failed: %w", err)
 		}
 	}
 
 	// Execute with safety wrapper
-	var orderID string
+	var placed *exchange.Order
 	executeOrder := func() error {
-		// Note: TimeOffset set on client during initialization handles timestamp sync
-		order, err := b.client.NewCreateOrderService().
-			Symbol(b.config.Symbol).
-			Side(binance.SideTypeBuy).
-			Type(binance.OrderTypeMarket).
-			Quantity(fmt.Sprintf("%.8f", b.config.Quantity)).
-			Do(context.Background())
+		order, err := b.exchange.PlaceOrder(context.Background(), req)
 
 		if err != nil {
-			return fmt.Errorf("buy order failed: %w", err)
+			return fmt.Errorf("%s order failed: %w", side, err)
 		}
 
-		orderID = fmt.Sprintf("%d", order.OrderID)
-		log.Printf("✅ BUY order executed: OrderID=%s", orderID)
+		placed = order
+		log.Printf("✅ %s order placed: OrderID=%d (%s)", side, order.OrderID, order.Status)
 		return nil
 	}
 
 	// Execute with safety manager if available
-	var err error
 	if b.safety != nil {
 		err = b.safety.ExecuteWithSafety(executeOrder)
-		if err == nil {
+		if err == nil && opening {
 			b.safety.OpenPosition()
 		}
 	} else {
 		err = executeOrder()
 	}
 
-	return orderID, err
+	return placed, err
+}
+
+// baseCommission returns the commission an order paid in the symbol's base asset
+func (b *Bot) baseCommission(symbol string, order *exchange.Order) float64 {
+	filters, err := b.symbolFilters(context.Background(), symbol)
+	if err != nil {
+		return 0
+	}
+	return order.CommissionIn(filters.BaseAsset)
 }
 
-func (b *Bot) executeSellOrder(price float64) (string, error) {
-	log.Printf("💥 Executing SELL order: %.0f @ %.8f", b.position.Quantity, price)
+// quoteCommission returns the commission an order paid in the symbol's quote asset
+func (b *Bot) quoteCommission(symbol string, order *exchange.Order) float64 {
+	filters, err := b.symbolFilters(context.Background(), symbol)
+	if err != nil {
+		return 0
+	}
+	return order.CommissionIn(filters.QuoteAsset)
+}
 
-	// Safety checks (Phase 7.5)
-	if b.safety != nil {
-		// Check if trade is allowed
-		if err := b.safety.CheckTradeAllowed(
-			context.Background(),
-			b.config.Symbol,
-			b.position.Quantity,
-			price,
-			"SELL",
-		); err != nil {
-			log.Printf("🛑 Trade blocked by safety checks: %v", err)
-			return "", fmt.Errorf("safety check failed: %w", err)
+// otherCommission returns the commission an order paid in neither of the symbol's assets
+// (e.g. BNB), with that asset. There is no price for it here, so P/L leaves it out.
+func (b *Bot) otherCommission(symbol string, order *exchange.Order) (float64, string) {
+	filters, err := b.symbolFilters(context.Background(), symbol)
+	if err != nil {
+		return 0, ""
+	}
+	for _, f := range order.Fills {
+		if f.Commission > 0 && f.CommissionAsset != filters.BaseAsset && f.CommissionAsset != filters.QuoteAsset {
+			return order.CommissionIn(f.CommissionAsset), f.CommissionAsset
 		}
 	}
+	return 0, ""
+}
 
-	// Execute with safety wrapper
-	var orderID string
-	executeOrder := func() error {
-		// Note: TimeOffset set on client during initialization handles timestamp sync
-		order, err := b.client.NewCreateOrderService().
-			Symbol(b.config.Symbol).
-			Side(binance.SideTypeSell).
-			Type(binance.OrderTypeMarket).
-			Quantity(fmt.Sprintf("%.8f", b.position.Quantity)).
-			Do(context.Background())
+// tradeRecord builds the database record of an executed order, at its volume-weighted fill price
+func (b *Bot) tradeRecord(m *market, order *exchange.Order, reason string, indicatorValues map[string]float64) *database.Trade {
+	var binanceOrderID string
+	if order.OrderID != 0 {
+		binanceOrderID = fmt.Sprintf("%d", order.OrderID)
+	}
 
-		if err != nil {
-			return fmt.Errorf("sell order failed: %w", err)
-		}
+	trade := &database.Trade{
+		Symbol:          m.symbol,
+		Side:            string(order.Side),
+		Quantity:        order.ExecutedQuantity,
+		Price:           order.AveragePrice(),
+		Total:           order.QuoteQuantity,
+		Strategy:        m.strategy.Name(),
+		IndicatorValues: database.SerializeIndicatorValues(indicatorValues),
+		SignalReason:    reason,
+		PaperTrade:      !b.config.TradingEnabled,
+		Timestamp:       executionTime(order),
+		BinanceOrderID:  binanceOrderID,
+	}
+	trade.Commission, trade.CommissionAsset, trade.Fills = tradeFills(order)
+	return trade
+}
 
-		orderID = fmt.Sprintf("%d", order.OrderID)
-		log.Printf("✅ SELL order executed: OrderID=%s", orderID)
-		return nil
+// executionTime returns when the exchange executed an order, or now for simulated fills
+func executionTime(order *exchange.Order) time.Time {
+	if order.TransactTime.IsZero() {
+		return time.Now()
 	}
+	return order.TransactTime
+}
 
-	// Execute with safety manager if available
-	var err error
-	if b.safety != nil {
-		err = b.safety.ExecuteWithSafety(executeOrder)
-		if err == nil {
-			// Calculate profit/loss
-			entryValue := b.position.Quantity * b.position.EntryPrice
-			exitValue := b.position.Quantity * price
-			profitLoss := exitValue - entryValue
-			isProfit := profitLoss > 0
-
-			b.safety.RecordTrade(profitLoss, isProfit)
-			b.safety.ClosePosition()
-		}
-	} else {
-		err = executeOrder()
+// tradeFills converts an order's fills for the trade record, with the total commission.
+// Binance charges every fill of an order in the same asset, so that of the first fill is used.
+func tradeFills(order *exchange.Order) (commission float64, commissionAsset string, fills []database.TradeFill) {
+	if len(order.Fills) == 0 {
+		return 0, "", nil
 	}
 
-	return orderID, err
+	commissionAsset = order.Fills[0].CommissionAsset
+	fills = make([]database.TradeFill, 0, len(order.Fills))
+	for _, f := range order.Fills {
+		fills = append(fills, database.TradeFill{
+			ExchangeTradeID: f.TradeID,
+			Price:           f.Price,
+			Quantity:        f.Quantity,
+			Commission:      f.Commission,
+			CommissionAsset: f.CommissionAsset,
+		})
+	}
+	return order.CommissionIn(commissionAsset), commissionAsset, fills
 }
 
 // GetRecentTrades returns the most recent trades from the database
@@ -765,12 +1357,36 @@ func (b *Bot) GetTradeSummary() (*database.TradeSummary, error) {
 	return b.db.GetTradeSummary()
 }
 
-// GetOpenPosition returns the current open position from database
+// GetOpenPosition returns the current open position of the primary symbol from database
 func (b *Bot) GetOpenPosition() (*database.Position, error) {
-	if b.db == nil {
+	if b.db == nil || len(b.symbols) == 0 {
 		return nil, nil
 	}
-	return b.db.GetOpenPosition(b.config.Symbol)
+	return b.db.GetOpenPosition(b.symbols[0])
+}
+
+// GetOpenPositions returns the open position of every configured symbol that has one
+func (b *Bot) GetOpenPositions() ([]database.Position, error) {
+	if b.db == nil {
+		return []database.Position{}, nil
+	}
+
+	positions := []database.Position{}
+	for _, symbol := range b.symbols {
+		pos, err := b.db.GetOpenPosition(symbol)
+		if err != nil {
+			return nil, err
+		}
+		if pos != nil {
+			positions = append(positions, *pos)
+		}
+	}
+	return positions, nil
+}
+
+// GetSymbols returns the symbols the bot trades
+func (b *Bot) GetSymbols() []string {
+	return b.symbols
 }
 
 // GetDB returns the database instance for direct access (used by demo data generation)
@@ -778,9 +1394,22 @@ func (b *Bot) GetDB() *database.DB {
 	return b.db
 }
 
-// GetClient returns the Binance client for API calls
+// GetExchange returns the exchange the bot trades through
+func (b *Bot) GetExchange() exchange.Exchange {
+	return b.exchange
+}
+
+// GetPaperExchange returns the paper-trading simulator, or nil when trading live
+func (b *Bot) GetPaperExchange() *exchange.PaperExchange {
+	return b.paper
+}
+
+// GetClient returns the Binance client for API calls (nil when not trading on Binance)
 func (b *Bot) GetClient() *binance.Client {
-	return b.client
+	if be, ok := b.exchange.(*exchange.BinanceExchange); ok {
+		return be.Client()
+	}
+	return nil
 }
 
 // Stop gracefully stops the bot by closing WebSocket connection
@@ -802,9 +1431,19 @@ func (b *Bot) Stop() error {
 	return nil
 }
 
-// CloseDatabase closes the database connection (call on shutdown)
+// CloseDatabase stores the buffered candles and strategy state and closes the
+// database connection (call on shutdown, once the context passed to Start has ended)
 func (b *Bot) CloseDatabase() error {
+	// Resting orders are cancelled when the bot stops; record their fills first
+	b.tracking.Wait()
+	if b.candles != nil {
+		if err := b.candles.Close(); err != nil {
+			log.Printf("⚠️  Failed to store buffered candles: %v", err)
+		}
+	}
 	if b.db != nil {
+		b.saveStates()
+		log.Println("💾 Strategy state saved")
 		return b.db.Close()
 	}
 	return nil
@@ -834,7 +1473,7 @@ func (b *Bot) emit(eventType string, message string, data map[string]interface{}
 //    - Handles configuration (symbol, RSI period, thresholds)
 
 // 2. Key Functionality:
-//    - Connects to Binance WebSocket for 1-minute kline/candlestick data
+//    - Connects to one Binance combined WebSocket stream for the configured symbols and kline interval
 //    - Calculates RSI in real-time as new candles close
 //    - Generates BUY signals when RSI falls below oversold level
 //    - Generates SELL signals when RSI rises above overbought level
@@ -854,7 +1493,8 @@ func (b *Bot) emit(eventType string, message string, data map[string]interface{}
 //    - Thread-safe position management
 
 // Configuration Options:
-//    - Symbol: Trading pair (e.g., "BTCUSDT")
+//    - Symbol / Symbols: Trading pair(s) (e.g., "BTCUSDT"); each symbol gets its own strategy and position
+//    - Interval: Kline interval (default "1m")
 //    - RSIPeriod: Number of periods for RSI calculation
 //    - OverboughtLevel: RSI threshold for sell signals
 //    - OversoldLevel: RSI threshold for buy signals
@@ -866,12 +1506,16 @@ func (b *Bot) emit(eventType string, message string, data map[string]interface{}
 // 2. Initialize bot with New(config)
 // 3. Start bot with Start(ctx)
 
-// Note: The bot currently logs trade actions rather than executing them when TradingEnabled is false.
+// Note: When TradingEnabled is false, orders go to an exchange.PaperExchange that simulates
+// fills, fees and balances against the live order book.
 
 // GetMultiTimeframeManager returns the multi-timeframe manager if using that strategy
 func (b *Bot) GetMultiTimeframeManager() *strategy.MultiTimeframeManager {
-	// Check if the current strategy is a multi-timeframe strategy
-	if mts, ok := b.strategy.(*strategy.MultiTimeframeStrategy); ok {
+	// Check if the primary symbol's strategy is a multi-timeframe strategy
+	if len(b.symbols) == 0 {
+		return nil
+	}
+	if mts, ok := b.markets[b.symbols[0]].strategy.(*strategy.MultiTimeframeStrategy); ok {
 		return mts.GetMultiTimeframeManager()
 	}
 	return nil
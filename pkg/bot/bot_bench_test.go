@@ -2,11 +2,20 @@ package bot
 
 import (
 	"encoding/json"
+	"rsi-bot/pkg/exchange"
 	"rsi-bot/pkg/models"
 	"testing"
 	"time"
 )
 
+// newFakeExchange returns an in-memory exchange priced for the benchmark symbol
+func newFakeExchange() *exchange.FakeExchange {
+	fake := exchange.NewFakeExchange()
+	fake.SetPrice("BTCUSDT", 45000.0)
+	fake.SetBalance("USDT", 100000.0)
+	return fake
+}
+
 // BenchmarkBot_HandleMessage benchmarks the message handling logic
 func BenchmarkBot_HandleMessage(b *testing.B) {
 	config := &models.Config{
@@ -20,10 +29,7 @@ func BenchmarkBot_HandleMessage(b *testing.B) {
 		APISecret:       "test_secret",
 	}
 
-	bot := New(config)
-	if bot.client == nil {
-		b.Skip("Bot not initialized - missing credentials")
-	}
+	bot := newBot(b, config, newFakeExchange())
 
 	// Create a realistic kline event message
 	klineEvent := models.KlineEvent{
@@ -47,6 +53,7 @@ func BenchmarkBot_HandleMessage(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
+		bot.markets["BTCUSDT"].lastCandle = time.Time{} // Replay the same candle every iteration
 		bot.handleMessage(message)
 	}
 }
@@ -64,10 +71,7 @@ func BenchmarkBot_ProcessSignal(b *testing.B) {
 		APISecret:       "test_secret",
 	}
 
-	bot := New(config)
-	if bot.client == nil {
-		b.Skip("Bot not initialized - missing credentials")
-	}
+	bot := newBot(b, config, newFakeExchange())
 
 	indicatorValues := map[string]float64{
 		"rsi": 50.0,
@@ -76,7 +80,7 @@ func BenchmarkBot_ProcessSignal(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		bot.processSignal(indicatorValues, currentPrice)
+		bot.processSignal(bot.markets["BTCUSDT"], indicatorValues, currentPrice)
 	}
 }
 
@@ -142,7 +146,7 @@ func BenchmarkBot_New(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		New(config)
+		newBot(b, config, newFakeExchange())
 	}
 }
 
@@ -159,10 +163,7 @@ func BenchmarkBot_EventEmission(b *testing.B) {
 		APISecret:       "test_secret",
 	}
 
-	bot := New(config)
-	if bot.client == nil {
-		b.Skip("Bot not initialized - missing credentials")
-	}
+	bot := newBot(b, config, newFakeExchange())
 
 	// Set up a no-op callback
 	callbackCount := 0
@@ -195,7 +196,7 @@ func BenchmarkBot_MemoryAllocation(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		bot := New(config)
+		bot := newBot(b, config, newFakeExchange())
 		_ = bot
 	}
 }
@@ -213,10 +214,7 @@ func BenchmarkBot_FullCycle(b *testing.B) {
 		APISecret:       "test_secret",
 	}
 
-	bot := New(config)
-	if bot.client == nil {
-		b.Skip("Bot not initialized - missing credentials")
-	}
+	bot := newBot(b, config, newFakeExchange())
 
 	basePrice := 45000.0
 	timestamp := time.Now()
@@ -259,10 +257,7 @@ func BenchmarkBot_DatabaseOperations(b *testing.B) {
 		APISecret:       "test_secret",
 	}
 
-	bot := New(config)
-	if bot.client == nil {
-		b.Skip("Bot not initialized - missing credentials")
-	}
+	bot := newBot(b, config, newFakeExchange())
 	defer bot.CloseDatabase()
 
 	b.ResetTimer()
@@ -0,0 +1,344 @@
+package bot
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/indicators"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+// newTestDB opens a database in a temporary directory, closed when the test ends
+func newTestDB(tb testing.TB) *database.DB {
+	tb.Helper()
+	db, err := database.New(filepath.Join(tb.TempDir(), "trading_bot.db"))
+	if err != nil {
+		tb.Fatalf("failed to open test database: %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	return db
+}
+
+// stubStrategy returns a fixed signal (with an optional detail) until it is changed
+type stubStrategy struct {
+	signal strategy.Signal
+	detail strategy.SignalDetail
+}
+
+func (s *stubStrategy) Name() string                                            { return "stub" }
+func (s *stubStrategy) GetIndicator() indicators.Indicator                      { return nil }
+func (s *stubStrategy) Update(price, volume float64, timestamp time.Time) error { return nil }
+func (s *stubStrategy) IsReady() bool                                           { return true }
+func (s *stubStrategy) GenerateSignal(ctx strategy.SignalContext) strategy.Signal {
+	return s.signal
+}
+func (s *stubStrategy) GetSignalReason() string             { return "stub " + s.signal.String() }
+func (s *stubStrategy) Reset()                              {}
+func (s *stubStrategy) SignalDetail() strategy.SignalDetail { return s.detail }
+
+// newBot creates a bot over ex with a temporary database
+func newBot(tb testing.TB, config *models.Config, ex exchange.Exchange) *Bot {
+	tb.Helper()
+	b, err := NewWithExchange(config, ex, newTestDB(tb))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return b
+}
+
+// newTestBot creates a live-trading bot for BTCUSDT over ex, with a stub strategy
+func newTestBot(t *testing.T, config *models.Config, ex exchange.Exchange) (*Bot, *market, *stubStrategy) {
+	t.Helper()
+	if config.Symbol == "" {
+		config.Symbol = "BTCUSDT"
+	}
+	if config.RSIPeriod == 0 {
+		config.RSIPeriod = 14
+	}
+
+	b := newBot(t, config, ex)
+	stub := &stubStrategy{}
+	m := b.markets[config.Symbol]
+	m.strategy = stub
+	return b, m, stub
+}
+
+func TestNewWithExchange_NeedsDatabase(t *testing.T) {
+	if _, err := NewWithExchange(&models.Config{Symbol: "BTCUSDT", RSIPeriod: 14}, newFakeExchange(), nil); err == nil {
+		t.Error("NewWithExchange without a database succeeded, want an error")
+	}
+}
+
+func TestProcessSignal_PlacesOrders(t *testing.T) {
+	fake := newFakeExchange()
+	b, m, stub := newTestBot(t, &models.Config{Quantity: 0.002, TradingEnabled: true}, fake)
+
+	stub.signal = strategy.SignalBuy
+	b.processSignal(m, map[string]float64{"rsi": 25}, 45000)
+
+	orders := fake.Orders()
+	if len(orders) != 1 {
+		t.Fatalf("got %d orders after BUY, want 1", len(orders))
+	}
+	if orders[0].Side != exchange.SideBuy || orders[0].Type != exchange.OrderTypeMarket || orders[0].OrigQuantity != 0.002 {
+		t.Errorf("BUY order = %s %s %.8f, want BUY MARKET 0.002", orders[0].Side, orders[0].Type, orders[0].OrigQuantity)
+	}
+	if !m.position.InPosition || m.position.Quantity != 0.002 || m.position.EntryPrice != 45000 {
+		t.Errorf("position = %+v, want 0.002 @ 45000", *m.position)
+	}
+	stored, err := b.db.GetOpenPosition("BTCUSDT")
+	if err != nil || stored == nil || stored.Quantity != 0.002 {
+		t.Fatalf("stored position = %+v (err %v), want an open 0.002 position", stored, err)
+	}
+
+	// A second BUY adds to the position at the new price
+	fake.SetPrice("BTCUSDT", 46000)
+	b.processSignal(m, nil, 46000)
+	if m.position.Quantity != 0.004 || m.position.EntryPrice != 45500 {
+		t.Errorf("position after scale-in = %.8f @ %.8f, want 0.004 @ 45500", m.position.Quantity, m.position.EntryPrice)
+	}
+
+	stub.signal = strategy.SignalSell
+	fake.SetPrice("BTCUSDT", 47000)
+	b.processSignal(m, nil, 47000)
+
+	orders = fake.Orders()
+	if len(orders) != 3 || orders[2].Side != exchange.SideSell || orders[2].OrigQuantity != 0.004 {
+		t.Fatalf("orders = %+v, want a SELL of 0.004 last", orders)
+	}
+	if m.position.InPosition {
+		t.Errorf("position still open after SELL: %+v", *m.position)
+	}
+
+	trades, err := b.db.GetRecentTrades(1)
+	if err != nil || len(trades) != 1 {
+		t.Fatalf("GetRecentTrades = %v, %v", trades, err)
+	}
+	if got, want := trades[0].ProfitLoss, 0.004*(47000-45500); !almostEqual(got, want) {
+		t.Errorf("SELL profit = %.8f, want %.8f", got, want)
+	}
+
+	// With no position, a SELL is ignored
+	b.processSignal(m, nil, 47000)
+	if len(fake.Orders()) != 3 {
+		t.Errorf("SELL without a position placed an order")
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff < 1e-6
+}
+
+// newShortBot creates a bot trading BTCUSDT 1 at a time on a paper exchange that may
+// short, with 1000 USDT, no fees, a 0.0001 BTC lot step and a 5 USDT minimum order value
+func newShortBot(t *testing.T, config *models.Config) (*Bot, *market, *stubStrategy, *exchange.PaperExchange) {
+	t.Helper()
+	fake := exchange.NewFakeExchange()
+	fake.SetSymbolFilters(&exchange.SymbolFilters{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", StepSize: 0.0001, MinNotional: 5})
+	paper := exchange.NewPaperExchange(fake, exchange.PaperConfig{InitialBalances: map[string]float64{"USDT": 1000}, AllowShort: true})
+
+	if config.Quantity == 0 {
+		config.Quantity = 1
+	}
+	config.Signals.AllowShort = true
+	b, m, stub := newTestBot(t, config, paper)
+	setPaperPrice(paper, 100)
+	return b, m, stub, paper
+}
+
+// setPaperPrice replays a deep book at price so market orders fill exactly there
+func setPaperPrice(paper *exchange.PaperExchange, price float64) {
+	paper.UpdatePrice("BTCUSDT", price)
+	paper.SetOrderBook("BTCUSDT", &exchange.OrderBook{
+		Bids: []exchange.PriceLevel{{Price: price, Quantity: 100}},
+		Asks: []exchange.PriceLevel{{Price: price, Quantity: 100}},
+	})
+}
+
+// sendSignal has the stub strategy give signal with an exit fraction at price
+func sendSignal(b *Bot, m *market, stub *stubStrategy, signal strategy.Signal, size, price float64) {
+	stub.signal, stub.detail = signal, strategy.SignalDetail{Size: size}
+	b.processSignal(m, nil, price)
+	stub.signal, stub.detail = strategy.SignalNone, strategy.SignalDetail{}
+}
+
+func checkPaperBalance(t *testing.T, paper *exchange.PaperExchange, asset string, want float64) {
+	t.Helper()
+	balances, err := paper.GetBalances(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := 0.0
+	for _, balance := range balances {
+		if balance.Asset == asset {
+			got = balance.Free + balance.Locked
+		}
+	}
+	if !almostEqual(got, want) {
+		t.Errorf("%s balance = %.8f, want %.8f", asset, got, want)
+	}
+}
+
+func TestExecuteSignal_ShortThenCover(t *testing.T) {
+	b, m, stub, paper := newShortBot(t, &models.Config{})
+
+	sendSignal(b, m, stub, strategy.SignalOpenShort, 0, 100)
+	if !m.position.InPosition || !m.position.IsShort() || m.position.Quantity != 1 || m.position.EntryPrice != 100 {
+		t.Fatalf("position = %+v, want short 1 @ 100", *m.position)
+	}
+	checkPaperBalance(t, paper, "BTC", -1)
+	checkPaperBalance(t, paper, "USDT", 1100)
+	stored, err := b.db.GetOpenPosition("BTCUSDT")
+	if err != nil || stored == nil || stored.PositionSide() != string(models.PositionShort) || stored.Quantity != 1 {
+		t.Fatalf("stored position = %+v (err %v), want an open short of 1", stored, err)
+	}
+
+	// A long exit does not apply to a short
+	sendSignal(b, m, stub, strategy.SignalSell, 0, 100)
+	if !m.position.InPosition || m.position.Quantity != 1 {
+		t.Fatalf("SELL changed the short: %+v", *m.position)
+	}
+
+	setPaperPrice(paper, 90)
+	sendSignal(b, m, stub, strategy.SignalCloseShort, 0, 90)
+	if m.position.InPosition || m.position.Side != "" {
+		t.Fatalf("position still open after COVER: %+v", *m.position)
+	}
+	checkPaperBalance(t, paper, "BTC", 0)
+	checkPaperBalance(t, paper, "USDT", 1010)
+
+	summary, err := b.db.GetTradeSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TotalBuys != 1 || summary.TotalSells != 1 || !almostEqual(summary.TotalProfitLoss, 10) {
+		t.Errorf("summary = %d buys, %d sells, P/L %.8f; want 1, 1 and 10", summary.TotalBuys, summary.TotalSells, summary.TotalProfitLoss)
+	}
+	if stored, _ := b.db.GetOpenPosition("BTCUSDT"); stored != nil {
+		t.Errorf("stored position still open: %+v", stored)
+	}
+}
+
+func TestExecuteSignal_PartialCover(t *testing.T) {
+	b, m, stub, paper := newShortBot(t, &models.Config{})
+	sendSignal(b, m, stub, strategy.SignalOpenShort, 0, 100)
+
+	setPaperPrice(paper, 90)
+	sendSignal(b, m, stub, strategy.SignalCloseShort, 0.4, 90)
+	if !m.position.InPosition || !m.position.IsShort() || !almostEqual(m.position.Quantity, 0.6) || m.position.EntryPrice != 100 {
+		t.Fatalf("position after covering 40%% = %+v, want short 0.6 @ 100", *m.position)
+	}
+	if !almostEqual(m.realizedPnL, 4) || !almostEqual(m.closedQuantity, 0.4) {
+		t.Errorf("realized %.8f on %.8f, want 4 on 0.4", m.realizedPnL, m.closedQuantity)
+	}
+	checkPaperBalance(t, paper, "BTC", -0.6)
+	stored, err := b.db.GetOpenPosition("BTCUSDT")
+	if err != nil || stored == nil || !almostEqual(stored.Quantity, 0.6) || !almostEqual(stored.RealizedPnL, 4) || stored.EntryPrice != 100 {
+		t.Fatalf("stored position = %+v (err %v), want 0.6 @ 100 with 4 realized", stored, err)
+	}
+
+	setPaperPrice(paper, 80)
+	sendSignal(b, m, stub, strategy.SignalCloseShort, 0, 80)
+	if m.position.InPosition {
+		t.Fatalf("position still open after the last COVER: %+v", *m.position)
+	}
+	checkPaperBalance(t, paper, "BTC", 0)
+	checkPaperBalance(t, paper, "USDT", 1016)
+	if summary, err := b.db.GetTradeSummary(); err != nil || !almostEqual(summary.TotalProfitLoss, 0.4*10+0.6*20) {
+		t.Errorf("summary P/L = %+v (err %v), want 16", summary, err)
+	}
+}
+
+func TestCloseQuantity_DustClosesAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64 // Short opened at 100
+		size     float64 // Fraction covered at 90
+		want     float64
+	}{
+		{"part above the minimum", 1, 0.4, 0.4},
+		{"part rounded to the lot step", 1, 0.33333, 0.3333},
+		{"part below the minimum", 0.1, 0.4, 0.1}, // 0.04 * 90 = 3.6 USDT
+		{"rest below the minimum", 0.1, 0.6, 0.1}, // 0.04 left
+		{"whole position", 1, 1, 1},
+		{"no size", 1, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, m, stub, paper := newShortBot(t, &models.Config{Quantity: tt.quantity})
+			sendSignal(b, m, stub, strategy.SignalOpenShort, 0, 100)
+			if m.position.Quantity != tt.quantity {
+				t.Fatalf("position = %+v, want short %.4f", *m.position, tt.quantity)
+			}
+
+			if got := b.closeQuantity(m, tt.size, 90); !almostEqual(got, tt.want) {
+				t.Errorf("closeQuantity(%.5f) = %.8f, want %.8f", tt.size, got, tt.want)
+			}
+
+			setPaperPrice(paper, 90)
+			sendSignal(b, m, stub, strategy.SignalCloseShort, tt.size, 90)
+			rest := tt.quantity - tt.want
+			if m.position.InPosition != (rest > 0) || !almostEqual(m.position.Quantity, rest) {
+				t.Errorf("position after the cover = %+v, want %.8f left", *m.position, rest)
+			}
+			checkPaperBalance(t, paper, "BTC", -rest)
+		})
+	}
+}
+
+func TestHandleMessage_CombinedStream(t *testing.T) {
+	b, btc, _ := newTestBot(t, &models.Config{Symbols: []string{"BTCUSDT", "ETHUSDT"}}, newFakeExchange())
+	eth := b.markets["ETHUSDT"]
+	start := time.Now().Truncate(time.Minute).Add(-10 * time.Minute)
+
+	// wrap puts an event in the envelope of a combined stream
+	wrap := func(stream string, event []byte) []byte {
+		return []byte(`{"stream":"` + stream + `","data":` + string(event) + `}`)
+	}
+	unclosed := func(message []byte) []byte {
+		return []byte(strings.Replace(string(message), `"x":true`, `"x":false`, 1))
+	}
+
+	steps := []struct {
+		name    string
+		message []byte
+		wantBTC float64 // Last price of each market after the message
+		wantETH float64
+		wantErr string
+	}{
+		{"bare event", klineMessage("BTCUSDT", start, 45000), 45000, 0, ""},
+		{"combined event", wrap("ethusdt@kline_1m", klineMessage("ETHUSDT", start, 2500)), 45000, 2500, ""},
+		{"routed by the event symbol", wrap("btcusdt@kline_1m", klineMessage("ethusdt", start.Add(time.Minute), 2600)), 45000, 2600, ""},
+		{"open candle ignored", unclosed(wrap("btcusdt@kline_1m", klineMessage("BTCUSDT", start.Add(time.Minute), 46000))), 45000, 2600, ""},
+		{"replayed candle ignored", klineMessage("BTCUSDT", start, 44000), 45000, 2600, ""},
+		{"unconfigured symbol", wrap("solusdt@kline_1m", klineMessage("SOLUSDT", start, 100)), 45000, 2600, `unconfigured symbol "SOLUSDT"`},
+		{"malformed message", []byte(`{"stream":`), 45000, 2600, "failed to unmarshal kline event"},
+	}
+	for _, step := range steps {
+		err := b.handleMessage(step.message)
+		if step.wantErr == "" && err != nil {
+			t.Errorf("%s: %v", step.name, err)
+		}
+		if step.wantErr != "" && (err == nil || !strings.Contains(err.Error(), step.wantErr)) {
+			t.Errorf("%s: error = %v, want %q", step.name, err, step.wantErr)
+		}
+		if btc.lastPrice != step.wantBTC || eth.lastPrice != step.wantETH {
+			t.Errorf("%s: last prices BTC %.2f, ETH %.2f; want %.2f, %.2f", step.name, btc.lastPrice, eth.lastPrice, step.wantBTC, step.wantETH)
+		}
+	}
+
+	if !btc.lastCandle.Equal(start) || !eth.lastCandle.Equal(start.Add(time.Minute)) {
+		t.Errorf("last candles BTC %s, ETH %s", btc.lastCandle, eth.lastCandle)
+	}
+}
@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySmoothing is the weight of the newest handshake in an endpoint's average latency
+const latencySmoothing = 0.3
+
+// EndpointHealth is the connection record of one market data WebSocket endpoint
+type EndpointHealth struct {
+	URL                 string
+	Successes           int           // Connections established
+	Failures            int           // Failed dials and connections dropped before becoming stable
+	ConsecutiveFailures int           // Failures since the last stable connection
+	Latency             time.Duration // Smoothed handshake time (0 until the first connection)
+	LastError           string
+	LastConnected       time.Time
+	LastFailure         time.Time
+}
+
+// endpointPool tracks the health of the configured endpoints and picks the one to dial next
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*EndpointHealth // In config order
+}
+
+// newEndpointPool creates a pool of endpoints with no history
+func newEndpointPool(urls []string) *endpointPool {
+	pool := &endpointPool{}
+	for _, url := range urls {
+		pool.endpoints = append(pool.endpoints, &EndpointHealth{URL: url})
+	}
+	return pool
+}
+
+// best returns the healthiest endpoint: fewest consecutive failures, then lowest
+// latency (untried endpoints last), then config order
+func (p *endpointPool) best() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ranked := append([]*EndpointHealth(nil), p.endpoints...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.ConsecutiveFailures != b.ConsecutiveFailures {
+			return a.ConsecutiveFailures < b.ConsecutiveFailures
+		}
+		if (a.Latency == 0) != (b.Latency == 0) {
+			return a.Latency != 0
+		}
+		return a.Latency < b.Latency
+	})
+	return ranked[0].URL
+}
+
+// recordConnect records an established connection and its handshake latency
+func (p *endpointPool) recordConnect(url string, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e := p.find(url); e != nil {
+		e.Successes++
+		e.LastConnected = time.Now()
+		if e.Latency == 0 {
+			e.Latency = latency
+		} else {
+			e.Latency = time.Duration(latencySmoothing*float64(latency) + (1-latencySmoothing)*float64(e.Latency))
+		}
+	}
+}
+
+// recordStable clears an endpoint's failure streak once a connection has held
+func (p *endpointPool) recordStable(url string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e := p.find(url); e != nil {
+		e.ConsecutiveFailures = 0
+	}
+}
+
+// recordFailure records a failed dial, a stale stream or a connection that dropped early
+func (p *endpointPool) recordFailure(url string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e := p.find(url); e != nil {
+		e.Failures++
+		e.ConsecutiveFailures++
+		e.LastFailure = time.Now()
+		if err != nil {
+			e.LastError = err.Error()
+		}
+	}
+}
+
+// snapshot returns a copy of every endpoint's record, in config order
+func (p *endpointPool) snapshot() []EndpointHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	health := make([]EndpointHealth, len(p.endpoints))
+	for i, e := range p.endpoints {
+		health[i] = *e
+	}
+	return health
+}
+
+func (p *endpointPool) find(url string) *EndpointHealth {
+	for _, e := range p.endpoints {
+		if e.URL == url {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestEndpointPool_Best(t *testing.T) {
+	type event struct {
+		url     string
+		kind    string // "connect", "stable" or "failure"
+		latency time.Duration
+	}
+	tests := []struct {
+		name   string
+		events []event
+		want   string
+	}{
+		{"config order when untried", nil, "a"},
+		{"tried before untried", []event{{"c", "connect", 50 * time.Millisecond}}, "c"},
+		{"lowest latency", []event{{"a", "connect", 90 * time.Millisecond}, {"b", "connect", 30 * time.Millisecond}, {"c", "connect", 60 * time.Millisecond}}, "b"},
+		{"equal latency in config order", []event{{"c", "connect", 30 * time.Millisecond}, {"b", "connect", 30 * time.Millisecond}}, "b"},
+		{"failure outranks latency", []event{{"a", "connect", 90 * time.Millisecond}, {"b", "connect", 30 * time.Millisecond}, {"b", "failure", 0}}, "a"},
+		{"failed dial ranks below untried", []event{{"a", "failure", 0}}, "b"},
+		{"fewest consecutive failures", []event{{"a", "failure", 0}, {"a", "failure", 0}, {"b", "failure", 0}, {"c", "failure", 0}, {"c", "failure", 0}}, "b"},
+		{
+			"stable connection clears the streak",
+			[]event{{"a", "connect", 90 * time.Millisecond}, {"b", "connect", 30 * time.Millisecond}, {"b", "failure", 0}, {"b", "failure", 0}, {"b", "connect", 30 * time.Millisecond}, {"b", "stable", 0}},
+			"b",
+		},
+		{
+			"a connection alone does not clear the streak",
+			[]event{{"a", "connect", 90 * time.Millisecond}, {"b", "connect", 30 * time.Millisecond}, {"b", "failure", 0}, {"b", "connect", 30 * time.Millisecond}},
+			"a",
+		},
+		{"unknown URLs are ignored", []event{{"x", "failure", 0}, {"x", "connect", time.Millisecond}}, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newEndpointPool([]string{"a", "b", "c"})
+			for _, e := range tt.events {
+				switch e.kind {
+				case "connect":
+					pool.recordConnect(e.url, e.latency)
+				case "stable":
+					pool.recordStable(e.url)
+				case "failure":
+					pool.recordFailure(e.url, errors.New("dial failed"))
+				}
+			}
+			if got := pool.best(); got != tt.want {
+				t.Errorf("best = %s, want %s (%+v)", got, tt.want, pool.snapshot())
+			}
+		})
+	}
+}
+
+func TestEndpointPool_Records(t *testing.T) {
+	pool := newEndpointPool([]string{"a", "b"})
+
+	pool.recordConnect("a", 100*time.Millisecond)
+	pool.recordConnect("a", 200*time.Millisecond)
+	pool.recordFailure("a", errors.New("stale stream"))
+	pool.recordFailure("a", nil)
+
+	a := pool.snapshot()[0]
+	// The newest handshake weighs latencySmoothing: 0.3*200 + 0.7*100
+	if a.Successes != 2 || a.Latency != 130*time.Millisecond || a.LastConnected.IsZero() {
+		t.Errorf("after 2 connections: %+v, want 2 successes at 130ms", a)
+	}
+	if a.Failures != 2 || a.ConsecutiveFailures != 2 || a.LastError != "stale stream" || a.LastFailure.IsZero() {
+		t.Errorf("after 2 failures: %+v, want both counted, keeping the last error", a)
+	}
+
+	pool.recordStable("a")
+	a = pool.snapshot()[0]
+	if a.Failures != 2 || a.ConsecutiveFailures != 0 {
+		t.Errorf("after a stable connection: %+v, want the streak cleared and the total kept", a)
+	}
+
+	// The snapshot is a copy
+	health := pool.snapshot()
+	health[1].Failures = 10
+	if pool.snapshot()[1].Failures != 0 {
+		t.Error("changing the snapshot changed the pool")
+	}
+}
+
+func TestStaleCheckInterval(t *testing.T) {
+	tests := []struct {
+		staleAfter, want time.Duration
+	}{
+		{time.Second, time.Second},
+		{6 * time.Second, 2 * time.Second},
+		{3 * time.Minute, time.Minute / 2},
+		{time.Hour, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := staleCheckInterval(tt.staleAfter); got != tt.want {
+			t.Errorf("staleCheckInterval(%s) = %s, want %s", tt.staleAfter, got, tt.want)
+		}
+	}
+}
+
+func TestRunStream_Stale(t *testing.T) {
+	// A server that accepts the connection (from the Binance origin the bot sends) but never sends a kline
+	upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	b, _, _ := newTestBot(t, &models.Config{Interval: "1s", Stream: models.StreamConfig{StaleIntervals: 1}}, exchange.NewFakeExchange())
+	endpoint := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, err := b.dialStream(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	err = b.runStream(context.Background(), conn, endpoint)
+	if !errors.Is(err, errStaleStream) {
+		t.Fatalf("runStream = %v, want a stale stream", err)
+	}
+	// Checked every second for a second of silence
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 5*time.Second {
+		t.Errorf("stale after %s, want about 2s", elapsed)
+	}
+}
@@ -0,0 +1,219 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+// orderPollInterval is how often a resting limit order is checked for fills (a variable for tests)
+var orderPollInterval = 2 * time.Second
+
+// clientOrderPrefix marks the orders the bot places, so the user data stream can tell them from manual ones
+const clientOrderPrefix = "rsibot-"
+
+// newClientOrderID returns a unique client order ID for an order placed by the bot
+func newClientOrderID() string {
+	return fmt.Sprintf("%s%d", clientOrderPrefix, time.Now().UnixNano())
+}
+
+// ownOrder reports whether the bot placed an order, from its client order ID
+func ownOrder(order *exchange.Order) bool {
+	return strings.HasPrefix(order.ClientOrderID, clientOrderPrefix)
+}
+
+// placesOrders reports whether signals go to an exchange (live or paper simulator)
+func (b *Bot) placesOrders() bool {
+	return b.config.TradingEnabled || b.paper != nil
+}
+
+// runContext returns the context the bot was started with (Background before Start)
+func (b *Bot) runContext() context.Context {
+	if b.runCtx == nil {
+		return context.Background()
+	}
+	return b.runCtx
+}
+
+// orderConfig returns how a signal is executed: the strategy's choice if it makes one,
+// otherwise the bot's orders config
+func (b *Bot) orderConfig(m *market, signal strategy.Signal) models.OrderConfig {
+	if requester, ok := m.strategy.(strategy.OrderRequester); ok {
+		if cfg, ok := requester.OrderConfig(signal); ok {
+			return cfg
+		}
+	}
+	return b.config.Orders
+}
+
+// orderRequest builds the order for a signal, rounded to the symbol's filters and
+// rejected here if the exchange would refuse it, before it reaches the circuit breaker.
+// Limit orders are priced at the configured offset from price: below it for buys, above for sells.
+func (b *Bot) orderRequest(symbol string, side exchange.OrderSide, quantity, price float64, cfg models.OrderConfig) (exchange.OrderRequest, error) {
+	filters, err := b.symbolFilters(context.Background(), symbol)
+	if err != nil {
+		return exchange.OrderRequest{}, fmt.Errorf("failed to get %s filters: %w", symbol, err)
+	}
+
+	req := exchange.OrderRequest{
+		Symbol:        symbol,
+		Side:          side,
+		Type:          exchange.OrderTypeMarket,
+		Quantity:      filters.RoundQuantity(quantity),
+		ClientOrderID: newClientOrderID(),
+	}
+
+	if cfg.IsLimit() {
+		offset := cfg.PriceOffsetPercent / 100.0
+		if side == exchange.SideBuy {
+			req.Price = filters.RoundPrice(price * (1 - offset))
+		} else {
+			req.Price = filters.RoundPrice(price * (1 + offset))
+		}
+
+		req.Type = exchange.OrderTypeLimit
+		req.TimeInForce = cfg.TimeInForce
+		if cfg.PostOnly {
+			req.Type = exchange.OrderTypeLimitMaker
+			req.TimeInForce = ""
+		}
+	}
+
+	if err := filters.Validate(req.Quantity, orderPrice(req, price), !cfg.IsLimit()); err != nil {
+		log.Printf("🛑 Order rejected by symbol filters: %v", err)
+		return req, err
+	}
+	return req, nil
+}
+
+// orderPrice returns the limit price of a request, or the expected price of a market order
+func orderPrice(req exchange.OrderRequest, marketPrice float64) float64 {
+	if req.Type == exchange.OrderTypeMarket {
+		return marketPrice
+	}
+	return req.Price
+}
+
+// sellable reports whether quantity can still be sold on its own at price,
+// i.e. it is not dust below the symbol's LOT_SIZE or MIN_NOTIONAL
+func (b *Bot) sellable(symbol string, quantity, price float64) bool {
+	filters, err := b.symbolFilters(context.Background(), symbol)
+	if err != nil {
+		return false
+	}
+	return filters.Validate(filters.RoundQuantity(quantity), price, true) == nil
+}
+
+// trackOrder follows a resting limit order in the background until it completes or ctx
+// ends. The market takes no new signals until then; done is called with the final order
+// under the market lock.
+func (b *Bot) trackOrder(ctx context.Context, m *market, order *exchange.Order, cfg models.OrderConfig, done func(*exchange.Order)) {
+	log.Printf("   ⏳ %s %s order %d resting at %.8f (timeout %s)", m.symbol, order.Side, order.OrderID, order.Price, cfg.TimeoutDuration())
+	b.emit("bot:order", fmt.Sprintf("%s %s limit order resting at %.8f", m.symbol, order.Side, order.Price), map[string]interface{}{
+		"symbol":  m.symbol,
+		"side":    string(order.Side),
+		"orderId": order.OrderID,
+		"price":   order.Price,
+	})
+
+	m.pending = order
+	b.tracking.Add(1)
+	go func() {
+		defer b.tracking.Done()
+		final := b.awaitOrder(ctx, m, order, cfg)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.pending = nil
+		done(final)
+	}()
+}
+
+// awaitOrder polls an order until it is done, its timeout passes or ctx ends. An order
+// still open is then cancelled; after a timeout with on_timeout "market" its unfilled rest
+// is sent as a market order, through the same safety checks as any other order.
+func (b *Bot) awaitOrder(ctx context.Context, m *market, order *exchange.Order, cfg models.OrderConfig) *exchange.Order {
+	symbol := m.symbol
+	deadline := time.Now().Add(cfg.TimeoutDuration())
+
+	current := order
+poll:
+	for current.IsOpen() && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			break poll
+		case <-time.After(orderPollInterval):
+		}
+
+		latest, err := b.exchange.GetOrder(ctx, symbol, order.OrderID)
+		if err != nil {
+			log.Printf("⚠️  %s: Failed to check order %d: %v", symbol, order.OrderID, err)
+			continue
+		}
+		current = keepFills(latest, current)
+	}
+
+	if !current.IsOpen() {
+		log.Printf("📬 %s: Order %d %s (filled %.8f of %.8f)", symbol, order.OrderID, current.Status, current.ExecutedQuantity, current.OrigQuantity)
+		return current
+	}
+
+	// Timed out or stopped: cancel the rest, keeping any partial fill. The cancel must
+	// still reach the exchange when ctx has ended.
+	stopped := ctx.Err() != nil
+	cancelCtx := context.WithoutCancel(ctx)
+	canceled, err := b.exchange.CancelOrder(cancelCtx, symbol, order.OrderID)
+	if err != nil {
+		// It may have filled between the last poll and the cancel
+		log.Printf("⚠️  %s: Failed to cancel order %d: %v", symbol, order.OrderID, err)
+		if latest, getErr := b.exchange.GetOrder(cancelCtx, symbol, order.OrderID); getErr == nil {
+			current = keepFills(latest, current)
+		}
+		return current
+	}
+	current = keepFills(canceled, current)
+	if stopped {
+		log.Printf("⏹️  %s: Order %d cancelled as the bot stopped (filled %.8f of %.8f)", symbol, order.OrderID, current.ExecutedQuantity, current.OrigQuantity)
+		return current
+	}
+	log.Printf("⌛ %s: Order %d cancelled after %s (filled %.8f of %.8f)", symbol, order.OrderID, cfg.TimeoutDuration(), current.ExecutedQuantity, current.OrigQuantity)
+
+	remaining := current.OrigQuantity - current.ExecutedQuantity
+	if cfg.OnTimeout != models.OnTimeoutMarket || remaining <= 0 {
+		return current
+	}
+
+	filled, err := b.executeOrder(m, current.Side, remaining, current.Price, models.OrderConfig{Type: models.OrderTypeMarket}, false)
+	if err != nil {
+		log.Printf("❌ %s: Unfilled %.8f not sent at market: %v", symbol, remaining, err)
+		return current
+	}
+
+	log.Printf("✅ %s: Unfilled rest sent at market: OrderID=%d, %.8f @ %.8f", symbol, filled.OrderID, filled.ExecutedQuantity, filled.AveragePrice())
+	return mergeOrders(current, filled)
+}
+
+// keepFills carries fills over from an earlier view of an order, as Binance order
+// queries (unlike the placement response) do not include them
+func keepFills(latest, previous *exchange.Order) *exchange.Order {
+	if len(latest.Fills) == 0 {
+		latest.Fills = previous.Fills
+	}
+	return latest
+}
+
+// mergeOrders combines a cancelled limit order with the market order that filled its rest
+func mergeOrders(limit, market *exchange.Order) *exchange.Order {
+	merged := *limit
+	merged.Status = market.Status
+	merged.ExecutedQuantity += market.ExecutedQuantity
+	merged.QuoteQuantity += market.QuoteQuantity
+	merged.Fills = append(append([]exchange.Fill(nil), limit.Fills...), market.Fills...)
+	return &merged
+}
@@ -0,0 +1,292 @@
+package bot
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/safety"
+	"rsi-bot/pkg/strategy"
+)
+
+// newLimitOrderBot returns a bot whose BUY signals rest 1 BTC at 99.9 (0.1% below 100)
+// until a fill, a cancel after timeout, or the timeout's market fallback
+func newLimitOrderBot(t *testing.T, orders models.OrderConfig) (*Bot, *market, *exchange.FakeExchange) {
+	t.Helper()
+	poll := orderPollInterval
+	orderPollInterval = time.Millisecond
+	t.Cleanup(func() { orderPollInterval = poll })
+
+	fake := exchange.NewFakeExchange()
+	fake.SetPrice("BTCUSDT", 100)
+	fake.SetSymbolFilters(&exchange.SymbolFilters{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", TickSize: 0.01, StepSize: 0.0001})
+
+	orders.Type = models.OrderTypeLimit
+	orders.PriceOffsetPercent = 0.1
+	b, m, stub := newTestBot(t, &models.Config{Quantity: 1, TradingEnabled: true, Orders: orders}, fake)
+	stub.signal = strategy.SignalBuy
+	return b, m, fake
+}
+
+// waitForOrder waits until the market's resting order has completed and been recorded
+func waitForOrder(t *testing.T, m *market) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		m.mu.Lock()
+		pending := m.pending
+		m.mu.Unlock()
+		if pending == nil {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("resting order never completed")
+}
+
+// submitBuy sends the BUY signal under the market lock, as handleMessage does
+func submitBuy(b *Bot, m *market) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b.processSignal(m, nil, 100)
+}
+
+func TestLimitOrder_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		orders    models.OrderConfig
+		fill      float64 // Quantity the market fills at the limit price while the order rests
+		wantOrder []exchange.OrderStatus
+		wantQty   float64 // Position quantity (0 = no position)
+		wantEntry float64
+		wantFills int
+	}{
+		{
+			name:      "fills while resting",
+			orders:    models.OrderConfig{Timeout: "5s"},
+			fill:      1,
+			wantOrder: []exchange.OrderStatus{exchange.OrderStatusFilled},
+			wantQty:   1,
+			wantEntry: 99.9,
+			wantFills: 1,
+		},
+		{
+			name:      "timeout cancels and keeps the partial fill",
+			orders:    models.OrderConfig{Timeout: "20ms", OnTimeout: models.OnTimeoutCancel},
+			fill:      0.4,
+			wantOrder: []exchange.OrderStatus{exchange.OrderStatusCanceled},
+			wantQty:   0.4,
+			wantEntry: 99.9,
+			wantFills: 1,
+		},
+		{
+			name:      "timeout sends the unfilled rest at market",
+			orders:    models.OrderConfig{Timeout: "20ms", OnTimeout: models.OnTimeoutMarket},
+			fill:      0.4,
+			wantOrder: []exchange.OrderStatus{exchange.OrderStatusCanceled, exchange.OrderStatusFilled},
+			wantQty:   1,
+			wantEntry: 0.4*99.9 + 0.6*100,
+			wantFills: 2,
+		},
+		{
+			name:      "timeout without fills opens nothing",
+			orders:    models.OrderConfig{Timeout: "20ms", OnTimeout: models.OnTimeoutCancel},
+			wantOrder: []exchange.OrderStatus{exchange.OrderStatusCanceled},
+		},
+		{
+			name:      "market fallback of an unfilled order",
+			orders:    models.OrderConfig{Timeout: "20ms", OnTimeout: models.OnTimeoutMarket},
+			wantOrder: []exchange.OrderStatus{exchange.OrderStatusCanceled, exchange.OrderStatusFilled},
+			wantQty:   1,
+			wantEntry: 100,
+			wantFills: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, m, fake := newLimitOrderBot(t, tt.orders)
+
+			// Hold the market lock so the order cannot time out before it is scripted
+			m.mu.Lock()
+			b.processSignal(m, nil, 100)
+			placed := fake.Orders()
+			if len(placed) != 1 || placed[0].Type != exchange.OrderTypeLimit || placed[0].Price != 99.9 || m.pending == nil {
+				m.mu.Unlock()
+				t.Fatalf("placed %+v, want one resting LIMIT BUY at 99.9", placed)
+			}
+			if tt.fill > 0 {
+				if err := fake.FillOrder(placed[0].OrderID, tt.fill, 99.9); err != nil {
+					t.Fatal(err)
+				}
+			}
+			m.mu.Unlock()
+			waitForOrder(t, m)
+
+			orders := fake.Orders()
+			if len(orders) != len(tt.wantOrder) {
+				t.Fatalf("got %d orders, want %d: %+v", len(orders), len(tt.wantOrder), orders)
+			}
+			for i, want := range tt.wantOrder {
+				if orders[i].Status != want {
+					t.Errorf("order %d status = %s, want %s", i+1, orders[i].Status, want)
+				}
+			}
+			if len(orders) == 2 && (orders[1].Type != exchange.OrderTypeMarket || math.Abs(orders[1].OrigQuantity-(1-tt.fill)) > 1e-9) {
+				t.Errorf("fallback order = %s %.8f, want MARKET %.8f", orders[1].Type, orders[1].OrigQuantity, 1-tt.fill)
+			}
+
+			if tt.wantQty == 0 {
+				if m.position.InPosition {
+					t.Errorf("position opened without fills: %+v", *m.position)
+				}
+				if trades, _ := b.db.GetRecentTrades(10); len(trades) != 0 {
+					t.Errorf("recorded %d trades without fills", len(trades))
+				}
+				return
+			}
+
+			if !m.position.InPosition || math.Abs(m.position.Quantity-tt.wantQty) > 1e-9 || math.Abs(m.position.EntryPrice-tt.wantEntry) > 1e-9 {
+				t.Errorf("position = %.8f @ %.8f, want %.8f @ %.8f", m.position.Quantity, m.position.EntryPrice, tt.wantQty, tt.wantEntry)
+			}
+			trades, err := b.db.GetRecentTrades(10)
+			if err != nil || len(trades) != 1 {
+				t.Fatalf("GetRecentTrades = %d trades, %v; want 1", len(trades), err)
+			}
+			fills, err := b.db.GetTradeFills(trades[0].ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(trades[0].Quantity-tt.wantQty) > 1e-9 || len(fills) != tt.wantFills {
+				t.Errorf("trade = %.8f with %d fills, want %.8f with %d", trades[0].Quantity, len(fills), tt.wantQty, tt.wantFills)
+			}
+		})
+	}
+}
+
+func TestLimitOrder_PostOnlyRejected(t *testing.T) {
+	b, m, fake := newLimitOrderBot(t, models.OrderConfig{PostOnly: true})
+
+	// The market moved below the limit price: a LIMIT_MAKER buy at 99.9 would take
+	fake.SetPrice("BTCUSDT", 99)
+	submitBuy(b, m)
+
+	if orders := fake.Orders(); len(orders) != 0 {
+		t.Errorf("rejected order was recorded: %+v", orders)
+	}
+	if m.pending != nil || m.position.InPosition {
+		t.Errorf("rejected order left state behind: pending %v, position %+v", m.pending, *m.position)
+	}
+
+	// Back above the limit it rests as a maker
+	fake.SetPrice("BTCUSDT", 100)
+	submitBuy(b, m)
+	orders := fake.Orders()
+	if len(orders) != 1 || orders[0].Type != exchange.OrderTypeLimitMaker || orders[0].Status != exchange.OrderStatusNew {
+		t.Fatalf("orders = %+v, want one resting LIMIT_MAKER", orders)
+	}
+	fake.FillOrder(orders[0].OrderID, 1, 99.9)
+	waitForOrder(t, m)
+	if !m.position.InPosition || m.position.EntryPrice != 99.9 {
+		t.Errorf("position = %+v, want 1 @ 99.9", *m.position)
+	}
+}
+
+func TestKeepFillsAndMergeOrders(t *testing.T) {
+	fill := exchange.Fill{TradeID: 1, Price: 99.9, Quantity: 0.4}
+	placed := &exchange.Order{OrderID: 1, Status: exchange.OrderStatusPartiallyFilled, OrigQuantity: 1, ExecutedQuantity: 0.4, QuoteQuantity: 39.96, Fills: []exchange.Fill{fill}}
+
+	// Binance order queries return no fills; the earlier ones are kept
+	queried := &exchange.Order{OrderID: 1, Status: exchange.OrderStatusCanceled, OrigQuantity: 1, ExecutedQuantity: 0.4, QuoteQuantity: 39.96}
+	kept := keepFills(queried, placed)
+	if kept.Status != exchange.OrderStatusCanceled || len(kept.Fills) != 1 || kept.Fills[0] != fill {
+		t.Errorf("keepFills = %+v, want the cancelled order with the earlier fill", kept)
+	}
+
+	// Newer fills win over older ones
+	newer := &exchange.Order{OrderID: 1, Fills: []exchange.Fill{fill, {TradeID: 2, Price: 99.9, Quantity: 0.1}}}
+	if got := keepFills(newer, placed); len(got.Fills) != 2 {
+		t.Errorf("keepFills dropped newer fills: %+v", got.Fills)
+	}
+
+	market := &exchange.Order{OrderID: 2, Status: exchange.OrderStatusFilled, OrigQuantity: 0.6, ExecutedQuantity: 0.6, QuoteQuantity: 60,
+		Fills: []exchange.Fill{{TradeID: 3, Price: 100, Quantity: 0.6}}}
+	merged := mergeOrders(kept, market)
+	if merged.OrderID != 1 || merged.Status != exchange.OrderStatusFilled || merged.ExecutedQuantity != 1 ||
+		math.Abs(merged.QuoteQuantity-99.96) > 1e-9 || len(merged.Fills) != 2 {
+		t.Errorf("mergeOrders = %+v", merged)
+	}
+	if len(kept.Fills) != 1 || kept.ExecutedQuantity != 0.4 {
+		t.Errorf("mergeOrders modified the limit order: %+v", kept)
+	}
+}
+
+func TestLimitOrder_StopCancelsWithoutMarketFallback(t *testing.T) {
+	b, m, fake := newLimitOrderBot(t, models.OrderConfig{Timeout: "1h", OnTimeout: models.OnTimeoutMarket})
+	ctx, stop := context.WithCancel(context.Background())
+	b.runCtx = ctx
+
+	m.mu.Lock()
+	b.processSignal(m, nil, 100)
+	placed := fake.Orders()
+	if len(placed) != 1 || m.pending == nil {
+		m.mu.Unlock()
+		t.Fatalf("placed %+v, want one resting order", placed)
+	}
+	fake.FillOrder(placed[0].OrderID, 0.4, 99.9)
+	m.mu.Unlock()
+
+	// Stopping the bot long before the timeout cancels the order; the rest is not sent at market
+	stop()
+	waitForOrder(t, m)
+
+	orders := fake.Orders()
+	if len(orders) != 1 || orders[0].Status != exchange.OrderStatusCanceled {
+		t.Fatalf("orders = %+v, want only the cancelled limit order", orders)
+	}
+	if !m.position.InPosition || math.Abs(m.position.Quantity-0.4) > 1e-9 {
+		t.Errorf("position = %+v, want the 0.4 filled before the stop", *m.position)
+	}
+}
+
+func TestLimitOrder_MarketFallbackSafetyChecked(t *testing.T) {
+	b, m, fake := newLimitOrderBot(t, models.OrderConfig{Timeout: "20ms", OnTimeout: models.OnTimeoutMarket})
+	fake.SetOrderBook("BTCUSDT", &exchange.OrderBook{
+		Bids: []exchange.PriceLevel{{Price: 99.9, Quantity: 10}},
+		Asks: []exchange.PriceLevel{{Price: 100, Quantity: 10}},
+	})
+
+	// One order an hour: the limit order passes, its market fallback does not
+	sm, err := safety.NewSafetyManager(fake, safety.Config{
+		Enabled:        true,
+		RateLimit:      safety.RateLimitConfig{MaxRequests: 1, Interval: "1h"},
+		Liquidity:      safety.LiquidityConfig{MaxSpreadPercent: 1, MinVolumeMultiplier: 1},
+		PositionLimits: safety.PositionLimitsConfig{MaxPositionSizeUSD: 1000, MaxPortfolioPercent: 100, MaxDailyLossUSD: 100, MaxTotalPositions: 5},
+	}, b.filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.safety = sm
+
+	m.mu.Lock()
+	b.processSignal(m, nil, 100)
+	placed := fake.Orders()
+	if len(placed) != 1 || m.pending == nil {
+		m.mu.Unlock()
+		t.Fatalf("placed %+v, want one resting order", placed)
+	}
+	fake.FillOrder(placed[0].OrderID, 0.4, 99.9)
+	m.mu.Unlock()
+	waitForOrder(t, m)
+
+	orders := fake.Orders()
+	if len(orders) != 1 || orders[0].Status != exchange.OrderStatusCanceled {
+		t.Fatalf("orders = %+v, want the market fallback refused by the rate limit", orders)
+	}
+	if !m.position.InPosition || math.Abs(m.position.Quantity-0.4) > 1e-9 {
+		t.Errorf("position = %+v, want only the 0.4 filled at the limit", *m.position)
+	}
+}
@@ -0,0 +1,213 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+const (
+	// reconcileLookback is how far back exchange executions are compared with the trades table
+	reconcileLookback = 24 * time.Hour
+
+	// reconcileTradeLimit is the most executions fetched per symbol (Binance's myTrades maximum)
+	reconcileTradeLimit = 1000
+
+	// reconcileTolerance is the relative balance shortfall ignored as rounding or dust
+	reconcileTolerance = 0.001
+)
+
+// Discrepancy kinds reported by reconciliation
+const (
+	discrepancyMissingFill      = "missing_fill"      // Execution of the bot's order not in the trades table (imported)
+	discrepancyOrphanedPosition = "orphaned_position" // Open position the account no longer holds (flagged)
+	discrepancyUntrackedBalance = "untracked_balance" // Holding without an open position, or an execution the bot did not place
+)
+
+// reconcile compares the exchange's recent executions and balances with the trades and
+// positions tables before trading starts. Missing fills of the bot's own orders (e.g. the
+// process died between the fill and InsertTrade) are imported as trades and applied to the
+// position; executions of orders placed elsewhere are only reported. Positions the account
+// no longer holds are flagged as orphaned, and every discrepancy is reported.
+// Paper trading is skipped: the simulator keeps no history across restarts.
+func (b *Bot) reconcile(ctx context.Context) {
+	if b.paper != nil || !b.placesOrders() {
+		return
+	}
+
+	balances, err := b.exchange.GetBalances(ctx)
+	if err != nil {
+		log.Printf("⚠️  Reconciliation skipped: failed to get balances: %v", err)
+		return
+	}
+	held := make(map[string]float64, len(balances))
+	for _, balance := range balances {
+		held[balance.Asset] = balance.Total()
+	}
+
+	found := 0
+	for _, symbol := range b.symbols {
+		n, err := b.reconcileMarket(ctx, b.markets[symbol], held)
+		if err != nil {
+			log.Printf("⚠️  %s: Reconciliation failed: %v", symbol, err)
+			continue
+		}
+		found += n
+	}
+
+	if found == 0 {
+		log.Println("🧾 Reconciliation: database matches the exchange")
+	} else {
+		log.Printf("🧾 Reconciliation: %d discrepancy(ies) found", found)
+	}
+}
+
+// reconcileMarket reconciles one symbol and returns the number of discrepancies found
+func (b *Bot) reconcileMarket(ctx context.Context, m *market, held map[string]float64) (int, error) {
+	since := time.Now().Add(-reconcileLookback)
+	trades, err := b.exchange.GetAccountTrades(ctx, m.symbol, since, reconcileTradeLimit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get account trades: %w", err)
+	}
+
+	// Trades may be timestamped a little after the execution, so look back further in the database.
+	// Executions are matched by fill; trades recorded without fills are matched by order ID.
+	orderIDs, tradeIDs, err := b.db.GetRecordedExecutions(m.symbol, false, since.Add(-time.Hour))
+	if err != nil {
+		return 0, err
+	}
+
+	var missing []exchange.AccountTrade
+	for _, t := range trades {
+		if !tradeIDs[t.TradeID] && !orderIDs[strconv.FormatInt(t.OrderID, 10)] {
+			missing = append(missing, t)
+		}
+	}
+
+	found := 0
+	for _, order := range exchange.OrdersFromTrades(missing) {
+		found++
+		own, err := b.placedByBot(ctx, order)
+		if err != nil {
+			return found, err
+		}
+		if own {
+			b.importOrder(m, order)
+		} else {
+			b.reportUntrackedOrder(m, order)
+		}
+	}
+
+	filters, err := b.symbolFilters(ctx, m.symbol)
+	if err != nil {
+		return found, fmt.Errorf("failed to get filters: %w", err)
+	}
+	holding := held[filters.BaseAsset]
+
+	switch {
+	case m.position.InPosition && m.position.IsShort():
+		// A short owes the base asset, which spot balances cannot show; it is left as recorded
+	case m.position.InPosition && holding < m.position.Quantity*(1-reconcileTolerance):
+		found++
+		b.reportDiscrepancy(m.symbol, discrepancyOrphanedPosition,
+			fmt.Sprintf("position %d holds %.8f %s but the account has %.8f", m.currentPositionID, m.position.Quantity, filters.BaseAsset, holding),
+			map[string]interface{}{"positionId": m.currentPositionID, "quantity": m.position.Quantity, "held": holding})
+		b.orphanPosition(m)
+	case !m.position.InPosition && b.sellable(m.symbol, holding, b.reconcilePrice(ctx, m)):
+		found++
+		b.reportDiscrepancy(m.symbol, discrepancyUntrackedBalance,
+			fmt.Sprintf("account holds %.8f %s without an open position", holding, filters.BaseAsset),
+			map[string]interface{}{"held": holding})
+	}
+
+	return found, nil
+}
+
+// placedByBot reports whether the bot placed an executed order. Account trades carry no
+// client order ID, so the order is looked up for it.
+func (b *Bot) placedByBot(ctx context.Context, order *exchange.Order) (bool, error) {
+	placed, err := b.exchange.GetOrder(ctx, order.Symbol, order.OrderID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get order %d: %w", order.OrderID, err)
+	}
+	return ownOrder(placed), nil
+}
+
+// reportUntrackedOrder reports an execution of an order placed outside the bot (e.g. by hand),
+// which is left out of the trades table and the position
+func (b *Bot) reportUntrackedOrder(m *market, order *exchange.Order) {
+	b.reportDiscrepancy(m.symbol, discrepancyUntrackedBalance,
+		fmt.Sprintf("%s order %d for %.8f @ %.8f was not placed by the bot, not importing it", order.Side, order.OrderID, order.ExecutedQuantity, order.AveragePrice()),
+		map[string]interface{}{"orderId": order.OrderID, "side": string(order.Side), "executed": order.ExecutedQuantity, "price": order.AveragePrice()})
+}
+
+// importOrder reports and applies an execution the database missed
+func (b *Bot) importOrder(m *market, order *exchange.Order) {
+	b.reportDiscrepancy(m.symbol, discrepancyMissingFill,
+		fmt.Sprintf("%s order %d for %.8f @ %.8f is not in the database, importing it", order.Side, order.OrderID, order.ExecutedQuantity, order.AveragePrice()),
+		map[string]interface{}{"orderId": order.OrderID, "side": string(order.Side), "executed": order.ExecutedQuantity, "price": order.AveragePrice()})
+
+	b.applyOrder(m, order, "Imported by startup reconciliation")
+}
+
+// applyOrder records an order that did not complete through the bot's order flow (a missed
+// fill of its own, or an adopted external order) and applies it to the position the way
+// a fill of its own would be. Fills that do not fit the position are recorded as trades only.
+func (b *Bot) applyOrder(m *market, order *exchange.Order, reason string) {
+	switch {
+	case order.Side == exchange.SideBuy && !m.position.InPosition:
+		b.recordOpen(m, models.PositionLong, order, 0, reason, nil)
+	case m.position.InPosition && order.Side == orderSide(strategy.CloseSignal(m.position.Side)):
+		b.recordClose(m, order, reason, nil)
+	default:
+		log.Printf("   ⚠️  %s: %s order %d does not match the position, recording the trade only", m.symbol, order.Side, order.OrderID)
+		if _, err := b.db.InsertTrade(b.tradeRecord(m, order, reason, nil)); err != nil {
+			log.Printf("   ⚠️  Failed to log trade to database: %v", err)
+		}
+	}
+}
+
+// orphanPosition flags the market's open position in the database and stops managing it
+func (b *Bot) orphanPosition(m *market) {
+	if m.currentPositionID > 0 {
+		if err := b.db.FlagPositionOrphaned(m.currentPositionID); err != nil {
+			log.Printf("   ⚠️  Failed to flag position: %v", err)
+		}
+	}
+
+	m.position.InPosition = false
+	m.position.Side = ""
+	m.position.Quantity = 0
+	m.position.EntryPrice = 0
+	m.position.LastUpdate = time.Now()
+	m.currentPositionID = 0
+	m.realizedPnL, m.closedQuantity = 0, 0
+	m.exits = nil
+}
+
+// reconcilePrice returns the market's last price, asking the exchange before the first candle
+func (b *Bot) reconcilePrice(ctx context.Context, m *market) float64 {
+	if m.lastPrice > 0 {
+		return m.lastPrice
+	}
+	price, err := b.exchange.GetPrice(ctx, m.symbol)
+	if err != nil {
+		return 0
+	}
+	return price
+}
+
+// reportDiscrepancy logs a reconciliation finding and emits it to the UI
+func (b *Bot) reportDiscrepancy(symbol, kind, detail string, data map[string]interface{}) {
+	log.Printf("🧾 %s %s: %s", symbol, kind, detail)
+
+	data["symbol"] = symbol
+	data["kind"] = kind
+	b.emit("bot:reconcile", fmt.Sprintf("%s: %s", symbol, detail), data)
+}
@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+// newReconcileBot returns a live-trading bot on BTCUSDT at 100 that records the kinds of
+// discrepancy reconciliation reports
+func newReconcileBot(t *testing.T) (*Bot, *market, *stubStrategy, *exchange.FakeExchange, *[]string) {
+	t.Helper()
+	fake := exchange.NewFakeExchange()
+	fake.SetPrice("BTCUSDT", 100)
+	fake.SetSymbolFilters(&exchange.SymbolFilters{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", StepSize: 0.0001})
+	b, m, stub := newTestBot(t, &models.Config{Quantity: 1, TradingEnabled: true}, fake)
+
+	var kinds []string
+	b.SetEventCallback(func(eventType string, message string, data map[string]interface{}) {
+		if eventType == "bot:reconcile" {
+			kinds = append(kinds, data["kind"].(string))
+		}
+	})
+	return b, m, stub, fake, &kinds
+}
+
+// placeMarketBuy buys 1 BTC on the exchange behind the bot's back
+func placeMarketBuy(t *testing.T, fake *exchange.FakeExchange, clientOrderID string) {
+	t.Helper()
+	req := exchange.OrderRequest{Symbol: "BTCUSDT", Side: exchange.SideBuy, Type: exchange.OrderTypeMarket, Quantity: 1, ClientOrderID: clientOrderID}
+	if _, err := fake.PlaceOrder(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	fake.SetBalance("BTC", 1)
+}
+
+func TestReconcile_MissingFill(t *testing.T) {
+	b, m, _, fake, kinds := newReconcileBot(t)
+	// The bot's order filled but the process died before recording it
+	placeMarketBuy(t, fake, newClientOrderID())
+
+	b.reconcile(context.Background())
+
+	if want := []string{discrepancyMissingFill}; !reflect.DeepEqual(*kinds, want) {
+		t.Errorf("reported %v, want %v", *kinds, want)
+	}
+	if !m.position.InPosition || m.position.Quantity != 1 || m.position.EntryPrice != 100 {
+		t.Errorf("position = %+v, want the imported 1 BTC @ 100", m.position)
+	}
+	trades, err := b.db.GetRecentTrades(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 1 || trades[0].BinanceOrderID != "1" || trades[0].SignalReason != "Imported by startup reconciliation" {
+		t.Errorf("trades = %+v, want the imported order", trades)
+	}
+
+	// Once imported, the order matches
+	*kinds = nil
+	b.reconcile(context.Background())
+	if len(*kinds) != 0 {
+		t.Errorf("second reconciliation reported %v, want nothing", *kinds)
+	}
+}
+
+func TestReconcile_OrphanedPosition(t *testing.T) {
+	b, m, stub, fake, kinds := newReconcileBot(t)
+	stub.signal = strategy.SignalBuy
+	b.processSignal(m, nil, 100)
+	if !m.position.InPosition {
+		t.Fatal("BUY did not open a position")
+	}
+	positionID := m.currentPositionID
+
+	// The BTC was sold or withdrawn outside the bot's trading history
+	fake.SetBalance("BTC", 0)
+	b.reconcile(context.Background())
+
+	if want := []string{discrepancyOrphanedPosition}; !reflect.DeepEqual(*kinds, want) {
+		t.Errorf("reported %v, want %v", *kinds, want)
+	}
+	if m.position.InPosition || m.currentPositionID != 0 {
+		t.Errorf("position = %+v (ID %d), want it dropped", m.position, m.currentPositionID)
+	}
+	if pos, err := b.db.GetOpenPosition("BTCUSDT"); err != nil || pos != nil {
+		t.Errorf("open position %d = %+v (%v), want it flagged orphaned", positionID, pos, err)
+	}
+}
+
+func TestReconcile_UntrackedBalance(t *testing.T) {
+	b, m, _, fake, kinds := newReconcileBot(t)
+	// Bought by hand on the exchange's website
+	placeMarketBuy(t, fake, "web_8f2a61c9")
+
+	b.reconcile(context.Background())
+
+	// Both the execution and the holding are reported, neither is taken over
+	if want := []string{discrepancyUntrackedBalance, discrepancyUntrackedBalance}; !reflect.DeepEqual(*kinds, want) {
+		t.Errorf("reported %v, want %v", *kinds, want)
+	}
+	if m.position.InPosition {
+		t.Errorf("position = %+v, want none for a manual order", m.position)
+	}
+	if trades, err := b.db.GetRecentTrades(10); err != nil || len(trades) != 0 {
+		t.Errorf("trades = %+v (%v), want none imported", trades, err)
+	}
+}
@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+// positionExits are the risk exits attached to an open position
+type positionExits struct {
+	takeProfit float64
+	stop       *strategy.TrailingStopTracker // Holds the stop-loss; only trails when enabled
+}
+
+// newRiskManager converts the risk config, returning nil when exits are disabled.
+// With risk_per_trade sizing the stop-loss is the same ATR stop the size was computed from.
+func newRiskManager(botConfig *models.Config) *strategy.RiskManager {
+	config := botConfig.Risk
+	if !config.Enabled {
+		return nil
+	}
+
+	riskConfig := strategy.DefaultRiskConfig()
+	riskConfig.StopLossPercent = config.StopLossPercent
+	riskConfig.TakeProfitPercent = config.TakeProfitPercent
+	riskConfig.UseRiskRewardRatio = config.RiskRewardRatio > 0
+	riskConfig.RiskRewardRatio = config.RiskRewardRatio
+	riskConfig.UseTrailingStop = config.TrailingStop
+	riskConfig.TrailingStopPercent = config.TrailingActivationPercent
+	riskConfig.TrailingStopDistance = config.TrailingDistancePercent
+	if botConfig.SizingMode() == models.SizingRiskPerTrade {
+		riskConfig.UseATRStopLoss = true
+		riskConfig.ATRMultiplier = botConfig.Sizing.ATRMultiplier
+	}
+
+	log.Printf("🛡️  Risk exits enabled: stop %.2f%%, trailing %v", riskConfig.StopLossPercent, riskConfig.UseTrailingStop)
+	return strategy.NewRiskManager(riskConfig)
+}
+
+// newExits computes the exits for a position opened at entryPrice.
+// volatility is the ATR for ATR stops (0 = fixed percentage stop).
+// Short exits mirror the long levels around the entry price and do not trail.
+func (b *Bot) newExits(side models.PositionSide, entryPrice, volatility float64) *positionExits {
+	if b.risk == nil {
+		return nil
+	}
+
+	stopLoss, takeProfit := b.risk.ExitLevels(entryPrice, volatility)
+	if side == models.PositionShort {
+		return b.buildExits(entryPrice, 2*entryPrice-stopLoss, 2*entryPrice-takeProfit, false)
+	}
+	return b.buildExits(entryPrice, stopLoss, takeProfit, b.risk.Config().UseTrailingStop)
+}
+
+// restoreExits rebuilds the exits of a position loaded from the database.
+// Positions opened before exits were persisted get fresh levels from their entry price.
+func (b *Bot) restoreExits(pos *database.Position) *positionExits {
+	if b.risk == nil {
+		return nil
+	}
+
+	side := models.PositionSide(pos.PositionSide())
+	if pos.StopLossPrice == 0 || pos.TakeProfitPrice == 0 {
+		return b.newExits(side, pos.EntryPrice, 0)
+	}
+
+	trailing := b.risk.Config().UseTrailingStop && side != models.PositionShort
+	exits := b.buildExits(pos.EntryPrice, pos.StopLossPrice, pos.TakeProfitPrice, trailing)
+	exits.stop.HighestPrice = math.Max(pos.EntryPrice, pos.HighestPrice)
+	exits.stop.TrailingActive = pos.TrailingActive
+	return exits
+}
+
+func (b *Bot) buildExits(entryPrice, stopLoss, takeProfit float64, trailing bool) *positionExits {
+	cfg := b.risk.Config()
+	stop := strategy.NewTrailingStopTracker(entryPrice, stopLoss, cfg.TrailingStopPercent, cfg.TrailingStopDistance)
+	if !trailing {
+		stop.ActivationPrice = math.Inf(1) // Fixed stop: never starts trailing
+	}
+	return &positionExits{takeProfit: takeProfit, stop: stop}
+}
+
+// checkExits updates the trailing stop with the latest price and reports whether
+// the open position must be closed, and why
+func (b *Bot) checkExits(m *market, price float64) (bool, string) {
+	if b.risk == nil || m.exits == nil || !m.position.InPosition {
+		return false, ""
+	}
+
+	if m.position.IsShort() {
+		return shortExit(m.position.EntryPrice, price, m.exits)
+	}
+
+	stop := m.exits.stop
+	prevStop, prevActive := stop.StopLossPrice, stop.TrailingActive
+	stop.Update(price)
+
+	// Persist stop moves so a restart resumes from the same level
+	if stop.StopLossPrice != prevStop || stop.TrailingActive != prevActive {
+		log.Printf("🛡️  %s: Stop-loss moved to %.8f (peak %.8f)", m.symbol, stop.StopLossPrice, stop.HighestPrice)
+		b.persistExits(m)
+	}
+
+	exit, reason := b.risk.ShouldExit(m.position.EntryPrice, price, stop.GetStopLossPrice(), m.exits.takeProfit)
+	if exit && stop.TrailingActive && price <= stop.GetStopLossPrice() {
+		reason = fmt.Sprintf("Trailing stop triggered at %.8f (peak %.8f)", stop.GetStopLossPrice(), stop.HighestPrice)
+	}
+	return exit, reason
+}
+
+// shortExit reports whether a short must be covered: its stop-loss is above the entry, its take-profit below
+func shortExit(entryPrice, price float64, exits *positionExits) (bool, string) {
+	stopLoss := exits.stop.GetStopLossPrice()
+	switch {
+	case price >= stopLoss:
+		return true, fmt.Sprintf("Stop-loss triggered at %.8f (%.2f%% loss)", stopLoss, (stopLoss-entryPrice)/entryPrice*100)
+	case price <= exits.takeProfit:
+		return true, fmt.Sprintf("Take-profit reached at %.8f (%.2f%% profit)", exits.takeProfit, (entryPrice-exits.takeProfit)/entryPrice*100)
+	}
+	return false, ""
+}
+
+// persistExits saves the current exit levels of an open position
+func (b *Bot) persistExits(m *market) {
+	if m.exits == nil || m.currentPositionID == 0 {
+		return
+	}
+
+	stop := m.exits.stop
+	if err := b.db.UpdatePositionStops(m.currentPositionID, stop.StopLossPrice, m.exits.takeProfit, stop.HighestPrice, stop.TrailingActive); err != nil {
+		log.Printf("⚠️  %s: Failed to persist stop levels: %v", m.symbol, err)
+	}
+}
@@ -0,0 +1,199 @@
+package bot
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+// testRiskConfig stops out 3% below entry, takes profit 10% above and trails 2% behind
+// the peak once the position is 4% in profit
+func testRiskConfig() models.RiskConfig {
+	return models.RiskConfig{
+		Enabled:                   true,
+		StopLossPercent:           3,
+		TakeProfitPercent:         10,
+		TrailingStop:              true,
+		TrailingActivationPercent: 4,
+		TrailingDistancePercent:   2,
+	}
+}
+
+// openTestPosition opens a long position at 100 through a BUY signal
+func openTestPosition(t *testing.T, risk models.RiskConfig) (*Bot, *market, *exchange.FakeExchange) {
+	t.Helper()
+	fake := exchange.NewFakeExchange()
+	fake.SetPrice("BTCUSDT", 100)
+	b, m, stub := newTestBot(t, &models.Config{Quantity: 1, TradingEnabled: true, Risk: risk}, fake)
+
+	stub.signal = strategy.SignalBuy
+	b.processSignal(m, nil, 100)
+	stub.signal = strategy.SignalNone
+	if !m.position.InPosition {
+		t.Fatal("BUY did not open a position")
+	}
+	return b, m, fake
+}
+
+func TestCheckExits(t *testing.T) {
+	tests := []struct {
+		name       string
+		prices     []float64 // Closes after the entry at 100; only the last may exit
+		wantReason string    // Prefix of the exit reason ("" = no exit)
+		wantStop   float64
+	}{
+		{"holds between stop and target", []float64{99, 101, 103}, "", 97},
+		{"stop-loss below entry", []float64{99, 96.9}, "Stop-loss triggered", 97},
+		{"take-profit above entry", []float64{105, 110.5}, "Take-profit reached", 110.5 * 0.98},
+		{"trailing starts at the activation price", []float64{104}, "", 104 * 0.98},
+		{"trailing stop follows the peak", []float64{105, 108, 106}, "", 108 * 0.98},
+		{"trailing stop triggers on a pullback", []float64{105, 108, 105.5}, "Trailing stop triggered", 108 * 0.98},
+		{"trailing stop never moves down", []float64{108, 101}, "Trailing stop triggered", 108 * 0.98},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, m, _ := openTestPosition(t, testRiskConfig())
+
+			var exit bool
+			var reason string
+			for i, price := range tt.prices {
+				exit, reason = b.checkExits(m, price)
+				if exit && i < len(tt.prices)-1 {
+					t.Fatalf("exited early at %.2f: %s", price, reason)
+				}
+			}
+
+			if exit != (tt.wantReason != "") || !strings.HasPrefix(reason, tt.wantReason) {
+				t.Errorf("checkExits = %v %q, want reason %q", exit, reason, tt.wantReason)
+			}
+			if got := m.exits.stop.GetStopLossPrice(); math.Abs(got-tt.wantStop) > 1e-9 {
+				t.Errorf("stop-loss = %.8f, want %.8f", got, tt.wantStop)
+			}
+
+			// Stop moves are persisted for restarts
+			stored, err := b.db.GetOpenPosition("BTCUSDT")
+			if err != nil || stored == nil {
+				t.Fatalf("GetOpenPosition = %v, %v", stored, err)
+			}
+			if math.Abs(stored.StopLossPrice-tt.wantStop) > 1e-9 || stored.TrailingActive != m.exits.stop.TrailingActive {
+				t.Errorf("stored stop = %.8f (trailing %v), want %.8f (trailing %v)",
+					stored.StopLossPrice, stored.TrailingActive, tt.wantStop, m.exits.stop.TrailingActive)
+			}
+		})
+	}
+}
+
+func TestCheckExits_FixedStopDoesNotTrail(t *testing.T) {
+	risk := testRiskConfig()
+	risk.TrailingStop = false
+	b, m, _ := openTestPosition(t, risk)
+
+	for _, price := range []float64{105, 109, 98} {
+		if exit, reason := b.checkExits(m, price); exit {
+			t.Fatalf("exited at %.2f: %s", price, reason)
+		}
+	}
+	if got := m.exits.stop.GetStopLossPrice(); got != 97 {
+		t.Errorf("stop-loss = %.8f, want the fixed 97", got)
+	}
+}
+
+func TestCheckExits_Disabled(t *testing.T) {
+	b, m, _ := openTestPosition(t, models.RiskConfig{})
+	if m.exits != nil {
+		t.Fatalf("exits set with risk disabled: %+v", m.exits)
+	}
+	for _, price := range []float64{1, 1000} {
+		if exit, reason := b.checkExits(m, price); exit {
+			t.Errorf("exited at %.2f with risk disabled: %s", price, reason)
+		}
+	}
+}
+
+func TestHandleMessage_RiskExitSells(t *testing.T) {
+	b, m, fake := openTestPosition(t, testRiskConfig())
+	fake.SetPrice("BTCUSDT", 96)
+
+	event := models.KlineEvent{EventType: "kline", Symbol: "BTCUSDT"}
+	event.Kline.Symbol = "BTCUSDT"
+	event.Kline.OpenTime = time.Now().UnixMilli()
+	event.Kline.Open, event.Kline.High, event.Kline.Low, event.Kline.Close = "99", "99", "96", "96"
+	event.Kline.IsClosed = true
+	message, _ := json.Marshal(event)
+
+	if err := b.handleMessage(message); err != nil {
+		t.Fatal(err)
+	}
+
+	orders := fake.Orders()
+	if len(orders) != 2 || orders[1].Side != exchange.SideSell || orders[1].OrigQuantity != 1 {
+		t.Fatalf("orders = %+v, want the stop-loss SELL of 1", orders)
+	}
+	if m.position.InPosition || m.exits != nil {
+		t.Errorf("position still open after the stop-loss: %+v", *m.position)
+	}
+}
+
+func TestNewExits_ShortMirrorsLong(t *testing.T) {
+	b, _, _ := newTestBot(t, &models.Config{Risk: testRiskConfig()}, exchange.NewFakeExchange())
+
+	long := b.newExits(models.PositionLong, 100, 0)
+	if long.stop.StopLossPrice != 97 || !almostEqual(long.takeProfit, 110) || !almostEqual(long.stop.ActivationPrice, 104) {
+		t.Errorf("long exits = stop %.8f, target %.8f, trailing from %.8f; want 97, 110, 104",
+			long.stop.StopLossPrice, long.takeProfit, long.stop.ActivationPrice)
+	}
+
+	// Mirrored around the entry: 2*entry - level
+	short := b.newExits(models.PositionShort, 100, 0)
+	if !almostEqual(short.stop.StopLossPrice, 2*100-97) || !almostEqual(short.takeProfit, 2*100-long.takeProfit) {
+		t.Errorf("short exits = stop %.8f, target %.8f; want 103, 90", short.stop.StopLossPrice, short.takeProfit)
+	}
+	if !math.IsInf(short.stop.ActivationPrice, 1) {
+		t.Errorf("short stop trails from %.8f, want a fixed stop", short.stop.ActivationPrice)
+	}
+}
+
+func TestCheckExits_Short(t *testing.T) {
+	tests := []struct {
+		name       string
+		prices     []float64 // Closes after the short entry at 100; only the last may exit
+		wantReason string
+	}{
+		{"holds between target and stop", []float64{101, 95, 92}, ""},
+		{"stop-loss above entry", []float64{101, 103.5}, "Stop-loss triggered at 103.00000000"},
+		{"take-profit below entry", []float64{95, 89}, "Take-profit reached at 90.00000000"},
+		{"stop does not trail the fall", []float64{91, 96, 102}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, m, stub, _ := newShortBot(t, &models.Config{Risk: testRiskConfig()})
+			sendSignal(b, m, stub, strategy.SignalOpenShort, 0, 100)
+			if m.exits == nil {
+				t.Fatal("short opened without exits")
+			}
+
+			var exit bool
+			var reason string
+			for i, price := range tt.prices {
+				exit, reason = b.checkExits(m, price)
+				if exit && i < len(tt.prices)-1 {
+					t.Fatalf("exited early at %.2f: %s", price, reason)
+				}
+			}
+			if exit != (tt.wantReason != "") || !strings.HasPrefix(reason, tt.wantReason) {
+				t.Errorf("checkExits = %v %q, want reason %q", exit, reason, tt.wantReason)
+			}
+			if got := m.exits.stop.GetStopLossPrice(); !almostEqual(got, 103) {
+				t.Errorf("stop-loss = %.8f, want the fixed 103", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+// newSizingManager builds the risk manager used to size risk_per_trade orders,
+// returning nil for the other sizing modes
+func newSizingManager(config *models.Config) *strategy.RiskManager {
+	if config.SizingMode() != models.SizingRiskPerTrade {
+		return nil
+	}
+
+	riskConfig := strategy.DefaultRiskConfig()
+	riskConfig.RiskPerTradePercent = config.Sizing.RiskPercent
+	riskConfig.MaxPositionSizePercent = config.Sizing.MaxPositionPercent
+	riskConfig.UseATRStopLoss = true
+	riskConfig.ATRMultiplier = config.Sizing.ATRMultiplier
+	if config.Risk.StopLossPercent > 0 {
+		riskConfig.StopLossPercent = config.Risk.StopLossPercent // Fallback until ATR is ready
+	}
+
+	log.Printf("📐 Risk-per-trade sizing: %.2f%% risk, %.1fx ATR(%d) stop", riskConfig.RiskPerTradePercent, riskConfig.ATRMultiplier, config.Sizing.ATRPeriod)
+	return strategy.NewRiskManager(riskConfig)
+}
+
+// newVolatility returns the ATR tracker of a market, or nil when sizing does not need it
+func newVolatility(config *models.Config) *strategy.ATRCalculator {
+	if config.SizingMode() != models.SizingRiskPerTrade {
+		return nil
+	}
+	return strategy.NewATRCalculator(config.Sizing.ATRPeriod)
+}
+
+// updateVolatility feeds a closed candle to the market's ATR and records its close
+func (m *market) updateVolatility(high, low, close float64) {
+	if m.atr != nil && m.lastPrice > 0 && high > 0 && low > 0 {
+		m.atr.Update(high, low, m.lastPrice)
+	}
+	m.lastPrice = close
+}
+
+// volatility returns the market's current ATR, or 0 while it is warming up
+func (m *market) volatility() float64 {
+	if m.atr == nil {
+		return 0
+	}
+	atr, ready := m.atr.GetATR()
+	if !ready {
+		return 0
+	}
+	return atr
+}
+
+// orderQuantity computes the entry quantity for a market at price according to the sizing
+// mode, scaled by the signal's suggested size (0 = 1) and rounded down to the LOT_SIZE step
+func (b *Bot) orderQuantity(ctx context.Context, m *market, price, multiplier float64) (float64, error) {
+	if price <= 0 {
+		return 0, fmt.Errorf("invalid price %.8f", price)
+	}
+
+	filters, err := b.symbolFilters(ctx, m.symbol)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s filters: %w", m.symbol, err)
+	}
+
+	var quantity float64
+	switch b.config.SizingMode() {
+	case models.SizingQuoteAmount:
+		quantity = b.config.Sizing.QuoteAmount / price
+
+	case models.SizingPercentEquity:
+		equity, err := b.accountEquity(ctx, m, filters, price)
+		if err != nil {
+			return 0, err
+		}
+		quantity = equity * (b.config.Sizing.EquityPercent / 100.0) / price
+
+	case models.SizingRiskPerTrade:
+		equity, err := b.accountEquity(ctx, m, filters, price)
+		if err != nil {
+			return 0, err
+		}
+		atr := m.volatility()
+		if atr == 0 {
+			log.Printf("   ⚠️  %s: ATR not ready, sizing with the %.2f%% stop-loss", m.symbol, b.sizing.Config().StopLossPercent)
+		}
+		size, err := b.sizing.CalculatePositionSize(equity, price, atr)
+		if err != nil {
+			return 0, fmt.Errorf("failed to size position: %w", err)
+		}
+		log.Printf("   📐 Risking $%.2f (%.2f%% of $%.2f) with stop at %.8f", size.RiskAmount, size.MaxLossPercent, equity, size.StopLossPrice)
+		quantity = size.Quantity
+
+	default:
+		quantity = b.config.QuantityFor(m.symbol)
+	}
+
+	// Strategies like DCA buy more on dips
+	if multiplier > 0 && multiplier != 1 {
+		log.Printf("   📐 %s size multiplier: %.2fx", m.strategy.Name(), multiplier)
+		quantity *= multiplier
+	}
+
+	rounded := filters.RoundQuantity(quantity)
+	if err := filters.Validate(rounded, price, true); err != nil {
+		return 0, err
+	}
+	return rounded, nil
+}
+
+// accountEquity values the account in the market's quote asset: the quote balance
+// plus every base asset of a market quoted in it, at that market's last price
+func (b *Bot) accountEquity(ctx context.Context, m *market, filters *exchange.SymbolFilters, price float64) (float64, error) {
+	if filters.QuoteAsset == "" {
+		return 0, fmt.Errorf("unknown quote asset for %s", m.symbol)
+	}
+
+	balances, err := b.exchange.GetBalances(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balances: %w", err)
+	}
+	held := make(map[string]float64, len(balances))
+	for _, balance := range balances {
+		held[balance.Asset] = balance.Total()
+	}
+
+	equity := held[filters.QuoteAsset]
+	for _, symbol := range b.symbols {
+		other := b.markets[symbol]
+		otherPrice := price
+		otherFilters := filters
+		if other != m {
+			if otherFilters, err = b.symbolFilters(ctx, symbol); err != nil || otherFilters.QuoteAsset != filters.QuoteAsset {
+				continue
+			}
+			// The caller holds m.mu; other markets are locked one at a time, only to read their price
+			other.mu.Lock()
+			otherPrice = other.lastPrice
+			other.mu.Unlock()
+		}
+		equity += held[otherFilters.BaseAsset] * otherPrice
+	}
+
+	if equity <= 0 {
+		return 0, fmt.Errorf("no %s equity available", filters.QuoteAsset)
+	}
+	return equity, nil
+}
+
+// symbolFilters returns the cached trading rules of a symbol
+func (b *Bot) symbolFilters(ctx context.Context, symbol string) (*exchange.SymbolFilters, error) {
+	return b.filters.Get(ctx, symbol)
+}
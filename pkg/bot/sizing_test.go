@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+)
+
+// klineMessage returns a closed kline event for symbol at close
+func klineMessage(symbol string, openTime time.Time, close float64) []byte {
+	event := models.KlineEvent{EventType: "kline", Symbol: symbol}
+	event.Kline.Symbol = symbol
+	event.Kline.OpenTime = openTime.UnixMilli()
+	price := fmt.Sprintf("%.8f", close)
+	event.Kline.Open, event.Kline.High, event.Kline.Low, event.Kline.Close = price, price, price, price
+	event.Kline.IsClosed = true
+	message, _ := json.Marshal(event)
+	return message
+}
+
+func TestOrderQuantity_PercentEquityAcrossMarkets(t *testing.T) {
+	fake := exchange.NewFakeExchange()
+	fake.SetBalance("USDT", 1000)
+	fake.SetBalance("ETH", 2)
+	fake.SetSymbolFilters(&exchange.SymbolFilters{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT"})
+	fake.SetSymbolFilters(&exchange.SymbolFilters{Symbol: "ETHUSDT", BaseAsset: "ETH", QuoteAsset: "USDT"})
+
+	config := &models.Config{
+		Symbols:        []string{"BTCUSDT", "ETHUSDT"},
+		TradingEnabled: true,
+		Sizing:         models.SizingConfig{Mode: models.SizingPercentEquity, EquityPercent: 10},
+	}
+	b, btc, _ := newTestBot(t, config, fake)
+	eth := b.markets["ETHUSDT"]
+	eth.lastPrice = 500
+
+	// 1000 USDT + 2 ETH at 500 = 2000 USDT; 10% of it buys 0.002 BTC at 100000
+	btc.mu.Lock()
+	quantity, err := b.orderQuantity(context.Background(), btc, 100000, 0)
+	btc.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(quantity-0.002) > 1e-12 {
+		t.Errorf("quantity = %.8f, want 0.002", quantity)
+	}
+
+	// Sizing one market while another receives candles must not race (go test -race)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		for i := 0; i < 50; i++ {
+			b.handleMessage(klineMessage("ETHUSDT", start.Add(time.Duration(i)*time.Minute), 500+float64(i)))
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		btc.mu.Lock()
+		if _, err := b.orderQuantity(context.Background(), btc, 100000, 0); err != nil {
+			t.Error(err)
+		}
+		btc.mu.Unlock()
+	}
+	wg.Wait()
+}
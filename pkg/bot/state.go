@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/strategy"
+)
+
+// restoreState loads the market's saved strategy state, so indicators and the DCA
+// schedule continue where they stopped. Warm-up then feeds only the candles closed
+// since. A state that cannot be restored is ignored and the strategy starts fresh.
+func (b *Bot) restoreState(m *market) {
+	saved, err := b.db.GetStrategyState(m.symbol)
+	if err != nil {
+		log.Printf("⚠️  %s: Failed to load saved strategy state: %v", m.symbol, err)
+		return
+	}
+	if saved == nil {
+		return
+	}
+
+	if interval := b.config.KlineInterval(); saved.Interval != interval {
+		log.Printf("⚠️  %s: Ignoring saved strategy state built from %s candles (now %s)", m.symbol, saved.Interval, interval)
+		return
+	}
+	if err := strategy.UnmarshalState(m.strategy, saved.State); err != nil {
+		log.Printf("⚠️  %s: Ignoring saved strategy state: %v", m.symbol, err)
+		m.strategy = newStrategy(b.config) // Restoring may have failed part-way (e.g. after a composite's first child)
+		return
+	}
+
+	m.lastCandle = saved.LastCandle
+	m.restored = true
+	log.Printf("💾 %s: Restored %s strategy state saved at %s (ready: %v)", m.symbol, m.strategy.Name(), saved.SavedAt.Local().Format(time.RFC3339), m.strategy.IsReady())
+}
+
+// catchUp feeds a restored market the candles that closed while the bot was down
+func (b *Bot) catchUp(ctx context.Context, m *market) {
+	// The ATR is not saved; prime it from the latest candles instead of the gap
+	atr := m.atr
+	m.atr = nil
+	b.fillGap(ctx, m, time.Now())
+	m.atr = atr
+
+	if m.atr != nil {
+		if err := b.warmUpVolatility(ctx, m); err != nil {
+			log.Printf("⚠️  %s: ATR warm-up failed, waiting for live candles: %v", m.symbol, err)
+		}
+	}
+
+	b.emit("bot:status", fmt.Sprintf("%s: Strategy state restored", m.symbol), map[string]interface{}{
+		"symbol": m.symbol,
+		"ready":  m.strategy.IsReady(),
+	})
+}
+
+// saveState stores the market's strategy state. The caller holds m.mu.
+func (b *Bot) saveState(m *market) {
+	// Nothing fed yet: a fresh strategy warms up better than a saved empty one
+	if m.lastCandle.IsZero() {
+		return
+	}
+
+	data, err := strategy.MarshalState(m.strategy)
+	if err != nil {
+		log.Printf("⚠️  %s: Failed to save strategy state: %v", m.symbol, err)
+		return
+	}
+
+	err = b.db.SaveStrategyState(&database.StrategyState{
+		Symbol:     m.symbol,
+		Strategy:   m.strategy.Name(),
+		Interval:   b.config.KlineInterval(),
+		State:      data,
+		LastCandle: m.lastCandle,
+		SavedAt:    time.Now(),
+	})
+	if err != nil {
+		log.Printf("⚠️  %s: %v", m.symbol, err)
+	}
+}
+
+// saveStates stores the strategy state of every market
+func (b *Bot) saveStates() {
+	for _, symbol := range b.symbols {
+		m := b.markets[symbol]
+		m.mu.Lock()
+		b.saveState(m)
+		m.mu.Unlock()
+	}
+}
+
+// runStateSaver saves every market's strategy state each stateSaveInterval until ctx is done
+func (b *Bot) runStateSaver(ctx context.Context) {
+	ticker := time.NewTicker(stateSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.saveStates()
+		}
+	}
+}
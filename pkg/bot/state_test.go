@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+// newStateBot creates a bot on BTCUSDT sharing db, so it restores what an earlier bot saved
+func newStateBot(t *testing.T, config *models.Config, ex exchange.Exchange, db *database.DB) (*Bot, *market) {
+	t.Helper()
+	config.Symbol = "BTCUSDT"
+	b, err := NewWithExchange(config, ex, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b, b.markets["BTCUSDT"]
+}
+
+// feedKlines updates the market's strategy with klines, as warm-up does
+func feedKlines(t *testing.T, m *market, klines []exchange.Kline) {
+	t.Helper()
+	for _, k := range klines {
+		if err := strategy.UpdateWithCandle(m.strategy, klineCandle(k)); err != nil {
+			t.Fatal(err)
+		}
+		m.lastCandle = k.OpenTime
+	}
+}
+
+// rsiChild configures a composite child RSI strategy over period price changes
+func rsiChild(period int) models.StrategyConfig {
+	return models.StrategyConfig{Type: "rsi", Indicator: models.IndicatorConfig{Type: "rsi", Params: map[string]interface{}{"period": period}}}
+}
+
+func TestRestoreState_CatchUp(t *testing.T) {
+	db := newTestDB(t)
+	fake := newFakeExchange()
+	klines := testKlines(time.Minute, 30)
+	fake.SetKlines("BTCUSDT", "1m", klines)
+	config := func() *models.Config {
+		return &models.Config{Strategy: models.StrategyConfig{Type: "rsi"}, WarmupCandles: 50}
+	}
+
+	// The first run stops after 20 candles
+	first, m := newStateBot(t, config(), fake, db)
+	feedKlines(t, m, klines[:20])
+	first.saveState(m)
+
+	b, m := newStateBot(t, config(), fake, db)
+	if !m.restored || !m.lastCandle.Equal(klines[19].OpenTime) || !m.strategy.IsReady() {
+		t.Fatalf("restored = %v at %s (ready %v), want the saved state at %s", m.restored, m.lastCandle, m.strategy.IsReady(), klines[19].OpenTime)
+	}
+
+	// Warm-up only feeds the candles closed since, ending where an uninterrupted run would
+	b.warmUp(context.Background())
+	if !m.lastCandle.Equal(klines[29].OpenTime) {
+		t.Errorf("caught up to %s, want %s", m.lastCandle, klines[29].OpenTime)
+	}
+	_, uninterrupted := newStateBot(t, &models.Config{Strategy: models.StrategyConfig{Type: "rsi"}}, fake, newTestDB(t))
+	feedKlines(t, uninterrupted, klines[:30])
+	got, _ := m.strategy.GetIndicator().GetValue()
+	want, _ := uninterrupted.strategy.GetIndicator().GetValue()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RSI after catching up = %v, want %v", got, want)
+	}
+}
+
+func TestRestoreState_DCASchedule(t *testing.T) {
+	db := newTestDB(t)
+	config := func() *models.Config { return &models.Config{Strategy: models.StrategyConfig{Type: "dca"}} }
+
+	// The weekly buy went out, scheduling the next one a week later than a fresh start would
+	first, m := newStateBot(t, config(), newFakeExchange(), db)
+	dca := m.strategy.(*strategy.DCAStrategy)
+	if signal := dca.GenerateSignal(strategy.SignalContext{Timestamp: dca.GetNextBuyTime().Add(time.Hour)}); signal != strategy.SignalBuy {
+		t.Fatalf("signal = %s, want the scheduled BUY", signal)
+	}
+	next := dca.GetNextBuyTime()
+	m.lastCandle = time.Now().Truncate(time.Minute)
+	first.saveState(m)
+
+	_, m = newStateBot(t, config(), newFakeExchange(), db)
+	if got := m.strategy.(*strategy.DCAStrategy).GetNextBuyTime(); !m.restored || !got.Equal(next) {
+		t.Errorf("next buy = %s (restored %v), want the saved %s", got, m.restored, next)
+	}
+}
+
+func TestRestoreState_Ignored(t *testing.T) {
+	klines := testKlines(time.Minute, 20)
+	tests := []struct {
+		name          string
+		saved, config models.Config
+	}{
+		{
+			"interval changed",
+			models.Config{Strategy: models.StrategyConfig{Type: "rsi"}},
+			models.Config{Strategy: models.StrategyConfig{Type: "rsi"}, Interval: "5m"},
+		},
+		{
+			// The first child restores, the second does not match
+			"composite child changed",
+			models.Config{Strategy: models.StrategyConfig{Type: "composite", Children: []models.StrategyConfig{rsiChild(14), rsiChild(14)}}},
+			models.Config{Strategy: models.StrategyConfig{Type: "composite", Children: []models.StrategyConfig{rsiChild(14), rsiChild(9)}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			first, m := newStateBot(t, &tt.saved, newFakeExchange(), db)
+			feedKlines(t, m, klines[:20])
+			if !m.strategy.IsReady() {
+				t.Fatal("saved strategy is not ready")
+			}
+			first.saveState(m)
+
+			_, m = newStateBot(t, &tt.config, newFakeExchange(), db)
+			if m.restored || !m.lastCandle.IsZero() {
+				t.Errorf("restored = %v at %s, want a fresh start", m.restored, m.lastCandle)
+			}
+			if m.strategy.IsReady() {
+				t.Error("strategy is ready, want it rebuilt without the saved state")
+			}
+			if composite, ok := m.strategy.(*strategy.CompositeStrategy); ok {
+				for i, child := range composite.Children() {
+					if child.Strategy.GetIndicator().IsReady() {
+						t.Errorf("child %d kept restored state", i+1)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"rsi-bot/pkg/exchange"
+)
+
+// streamUserData follows the account's user data stream in the background, keeping
+// balances and open orders current and reporting fills of orders placed outside the bot
+// (applying them to positions with adopt_external_orders). Only live exchanges stream;
+// paper trading has no outside activity.
+func (b *Bot) streamUserData(ctx context.Context) {
+	streamer, ok := b.exchange.(exchange.UserDataStreamer)
+	if !ok || b.paper != nil {
+		return
+	}
+
+	log.Println("👤 Following the user data stream for order and balance updates")
+	go func() {
+		streamer.StreamUserData(ctx, exchange.UserDataHandler{
+			OnConnect: func() {
+				log.Println("✅ User data stream connected")
+				b.refreshBalances(ctx)
+			},
+			OnOrder:   b.handleOrderUpdate,
+			OnAccount: b.handleAccountUpdate,
+			OnError: func(err error) {
+				log.Printf("⚠️  %v", err)
+			},
+		})
+	}()
+}
+
+// refreshBalances replaces the streamed balances with a REST snapshot (after each
+// (re)connection, as changes made while disconnected are not replayed)
+func (b *Bot) refreshBalances(ctx context.Context) {
+	balances, err := b.exchange.GetBalances(ctx)
+	if err != nil {
+		log.Printf("⚠️  Failed to get balances: %v", err)
+		return
+	}
+
+	b.accountMu.Lock()
+	b.balances = make(map[string]exchange.Balance, len(balances))
+	for _, balance := range balances {
+		b.balances[balance.Asset] = balance
+	}
+	b.accountMu.Unlock()
+
+	b.emitBalances(balances)
+}
+
+// handleAccountUpdate records the balances changed by an account event
+func (b *Bot) handleAccountUpdate(update exchange.AccountUpdate) {
+	b.accountMu.Lock()
+	if b.balances == nil {
+		b.balances = make(map[string]exchange.Balance, len(update.Balances))
+	}
+	for _, balance := range update.Balances {
+		b.balances[balance.Asset] = balance
+	}
+	b.accountMu.Unlock()
+
+	b.emitBalances(update.Balances)
+}
+
+// emitBalances reports changed balances to the UI
+func (b *Bot) emitBalances(balances []exchange.Balance) {
+	changed := make([]map[string]interface{}, 0, len(balances))
+	for _, balance := range balances {
+		if balance.Total() == 0 {
+			continue
+		}
+		changed = append(changed, map[string]interface{}{
+			"asset":  balance.Asset,
+			"free":   balance.Free,
+			"locked": balance.Locked,
+		})
+	}
+	b.emit("bot:balance", fmt.Sprintf("%d balance(s) updated", len(changed)), map[string]interface{}{
+		"balances": changed,
+	})
+}
+
+// handleOrderUpdate tracks an order event. Orders placed by the bot are completed by the
+// order flow that placed them; a finished order placed elsewhere (e.g. on the Binance
+// website) is only reported, unless adopt_external_orders has it recorded and applied to
+// the position like a fill of the bot's own.
+func (b *Bot) handleOrderUpdate(update exchange.OrderUpdate) {
+	order := update.Order
+
+	b.accountMu.Lock()
+	if b.openOrders == nil {
+		b.openOrders = make(map[int64]*exchange.Order)
+	}
+	if previous, ok := b.openOrders[order.OrderID]; ok {
+		order.Fills = append(previous.Fills, order.Fills...)
+	}
+	if order.IsOpen() {
+		b.openOrders[order.OrderID] = &order
+	} else {
+		delete(b.openOrders, order.OrderID)
+	}
+	b.accountMu.Unlock()
+
+	own := ownOrder(&order)
+	log.Printf("📨 %s %s order %d %s: %s %.8f of %.8f", order.Symbol, order.Side, order.OrderID, update.ExecutionType, order.Status, order.ExecutedQuantity, order.OrigQuantity)
+	b.emit("bot:order", fmt.Sprintf("%s %s order %d %s", order.Symbol, order.Side, order.OrderID, order.Status), map[string]interface{}{
+		"symbol":        order.Symbol,
+		"side":          string(order.Side),
+		"orderId":       order.OrderID,
+		"status":        string(order.Status),
+		"executionType": update.ExecutionType,
+		"executed":      order.ExecutedQuantity,
+		"quantity":      order.OrigQuantity,
+		"price":         order.AveragePrice(),
+		"external":      !own,
+	})
+
+	if own || order.IsOpen() || order.ExecutedQuantity == 0 {
+		return
+	}
+
+	m, ok := b.markets[order.Symbol]
+	if !ok {
+		return
+	}
+
+	if !b.config.AdoptExternalOrders {
+		log.Printf("👤 %s: %s order %d was placed outside the bot, leaving the position as is", m.symbol, order.Side, order.OrderID)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	log.Printf("👤 %s: %s order %d was placed outside the bot, updating the position", m.symbol, order.Side, order.OrderID)
+	b.applyOrder(m, &order, "External order (user data stream)")
+}
+
+// Balances returns the account balances from the user data stream, sorted by asset,
+// or nil if the stream is not running
+func (b *Bot) Balances() []exchange.Balance {
+	b.accountMu.Lock()
+	defer b.accountMu.Unlock()
+
+	if b.balances == nil {
+		return nil
+	}
+	balances := make([]exchange.Balance, 0, len(b.balances))
+	for _, balance := range b.balances {
+		balances = append(balances, balance)
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Asset < balances[j].Asset
+	})
+	return balances
+}
+
+// OpenOrders returns the open orders seen on the user data stream
+func (b *Bot) OpenOrders() []exchange.Order {
+	b.accountMu.Lock()
+	defer b.accountMu.Unlock()
+
+	orders := make([]exchange.Order, 0, len(b.openOrders))
+	for _, order := range b.openOrders {
+		orders = append(orders, *order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].OrderID < orders[j].OrderID
+	})
+	return orders
+}
@@ -0,0 +1,155 @@
+package bot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+)
+
+// orderUpdate returns a user data stream event for a BUY order of 1 BTC, with fill as its last execution
+func orderUpdate(clientOrderID string, status exchange.OrderStatus, executed, quote float64, fill *exchange.Fill) exchange.OrderUpdate {
+	update := exchange.OrderUpdate{
+		Order: exchange.Order{
+			OrderID:          7,
+			ClientOrderID:    clientOrderID,
+			Symbol:           "BTCUSDT",
+			Side:             exchange.SideBuy,
+			Type:             exchange.OrderTypeLimit,
+			Status:           status,
+			Price:            100,
+			OrigQuantity:     1,
+			ExecutedQuantity: executed,
+			QuoteQuantity:    quote,
+		},
+		ExecutionType: "NEW",
+	}
+	if fill != nil {
+		update.ExecutionType = "TRADE"
+		update.LastFill = fill
+		update.Order.Fills = []exchange.Fill{*fill}
+	}
+	return update
+}
+
+// fillOrder sends the events of an order filling in two executions, 0.4 @ 99 then 0.6 @ 100
+func fillOrder(b *Bot, clientOrderID string) {
+	b.handleOrderUpdate(orderUpdate(clientOrderID, exchange.OrderStatusNew, 0, 0, nil))
+	b.handleOrderUpdate(orderUpdate(clientOrderID, exchange.OrderStatusPartiallyFilled, 0.4, 39.6, &exchange.Fill{TradeID: 1, Price: 99, Quantity: 0.4}))
+	b.handleOrderUpdate(orderUpdate(clientOrderID, exchange.OrderStatusFilled, 1, 99.6, &exchange.Fill{TradeID: 2, Price: 100, Quantity: 0.6}))
+}
+
+func TestHandleOrderUpdate_PartialFillsAccumulate(t *testing.T) {
+	b, _, _ := newTestBot(t, &models.Config{TradingEnabled: true}, exchange.NewFakeExchange())
+
+	b.handleOrderUpdate(orderUpdate("web_1", exchange.OrderStatusNew, 0, 0, nil))
+	b.handleOrderUpdate(orderUpdate("web_1", exchange.OrderStatusPartiallyFilled, 0.4, 39.6, &exchange.Fill{TradeID: 1, Price: 99, Quantity: 0.4}))
+	b.handleOrderUpdate(orderUpdate("web_1", exchange.OrderStatusPartiallyFilled, 0.7, 69.6, &exchange.Fill{TradeID: 2, Price: 100, Quantity: 0.3}))
+
+	open := b.OpenOrders()
+	if len(open) != 1 {
+		t.Fatalf("open orders = %+v, want the partially filled one", open)
+	}
+	if got := open[0]; got.ExecutedQuantity != 0.7 || len(got.Fills) != 2 || got.Fills[0].TradeID != 1 || got.Fills[1].TradeID != 2 {
+		t.Errorf("open order = %+v, want 0.7 executed in fills 1 and 2", got)
+	}
+
+	b.handleOrderUpdate(orderUpdate("web_1", exchange.OrderStatusFilled, 1, 99.6, &exchange.Fill{TradeID: 3, Price: 100, Quantity: 0.3}))
+	if open := b.OpenOrders(); len(open) != 0 {
+		t.Errorf("open orders = %+v, want none once filled", open)
+	}
+}
+
+func TestHandleOrderUpdate(t *testing.T) {
+	tests := []struct {
+		name          string
+		clientOrderID string
+		adopt         bool
+		wantPosition  bool
+	}{
+		// The order flow that placed it records the bot's own order
+		{"own order", newClientOrderID(), true, false},
+		{"external order reported", "web_1", false, false},
+		{"external order adopted", "web_1", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, m, _ := newTestBot(t, &models.Config{TradingEnabled: true, AdoptExternalOrders: tt.adopt}, exchange.NewFakeExchange())
+
+			var external []bool
+			b.SetEventCallback(func(eventType string, message string, data map[string]interface{}) {
+				if eventType == "bot:order" {
+					external = append(external, data["external"].(bool))
+				}
+			})
+			fillOrder(b, tt.clientOrderID)
+
+			wantExternal := !strings.HasPrefix(tt.clientOrderID, clientOrderPrefix)
+			if want := []bool{wantExternal, wantExternal, wantExternal}; !reflect.DeepEqual(external, want) {
+				t.Errorf("order events external = %v, want %v", external, want)
+			}
+			if m.position.InPosition != tt.wantPosition {
+				t.Fatalf("in position = %v, want %v", m.position.InPosition, tt.wantPosition)
+			}
+			trades, err := b.db.GetRecentTrades(10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.wantPosition {
+				if len(trades) != 0 {
+					t.Errorf("trades = %+v, want none recorded", trades)
+				}
+				return
+			}
+
+			// Every execution counts, at the volume-weighted price
+			if m.position.Quantity != 1 || !almostEqual(m.position.EntryPrice, 99.6) {
+				t.Errorf("position = %+v, want 1 @ 99.6", m.position)
+			}
+			if len(trades) != 1 || trades[0].SignalReason != "External order (user data stream)" {
+				t.Fatalf("trades = %+v, want the external order", trades)
+			}
+			fills, err := b.db.GetTradeFills(trades[0].ID)
+			if err != nil || len(fills) != 2 {
+				t.Errorf("fills = %+v (%v), want both executions", fills, err)
+			}
+		})
+	}
+}
+
+func TestHandleAccountUpdate(t *testing.T) {
+	b, _, _ := newTestBot(t, &models.Config{}, exchange.NewFakeExchange())
+	if balances := b.Balances(); balances != nil {
+		t.Errorf("balances before the stream = %+v, want nil", balances)
+	}
+
+	var emitted [][]map[string]interface{}
+	b.SetEventCallback(func(eventType string, message string, data map[string]interface{}) {
+		if eventType == "bot:balance" {
+			emitted = append(emitted, data["balances"].([]map[string]interface{}))
+		}
+	})
+
+	b.handleAccountUpdate(exchange.AccountUpdate{Balances: []exchange.Balance{
+		{Asset: "USDT", Free: 900, Locked: 100},
+		{Asset: "BTC", Free: 1},
+	}})
+	// Only the changed assets are sent; the rest keep their last value
+	b.handleAccountUpdate(exchange.AccountUpdate{Balances: []exchange.Balance{
+		{Asset: "USDT", Free: 1000},
+		{Asset: "BNB"},
+	}})
+
+	want := []exchange.Balance{{Asset: "BNB"}, {Asset: "BTC", Free: 1}, {Asset: "USDT", Free: 1000}}
+	if got := b.Balances(); !reflect.DeepEqual(got, want) {
+		t.Errorf("balances = %+v, want %+v", got, want)
+	}
+
+	// Empty balances are left out of the UI event
+	if len(emitted) != 2 || len(emitted[0]) != 2 || len(emitted[1]) != 1 || emitted[1][0]["asset"] != "USDT" {
+		t.Errorf("emitted %v, want 2 then the USDT balance", emitted)
+	}
+}
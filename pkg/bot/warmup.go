@@ -0,0 +1,256 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/strategy"
+)
+
+// warmUp pre-feeds every market's strategy with recent history so it can
+// trade on the first live candle instead of waiting period+1 intervals
+func (b *Bot) warmUp(ctx context.Context) {
+	for _, symbol := range b.symbols {
+		m := b.markets[symbol]
+
+		// A restored strategy only needs the candles closed since its state was saved
+		if m.restored {
+			b.catchUp(ctx, m)
+			continue
+		}
+		if b.config.WarmupCandles <= 0 {
+			continue
+		}
+
+		// warmUpStrategy feeds the ATR along with the strategy; other paths need it loaded separately
+		_, multiTimeframe := m.strategy.(*strategy.MultiTimeframeStrategy)
+		replay := !multiTimeframe && !m.strategy.IsReady()
+		if m.atr != nil && !replay {
+			if err := b.warmUpVolatility(ctx, m); err != nil {
+				log.Printf("⚠️  %s: ATR warm-up failed, waiting for live candles: %v", symbol, err)
+			}
+		}
+
+		var err error
+		if mts, ok := m.strategy.(*strategy.MultiTimeframeStrategy); ok {
+			err = b.warmUpMultiTimeframe(ctx, m, mts.GetMultiTimeframeManager())
+		} else if !m.strategy.IsReady() {
+			err = b.warmUpStrategy(ctx, m)
+		} else {
+			continue // Nothing to warm up (e.g. DCA)
+		}
+
+		if err != nil {
+			log.Printf("⚠️  %s: Warm-up failed, waiting for live candles: %v", symbol, err)
+			continue
+		}
+
+		log.Printf("🔥 %s: %s strategy warmed up (ready: %v)", symbol, m.strategy.Name(), m.strategy.IsReady())
+		b.emit("bot:status", fmt.Sprintf("%s: Strategy warmed up from history", symbol), map[string]interface{}{
+			"symbol": symbol,
+			"ready":  m.strategy.IsReady(),
+		})
+	}
+}
+
+// warmUpStrategy replays closed candles of the bot's interval through the strategy
+func (b *Bot) warmUpStrategy(ctx context.Context, m *market) error {
+	klines, err := b.loadKlines(ctx, m.symbol, b.config.KlineInterval(), b.config.WarmupCandles)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	fed := 0
+	for _, k := range klines {
+		if !k.IsClosed(now) || !k.OpenTime.After(m.lastCandle) {
+			continue
+		}
+		if err := strategy.UpdateWithCandle(m.strategy, klineCandle(k)); err != nil {
+			return fmt.Errorf("failed to update strategy: %w", err)
+		}
+		m.updateVolatility(k.High, k.Low, k.Close)
+		m.lastCandle = k.OpenTime
+		fed++
+	}
+
+	if b.paper != nil && fed > 0 {
+		b.paper.UpdatePrice(m.symbol, klines[len(klines)-1].Close)
+	}
+
+	log.Printf("📜 %s: Replayed %d historical %s candles", m.symbol, fed, b.config.KlineInterval())
+	return nil
+}
+
+// warmUpVolatility primes the ATR used for risk-per-trade sizing from closed candles
+func (b *Bot) warmUpVolatility(ctx context.Context, m *market) error {
+	klines, err := b.loadKlines(ctx, m.symbol, b.config.KlineInterval(), b.config.Sizing.ATRPeriod+2) // +1 previous close, +1 open candle
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, k := range klines {
+		if k.IsClosed(now) {
+			m.updateVolatility(k.High, k.Low, k.Close)
+		}
+	}
+	return nil
+}
+
+// warmUpMultiTimeframe loads each timeframe from its own klines, so the 1d leg
+// does not need days of 1m candles
+func (b *Bot) warmUpMultiTimeframe(ctx context.Context, m *market, mgr *strategy.MultiTimeframeManager) error {
+	now := time.Now()
+
+	for _, tf := range mgr.Timeframes() {
+		klines, err := b.loadKlines(ctx, m.symbol, tf.String(), mgr.MaxCandles()+1)
+		if err != nil {
+			return fmt.Errorf("%s: %w", tf, err)
+		}
+
+		candles := make([]strategy.OHLCV, 0, len(klines))
+		var current *strategy.OHLCV
+		for _, k := range klines {
+			candle := klineCandle(k)
+			if k.IsClosed(now) {
+				candles = append(candles, candle)
+			} else {
+				current = &candle
+			}
+		}
+
+		if err := mgr.Preload(tf, candles, current); err != nil {
+			return err
+		}
+		log.Printf("📜 %s: Preloaded %d %s candles", m.symbol, len(candles), tf)
+	}
+
+	return nil
+}
+
+// klineCandle converts an exchange kline to the bar type strategies are fed
+func klineCandle(k exchange.Kline) strategy.OHLCV {
+	return strategy.OHLCV{
+		Timestamp:      k.OpenTime,
+		Open:           k.Open,
+		High:           k.High,
+		Low:            k.Low,
+		Close:          k.Close,
+		Volume:         k.Volume,
+		Trades:         k.Trades,
+		TakerBuyVolume: k.TakerBuyVolume,
+	}
+}
+
+// loadKlines fetches recent candles over REST and caches them in the database.
+// When the exchange is unreachable it falls back to the cached candles.
+func (b *Bot) loadKlines(ctx context.Context, symbol, interval string, limit int) ([]exchange.Kline, error) {
+	klines, err := b.exchange.GetKlines(ctx, symbol, interval, limit)
+	if err == nil && len(klines) > 0 {
+		b.cacheKlines(symbol, interval, klines)
+		return klines, nil
+	}
+
+	duration := intervalDuration(interval)
+	end := time.Now()
+	start := end.Add(-time.Duration(limit) * duration)
+	cached, dbErr := b.db.GetCandles(symbol, interval, start, end)
+	if dbErr != nil || len(cached) == 0 {
+		return nil, fmt.Errorf("failed to fetch klines (%v) and no cached candles available", err)
+	}
+
+	log.Printf("💾 %s: Using %d cached %s candles (exchange unavailable: %v)", symbol, len(cached), interval, err)
+	klines = make([]exchange.Kline, len(cached))
+	for i, c := range cached {
+		klines[i] = exchange.Kline{
+			OpenTime:  c.OpenTime,
+			CloseTime: c.OpenTime.Add(duration - time.Millisecond),
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		}
+	}
+	return klines, nil
+}
+
+// cacheKlines stores closed klines so later restarts can warm up offline
+func (b *Bot) cacheKlines(symbol, interval string, klines []exchange.Kline) {
+	now := time.Now()
+	candles := make([]database.Candle, 0, len(klines))
+	for _, k := range klines {
+		if !k.IsClosed(now) {
+			continue
+		}
+		candles = append(candles, database.Candle{
+			Symbol:   symbol,
+			Interval: interval,
+			OpenTime: k.OpenTime,
+			Open:     k.Open,
+			High:     k.High,
+			Low:      k.Low,
+			Close:    k.Close,
+			Volume:   k.Volume,
+		})
+	}
+
+	if err := b.db.InsertCandles(candles); err != nil {
+		log.Printf("⚠️  %s: Failed to cache %s candles: %v", symbol, interval, err)
+	}
+}
+
+// nextCandleOpen returns when the candle after the one opening at open opens. Monthly
+// candles open on the first of each calendar month (UTC), so they are not a fixed duration apart.
+func nextCandleOpen(interval string, open time.Time) time.Time {
+	if interval == "1M" {
+		return open.UTC().AddDate(0, 1, 0)
+	}
+	return open.Add(intervalDuration(interval))
+}
+
+// intervalDuration converts a Binance kline interval to a duration ("1M" approximated as
+// 30 days; see nextCandleOpen)
+func intervalDuration(interval string) time.Duration {
+	switch interval {
+	case "1s":
+		return time.Second
+	case "1m":
+		return time.Minute
+	case "3m":
+		return 3 * time.Minute
+	case "5m":
+		return 5 * time.Minute
+	case "15m":
+		return 15 * time.Minute
+	case "30m":
+		return 30 * time.Minute
+	case "1h":
+		return time.Hour
+	case "2h":
+		return 2 * time.Hour
+	case "4h":
+		return 4 * time.Hour
+	case "6h":
+		return 6 * time.Hour
+	case "8h":
+		return 8 * time.Hour
+	case "12h":
+		return 12 * time.Hour
+	case "1d":
+		return 24 * time.Hour
+	case "3d":
+		return 3 * 24 * time.Hour
+	case "1w":
+		return 7 * 24 * time.Hour
+	case "1M":
+		return 30 * 24 * time.Hour
+	default:
+		return time.Minute
+	}
+}
@@ -0,0 +1,161 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/exchange"
+	"rsi-bot/pkg/models"
+	"rsi-bot/pkg/strategy"
+)
+
+// countingStrategy is a stub strategy that is never ready and records the candles it is fed
+type countingStrategy struct {
+	stubStrategy
+	fed []time.Time
+}
+
+func (s *countingStrategy) IsReady() bool { return false }
+
+func (s *countingStrategy) Update(price, volume float64, timestamp time.Time) error {
+	s.fed = append(s.fed, timestamp)
+	return nil
+}
+
+// testKlines returns n closed klines of the given interval, the last one closing just
+// before now, followed by the open kline of the current interval
+func testKlines(interval time.Duration, n int) []exchange.Kline {
+	current := time.Now().Truncate(interval)
+	klines := make([]exchange.Kline, 0, n+1)
+	for i := n; i >= 0; i-- {
+		open := current.Add(-time.Duration(i) * interval)
+		price := 100 + float64(i%5)
+		klines = append(klines, exchange.Kline{
+			OpenTime:  open,
+			CloseTime: open.Add(interval - time.Millisecond),
+			Open:      price,
+			High:      price + 1,
+			Low:       price - 1,
+			Close:     price,
+			Volume:    10,
+		})
+	}
+	// Still open however long the test takes
+	klines[n].CloseTime = time.Now().Add(interval)
+	return klines
+}
+
+// newWarmUpBot creates a bot warming up from 50 candles, with a counting strategy on BTCUSDT
+func newWarmUpBot(t *testing.T, fake *exchange.FakeExchange) (*Bot, *market, *countingStrategy) {
+	t.Helper()
+	b, m, _ := newTestBot(t, &models.Config{WarmupCandles: 50}, fake)
+	counting := &countingStrategy{}
+	m.strategy = counting
+	return b, m, counting
+}
+
+func TestWarmUp_FromREST(t *testing.T) {
+	fake := newFakeExchange()
+	klines := testKlines(time.Minute, 30)
+	fake.SetKlines("BTCUSDT", "1m", klines)
+	b, m, counting := newWarmUpBot(t, fake)
+
+	b.warmUp(context.Background())
+
+	// The open kline is neither fed nor cached
+	lastClosed := klines[len(klines)-2].OpenTime
+	if len(counting.fed) != 30 || !counting.fed[0].Equal(klines[0].OpenTime) || !m.lastCandle.Equal(lastClosed) {
+		t.Errorf("fed %d candles up to %s, want 30 up to %s", len(counting.fed), m.lastCandle, lastClosed)
+	}
+	cached, err := b.db.GetCandles("BTCUSDT", "1m", klines[0].OpenTime, klines[len(klines)-1].OpenTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cached) != 30 {
+		t.Errorf("cached %d candles, want the 30 closed ones", len(cached))
+	}
+}
+
+func TestWarmUp_SkipsCandlesAlreadyFed(t *testing.T) {
+	fake := newFakeExchange()
+	klines := testKlines(time.Minute, 30)
+	fake.SetKlines("BTCUSDT", "1m", klines)
+	b, m, counting := newWarmUpBot(t, fake)
+
+	m.lastCandle = klines[19].OpenTime
+	b.warmUp(context.Background())
+
+	if len(counting.fed) != 10 || !counting.fed[0].Equal(klines[20].OpenTime) {
+		t.Errorf("fed %v, want the 10 candles after %s", counting.fed, m.lastCandle)
+	}
+}
+
+func TestWarmUp_FallsBackToCachedCandles(t *testing.T) {
+	fake := newFakeExchange()
+	b, m, counting := newWarmUpBot(t, fake)
+
+	klines := testKlines(time.Minute, 20)
+	candles := make([]database.Candle, 0, len(klines)-1)
+	for _, k := range klines[:len(klines)-1] {
+		candles = append(candles, database.Candle{Symbol: "BTCUSDT", Interval: "1m", OpenTime: k.OpenTime, Open: k.Open, High: k.High, Low: k.Low, Close: k.Close, Volume: k.Volume})
+	}
+	if err := b.db.InsertCandles(candles); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Err = errors.New("exchange unreachable")
+	b.warmUp(context.Background())
+
+	if len(counting.fed) != 20 || !m.lastCandle.Equal(klines[19].OpenTime) {
+		t.Errorf("fed %d cached candles up to %s, want 20 up to %s", len(counting.fed), m.lastCandle, klines[19].OpenTime)
+	}
+}
+
+func TestWarmUp_NoHistory(t *testing.T) {
+	fake := newFakeExchange()
+	fake.Err = errors.New("exchange unreachable")
+	b, m, counting := newWarmUpBot(t, fake)
+
+	b.warmUp(context.Background())
+
+	if len(counting.fed) != 0 || !m.lastCandle.IsZero() {
+		t.Errorf("fed %d candles without the exchange or a cache, want none", len(counting.fed))
+	}
+}
+
+func TestWarmUp_MultiTimeframePreload(t *testing.T) {
+	fake := newFakeExchange()
+	b, m, _ := newWarmUpBot(t, fake)
+	mts, err := strategy.NewMultiTimeframeStrategy(strategy.DefaultMultiTimeframeStrategyConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.strategy = mts
+	mgr := mts.GetMultiTimeframeManager()
+
+	// More history than the manager keeps for the fastest timeframe, a few bars for the slowest
+	closed := map[strategy.Timeframe]int{strategy.Timeframe5m: mgr.MaxCandles() + 50, strategy.Timeframe1h: 40, strategy.Timeframe1d: 5}
+	open := make(map[strategy.Timeframe]time.Time)
+	for tf, n := range closed {
+		duration, _ := tf.GetDuration()
+		klines := testKlines(duration, n)
+		fake.SetKlines("BTCUSDT", tf.String(), klines)
+		open[tf] = klines[n].OpenTime
+	}
+
+	b.warmUp(context.Background())
+
+	for tf, n := range closed {
+		data := mgr.TimeframeData[tf]
+		want := min(n, mgr.MaxCandles())
+		if data.GetCandleCount() != want {
+			t.Errorf("%s: preloaded %d candles, want %d", tf, data.GetCandleCount(), want)
+		}
+		if current, ok := data.GetCurrentCandle(); !ok || !current.Timestamp.Equal(open[tf]) {
+			t.Errorf("%s: current bar = %v, want the open kline", tf, current)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"rsi-bot/pkg/models"
 
@@ -27,6 +30,44 @@ func Load(configPath string) (*models.Config, error) {
 	viper.SetDefault("oversold_level", 30.0)
 	viper.SetDefault("quantity", 150000.0)
 	viper.SetDefault("trading_enabled", false)
+	viper.SetDefault("adopt_external_orders", false)
+	viper.SetDefault("interval", "1m")
+	viper.SetDefault("warmup_candles", 500)
+
+	// Risk exit defaults (strategy.DefaultRiskConfig)
+	viper.SetDefault("risk.enabled", false)
+	viper.SetDefault("risk.stop_loss_percent", 3.0)
+	viper.SetDefault("risk.take_profit_percent", 6.0)
+	viper.SetDefault("risk.risk_reward_ratio", 2.0)
+	viper.SetDefault("risk.trailing_stop", true)
+	viper.SetDefault("risk.trailing_activation_percent", 4.0)
+	viper.SetDefault("risk.trailing_distance_percent", 2.0)
+
+	// Position sizing defaults (fixed quantity keeps the old behaviour)
+	viper.SetDefault("sizing.mode", models.SizingFixedQuantity)
+	viper.SetDefault("sizing.equity_percent", 10.0)
+	viper.SetDefault("sizing.risk_percent", 1.0)
+	viper.SetDefault("sizing.max_position_percent", 25.0)
+	viper.SetDefault("sizing.atr_period", 14)
+	viper.SetDefault("sizing.atr_multiplier", 2.0)
+
+	// Order execution defaults (market orders keep the old behaviour)
+	viper.SetDefault("orders.type", models.OrderTypeMarket)
+	viper.SetDefault("orders.time_in_force", "GTC")
+	viper.SetDefault("orders.timeout", "2m")
+	viper.SetDefault("orders.on_timeout", models.OnTimeoutCancel)
+
+	// Stream reconnect defaults (1s doubling up to 1m, reconnect after 3 silent intervals)
+	viper.SetDefault("stream.endpoints", models.DefaultStreamEndpoints)
+	viper.SetDefault("stream.reconnect_strategy", "exponential")
+	viper.SetDefault("stream.reconnect_base_delay", "1s")
+	viper.SetDefault("stream.reconnect_max_delay", "1m")
+	viper.SetDefault("stream.stale_intervals", 3)
+
+	// Paper trading defaults (Binance spot fees)
+	viper.SetDefault("paper.initial_balances", map[string]float64{"USDT": 1000})
+	viper.SetDefault("paper.maker_fee_percent", 0.1)
+	viper.SetDefault("paper.taker_fee_percent", 0.1)
 
 	// Try to read config file
 	if err := viper.ReadInConfig(); err != nil {
@@ -41,9 +82,113 @@ func Load(configPath string) (*models.Config, error) {
 		return nil, err
 	}
 
+	if !isValidInterval(config.Interval) {
+		return nil, fmt.Errorf("invalid interval %q (valid: %s)", config.Interval, strings.Join(models.ValidIntervals, ", "))
+	}
+
+	if err := validateSizing(config.Sizing); err != nil {
+		return nil, err
+	}
+
+	if err := validateOrders(config.Orders); err != nil {
+		return nil, err
+	}
+
+	if err := validateStream(config.Stream); err != nil {
+		return nil, err
+	}
+
+	if config.Signals.MinStrength < 0 || config.Signals.MinStrength > 1 {
+		return nil, fmt.Errorf("signals.min_strength must be between 0 and 1, got %.2f", config.Signals.MinStrength)
+	}
+
 	return &config, nil
 }
 
+// validateSizing checks that the selected sizing mode has the parameter it needs
+func validateSizing(sizing models.SizingConfig) error {
+	switch sizing.Mode {
+	case models.SizingFixedQuantity:
+	case models.SizingQuoteAmount:
+		if sizing.QuoteAmount <= 0 {
+			return fmt.Errorf("sizing.quote_amount must be positive for mode %q", sizing.Mode)
+		}
+	case models.SizingPercentEquity:
+		if sizing.EquityPercent <= 0 || sizing.EquityPercent > 100 {
+			return fmt.Errorf("sizing.equity_percent must be in (0, 100] for mode %q", sizing.Mode)
+		}
+	case models.SizingRiskPerTrade:
+		if sizing.RiskPercent <= 0 || sizing.MaxPositionPercent <= 0 {
+			return fmt.Errorf("sizing.risk_percent and sizing.max_position_percent must be positive for mode %q", sizing.Mode)
+		}
+		if sizing.ATRPeriod <= 0 || sizing.ATRMultiplier <= 0 {
+			return fmt.Errorf("sizing.atr_period and sizing.atr_multiplier must be positive for mode %q", sizing.Mode)
+		}
+	default:
+		return fmt.Errorf("invalid sizing.mode %q (valid: %s, %s, %s, %s)", sizing.Mode,
+			models.SizingFixedQuantity, models.SizingQuoteAmount, models.SizingPercentEquity, models.SizingRiskPerTrade)
+	}
+	return nil
+}
+
+// validateOrders checks the order execution settings
+func validateOrders(orders models.OrderConfig) error {
+	if orders.Type != models.OrderTypeMarket && orders.Type != models.OrderTypeLimit {
+		return fmt.Errorf("invalid orders.type %q (valid: %s, %s)", orders.Type, models.OrderTypeMarket, models.OrderTypeLimit)
+	}
+	switch orders.TimeInForce {
+	case "GTC", "IOC", "FOK":
+	default:
+		return fmt.Errorf("invalid orders.time_in_force %q (valid: GTC, IOC, FOK)", orders.TimeInForce)
+	}
+	if orders.PriceOffsetPercent < 0 {
+		return fmt.Errorf("orders.price_offset_percent cannot be negative")
+	}
+	if timeout, err := time.ParseDuration(orders.Timeout); err != nil || timeout <= 0 {
+		return fmt.Errorf("invalid orders.timeout %q", orders.Timeout)
+	}
+	if orders.OnTimeout != models.OnTimeoutCancel && orders.OnTimeout != models.OnTimeoutMarket {
+		return fmt.Errorf("invalid orders.on_timeout %q (valid: %s, %s)", orders.OnTimeout, models.OnTimeoutCancel, models.OnTimeoutMarket)
+	}
+	return nil
+}
+
+// validateStream checks the WebSocket endpoints and reconnect settings
+func validateStream(stream models.StreamConfig) error {
+	for _, endpoint := range stream.Endpoints {
+		if !strings.HasPrefix(endpoint, "wss://") && !strings.HasPrefix(endpoint, "ws://") {
+			return fmt.Errorf("invalid stream endpoint %q (must start with wss:// or ws://)", endpoint)
+		}
+	}
+	switch stream.ReconnectStrategy {
+	case "immediate", "linear", "exponential":
+	default:
+		return fmt.Errorf("invalid stream.reconnect_strategy %q (valid: immediate, linear, exponential)", stream.ReconnectStrategy)
+	}
+	baseDelay, err := time.ParseDuration(stream.ReconnectBaseDelay)
+	if err != nil || baseDelay <= 0 {
+		return fmt.Errorf("invalid stream.reconnect_base_delay %q", stream.ReconnectBaseDelay)
+	}
+	maxDelay, err := time.ParseDuration(stream.ReconnectMaxDelay)
+	if err != nil || maxDelay < baseDelay {
+		return fmt.Errorf("invalid stream.reconnect_max_delay %q (must be at least reconnect_base_delay)", stream.ReconnectMaxDelay)
+	}
+	if stream.StaleIntervals < 0 {
+		return fmt.Errorf("stream.stale_intervals cannot be negative")
+	}
+	return nil
+}
+
+// isValidInterval reports whether Binance streams klines at this interval
+func isValidInterval(interval string) bool {
+	for _, valid := range models.ValidIntervals {
+		if interval == valid {
+			return true
+		}
+	}
+	return false
+}
+
 /*
 Configuration Loader
 
@@ -59,11 +204,30 @@ Key Features:
 
 Configuration Parameters:
 - symbol: Trading pair (default: "SHIBUSDT")
+- symbols: List of trading pairs streamed over one connection (overrides symbol)
+- interval: Kline interval, e.g. "1m", "5m", "1h" (default: "1m")
+- quantities: Per-symbol order sizes (default: quantity)
+- warmup_candles: Historical candles replayed into strategies at startup (default: 500, 0 disables)
 - rsi_period: Number of periods for RSI calculation (default: 14)
 - overbought_level: RSI threshold for sell signals (default: 70.0)
 - oversold_level: RSI threshold for buy signals (default: 30.0)
 - quantity: Base order size (default: 150000.0)
 - trading_enabled: Switch between live/paper trading (default: false)
+- adopt_external_orders: Apply fills of orders placed outside the bot (e.g. on the
+  Binance website) to its positions instead of only reporting them (default: false)
+- risk: Stop-loss, take-profit and trailing stop enforced on open positions
+  (default: disabled; when enabled 3% stop, 2:1 reward/risk target, trail 2% after +4%)
+- sizing: How BUY quantities are computed - fixed_quantity, quote_amount,
+  percent_equity or risk_per_trade (ATR stop); rounded to the LOT_SIZE step
+  (default: fixed_quantity)
+- orders: Market orders, or limit orders (GTC/IOC/FOK, post-only) at a price
+  offset that are cancelled or filled at market after a timeout
+  (default: market)
+- stream: Kline WebSocket endpoints, tried healthiest first, with reconnect backoff
+  (immediate, linear or exponential) and a reconnect after N silent intervals
+  (default: 3 Binance endpoints, 1s doubling up to 1m, 3 intervals)
+- paper: Simulated account used when trading is disabled
+  (default: 1000 USDT, 0.1% maker/taker fees)
 
 Usage:
 1. Call Load(configPath) with optional config file path
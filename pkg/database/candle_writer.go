@@ -0,0 +1,114 @@
+package database
+
+import (
+	"sync"
+	"time"
+)
+
+// maxPendingBatches bounds how many batches a CandleWriter keeps while writes keep failing
+const maxPendingBatches = 100
+
+// CandleWriter buffers candles and stores them with InsertCandles, one transaction
+// per batch instead of one per candle. Batches are written when full and every
+// flush interval; Close writes whatever is left.
+type CandleWriter struct {
+	db            *DB
+	batchSize     int
+	flushInterval time.Duration
+	onError       func(error) // Called with write errors from the background flush (optional)
+
+	mu      sync.Mutex
+	pending []Candle
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewCandleWriter starts a writer that stores candles in batches of batchSize,
+// flushing partial batches every flushInterval
+func (db *DB) NewCandleWriter(batchSize int, flushInterval time.Duration, onError func(error)) *CandleWriter {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	w := &CandleWriter{
+		db:            db,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		onError:       onError,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Add queues a candle, writing the batch once it is full
+func (w *CandleWriter) Add(candle Candle) {
+	w.mu.Lock()
+	w.pending = append(w.pending, candle)
+	full := len(w.pending) >= w.batchSize
+	w.mu.Unlock()
+
+	if full {
+		w.report(w.Flush())
+	}
+}
+
+// Flush writes every queued candle. Candles that fail to write stay queued for the next flush.
+func (w *CandleWriter) Flush() error {
+	w.mu.Lock()
+	batch := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := w.db.InsertCandles(batch); err != nil {
+		w.mu.Lock()
+		w.pending = append(batch, w.pending...)
+		if limit := w.batchSize * maxPendingBatches; len(w.pending) > limit {
+			w.pending = w.pending[len(w.pending)-limit:] // Drop the oldest
+		}
+		w.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Close stops the background flush and writes the remaining candles
+func (w *CandleWriter) Close() error {
+	w.closeOnce.Do(func() { close(w.stop) })
+	<-w.done
+	return w.Flush()
+}
+
+// run flushes partial batches until Close
+func (w *CandleWriter) run() {
+	defer close(w.done)
+	if w.flushInterval <= 0 {
+		<-w.stop
+		return
+	}
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.report(w.Flush())
+		}
+	}
+}
+
+func (w *CandleWriter) report(err error) {
+	if err != nil && w.onError != nil {
+		w.onError(err)
+	}
+}
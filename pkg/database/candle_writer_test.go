@@ -0,0 +1,180 @@
+package database
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCandles returns n 1m BTCUSDT candles opening from 2024-01-01, closing at 100 + i
+func testCandles(n int) []Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]Candle, n)
+	for i := range candles {
+		price := 100 + float64(i)
+		candles[i] = Candle{Symbol: "BTCUSDT", Interval: "1m", OpenTime: start.Add(time.Duration(i) * time.Minute), Open: price, High: price, Low: price, Close: price, Volume: 1}
+	}
+	return candles
+}
+
+// storedCandles returns every stored 1m BTCUSDT candle
+func storedCandles(t *testing.T, db *DB) []Candle {
+	t.Helper()
+	candles, err := db.GetCandles("BTCUSDT", "1m", time.Time{}, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return candles
+}
+
+func TestCandleWriter_Batches(t *testing.T) {
+	db := newTestDB(t)
+	w := db.NewCandleWriter(3, 0, nil)
+	candles := testCandles(4)
+
+	for i, c := range candles {
+		w.Add(c)
+		// A full batch is written as it fills, the rest waits
+		want := 0
+		if i >= 2 {
+			want = 3
+		}
+		if got := len(storedCandles(t, db)); got != want {
+			t.Errorf("after %d candle(s): %d stored, want %d", i+1, got, want)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(storedCandles(t, db)); got != 4 {
+		t.Errorf("after Close: %d stored, want 4", got)
+	}
+	// Closing again is harmless
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestCandleWriter_FlushInterval(t *testing.T) {
+	db := newTestDB(t)
+	w := db.NewCandleWriter(100, 10*time.Millisecond, nil)
+	defer w.Close()
+
+	w.Add(testCandles(1)[0])
+	deadline := time.Now().Add(5 * time.Second)
+	for len(storedCandles(t, db)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("partial batch never flushed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCandleWriter_RequeuesFailedBatches(t *testing.T) {
+	db := newTestDB(t)
+	// Every insert fails until the trigger is dropped
+	if _, err := db.conn.Exec(`CREATE TRIGGER fail_candles BEFORE INSERT ON candles BEGIN SELECT RAISE(ABORT, 'disk full'); END`); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var reported []error
+	batchSize := 2
+	w := db.NewCandleWriter(batchSize, 0, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	})
+
+	limit := batchSize * maxPendingBatches
+	candles := testCandles(limit + 50)
+	for _, c := range candles {
+		w.Add(c)
+	}
+	if err := w.Flush(); err == nil {
+		t.Fatal("Flush succeeded while inserts fail")
+	}
+	mu.Lock()
+	// Each Add from the second one on tried to write the full queue
+	if len(reported) != len(candles)-1 {
+		t.Errorf("%d errors reported, want %d", len(reported), len(candles)-1)
+	}
+	mu.Unlock()
+
+	if _, err := db.conn.Exec(`DROP TRIGGER fail_candles`); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The queue kept the newest candles, in order
+	stored := storedCandles(t, db)
+	if len(stored) != limit {
+		t.Fatalf("%d stored, want the last %d", len(stored), limit)
+	}
+	for i, c := range stored {
+		if want := candles[len(candles)-limit+i]; !c.OpenTime.Equal(want.OpenTime) || c.Close != want.Close {
+			t.Fatalf("candle %d = %+v, want %+v", i, c, want)
+		}
+	}
+}
+
+func TestInsertCandles_Upsert(t *testing.T) {
+	db := newTestDB(t)
+	candles := testCandles(3)
+	if err := db.InsertCandles(candles); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same open time, given in another time zone, replaces the stored candle
+	updated := candles[1]
+	updated.OpenTime = updated.OpenTime.In(time.FixedZone("UTC+2", 2*60*60))
+	updated.Close, updated.Volume = 150, 7
+	other := candles[1]
+	other.Interval = "5m"
+	if err := db.InsertCandles([]Candle{updated, other}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := storedCandles(t, db)
+	if len(stored) != 3 {
+		t.Fatalf("%d stored, want 3", len(stored))
+	}
+	if c := stored[1]; !c.OpenTime.Equal(candles[1].OpenTime) || c.Close != 150 || c.Volume != 7 {
+		t.Errorf("replaced candle = %+v, want close 150 and volume 7", c)
+	}
+	if c := stored[0]; !c.OpenTime.Equal(candles[0].OpenTime) || c.Close != candles[0].Close {
+		t.Errorf("first candle = %+v, want %+v", c, candles[0])
+	}
+
+	// The range is inclusive and limited to the symbol and interval
+	inRange, err := db.GetCandles("BTCUSDT", "1m", candles[1].OpenTime, candles[2].OpenTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inRange) != 2 || !inRange[0].OpenTime.Equal(candles[1].OpenTime) {
+		t.Errorf("range = %+v, want the last 2 candles", inRange)
+	}
+	if fiveMinute, err := db.GetCandles("BTCUSDT", "5m", time.Time{}, candles[2].OpenTime); err != nil || len(fiveMinute) != 1 {
+		t.Errorf("5m candles = %+v (%v), want 1", fiveMinute, err)
+	}
+	if none, err := db.GetCandles("ETHUSDT", "1m", time.Time{}, candles[2].OpenTime); err != nil || len(none) != 0 {
+		t.Errorf("ETHUSDT candles = %+v (%v), want none", none, err)
+	}
+}
+
+func TestInsertCandles_RollsBackOnError(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.conn.Exec(`CREATE TRIGGER fail_third BEFORE INSERT ON candles WHEN NEW.close = 102 BEGIN SELECT RAISE(ABORT, 'bad candle'); END`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.InsertCandles(testCandles(4)); err == nil {
+		t.Fatal("InsertCandles succeeded with a failing candle")
+	}
+	if stored := storedCandles(t, db); len(stored) != 0 {
+		t.Errorf("%d stored, want none from the failed batch", len(stored))
+	}
+}
@@ -70,6 +70,8 @@ func (db *DB) initTables() error {
 		profit_loss REAL,
 		profit_loss_percent REAL,
 		related_buy_id INTEGER,
+		position_side TEXT,
+		signal_strength REAL,
 		FOREIGN KEY (related_buy_id) REFERENCES trades(id)
 	);
 
@@ -87,32 +89,182 @@ func (db *DB) initTables() error {
 		profit_loss_percent REAL,
 		buy_trade_id INTEGER NOT NULL,
 		sell_trade_id INTEGER,
+		stop_loss_price REAL,
+		take_profit_price REAL,
+		highest_price REAL,
+		trailing_active BOOLEAN NOT NULL DEFAULT 0,
+		orphaned BOOLEAN NOT NULL DEFAULT 0,
+		side TEXT NOT NULL DEFAULT 'long',
+		realized_pnl REAL,
+		closed_quantity REAL,
 		FOREIGN KEY (buy_trade_id) REFERENCES trades(id),
 		FOREIGN KEY (sell_trade_id) REFERENCES trades(id)
 	);
 
+	CREATE TABLE IF NOT EXISTS trade_fills (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		trade_id INTEGER NOT NULL,
+		exchange_trade_id INTEGER,
+		price REAL NOT NULL,
+		quantity REAL NOT NULL,
+		commission REAL NOT NULL DEFAULT 0,
+		commission_asset TEXT,
+		FOREIGN KEY (trade_id) REFERENCES trades(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS candles (
+		symbol TEXT NOT NULL,
+		interval TEXT NOT NULL,
+		open_time DATETIME NOT NULL,
+		open REAL NOT NULL,
+		high REAL NOT NULL,
+		low REAL NOT NULL,
+		close REAL NOT NULL,
+		volume REAL NOT NULL,
+		PRIMARY KEY (symbol, interval, open_time)
+	);
+
+	CREATE TABLE IF NOT EXISTS strategy_state (
+		symbol TEXT PRIMARY KEY,
+		strategy TEXT NOT NULL,
+		interval TEXT NOT NULL,
+		state TEXT NOT NULL,
+		last_candle DATETIME NOT NULL,
+		saved_at DATETIME NOT NULL
+	);
+
 	CREATE INDEX IF NOT EXISTS idx_trades_timestamp ON trades(timestamp);
 	CREATE INDEX IF NOT EXISTS idx_trades_symbol ON trades(symbol);
+	CREATE INDEX IF NOT EXISTS idx_trade_fills_trade_id ON trade_fills(trade_id);
 	CREATE INDEX IF NOT EXISTS idx_positions_symbol ON positions(symbol);
 	CREATE INDEX IF NOT EXISTS idx_positions_is_open ON positions(is_open);
 	`
 
-	_, err := db.conn.Exec(schema)
-	return err
+	if _, err := db.conn.Exec(schema); err != nil {
+		return err
+	}
+
+	return db.migrate()
 }
 
-// InsertTrade inserts a new trade into the database
-func (db *DB) InsertTrade(trade *Trade) (int64, error) {
-	query := `
+// migrate adds columns introduced after a database was first created
+func (db *DB) migrate() error {
+	columns := []struct{ table, name, definition string }{
+		{"trades", "commission", "REAL"},
+		{"trades", "commission_asset", "TEXT"},
+		{"positions", "stop_loss_price", "REAL"},
+		{"positions", "take_profit_price", "REAL"},
+		{"positions", "highest_price", "REAL"},
+		{"positions", "trailing_active", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"positions", "orphaned", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"positions", "side", "TEXT NOT NULL DEFAULT 'long'"},
+		{"positions", "realized_pnl", "REAL"},
+		{"positions", "closed_quantity", "REAL"},
+		{"trades", "position_side", "TEXT"},
+		{"trades", "signal_strength", "REAL"},
+	}
+
+	for _, col := range columns {
+		exists, err := db.columnExists(col.table, col.name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.conn.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", col.table, col.name, col.definition)); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", col.table, col.name, err)
+		}
+	}
+
+	return nil
+}
+
+// columnExists reports whether a table has a column
+func (db *DB) columnExists(table, column string) (bool, error) {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to read schema of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return false, fmt.Errorf("failed to scan schema of %s: %w", table, err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// insertTradeQuery and insertFillQuery are shared by the single and bulk trade inserts
+const (
+	insertTradeQuery = `
 		INSERT INTO trades (
 			symbol, side, quantity, price, total, strategy,
 			indicator_values, signal_reason, paper_trade, timestamp,
-			binance_order_id, profit_loss, profit_loss_percent, related_buy_id
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			binance_order_id, profit_loss, profit_loss_percent, related_buy_id,
+			commission, commission_asset, position_side, signal_strength
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	insertFillQuery = `
+		INSERT INTO trade_fills (
+			trade_id, exchange_trade_id, price, quantity, commission, commission_asset
+		) VALUES (?, ?, ?, ?, ?, ?)
 	`
+)
 
-	result, err := db.conn.Exec(
-		query,
+// InsertTrade inserts a new trade and its fills into the database
+func (db *DB) InsertTrade(trade *Trade) (int64, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := insertTrade(tx, trade)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+// InsertTradesInTransaction inserts multiple trades in a single transaction
+// This is much faster and avoids database lock issues when inserting bulk data
+func (db *DB) InsertTradesInTransaction(trades []*Trade) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Rollback if we don't commit
+
+	for _, trade := range trades {
+		if _, err := insertTrade(tx, trade); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertTrade writes a trade and its fills within tx and returns the trade's ID
+func insertTrade(tx *sql.Tx, trade *Trade) (int64, error) {
+	result, err := tx.Exec(
+		insertTradeQuery,
 		trade.Symbol,
 		trade.Side,
 		trade.Quantity,
@@ -127,6 +279,10 @@ func (db *DB) InsertTrade(trade *Trade) (int64, error) {
 		nullFloat64(trade.ProfitLoss),
 		nullFloat64(trade.ProfitLossPercent),
 		nullInt64(trade.RelatedBuyID),
+		nullFloat64(trade.Commission),
+		nullString(trade.CommissionAsset),
+		nullString(trade.PositionSide),
+		nullFloat64(trade.SignalStrength),
 	)
 
 	if err != nil {
@@ -138,59 +294,55 @@ func (db *DB) InsertTrade(trade *Trade) (int64, error) {
 		return 0, fmt.Errorf("failed to get last insert ID: %w", err)
 	}
 
+	for _, fill := range trade.Fills {
+		_, err := tx.Exec(
+			insertFillQuery,
+			id,
+			nullInt64(fill.ExchangeTradeID),
+			fill.Price,
+			fill.Quantity,
+			fill.Commission,
+			nullString(fill.CommissionAsset),
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert trade fill: %w", err)
+		}
+	}
+
 	return id, nil
 }
 
-// InsertTradesInTransaction inserts multiple trades in a single transaction
-// This is much faster and avoids database lock issues when inserting bulk data
-func (db *DB) InsertTradesInTransaction(trades []*Trade) error {
-	tx, err := db.conn.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback() // Rollback if we don't commit
-
+// GetTradeFills retrieves the individual fills of a trade, in execution order
+func (db *DB) GetTradeFills(tradeID int64) ([]TradeFill, error) {
 	query := `
-		INSERT INTO trades (
-			symbol, side, quantity, price, total, strategy,
-			indicator_values, signal_reason, paper_trade, timestamp,
-			binance_order_id, profit_loss, profit_loss_percent, related_buy_id
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		SELECT id, trade_id, exchange_trade_id, price, quantity, commission, commission_asset
+		FROM trade_fills
+		WHERE trade_id = ?
+		ORDER BY id ASC
 	`
 
-	stmt, err := tx.Prepare(query)
+	rows, err := db.conn.Query(query, tradeID)
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		return nil, fmt.Errorf("failed to query trade fills: %w", err)
 	}
-	defer stmt.Close()
+	defer rows.Close()
 
-	for _, trade := range trades {
-		_, err := stmt.Exec(
-			trade.Symbol,
-			trade.Side,
-			trade.Quantity,
-			trade.Price,
-			trade.Total,
-			trade.Strategy,
-			trade.IndicatorValues,
-			trade.SignalReason,
-			trade.PaperTrade,
-			trade.Timestamp,
-			trade.BinanceOrderID,
-			nullFloat64(trade.ProfitLoss),
-			nullFloat64(trade.ProfitLossPercent),
-			nullInt64(trade.RelatedBuyID),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to insert trade: %w", err)
+	var fills []TradeFill
+	for rows.Next() {
+		var f TradeFill
+		var exchangeTradeID sql.NullInt64
+		var commissionAsset sql.NullString
+
+		if err := rows.Scan(&f.ID, &f.TradeID, &exchangeTradeID, &f.Price, &f.Quantity, &f.Commission, &commissionAsset); err != nil {
+			return nil, fmt.Errorf("failed to scan trade fill: %w", err)
 		}
-	}
+		f.ExchangeTradeID = exchangeTradeID.Int64
+		f.CommissionAsset = commissionAsset.String
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		fills = append(fills, f)
 	}
 
-	return nil
+	return fills, rows.Err()
 }
 
 // InsertPosition inserts a new position into the database
@@ -199,8 +351,9 @@ func (db *DB) InsertPosition(pos *Position) (int64, error) {
 		INSERT INTO positions (
 			symbol, quantity, entry_price, entry_time, exit_price,
 			exit_time, strategy, is_open, profit_loss, profit_loss_percent,
-			buy_trade_id, sell_trade_id
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			buy_trade_id, sell_trade_id, stop_loss_price, take_profit_price,
+			highest_price, trailing_active, side
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := db.conn.Exec(
@@ -217,6 +370,11 @@ func (db *DB) InsertPosition(pos *Position) (int64, error) {
 		nullFloat64(pos.ProfitLossPercent),
 		pos.BuyTradeID,
 		nullInt64(pos.SellTradeID),
+		nullFloat64(pos.StopLossPrice),
+		nullFloat64(pos.TakeProfitPrice),
+		nullFloat64(pos.HighestPrice),
+		pos.TrailingActive,
+		pos.PositionSide(),
 	)
 
 	if err != nil {
@@ -248,16 +406,68 @@ func (db *DB) UpdatePosition(id int64, exitPrice float64, exitTime time.Time, pr
 	return nil
 }
 
-// GetOpenPosition retrieves the currently open position for a symbol
+// ReducePosition records a partial close: the quantity still open, and the P/L
+// realized and quantity closed so far (UpdatePosition adds them up on the final close)
+func (db *DB) ReducePosition(id int64, quantity, realizedPnL, closedQuantity float64) error {
+	query := `UPDATE positions SET quantity = ?, realized_pnl = ?, closed_quantity = ? WHERE id = ?`
+	_, err := db.conn.Exec(query, quantity, nullFloat64(realizedPnL), nullFloat64(closedQuantity), id)
+	if err != nil {
+		return fmt.Errorf("failed to reduce position: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePositionEntry records a scale-in: the new quantity and average entry price
+func (db *DB) UpdatePositionEntry(id int64, quantity, entryPrice float64) error {
+	_, err := db.conn.Exec(`UPDATE positions SET quantity = ?, entry_price = ? WHERE id = ?`, quantity, entryPrice, id)
+	if err != nil {
+		return fmt.Errorf("failed to update position entry: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePositionStops persists the risk exit levels of an open position
+func (db *DB) UpdatePositionStops(id int64, stopLoss, takeProfit, highestPrice float64, trailingActive bool) error {
+	query := `
+		UPDATE positions
+		SET stop_loss_price = ?, take_profit_price = ?, highest_price = ?, trailing_active = ?
+		WHERE id = ?
+	`
+
+	_, err := db.conn.Exec(query, nullFloat64(stopLoss), nullFloat64(takeProfit), nullFloat64(highestPrice), trailingActive, id)
+	if err != nil {
+		return fmt.Errorf("failed to update position stops: %w", err)
+	}
+
+	return nil
+}
+
+// FlagPositionOrphaned marks an open position the exchange account no longer holds.
+// It stays open for manual review but is no longer restored by GetOpenPosition.
+func (db *DB) FlagPositionOrphaned(id int64) error {
+	_, err := db.conn.Exec(`UPDATE positions SET orphaned = 1 WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to flag position as orphaned: %w", err)
+	}
+
+	return nil
+}
+
+// GetOpenPosition retrieves the currently open position for a symbol (orphaned positions are skipped)
 func (db *DB) GetOpenPosition(symbol string) (*Position, error) {
 	query := `
-		SELECT id, symbol, quantity, entry_price, entry_time, strategy, buy_trade_id
+		SELECT id, symbol, quantity, entry_price, entry_time, strategy, buy_trade_id,
+			   stop_loss_price, take_profit_price, highest_price, trailing_active,
+			   side, realized_pnl, closed_quantity
 		FROM positions
-		WHERE symbol = ? AND is_open = 1
+		WHERE symbol = ? AND is_open = 1 AND orphaned = 0
 		LIMIT 1
 	`
 
 	var pos Position
+	var stopLoss, takeProfit, highest, realizedPnL, closedQuantity sql.NullFloat64
 	err := db.conn.QueryRow(query, symbol).Scan(
 		&pos.ID,
 		&pos.Symbol,
@@ -266,6 +476,13 @@ func (db *DB) GetOpenPosition(symbol string) (*Position, error) {
 		&pos.EntryTime,
 		&pos.Strategy,
 		&pos.BuyTradeID,
+		&stopLoss,
+		&takeProfit,
+		&highest,
+		&pos.TrailingActive,
+		&pos.Side,
+		&realizedPnL,
+		&closedQuantity,
 	)
 
 	if err == sql.ErrNoRows {
@@ -277,6 +494,11 @@ func (db *DB) GetOpenPosition(symbol string) (*Position, error) {
 	}
 
 	pos.IsOpen = true
+	pos.StopLossPrice = stopLoss.Float64
+	pos.TakeProfitPrice = takeProfit.Float64
+	pos.HighestPrice = highest.Float64
+	pos.RealizedPnL = realizedPnL.Float64
+	pos.ClosedQuantity = closedQuantity.Float64
 	return &pos, nil
 }
 
@@ -285,7 +507,8 @@ func (db *DB) GetRecentTrades(limit int) ([]Trade, error) {
 	query := `
 		SELECT id, symbol, side, quantity, price, total, strategy,
 			   indicator_values, signal_reason, paper_trade, timestamp,
-			   binance_order_id, profit_loss, profit_loss_percent, related_buy_id
+			   binance_order_id, profit_loss, profit_loss_percent, related_buy_id,
+			   commission, commission_asset, position_side, signal_strength
 		FROM trades
 		ORDER BY timestamp DESC
 		LIMIT ?
@@ -300,9 +523,9 @@ func (db *DB) GetRecentTrades(limit int) ([]Trade, error) {
 	var trades []Trade
 	for rows.Next() {
 		var t Trade
-		var profitLoss, profitLossPercent sql.NullFloat64
+		var profitLoss, profitLossPercent, commission, signalStrength sql.NullFloat64
 		var relatedBuyID sql.NullInt64
-		var binanceOrderID sql.NullString
+		var binanceOrderID, commissionAsset, positionSide sql.NullString
 
 		err := rows.Scan(
 			&t.ID,
@@ -320,6 +543,10 @@ func (db *DB) GetRecentTrades(limit int) ([]Trade, error) {
 			&profitLoss,
 			&profitLossPercent,
 			&relatedBuyID,
+			&commission,
+			&commissionAsset,
+			&positionSide,
+			&signalStrength,
 		)
 
 		if err != nil {
@@ -338,6 +565,10 @@ func (db *DB) GetRecentTrades(limit int) ([]Trade, error) {
 		if binanceOrderID.Valid {
 			t.BinanceOrderID = binanceOrderID.String
 		}
+		t.Commission = commission.Float64
+		t.CommissionAsset = commissionAsset.String
+		t.PositionSide = positionSide.String
+		t.SignalStrength = signalStrength.Float64
 
 		trades = append(trades, t)
 	}
@@ -345,12 +576,47 @@ func (db *DB) GetRecentTrades(limit int) ([]Trade, error) {
 	return trades, nil
 }
 
+// GetRecordedExecutions returns the exchange order IDs and exchange trade (fill) IDs
+// recorded for the live (or paper) trades of a symbol since the given time
+func (db *DB) GetRecordedExecutions(symbol string, paperTrade bool, since time.Time) (orderIDs map[string]bool, tradeIDs map[int64]bool, err error) {
+	orderIDs = make(map[string]bool)
+	tradeIDs = make(map[int64]bool)
+
+	rows, err := db.conn.Query(`
+		SELECT t.binance_order_id, f.exchange_trade_id
+		FROM trades t
+		LEFT JOIN trade_fills f ON f.trade_id = t.id
+		WHERE t.symbol = ? AND t.paper_trade = ? AND t.timestamp >= ?
+	`, symbol, paperTrade, since)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query recorded executions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID sql.NullString
+		var tradeID sql.NullInt64
+		if err := rows.Scan(&orderID, &tradeID); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan recorded execution: %w", err)
+		}
+		if orderID.Valid && orderID.String != "" {
+			orderIDs[orderID.String] = true
+		}
+		if tradeID.Valid {
+			tradeIDs[tradeID.Int64] = true
+		}
+	}
+
+	return orderIDs, tradeIDs, rows.Err()
+}
+
 // GetTradesByDateRange retrieves trades within a date range
 func (db *DB) GetTradesByDateRange(start, end time.Time) ([]Trade, error) {
 	query := `
 		SELECT id, symbol, side, quantity, price, total, strategy,
 			   indicator_values, signal_reason, paper_trade, timestamp,
-			   binance_order_id, profit_loss, profit_loss_percent, related_buy_id
+			   binance_order_id, profit_loss, profit_loss_percent, related_buy_id,
+			   commission, commission_asset, position_side, signal_strength
 		FROM trades
 		WHERE timestamp BETWEEN ? AND ?
 		ORDER BY timestamp DESC
@@ -365,9 +631,9 @@ func (db *DB) GetTradesByDateRange(start, end time.Time) ([]Trade, error) {
 	var trades []Trade
 	for rows.Next() {
 		var t Trade
-		var profitLoss, profitLossPercent sql.NullFloat64
+		var profitLoss, profitLossPercent, commission, signalStrength sql.NullFloat64
 		var relatedBuyID sql.NullInt64
-		var binanceOrderID sql.NullString
+		var binanceOrderID, commissionAsset, positionSide sql.NullString
 
 		err := rows.Scan(
 			&t.ID,
@@ -385,6 +651,10 @@ func (db *DB) GetTradesByDateRange(start, end time.Time) ([]Trade, error) {
 			&profitLoss,
 			&profitLossPercent,
 			&relatedBuyID,
+			&commission,
+			&commissionAsset,
+			&positionSide,
+			&signalStrength,
 		)
 
 		if err != nil {
@@ -403,6 +673,10 @@ func (db *DB) GetTradesByDateRange(start, end time.Time) ([]Trade, error) {
 		if binanceOrderID.Valid {
 			t.BinanceOrderID = binanceOrderID.String
 		}
+		t.Commission = commission.Float64
+		t.CommissionAsset = commissionAsset.String
+		t.PositionSide = positionSide.String
+		t.SignalStrength = signalStrength.Float64
 
 		trades = append(trades, t)
 	}
@@ -410,6 +684,157 @@ func (db *DB) GetTradesByDateRange(start, end time.Time) ([]Trade, error) {
 	return trades, nil
 }
 
+// InsertCandles stores historical candles in a single transaction
+// Existing candles with the same symbol, interval and open time are replaced
+func (db *DB) InsertCandles(candles []Candle) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO candles (
+			symbol, interval, open_time, open, high, low, close, volume
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, c := range candles {
+		_, err := stmt.Exec(c.Symbol, c.Interval, c.OpenTime.UTC(), c.Open, c.High, c.Low, c.Close, c.Volume)
+		if err != nil {
+			return fmt.Errorf("failed to insert candle: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetCandles retrieves candles for a symbol and interval within a date range, oldest first
+func (db *DB) GetCandles(symbol, interval string, start, end time.Time) ([]Candle, error) {
+	query := `
+		SELECT symbol, interval, open_time, open, high, low, close, volume
+		FROM candles
+		WHERE symbol = ? AND interval = ? AND open_time BETWEEN ? AND ?
+		ORDER BY open_time ASC
+	`
+
+	rows, err := db.conn.Query(query, symbol, interval, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query candles: %w", err)
+	}
+	defer rows.Close()
+
+	var candles []Candle
+	for rows.Next() {
+		var c Candle
+		if err := rows.Scan(&c.Symbol, &c.Interval, &c.OpenTime, &c.Open, &c.High, &c.Low, &c.Close, &c.Volume); err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+		candles = append(candles, c)
+	}
+
+	return candles, rows.Err()
+}
+
+// GetLatestCandles retrieves the most recent limit candles for a symbol and interval, oldest first
+func (db *DB) GetLatestCandles(symbol, interval string, limit int) ([]Candle, error) {
+	query := `
+		SELECT symbol, interval, open_time, open, high, low, close, volume
+		FROM (
+			SELECT * FROM candles
+			WHERE symbol = ? AND interval = ?
+			ORDER BY open_time DESC
+			LIMIT ?
+		)
+		ORDER BY open_time ASC
+	`
+
+	rows, err := db.conn.Query(query, symbol, interval, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query candles: %w", err)
+	}
+	defer rows.Close()
+
+	var candles []Candle
+	for rows.Next() {
+		var c Candle
+		if err := rows.Scan(&c.Symbol, &c.Interval, &c.OpenTime, &c.Open, &c.High, &c.Low, &c.Close, &c.Volume); err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+		candles = append(candles, c)
+	}
+
+	return candles, rows.Err()
+}
+
+// GetCandleRange returns the first and last stored open times and the number of
+// candles for a symbol and interval (zero times and 0 when none are stored)
+func (db *DB) GetCandleRange(symbol, interval string) (first, last time.Time, count int, err error) {
+	err = db.conn.QueryRow(`SELECT COUNT(*) FROM candles WHERE symbol = ? AND interval = ?`, symbol, interval).Scan(&count)
+	if err != nil || count == 0 {
+		if err != nil {
+			err = fmt.Errorf("failed to count candles: %w", err)
+		}
+		return first, last, 0, err
+	}
+
+	// Scanned from the column (not MIN/MAX) so the driver parses the DATETIME
+	query := `SELECT open_time FROM candles WHERE symbol = ? AND interval = ? ORDER BY open_time %s LIMIT 1`
+	if err = db.conn.QueryRow(fmt.Sprintf(query, "ASC"), symbol, interval).Scan(&first); err != nil {
+		return first, last, 0, fmt.Errorf("failed to query first candle: %w", err)
+	}
+	if err = db.conn.QueryRow(fmt.Sprintf(query, "DESC"), symbol, interval).Scan(&last); err != nil {
+		return first, last, 0, fmt.Errorf("failed to query last candle: %w", err)
+	}
+	return first, last, count, nil
+}
+
+// SaveStrategyState stores a symbol's strategy state, replacing the previous one
+func (db *DB) SaveStrategyState(state *StrategyState) error {
+	_, err := db.conn.Exec(`
+		INSERT OR REPLACE INTO strategy_state (
+			symbol, strategy, interval, state, last_candle, saved_at
+		) VALUES (?, ?, ?, ?, ?, ?)
+	`, state.Symbol, state.Strategy, state.Interval, string(state.State), state.LastCandle.UTC(), state.SavedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save strategy state: %w", err)
+	}
+	return nil
+}
+
+// GetStrategyState retrieves a symbol's saved strategy state (nil if none was saved)
+func (db *DB) GetStrategyState(symbol string) (*StrategyState, error) {
+	var state StrategyState
+	var data string
+	err := db.conn.QueryRow(`
+		SELECT symbol, strategy, interval, state, last_candle, saved_at
+		FROM strategy_state
+		WHERE symbol = ?
+	`, symbol).Scan(&state.Symbol, &state.Strategy, &state.Interval, &data, &state.LastCandle, &state.SavedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get strategy state: %w", err)
+	}
+
+	state.State = []byte(data)
+	return &state, nil
+}
+
+// closingTrade matches the trades that close (part of) a position and carry its P/L:
+// SELLs of long positions and BUYs covering shorts (see Trade.Closes)
+const closingTrade = `((side = 'SELL' AND COALESCE(position_side, 'long') = 'long') OR (side = 'BUY' AND position_side = 'short'))`
+
 // GetTradeSummary calculates aggregate statistics
 func (db *DB) GetTradeSummary() (*TradeSummary, error) {
 	query := `
@@ -417,26 +842,36 @@ func (db *DB) GetTradeSummary() (*TradeSummary, error) {
 			COUNT(*) as total_trades,
 			COALESCE(SUM(CASE WHEN side = 'BUY' THEN 1 ELSE 0 END), 0) as total_buys,
 			COALESCE(SUM(CASE WHEN side = 'SELL' THEN 1 ELSE 0 END), 0) as total_sells,
-			COALESCE(SUM(CASE WHEN side = 'SELL' THEN profit_loss ELSE 0 END), 0) as total_profit_loss,
-			COALESCE(AVG(CASE WHEN side = 'SELL' THEN profit_loss ELSE NULL END), 0) as avg_profit_loss,
-			COALESCE(MAX(CASE WHEN side = 'SELL' THEN profit_loss ELSE NULL END), 0) as largest_win,
-			COALESCE(MIN(CASE WHEN side = 'SELL' THEN profit_loss ELSE NULL END), 0) as largest_loss,
+			COALESCE(SUM(CASE WHEN ` + closingTrade + ` THEN 1 ELSE 0 END), 0) as total_closes,
+			COALESCE(SUM(CASE WHEN ` + closingTrade + ` THEN profit_loss ELSE 0 END), 0) as total_profit_loss,
+			COALESCE(AVG(CASE WHEN ` + closingTrade + ` THEN profit_loss ELSE NULL END), 0) as avg_profit_loss,
+			COALESCE(MAX(CASE WHEN ` + closingTrade + ` THEN profit_loss ELSE NULL END), 0) as largest_win,
+			COALESCE(MIN(CASE WHEN ` + closingTrade + ` THEN profit_loss ELSE NULL END), 0) as largest_loss,
+			COALESCE(SUM(CASE
+				WHEN commission_asset IS NULL OR commission_asset = '' THEN 0
+				WHEN symbol LIKE '%' || commission_asset THEN commission
+				WHEN symbol LIKE commission_asset || '%' THEN commission * price
+				ELSE 0 -- Third asset (e.g. BNB), see unconverted fees
+			END), 0) as total_fees,
 			MIN(timestamp) as start_date,
 			MAX(timestamp) as end_date
 		FROM trades
 	`
 
 	var summary TradeSummary
+	var closes int
 	var startDateStr, endDateStr sql.NullString
 
 	err := db.conn.QueryRow(query).Scan(
 		&summary.TotalTrades,
 		&summary.TotalBuys,
 		&summary.TotalSells,
+		&closes,
 		&summary.TotalProfitLoss,
 		&summary.AverageProfitLoss,
 		&summary.LargestWin,
 		&summary.LargestLoss,
+		&summary.TotalFees,
 		&startDateStr,
 		&endDateStr,
 	)
@@ -447,28 +882,72 @@ func (db *DB) GetTradeSummary() (*TradeSummary, error) {
 
 	// Parse string timestamps to time.Time
 	if startDateStr.Valid {
-		if t, err := time.Parse(time.RFC3339, startDateStr.String); err == nil {
-			summary.StartDate = t
-		}
+		summary.StartDate = parseStoredTime(startDateStr.String)
 	}
 	if endDateStr.Valid {
-		if t, err := time.Parse(time.RFC3339, endDateStr.String); err == nil {
-			summary.EndDate = t
-		}
+		summary.EndDate = parseStoredTime(endDateStr.String)
 	}
 
 	// Calculate win rate
-	if summary.TotalSells > 0 {
-		winQuery := `SELECT COUNT(*) FROM trades WHERE side = 'SELL' AND profit_loss > 0`
+	if closes > 0 {
+		winQuery := `SELECT COUNT(*) FROM trades WHERE ` + closingTrade + ` AND profit_loss > 0`
 		var wins int
 		if err := db.conn.QueryRow(winQuery).Scan(&wins); err == nil {
-			summary.WinRate = (float64(wins) / float64(summary.TotalSells)) * 100
+			summary.WinRate = (float64(wins) / float64(closes)) * 100
 		}
 	}
 
+	unconverted, err := db.unconvertedFees()
+	if err != nil {
+		return nil, err
+	}
+	summary.UnconvertedFees = unconverted
+
 	return &summary, nil
 }
 
+// unconvertedFees sums the commission paid in neither asset of the traded symbol
+// (e.g. BNB), by asset. It has no price here, so TotalFees and P/L leave it out.
+func (db *DB) unconvertedFees() (map[string]float64, error) {
+	rows, err := db.conn.Query(`
+		SELECT commission_asset, SUM(commission)
+		FROM trades
+		WHERE commission_asset IS NOT NULL AND commission_asset != ''
+			AND symbol NOT LIKE '%' || commission_asset
+			AND symbol NOT LIKE commission_asset || '%'
+		GROUP BY commission_asset
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum unconverted fees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees map[string]float64
+	for rows.Next() {
+		var asset string
+		var total float64
+		if err := rows.Scan(&asset, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan unconverted fees: %w", err)
+		}
+		if fees == nil {
+			fees = make(map[string]float64)
+		}
+		fees[asset] = total
+	}
+	return fees, rows.Err()
+}
+
+// parseStoredTime parses a timestamp read back as text. The driver stores time.Time
+// values in time.Time.String form; RFC3339 is accepted for rows written by hand.
+func parseStoredTime(s string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999 -0700 MST", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 // Helper functions for NULL handling
 func nullFloat64(f float64) sql.NullFloat64 {
 	if f == 0 {
@@ -484,6 +963,13 @@ func nullInt64(i int64) sql.NullInt64 {
 	return sql.NullInt64{Int64: i, Valid: true}
 }
 
+func nullString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{Valid: false}
+	}
+	return sql.NullString{String: s, Valid: true}
+}
+
 // SerializeIndicatorValues converts a map to JSON string for storage
 func SerializeIndicatorValues(values map[string]float64) string {
 	data, err := json.Marshal(values)
@@ -520,6 +1006,12 @@ func (db *DB) ClearPaperTrades() error {
 		return fmt.Errorf("failed to delete paper positions: %w", err)
 	}
 
+	// Delete fills of paper trades
+	_, err = tx.Exec("DELETE FROM trade_fills WHERE trade_id IN (SELECT id FROM trades WHERE paper_trade = 1)")
+	if err != nil {
+		return fmt.Errorf("failed to delete paper trade fills: %w", err)
+	}
+
 	// Delete paper trades
 	_, err = tx.Exec("DELETE FROM trades WHERE paper_trade = 1")
 	if err != nil {
@@ -0,0 +1,244 @@
+package database
+
+import (
+	"database/sql"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestDB opens a fresh in-memory database, shared by the pool's connections
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := New(memoryDSN(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// memoryDSN names an in-memory database after the test so tests never share one
+func memoryDSN(t *testing.T) string {
+	return "file:" + strings.ReplaceAll(t.Name(), "/", "_") + "?mode=memory&cache=shared"
+}
+
+func TestMigrate_BaselineSchema(t *testing.T) {
+	// The schema of the first release, before commissions, shorts and protective stops
+	dsn := memoryDSN(t)
+	baseline, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer baseline.Close()
+	if _, err := baseline.Exec(`
+	CREATE TABLE trades (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		symbol TEXT NOT NULL,
+		side TEXT NOT NULL CHECK(side IN ('BUY', 'SELL')),
+		quantity REAL NOT NULL,
+		price REAL NOT NULL,
+		total REAL NOT NULL,
+		strategy TEXT NOT NULL,
+		indicator_values TEXT,
+		signal_reason TEXT,
+		paper_trade BOOLEAN NOT NULL DEFAULT 1,
+		timestamp DATETIME NOT NULL,
+		binance_order_id TEXT,
+		profit_loss REAL,
+		profit_loss_percent REAL,
+		related_buy_id INTEGER,
+		FOREIGN KEY (related_buy_id) REFERENCES trades(id)
+	);
+
+	CREATE TABLE positions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		symbol TEXT NOT NULL,
+		quantity REAL NOT NULL,
+		entry_price REAL NOT NULL,
+		entry_time DATETIME NOT NULL,
+		exit_price REAL,
+		exit_time DATETIME,
+		strategy TEXT NOT NULL,
+		is_open BOOLEAN NOT NULL DEFAULT 1,
+		profit_loss REAL,
+		profit_loss_percent REAL,
+		buy_trade_id INTEGER NOT NULL,
+		sell_trade_id INTEGER,
+		FOREIGN KEY (buy_trade_id) REFERENCES trades(id),
+		FOREIGN KEY (sell_trade_id) REFERENCES trades(id)
+	);
+
+	INSERT INTO trades (symbol, side, quantity, price, total, strategy, indicator_values, signal_reason, paper_trade, timestamp)
+	VALUES ('BTCUSDT', 'BUY', 1, 100, 100, 'RSI', '{}', 'RSI BUY', 1, '2024-01-01 00:00:00 +0000 UTC');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := New(dsn)
+	if err != nil {
+		t.Fatalf("opening a baseline database: %v", err)
+	}
+	defer db.Close()
+
+	for table, columns := range map[string][]string{
+		"trades":    {"commission", "commission_asset", "position_side", "signal_strength"},
+		"positions": {"stop_loss_price", "take_profit_price", "highest_price", "trailing_active", "orphaned", "side", "realized_pnl", "closed_quantity"},
+	} {
+		for _, column := range columns {
+			if exists, err := db.columnExists(table, column); err != nil || !exists {
+				t.Errorf("%s.%s exists = %v (%v), want it added", table, column, exists, err)
+			}
+		}
+	}
+	if exists, err := db.columnExists("trades", "no_such_column"); err != nil || exists {
+		t.Errorf("trades.no_such_column exists = %v (%v), want false", exists, err)
+	}
+
+	// Migrating again is a no-op
+	if err := db.migrate(); err != nil {
+		t.Fatalf("second migrate: %v", err)
+	}
+
+	// The old row reads back as a long, and new rows use the added columns
+	trades, err := db.GetRecentTrades(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 1 || trades[0].PositionSide != "" || trades[0].Commission != 0 || !trades[0].Timestamp.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("baseline trades = %+v, want the one BUY", trades)
+	}
+	tradeID, err := db.InsertTrade(&Trade{Symbol: "BTCUSDT", Side: "SELL", Quantity: 1, Price: 90, Total: 90, Strategy: "RSI", Timestamp: time.Now(), Commission: 0.09, CommissionAsset: "USDT", PositionSide: PositionSideShort})
+	if err != nil {
+		t.Fatalf("inserting into a migrated database: %v", err)
+	}
+	if _, err := db.InsertPosition(&Position{Symbol: "BTCUSDT", Quantity: 1, EntryPrice: 90, EntryTime: time.Now(), Strategy: "RSI", IsOpen: true, BuyTradeID: tradeID, Side: PositionSideShort, StopLossPrice: 95}); err != nil {
+		t.Fatalf("inserting a position into a migrated database: %v", err)
+	}
+	pos, err := db.GetOpenPosition("BTCUSDT")
+	if err != nil || pos == nil || pos.Side != PositionSideShort || pos.StopLossPrice != 95 {
+		t.Errorf("open position = %+v (%v), want the short with its stop", pos, err)
+	}
+}
+
+func TestInsertTrade_Fills(t *testing.T) {
+	db := newTestDB(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	trade := &Trade{
+		Symbol: "BTCUSDT", Side: "BUY", Quantity: 0.3, Price: 100, Total: 30, Strategy: "RSI",
+		Timestamp: start.Add(time.Hour), BinanceOrderID: "42", Commission: 0.0003, CommissionAsset: "BTC",
+		Fills: []TradeFill{
+			{ExchangeTradeID: 7, Price: 99, Quantity: 0.1, Commission: 0.0001, CommissionAsset: "BTC"},
+			{ExchangeTradeID: 8, Price: 100.5, Quantity: 0.2, Commission: 0.0002, CommissionAsset: "BTC"},
+		},
+	}
+	tradeID, err := db.InsertTrade(trade)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fills, err := db.GetTradeFills(tradeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fills) != 2 {
+		t.Fatalf("got %d fills, want 2", len(fills))
+	}
+	for i, f := range fills {
+		want := trade.Fills[i]
+		want.ID, want.TradeID = f.ID, tradeID
+		if f != want {
+			t.Errorf("fill %d = %+v, want %+v", i, f, want)
+		}
+	}
+
+	// A paper trade, a trade of another symbol and one before the cutoff are left out
+	others := []*Trade{
+		{Symbol: "BTCUSDT", Side: "SELL", Quantity: 0.1, Price: 100, Total: 10, Strategy: "RSI", PaperTrade: true, Timestamp: start.Add(2 * time.Hour), BinanceOrderID: "43", Fills: []TradeFill{{ExchangeTradeID: 9, Price: 100, Quantity: 0.1}}},
+		{Symbol: "ETHUSDT", Side: "BUY", Quantity: 1, Price: 10, Total: 10, Strategy: "RSI", Timestamp: start.Add(2 * time.Hour), BinanceOrderID: "44", Fills: []TradeFill{{ExchangeTradeID: 10, Price: 10, Quantity: 1}}},
+		{Symbol: "BTCUSDT", Side: "BUY", Quantity: 0.1, Price: 100, Total: 10, Strategy: "RSI", Timestamp: start.Add(-time.Hour), BinanceOrderID: "41", Fills: []TradeFill{{ExchangeTradeID: 6, Price: 100, Quantity: 0.1}}},
+		// Recorded without fills, e.g. before trade_fills existed
+		{Symbol: "BTCUSDT", Side: "SELL", Quantity: 0.1, Price: 101, Total: 10.1, Strategy: "RSI", Timestamp: start.Add(3 * time.Hour), BinanceOrderID: "45"},
+	}
+	if err := db.InsertTradesInTransaction(others); err != nil {
+		t.Fatal(err)
+	}
+
+	orderIDs, tradeIDs, err := db.GetRecordedExecutions("BTCUSDT", false, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]bool{"42": true, "45": true}; !reflect.DeepEqual(orderIDs, want) {
+		t.Errorf("order IDs = %v, want %v", orderIDs, want)
+	}
+	if want := map[int64]bool{7: true, 8: true}; !reflect.DeepEqual(tradeIDs, want) {
+		t.Errorf("trade IDs = %v, want %v", tradeIDs, want)
+	}
+
+	orderIDs, tradeIDs, err = db.GetRecordedExecutions("BTCUSDT", true, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(orderIDs, map[string]bool{"43": true}) || !reflect.DeepEqual(tradeIDs, map[int64]bool{9: true}) {
+		t.Errorf("paper executions = %v %v, want order 43 with fill 9", orderIDs, tradeIDs)
+	}
+}
+
+func TestGetTradeSummary_LongAndShort(t *testing.T) {
+	db := newTestDB(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+
+	trades := []*Trade{
+		// Long: bought with the fee in BTC, sold at a 10 profit with the fee in USDT
+		{Symbol: "BTCUSDT", Side: "BUY", Quantity: 1, Price: 100, Total: 100, Strategy: "RSI", Timestamp: at(0), PositionSide: PositionSideLong, Commission: 0.001, CommissionAsset: "BTC"},
+		{Symbol: "BTCUSDT", Side: "SELL", Quantity: 1, Price: 110, Total: 110, Strategy: "RSI", Timestamp: at(1), PositionSide: PositionSideLong, Commission: 0.11, CommissionAsset: "USDT", ProfitLoss: 9.79},
+		// Short: sold, then bought back higher for a loss
+		{Symbol: "BTCUSDT", Side: "SELL", Quantity: 1, Price: 100, Total: 100, Strategy: "RSI", Timestamp: at(2), PositionSide: PositionSideShort, Commission: 0.1, CommissionAsset: "USDT"},
+		{Symbol: "BTCUSDT", Side: "BUY", Quantity: 1, Price: 105, Total: 105, Strategy: "RSI", Timestamp: at(3), PositionSide: PositionSideShort, Commission: 0.001, CommissionAsset: "BTC", ProfitLoss: -5.2},
+		// Short covered lower, fees paid in BNB
+		{Symbol: "ETHUSDT", Side: "SELL", Quantity: 2, Price: 50, Total: 100, Strategy: "RSI", Timestamp: at(4), PositionSide: PositionSideShort, Commission: 0.02, CommissionAsset: "BNB"},
+		{Symbol: "ETHUSDT", Side: "BUY", Quantity: 2, Price: 40, Total: 80, Strategy: "RSI", Timestamp: at(5), PositionSide: PositionSideShort, Commission: 0.01, CommissionAsset: "BNB", ProfitLoss: 20},
+		// A long from before position sides were recorded
+		{Symbol: "ETHUSDT", Side: "BUY", Quantity: 1, Price: 50, Total: 50, Strategy: "RSI", Timestamp: at(6)},
+		{Symbol: "ETHUSDT", Side: "SELL", Quantity: 1, Price: 48, Total: 48, Strategy: "RSI", Timestamp: at(7), ProfitLoss: -2},
+	}
+	if err := db.InsertTradesInTransaction(trades); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := db.GetTradeSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.TotalTrades != 8 || summary.TotalBuys != 4 || summary.TotalSells != 4 {
+		t.Errorf("trades = %d (%d buys, %d sells), want 8 (4, 4)", summary.TotalTrades, summary.TotalBuys, summary.TotalSells)
+	}
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"total P/L", summary.TotalProfitLoss, 9.79 - 5.2 + 20 - 2},
+		{"average P/L", summary.AverageProfitLoss, (9.79 - 5.2 + 20 - 2) / 4},
+		{"largest win", summary.LargestWin, 20},
+		{"largest loss", summary.LargestLoss, -5.2},
+		{"win rate", summary.WinRate, 50},
+		// BTC fees at the trade price, USDT as is, BNB left out
+		{"total fees", summary.TotalFees, 0.001*100 + 0.11 + 0.1 + 0.001*105},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if fees := summary.UnconvertedFees; len(fees) != 1 || math.Abs(fees["BNB"]-0.03) > 1e-9 {
+		t.Errorf("unconverted fees = %v, want 0.03 BNB", fees)
+	}
+	if !summary.StartDate.Equal(at(0)) || !summary.EndDate.Equal(at(7)) {
+		t.Errorf("dates = %s to %s, want %s to %s", summary.StartDate, summary.EndDate, at(0), at(7))
+	}
+}
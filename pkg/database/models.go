@@ -17,10 +17,38 @@ type Trade struct {
 	Timestamp       time.Time `json:"timestamp"`
 	BinanceOrderID  string    `json:"binance_order_id,omitempty"` // Only populated for real trades
 
-	// Profit/Loss tracking (only for SELL trades)
+	// Execution details from the exchange (Quantity, Price and Total are the executed values)
+	Commission      float64     `json:"commission,omitempty"`
+	CommissionAsset string      `json:"commission_asset,omitempty"`
+	Fills           []TradeFill `json:"fills,omitempty"` // Stored in trade_fills; not loaded with trade lists
+
+	// Profit/Loss tracking (only for trades that close a position, see Closes)
 	ProfitLoss        float64 `json:"profit_loss,omitempty"` // Absolute profit/loss
 	ProfitLossPercent float64 `json:"profit_loss_percent,omitempty"` // Percentage
 	RelatedBuyID      int64   `json:"related_buy_id,omitempty"` // Links SELL to its BUY
+
+	// Side of the position the trade opened or closed ("long" or "short"; empty = long)
+	PositionSide   string  `json:"position_side,omitempty"`
+	SignalStrength float64 `json:"signal_strength,omitempty"` // Strength (0-1) of the signal behind the trade
+}
+
+// Closes reports whether the trade closes (part of) a position: a SELL of a long or a BUY covering a short
+func (t *Trade) Closes() bool {
+	if t.PositionSide == PositionSideShort {
+		return t.Side == "BUY"
+	}
+	return t.Side == "SELL"
+}
+
+// TradeFill is a single exchange execution that made up a trade
+type TradeFill struct {
+	ID              int64   `json:"id"`
+	TradeID         int64   `json:"trade_id"`
+	ExchangeTradeID int64   `json:"exchange_trade_id,omitempty"`
+	Price           float64 `json:"price"`
+	Quantity        float64 `json:"quantity"`
+	Commission      float64 `json:"commission"`
+	CommissionAsset string  `json:"commission_asset"`
 }
 
 // Position represents the current or historical position
@@ -42,6 +70,58 @@ type Position struct {
 	// Trade references
 	BuyTradeID  int64 `json:"buy_trade_id"`
 	SellTradeID int64 `json:"sell_trade_id,omitempty"`
+
+	// Risk exits (persisted so they survive restarts)
+	StopLossPrice   float64 `json:"stop_loss_price,omitempty"`
+	TakeProfitPrice float64 `json:"take_profit_price,omitempty"`
+	HighestPrice    float64 `json:"highest_price,omitempty"` // Peak since entry, for the trailing stop
+	TrailingActive  bool    `json:"trailing_active"`
+
+	// Set by startup reconciliation when the exchange account no longer holds the position
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Direction ("long" or "short") and what partial closes have realized so far
+	Side           string  `json:"side"`
+	RealizedPnL    float64 `json:"realized_pnl,omitempty"`
+	ClosedQuantity float64 `json:"closed_quantity,omitempty"`
+}
+
+// Position sides stored in positions.side and trades.position_side
+const (
+	PositionSideLong  = "long"
+	PositionSideShort = "short"
+)
+
+// PositionSide returns the position's side, long when unset
+func (p *Position) PositionSide() string {
+	if p.Side == "" {
+		return PositionSideLong
+	}
+	return p.Side
+}
+
+// Candle represents a stored OHLCV bar (streamed, fetched for warm-up or imported),
+// used for warm starts, backtesting and charts
+type Candle struct {
+	Symbol   string    `json:"symbol"`
+	Interval string    `json:"interval"` // e.g. "1m", "1h", "1d"
+	OpenTime time.Time `json:"open_time"`
+	Open     float64   `json:"open"`
+	High     float64   `json:"high"`
+	Low      float64   `json:"low"`
+	Close    float64   `json:"close"`
+	Volume   float64   `json:"volume"`
+}
+
+// StrategyState is a symbol's saved strategy and indicator state, restored on
+// startup so the bot resumes without re-warming
+type StrategyState struct {
+	Symbol     string    `json:"symbol"`
+	Strategy   string    `json:"strategy"` // Strategy name, e.g. "RSI"
+	Interval   string    `json:"interval"` // Kline interval the strategy was fed
+	State      []byte    `json:"state"`    // strategy.MarshalState output
+	LastCandle time.Time `json:"last_candle"` // Open time of the last candle in the state
+	SavedAt    time.Time `json:"saved_at"`
 }
 
 // TradeSummary provides aggregate statistics
@@ -49,7 +129,9 @@ type TradeSummary struct {
 	TotalTrades       int       `json:"total_trades"`
 	TotalBuys         int       `json:"total_buys"`
 	TotalSells        int       `json:"total_sells"`
-	TotalProfitLoss   float64   `json:"total_profit_loss"`
+	TotalProfitLoss   float64   `json:"total_profit_loss"` // Net of fees
+	TotalFees         float64   `json:"total_fees"` // Commission paid in the symbols' base or quote asset, in quote asset
+	UnconvertedFees   map[string]float64 `json:"unconverted_fees,omitempty"` // Commission paid in other assets (e.g. BNB), by asset
 	WinRate           float64   `json:"win_rate"` // Percentage of profitable trades
 	AverageProfitLoss float64   `json:"average_profit_loss"`
 	LargestWin        float64   `json:"largest_win"`
@@ -0,0 +1,344 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+// BinanceExchange adapts a go-binance client to the Exchange interface
+type BinanceExchange struct {
+	client *binance.Client
+}
+
+// NewBinanceExchange wraps an existing Binance client
+func NewBinanceExchange(client *binance.Client) *BinanceExchange {
+	return &BinanceExchange{client: client}
+}
+
+// Client returns the underlying Binance client (for endpoints not covered by Exchange)
+func (e *BinanceExchange) Client() *binance.Client {
+	return e.client
+}
+
+// SyncTime sets the client's time offset from the server clock to prevent timestamp errors (-1021).
+// A 1 second safety buffer keeps requests behind server time. Returns the applied offset in ms.
+func (e *BinanceExchange) SyncTime(ctx context.Context) (int64, error) {
+	serverTime, err := e.client.NewServerTimeService().Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get server time: %w", err)
+	}
+
+	localTime := time.Now().UnixMilli()
+	e.client.TimeOffset = serverTime - localTime - 1000
+	return e.client.TimeOffset, nil
+}
+
+// PlaceOrder submits a new order with a FULL response so fills are returned
+func (e *BinanceExchange) PlaceOrder(ctx context.Context, req OrderRequest) (*Order, error) {
+	svc := e.client.NewCreateOrderService().
+		Symbol(req.Symbol).
+		Side(binance.SideType(req.Side)).
+		Type(binance.OrderType(req.Type)).
+		Quantity(formatDecimal(req.Quantity)).
+		NewOrderRespType(binance.NewOrderRespTypeFULL)
+
+	if req.Type == OrderTypeLimitMaker {
+		svc = svc.Price(formatDecimal(req.Price))
+	}
+	if req.Type == OrderTypeLimit {
+		tif := req.TimeInForce
+		if tif == "" {
+			tif = string(binance.TimeInForceTypeGTC)
+		}
+		svc = svc.Price(formatDecimal(req.Price)).TimeInForce(binance.TimeInForceType(tif))
+	}
+
+	if req.ClientOrderID != "" {
+		svc = svc.NewClientOrderID(req.ClientOrderID)
+	}
+
+	res, err := svc.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &Order{
+		OrderID:          res.OrderID,
+		ClientOrderID:    res.ClientOrderID,
+		Symbol:           res.Symbol,
+		Side:             OrderSide(res.Side),
+		Type:             OrderType(res.Type),
+		Status:           OrderStatus(res.Status),
+		Price:            parseFloat(res.Price),
+		OrigQuantity:     parseFloat(res.OrigQuantity),
+		ExecutedQuantity: parseFloat(res.ExecutedQuantity),
+		QuoteQuantity:    parseFloat(res.CummulativeQuoteQuantity),
+		TransactTime:     time.UnixMilli(res.TransactTime),
+	}
+
+	for _, f := range res.Fills {
+		order.Fills = append(order.Fills, Fill{
+			TradeID:         f.TradeID,
+			Price:           parseFloat(f.Price),
+			Quantity:        parseFloat(f.Quantity),
+			Commission:      parseFloat(f.Commission),
+			CommissionAsset: f.CommissionAsset,
+		})
+	}
+
+	return order, nil
+}
+
+// formatDecimal formats a quantity or price without trailing zeros or float noise
+// beyond 8 decimals, so values already rounded to the symbol's step pass the filters
+func formatDecimal(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1e8)/1e8, 'f', -1, 64)
+}
+
+// CancelOrder cancels an open order
+func (e *BinanceExchange) CancelOrder(ctx context.Context, symbol string, orderID int64) (*Order, error) {
+	res, err := e.client.NewCancelOrderService().Symbol(symbol).OrderID(orderID).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Order{
+		OrderID:          res.OrderID,
+		ClientOrderID:    res.ClientOrderID,
+		Symbol:           res.Symbol,
+		Side:             OrderSide(res.Side),
+		Type:             OrderType(res.Type),
+		Status:           OrderStatus(res.Status),
+		Price:            parseFloat(res.Price),
+		OrigQuantity:     parseFloat(res.OrigQuantity),
+		ExecutedQuantity: parseFloat(res.ExecutedQuantity),
+		QuoteQuantity:    parseFloat(res.CummulativeQuoteQuantity),
+		TransactTime:     time.UnixMilli(res.TransactTime),
+	}, nil
+}
+
+// GetOrder returns the current state of an order
+func (e *BinanceExchange) GetOrder(ctx context.Context, symbol string, orderID int64) (*Order, error) {
+	res, err := e.client.NewGetOrderService().Symbol(symbol).OrderID(orderID).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return convertOrder(res), nil
+}
+
+// GetBalances returns all account balances
+func (e *BinanceExchange) GetBalances(ctx context.Context) ([]Balance, error) {
+	account, err := e.client.NewGetAccountService().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]Balance, 0, len(account.Balances))
+	for _, b := range account.Balances {
+		balances = append(balances, Balance{
+			Asset:  b.Asset,
+			Free:   parseFloat(b.Free),
+			Locked: parseFloat(b.Locked),
+		})
+	}
+	return balances, nil
+}
+
+// GetOrderBook returns a depth snapshot
+func (e *BinanceExchange) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	depth, err := e.client.NewDepthService().Symbol(symbol).Limit(limit).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	book := &OrderBook{
+		Bids: make([]PriceLevel, 0, len(depth.Bids)),
+		Asks: make([]PriceLevel, 0, len(depth.Asks)),
+	}
+	for _, b := range depth.Bids {
+		book.Bids = append(book.Bids, PriceLevel{Price: parseFloat(b.Price), Quantity: parseFloat(b.Quantity)})
+	}
+	for _, a := range depth.Asks {
+		book.Asks = append(book.Asks, PriceLevel{Price: parseFloat(a.Price), Quantity: parseFloat(a.Quantity)})
+	}
+	return book, nil
+}
+
+// GetPrice returns the latest traded price for a symbol
+func (e *BinanceExchange) GetPrice(ctx context.Context, symbol string) (float64, error) {
+	prices, err := e.client.NewListPricesService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(prices) == 0 {
+		return 0, fmt.Errorf("no price returned for %s", symbol)
+	}
+	return strconv.ParseFloat(prices[0].Price, 64)
+}
+
+// GetServerTime returns the exchange's clock
+func (e *BinanceExchange) GetServerTime(ctx context.Context) (time.Time, error) {
+	serverTime, err := e.client.NewServerTimeService().Do(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(serverTime), nil
+}
+
+// GetSymbolFilters loads exchangeInfo for a symbol and extracts its trading rules
+func (e *BinanceExchange) GetSymbolFilters(ctx context.Context, symbol string) (*SymbolFilters, error) {
+	info, err := e.client.NewExchangeInfoService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range info.Symbols {
+		if s.Symbol != symbol {
+			continue
+		}
+
+		filters := &SymbolFilters{
+			Symbol:     s.Symbol,
+			BaseAsset:  s.BaseAsset,
+			QuoteAsset: s.QuoteAsset,
+		}
+
+		if lot := s.LotSizeFilter(); lot != nil {
+			filters.MinQty = parseFloat(lot.MinQuantity)
+			filters.MaxQty = parseFloat(lot.MaxQuantity)
+			filters.StepSize = parseFloat(lot.StepSize)
+		}
+		if pf := s.PriceFilter(); pf != nil {
+			filters.MinPrice = parseFloat(pf.MinPrice)
+			filters.MaxPrice = parseFloat(pf.MaxPrice)
+			filters.TickSize = parseFloat(pf.TickSize)
+		}
+		if nf := s.NotionalFilter(); nf != nil {
+			filters.MinNotional = parseFloat(nf.MinNotional)
+		}
+
+		// Older symbols still publish the legacy MIN_NOTIONAL filter
+		if filters.MinNotional == 0 {
+			for _, f := range s.Filters {
+				if f["filterType"] == "MIN_NOTIONAL" {
+					if v, ok := f["minNotional"].(string); ok {
+						filters.MinNotional = parseFloat(v)
+					}
+				}
+			}
+		}
+
+		return filters, nil
+	}
+
+	return nil, fmt.Errorf("symbol %s not found in exchange info", symbol)
+}
+
+// maxKlinesPerRequest is Binance's limit for the klines endpoint
+const maxKlinesPerRequest = 1000
+
+// GetKlines returns the most recent candles for a symbol
+func (e *BinanceExchange) GetKlines(ctx context.Context, symbol, interval string, limit int) ([]Kline, error) {
+	if limit <= 0 || limit > maxKlinesPerRequest {
+		limit = maxKlinesPerRequest
+	}
+
+	klines, err := e.client.NewKlinesService().Symbol(symbol).Interval(interval).Limit(limit).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return convertKlines(klines), nil
+}
+
+// GetKlinesSince returns the candles opening at or after since
+func (e *BinanceExchange) GetKlinesSince(ctx context.Context, symbol, interval string, since time.Time, limit int) ([]Kline, error) {
+	if limit <= 0 || limit > maxKlinesPerRequest {
+		limit = maxKlinesPerRequest
+	}
+
+	klines, err := e.client.NewKlinesService().Symbol(symbol).Interval(interval).StartTime(since.UnixMilli()).Limit(limit).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return convertKlines(klines), nil
+}
+
+// convertKlines converts Binance klines to the exchange type
+func convertKlines(klines []*binance.Kline) []Kline {
+	result := make([]Kline, 0, len(klines))
+	for _, k := range klines {
+		result = append(result, Kline{
+			OpenTime:       time.UnixMilli(k.OpenTime),
+			CloseTime:      time.UnixMilli(k.CloseTime),
+			Open:           parseFloat(k.Open),
+			High:           parseFloat(k.High),
+			Low:            parseFloat(k.Low),
+			Close:          parseFloat(k.Close),
+			Volume:         parseFloat(k.Volume),
+			Trades:         k.TradeNum,
+			TakerBuyVolume: parseFloat(k.TakerBuyBaseAssetVolume),
+		})
+	}
+	return result
+}
+
+// GetAccountTrades returns the account's executions for a symbol (myTrades)
+func (e *BinanceExchange) GetAccountTrades(ctx context.Context, symbol string, since time.Time, limit int) ([]AccountTrade, error) {
+	res, err := e.client.NewListTradesService().
+		Symbol(symbol).
+		StartTime(since.UnixMilli()).
+		Limit(limit).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	trades := make([]AccountTrade, 0, len(res))
+	for _, t := range res {
+		side := SideSell
+		if t.IsBuyer {
+			side = SideBuy
+		}
+		trades = append(trades, AccountTrade{
+			TradeID:         t.ID,
+			OrderID:         t.OrderID,
+			Symbol:          t.Symbol,
+			Side:            side,
+			Price:           parseFloat(t.Price),
+			Quantity:        parseFloat(t.Quantity),
+			QuoteQuantity:   parseFloat(t.QuoteQuantity),
+			Commission:      parseFloat(t.Commission),
+			CommissionAsset: t.CommissionAsset,
+			Time:            time.UnixMilli(t.Time),
+		})
+	}
+	return trades, nil
+}
+
+// convertOrder maps a Binance order to an Order
+func convertOrder(o *binance.Order) *Order {
+	return &Order{
+		OrderID:          o.OrderID,
+		ClientOrderID:    o.ClientOrderID,
+		Symbol:           o.Symbol,
+		Side:             OrderSide(o.Side),
+		Type:             OrderType(o.Type),
+		Status:           OrderStatus(o.Status),
+		Price:            parseFloat(o.Price),
+		OrigQuantity:     parseFloat(o.OrigQuantity),
+		ExecutedQuantity: parseFloat(o.ExecutedQuantity),
+		QuoteQuantity:    parseFloat(o.CummulativeQuoteQuantity),
+		TransactTime:     time.UnixMilli(o.UpdateTime),
+	}
+}
+
+// parseFloat parses a Binance decimal string, returning 0 on malformed input
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
@@ -0,0 +1,208 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// userStreamKeepalive is how often the listenKey is extended (it expires after 60 minutes)
+	userStreamKeepalive = 30 * time.Minute
+
+	// userStreamReadTimeout drops a connection that has gone quiet; Binance pings every few minutes
+	userStreamReadTimeout = 10 * time.Minute
+
+	// userStreamMaxBackoff caps the delay between reconnection attempts
+	userStreamMaxBackoff = time.Minute
+)
+
+// errListenKeyExpired ends a connection whose listenKey the exchange expired
+var errListenKeyExpired = errors.New("listen key expired")
+
+// StreamUserData consumes the account's user data stream: it creates a listenKey, keeps it
+// alive, and reconnects with a new one (backing off up to a minute) whenever the connection drops
+func (e *BinanceExchange) StreamUserData(ctx context.Context, handler UserDataHandler) error {
+	backoff := time.Second
+	for {
+		connected, err := e.runUserStream(ctx, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if handler.OnError != nil {
+			handler.OnError(fmt.Errorf("user data stream disconnected, reconnecting in %s: %w", backoff, err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		if connected {
+			backoff = time.Second
+		} else if backoff *= 2; backoff > userStreamMaxBackoff {
+			backoff = userStreamMaxBackoff
+		}
+	}
+}
+
+// runUserStream serves one listenKey until the connection fails; connected reports whether it got that far
+func (e *BinanceExchange) runUserStream(ctx context.Context, handler UserDataHandler) (connected bool, err error) {
+	listenKey, err := e.client.NewStartUserStreamService().Do(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to start user data stream: %w", err)
+	}
+	defer e.client.NewCloseUserStreamService().ListenKey(listenKey).Do(context.Background())
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, e.userStreamURL()+listenKey, nil)
+	if err != nil {
+		return false, fmt.Errorf("user data stream dial failed: %w", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(userStreamReadTimeout))
+	conn.SetPingHandler(func(appData string) error {
+		conn.SetReadDeadline(time.Now().Add(userStreamReadTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(10*time.Second))
+	})
+
+	if handler.OnConnect != nil {
+		handler.OnConnect()
+	}
+
+	// Keep the listenKey alive; closing the connection ends the read loop below
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(userStreamKeepalive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				conn.Close()
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := e.client.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(ctx); err != nil {
+					conn.Close() // Reconnect with a new listenKey
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return true, err
+		}
+		conn.SetReadDeadline(time.Now().Add(userStreamReadTimeout))
+
+		if err := dispatchUserData(message, handler); err != nil {
+			if errors.Is(err, errListenKeyExpired) {
+				return true, err
+			}
+			if handler.OnError != nil {
+				handler.OnError(err)
+			}
+		}
+	}
+}
+
+// userStreamURL returns the WebSocket base matching the REST endpoint (testnet or production)
+func (e *BinanceExchange) userStreamURL() string {
+	if strings.Contains(e.client.BaseURL, "testnet") {
+		return "wss://testnet.binance.vision/ws/"
+	}
+	return "wss://stream.binance.com:9443/ws/"
+}
+
+// dispatchUserData decodes a user data stream message and passes it to the handler
+func dispatchUserData(message []byte, handler UserDataHandler) error {
+	var event struct {
+		Type string `json:"e"`
+		Time int64  `json:"E"`
+	}
+	if err := json.Unmarshal(message, &event); err != nil {
+		return fmt.Errorf("failed to decode user data event: %w", err)
+	}
+
+	switch event.Type {
+	case "executionReport":
+		var update binance.WsOrderUpdate
+		if err := json.Unmarshal(message, &update); err != nil {
+			return fmt.Errorf("failed to decode executionReport: %w", err)
+		}
+		if handler.OnOrder != nil {
+			handler.OnOrder(convertOrderUpdate(&update))
+		}
+
+	case "outboundAccountPosition":
+		var update binance.WsAccountUpdateList
+		if err := json.Unmarshal(message, &update); err != nil {
+			return fmt.Errorf("failed to decode outboundAccountPosition: %w", err)
+		}
+		account := AccountUpdate{Time: time.UnixMilli(event.Time)}
+		for _, b := range update.WsAccountUpdates {
+			account.Balances = append(account.Balances, Balance{
+				Asset:  b.Asset,
+				Free:   parseFloat(b.Free),
+				Locked: parseFloat(b.Locked),
+			})
+		}
+		if handler.OnAccount != nil {
+			handler.OnAccount(account)
+		}
+
+	case "listenKeyExpired":
+		return errListenKeyExpired
+	}
+
+	return nil
+}
+
+// convertOrderUpdate maps an executionReport to an OrderUpdate
+func convertOrderUpdate(u *binance.WsOrderUpdate) OrderUpdate {
+	clientOrderID := u.ClientOrderId
+	if u.OrigCustomOrderId != "" {
+		clientOrderID = u.OrigCustomOrderId // Cancellations carry the cancel request's ID in c
+	}
+
+	update := OrderUpdate{
+		ExecutionType: u.ExecutionType,
+		Order: Order{
+			OrderID:          u.Id,
+			ClientOrderID:    clientOrderID,
+			Symbol:           u.Symbol,
+			Side:             OrderSide(u.Side),
+			Type:             OrderType(u.Type),
+			Status:           OrderStatus(u.Status),
+			Price:            parseFloat(u.Price),
+			OrigQuantity:     parseFloat(u.Volume),
+			ExecutedQuantity: parseFloat(u.FilledVolume),
+			QuoteQuantity:    parseFloat(u.FilledQuoteVolume),
+			TransactTime:     time.UnixMilli(u.TransactionTime),
+		},
+	}
+
+	if u.ExecutionType == "TRADE" {
+		update.LastFill = &Fill{
+			TradeID:         u.TradeId,
+			Price:           parseFloat(u.LatestPrice),
+			Quantity:        parseFloat(u.LatestVolume),
+			Commission:      parseFloat(u.FeeCost),
+			CommissionAsset: u.FeeAsset,
+		}
+		update.Order.Fills = []Fill{*update.LastFill}
+	}
+
+	return update
+}
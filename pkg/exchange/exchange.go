@@ -0,0 +1,395 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OrderSide is the direction of an order
+type OrderSide string
+
+const (
+	SideBuy  OrderSide = "BUY"
+	SideSell OrderSide = "SELL"
+)
+
+// OrderType is the execution type of an order
+type OrderType string
+
+const (
+	OrderTypeMarket     OrderType = "MARKET"
+	OrderTypeLimit      OrderType = "LIMIT"
+	OrderTypeLimitMaker OrderType = "LIMIT_MAKER" // Post-only limit order, rejected if it would take liquidity
+)
+
+// OrderStatus is the lifecycle state of an order on the exchange
+type OrderStatus string
+
+const (
+	OrderStatusNew             OrderStatus = "NEW"
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	OrderStatusFilled          OrderStatus = "FILLED"
+	OrderStatusCanceled        OrderStatus = "CANCELED"
+	OrderStatusRejected        OrderStatus = "REJECTED"
+	OrderStatusExpired         OrderStatus = "EXPIRED"
+)
+
+// Exchange is the set of venue operations the bot and safety checks depend on.
+// Implementations: BinanceExchange (live/testnet) and FakeExchange (in-memory, for tests).
+type Exchange interface {
+	// PlaceOrder submits a new order and returns the exchange's view of it
+	PlaceOrder(ctx context.Context, req OrderRequest) (*Order, error)
+
+	// CancelOrder cancels an open order
+	CancelOrder(ctx context.Context, symbol string, orderID int64) (*Order, error)
+
+	// GetOrder returns the current state of an order
+	GetOrder(ctx context.Context, symbol string, orderID int64) (*Order, error)
+
+	// GetBalances returns all account balances
+	GetBalances(ctx context.Context) ([]Balance, error)
+
+	// GetOrderBook returns up to limit price levels on each side of the book
+	GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error)
+
+	// GetPrice returns the latest traded price for a symbol
+	GetPrice(ctx context.Context, symbol string) (float64, error)
+
+	// GetServerTime returns the exchange's clock
+	GetServerTime(ctx context.Context) (time.Time, error)
+
+	// GetSymbolFilters returns trading rules (lot size, tick size, min notional) for a symbol
+	GetSymbolFilters(ctx context.Context, symbol string) (*SymbolFilters, error)
+
+	// GetKlines returns up to limit of the most recent candles, oldest first.
+	// The last kline may still be open (CloseTime in the future).
+	GetKlines(ctx context.Context, symbol, interval string, limit int) ([]Kline, error)
+
+	// GetKlinesSince returns up to limit candles opening at or after since, oldest first.
+	// Used to backfill candles missed while the stream was down.
+	GetKlinesSince(ctx context.Context, symbol, interval string, since time.Time, limit int) ([]Kline, error)
+
+	// GetAccountTrades returns up to limit of the account's executions for a symbol
+	// since the given time, oldest first
+	GetAccountTrades(ctx context.Context, symbol string, since time.Time, limit int) ([]AccountTrade, error)
+}
+
+// UserDataStreamer is implemented by exchanges that push account and order updates
+// (Binance's user data stream). The paper simulator does not: it has no outside activity.
+type UserDataStreamer interface {
+	// StreamUserData delivers updates to handler until ctx is cancelled, reconnecting as needed
+	StreamUserData(ctx context.Context, handler UserDataHandler) error
+}
+
+// ShortSeller is implemented by exchanges that can sell more of a base asset than the
+// account holds (margin or futures accounts). Spot Binance cannot; the paper simulator can when configured to.
+type ShortSeller interface {
+	// CanShort reports whether sells may exceed the base balance, borrowing the rest
+	CanShort() bool
+}
+
+// UserDataHandler receives user data stream events; nil callbacks are skipped
+type UserDataHandler struct {
+	OnConnect func() // After every (re)connection; updates missed while disconnected are not replayed
+	OnOrder   func(OrderUpdate)
+	OnAccount func(AccountUpdate)
+	OnError   func(error) // Connection and decoding errors; the stream reconnects by itself
+}
+
+// OrderUpdate is a change to one of the account's orders (executionReport)
+type OrderUpdate struct {
+	Order         Order  // State after the event; Fills holds only LastFill
+	ExecutionType string // NEW, CANCELED, REPLACED, REJECTED, TRADE or EXPIRED
+	LastFill      *Fill  // The execution of a TRADE event, nil otherwise
+}
+
+// AccountUpdate carries the balances changed by an event (outboundAccountPosition)
+type AccountUpdate struct {
+	Balances []Balance
+	Time     time.Time
+}
+
+// OrderRequest describes an order to submit
+type OrderRequest struct {
+	Symbol        string
+	Side          OrderSide
+	Type          OrderType
+	Quantity      float64
+	Price         float64 // Required for limit orders, ignored for market orders
+	TimeInForce   string  // "GTC", "IOC", "FOK" (LIMIT orders only; LIMIT_MAKER is always GTC)
+	ClientOrderID string  // Optional idempotency key
+}
+
+// Order is the exchange's view of a submitted order
+type Order struct {
+	OrderID          int64
+	ClientOrderID    string
+	Symbol           string
+	Side             OrderSide
+	Type             OrderType
+	Status           OrderStatus
+	Price            float64 // Limit price (0 for market orders)
+	OrigQuantity     float64
+	ExecutedQuantity float64
+	QuoteQuantity    float64 // Cumulative quote asset spent/received
+	Fills            []Fill
+	TransactTime     time.Time
+}
+
+// IsOpen reports whether the order can still fill
+func (o *Order) IsOpen() bool {
+	return o.Status == OrderStatusNew || o.Status == OrderStatusPartiallyFilled
+}
+
+// AveragePrice returns the volume-weighted fill price, or 0 if nothing has executed
+func (o *Order) AveragePrice() float64 {
+	if o.ExecutedQuantity == 0 {
+		return 0
+	}
+	return o.QuoteQuantity / o.ExecutedQuantity
+}
+
+// CommissionIn returns the total commission charged in the given asset
+func (o *Order) CommissionIn(asset string) float64 {
+	total := 0.0
+	for _, f := range o.Fills {
+		if f.CommissionAsset == asset {
+			total += f.Commission
+		}
+	}
+	return total
+}
+
+// Fill is a single execution against an order
+type Fill struct {
+	TradeID         int64
+	Price           float64
+	Quantity        float64
+	Commission      float64
+	CommissionAsset string
+}
+
+// AccountTrade is an execution of one of the account's orders, as listed by the exchange
+type AccountTrade struct {
+	TradeID         int64
+	OrderID         int64
+	Symbol          string
+	Side            OrderSide
+	Price           float64
+	Quantity        float64
+	QuoteQuantity   float64
+	Commission      float64
+	CommissionAsset string
+	Time            time.Time
+}
+
+// AccountTrades lists the fills of an order as account trades
+func (o *Order) AccountTrades() []AccountTrade {
+	trades := make([]AccountTrade, 0, len(o.Fills))
+	for _, f := range o.Fills {
+		trades = append(trades, AccountTrade{
+			TradeID:         f.TradeID,
+			OrderID:         o.OrderID,
+			Symbol:          o.Symbol,
+			Side:            o.Side,
+			Price:           f.Price,
+			Quantity:        f.Quantity,
+			QuoteQuantity:   f.Price * f.Quantity,
+			Commission:      f.Commission,
+			CommissionAsset: f.CommissionAsset,
+			Time:            o.TransactTime,
+		})
+	}
+	return trades
+}
+
+// OrdersFromTrades groups account trades into filled orders, in order of first execution.
+// Status is FILLED and OrigQuantity the executed quantity, as trades do not carry the order's state.
+func OrdersFromTrades(trades []AccountTrade) []*Order {
+	var orders []*Order
+	byID := make(map[int64]*Order)
+	for _, t := range trades {
+		order, ok := byID[t.OrderID]
+		if !ok {
+			order = &Order{
+				OrderID: t.OrderID,
+				Symbol:  t.Symbol,
+				Side:    t.Side,
+				Status:  OrderStatusFilled,
+			}
+			byID[t.OrderID] = order
+			orders = append(orders, order)
+		}
+
+		order.OrigQuantity += t.Quantity
+		order.ExecutedQuantity += t.Quantity
+		order.QuoteQuantity += t.QuoteQuantity
+		order.TransactTime = t.Time
+		order.Fills = append(order.Fills, Fill{
+			TradeID:         t.TradeID,
+			Price:           t.Price,
+			Quantity:        t.Quantity,
+			Commission:      t.Commission,
+			CommissionAsset: t.CommissionAsset,
+		})
+	}
+	return orders
+}
+
+// Balance holds the free and locked amount of an asset
+type Balance struct {
+	Asset  string
+	Free   float64
+	Locked float64
+}
+
+// Total returns free + locked
+func (b Balance) Total() float64 {
+	return b.Free + b.Locked
+}
+
+// PriceLevel is a single aggregated level in the order book
+type PriceLevel struct {
+	Price    float64
+	Quantity float64
+}
+
+// OrderBook is a depth snapshot; bids are sorted best (highest) first, asks best (lowest) first
+type OrderBook struct {
+	Bids []PriceLevel
+	Asks []PriceLevel
+}
+
+// Kline is a single OHLCV candle
+type Kline struct {
+	OpenTime       time.Time
+	CloseTime      time.Time
+	Open           float64
+	High           float64
+	Low            float64
+	Close          float64
+	Volume         float64
+	Trades         int64   // Number of trades
+	TakerBuyVolume float64 // Base asset volume bought by takers
+}
+
+// IsClosed reports whether the candle had closed at the given time
+func (k Kline) IsClosed(now time.Time) bool {
+	return !k.CloseTime.After(now)
+}
+
+// SymbolFilters holds the trading rules of a symbol
+type SymbolFilters struct {
+	Symbol      string
+	BaseAsset   string
+	QuoteAsset  string
+	MinQty      float64 // LOT_SIZE
+	MaxQty      float64
+	StepSize    float64
+	MinPrice    float64 // PRICE_FILTER
+	MaxPrice    float64
+	TickSize    float64
+	MinNotional float64 // MIN_NOTIONAL / NOTIONAL
+}
+
+// Symbol filter names, as reported by Binance in -1013 rejections
+const (
+	FilterLotSize     = "LOT_SIZE"
+	FilterPrice       = "PRICE_FILTER"
+	FilterMinNotional = "MIN_NOTIONAL"
+)
+
+// FilterError is returned for an order that the exchange would reject with a filter failure
+type FilterError struct {
+	Symbol string
+	Filter string // FilterLotSize, FilterPrice or FilterMinNotional
+	Reason string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("%s: %s filter failure: %s", e.Symbol, e.Filter, e.Reason)
+}
+
+// IsFilterError reports whether err is a FilterError for the given filter ("" = any filter)
+func IsFilterError(err error, filter string) bool {
+	var filterErr *FilterError
+	return errors.As(err, &filterErr) && (filter == "" || filterErr.Filter == filter)
+}
+
+// RoundQuantity rounds a quantity down to the LOT_SIZE step and caps it at MaxQty
+func (f *SymbolFilters) RoundQuantity(quantity float64) float64 {
+	if f.MaxQty > 0 && quantity > f.MaxQty {
+		quantity = f.MaxQty
+	}
+	return floorToStep(quantity, f.StepSize)
+}
+
+// RoundPrice rounds a price to the nearest PRICE_FILTER tick
+func (f *SymbolFilters) RoundPrice(price float64) float64 {
+	if f.TickSize <= 0 {
+		return price
+	}
+	return floorToStep(price+f.TickSize/2, f.TickSize)
+}
+
+// Validate checks an order against LOT_SIZE, PRICE_FILTER and MIN_NOTIONAL.
+// For market orders price is the expected fill price; it is only used for the notional.
+func (f *SymbolFilters) Validate(quantity, price float64, market bool) error {
+	filterErr := func(filter, format string, args ...interface{}) error {
+		return &FilterError{Symbol: f.Symbol, Filter: filter, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if quantity <= 0 {
+		return filterErr(FilterLotSize, "quantity %.8f must be positive", quantity)
+	}
+	if f.MinQty > 0 && quantity < f.MinQty {
+		return filterErr(FilterLotSize, "quantity %.8f below minimum %.8f", quantity, f.MinQty)
+	}
+	if f.MaxQty > 0 && quantity > f.MaxQty {
+		return filterErr(FilterLotSize, "quantity %.8f above maximum %.8f", quantity, f.MaxQty)
+	}
+	if f.StepSize > 0 && floorToStep(quantity, f.StepSize) != quantity {
+		return filterErr(FilterLotSize, "quantity %.8f is not a multiple of step %.8f", quantity, f.StepSize)
+	}
+
+	if !market {
+		if f.MinPrice > 0 && price < f.MinPrice {
+			return filterErr(FilterPrice, "price %.8f below minimum %.8f", price, f.MinPrice)
+		}
+		if f.MaxPrice > 0 && price > f.MaxPrice {
+			return filterErr(FilterPrice, "price %.8f above maximum %.8f", price, f.MaxPrice)
+		}
+		if f.TickSize > 0 && f.RoundPrice(price) != price {
+			return filterErr(FilterPrice, "price %.8f is not a multiple of tick %.8f", price, f.TickSize)
+		}
+	}
+
+	if f.MinNotional > 0 && quantity*price < f.MinNotional {
+		return filterErr(FilterMinNotional, "order value %.8f below minimum %.8f", quantity*price, f.MinNotional)
+	}
+	return nil
+}
+
+// floorToStep rounds value down to a multiple of step (no-op when step is 0)
+func floorToStep(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	// The epsilon keeps exact multiples from flooring one step down (0.3/0.1 = 2.9999...);
+	// it grows with the number of steps, as does the division's rounding error
+	ratio := value / step
+	steps := math.Floor(ratio + math.Max(1e-9, ratio*1e-12))
+	// Round to the decimals of the step to drop float noise (3*0.1 = 0.30000000000000004);
+	// they come from its shortest decimal form, so a 0.25 step keeps two
+	decimals := 0
+	if s := strconv.FormatFloat(step, 'f', -1, 64); strings.Contains(s, ".") {
+		decimals = len(s) - strings.IndexByte(s, '.') - 1
+	}
+	scale := math.Pow10(decimals)
+	return math.Round(steps*step*scale) / scale
+}
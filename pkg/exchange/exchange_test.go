@@ -0,0 +1,146 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFloorToStep(t *testing.T) {
+	tests := []struct {
+		value, step, want float64
+	}{
+		{0.1 + 0.2, 0.1, 0.3}, // 0.30000000000000004
+		{0.3, 0.1, 0.3},       // 0.3/0.1 = 2.9999999999999996
+		{0.7, 0.1, 0.7},
+		{3 * 0.1, 0.1, 0.3},
+		{1.23456789, 0.001, 1.234},
+		{1.2349999, 0.001, 1.234},
+		{0.00000999, 0.00001, 0},
+		{0.000019, 0.00001, 0.00001},
+		{12345.6789, 1, 12345},
+		{7.9, 0.5, 7.5},
+		{0.3, 0.25, 0.25}, // Steps that are not powers of ten keep all their decimals
+		{0.03, 0.025, 0.025},
+		{1.99, 0.25, 1.75},
+		{0.0749, 0.0125, 0.0625},
+		{1234567, 1000, 1234000},
+		{9000, 0.00001, 9000}, // Many steps: 899999999.9999999
+		{8999.99999, 0.00001, 8999.99999},
+		{0.12345, 0, 0.12345}, // No step
+		{0, 0.01, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v/%v", tt.value, tt.step), func(t *testing.T) {
+			if got := floorToStep(tt.value, tt.step); got != tt.want {
+				t.Errorf("floorToStep(%v, %v) = %v, want %v", tt.value, tt.step, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSymbolFilters_Round(t *testing.T) {
+	filters := &SymbolFilters{Symbol: "BTCUSDT", StepSize: 0.00001, MaxQty: 9000, TickSize: 0.01}
+
+	quantities := []struct{ in, want float64 }{
+		{0.123456789, 0.12345},
+		{0.1 + 0.2, 0.3},
+		{10000, 9000}, // Capped at MaxQty
+	}
+	for _, q := range quantities {
+		if got := filters.RoundQuantity(q.in); got != q.want {
+			t.Errorf("RoundQuantity(%v) = %v, want %v", q.in, got, q.want)
+		}
+	}
+
+	prices := []struct{ in, want float64 }{
+		{45000.123, 45000.12},
+		{45000.126, 45000.13}, // Nearest tick, not floor
+		{0.1 + 0.2, 0.3},
+		{45000, 45000},
+	}
+	for _, p := range prices {
+		if got := filters.RoundPrice(p.in); got != p.want {
+			t.Errorf("RoundPrice(%v) = %v, want %v", p.in, got, p.want)
+		}
+	}
+}
+
+func TestSymbolFilters_Validate(t *testing.T) {
+	filters := &SymbolFilters{
+		Symbol:      "BTCUSDT",
+		MinQty:      0.0001,
+		MaxQty:      100,
+		StepSize:    0.0001,
+		MinPrice:    0.01,
+		MaxPrice:    1000000,
+		TickSize:    0.01,
+		MinNotional: 5,
+	}
+
+	tests := []struct {
+		name       string
+		quantity   float64
+		price      float64
+		market     bool
+		wantFilter string // "" = valid
+	}{
+		{"valid limit", 0.001, 45000, false, ""},
+		{"valid market", 0.001, 45000.123, true, ""},
+		{"step from float sum", 0.1 + 0.2, 100, false, ""},
+		{"zero quantity", 0, 45000, true, FilterLotSize},
+		{"below min quantity", 0.00005, 45000, true, FilterLotSize},
+		{"above max quantity", 101, 45000, true, FilterLotSize},
+		{"off the step", 0.00015, 45000, true, FilterLotSize},
+		{"limit below min price", 50, 0.001, false, FilterPrice},
+		{"limit above max price", 0.001, 2000000, false, FilterPrice},
+		{"limit off the tick", 0.001, 45000.123, false, FilterPrice},
+		{"market ignores the price filter", 0.001, 2000000, true, ""},
+		{"below min notional", 0.0001, 45000, true, FilterMinNotional},
+		{"limit below min notional", 0.0001, 45000, false, FilterMinNotional},
+		{"exactly min notional", 0.0001, 50000, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := filters.Validate(tt.quantity, tt.price, tt.market)
+			if tt.wantFilter == "" {
+				if err != nil {
+					t.Errorf("Validate(%v, %v, %v) = %v, want nil", tt.quantity, tt.price, tt.market, err)
+				}
+				return
+			}
+			if !IsFilterError(err, tt.wantFilter) {
+				t.Errorf("Validate(%v, %v, %v) = %v, want a %s failure", tt.quantity, tt.price, tt.market, err, tt.wantFilter)
+			}
+		})
+	}
+}
+
+func TestFilterError(t *testing.T) {
+	err := (&SymbolFilters{Symbol: "BTCUSDT", MinNotional: 10}).Validate(0.0001, 45000, true)
+
+	var filterErr *FilterError
+	if !errors.As(err, &filterErr) {
+		t.Fatalf("Validate returned %T, want *FilterError", err)
+	}
+	if filterErr.Symbol != "BTCUSDT" || filterErr.Filter != FilterMinNotional {
+		t.Errorf("FilterError = %+v", filterErr)
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "BTCUSDT: MIN_NOTIONAL filter failure: ") {
+		t.Errorf("Error() = %q", msg)
+	}
+
+	wrapped := fmt.Errorf("safety check failed: %w", err)
+	if !IsFilterError(wrapped, FilterMinNotional) || !IsFilterError(wrapped, "") {
+		t.Error("IsFilterError does not see through wrapping")
+	}
+	if IsFilterError(wrapped, FilterLotSize) {
+		t.Error("IsFilterError matched the wrong filter")
+	}
+	if IsFilterError(errors.New("MIN_NOTIONAL"), "") || IsFilterError(nil, "") {
+		t.Error("IsFilterError matched a non-filter error")
+	}
+}
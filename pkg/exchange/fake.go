@@ -0,0 +1,352 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FakeExchange is an in-memory Exchange for tests and benchmarks.
+// Market orders fill immediately at the configured price; limit orders rest until
+// cancelled or filled by the test with FillOrder. LIMIT_MAKER orders that would cross
+// the configured price are rejected. It does not check balances - see the
+// paper-trading simulator for that.
+type FakeExchange struct {
+	mu sync.Mutex
+
+	prices   map[string]float64
+	books    map[string]*OrderBook
+	filters  map[string]*SymbolFilters
+	balances map[string]Balance
+	orders   map[int64]*Order
+	klines   map[string][]Kline // Keyed by symbol + "@" + interval
+
+	nextOrderID int64
+	nextTradeID int64
+
+	// Err, when set, is returned by every call (simulates an outage)
+	Err error
+}
+
+// NewFakeExchange creates an empty fake exchange
+func NewFakeExchange() *FakeExchange {
+	return &FakeExchange{
+		prices:      make(map[string]float64),
+		books:       make(map[string]*OrderBook),
+		filters:     make(map[string]*SymbolFilters),
+		balances:    make(map[string]Balance),
+		orders:      make(map[int64]*Order),
+		klines:      make(map[string][]Kline),
+		nextOrderID: 1,
+		nextTradeID: 1,
+	}
+}
+
+// SetPrice sets the price market orders fill at
+func (f *FakeExchange) SetPrice(symbol string, price float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prices[symbol] = price
+}
+
+// SetOrderBook sets the depth snapshot returned for a symbol
+func (f *FakeExchange) SetOrderBook(symbol string, book *OrderBook) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.books[symbol] = book
+}
+
+// SetSymbolFilters sets the trading rules returned for a symbol
+func (f *FakeExchange) SetSymbolFilters(filters *SymbolFilters) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.filters[filters.Symbol] = filters
+}
+
+// SetBalance sets the free balance of an asset
+func (f *FakeExchange) SetBalance(asset string, free float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.balances[asset] = Balance{Asset: asset, Free: free}
+}
+
+// SetKlines sets the candle history returned for a symbol and interval (oldest first)
+func (f *FakeExchange) SetKlines(symbol, interval string, klines []Kline) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.klines[symbol+"@"+interval] = klines
+}
+
+// Orders returns every order placed so far, in placement order
+func (f *FakeExchange) Orders() []Order {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	orders := make([]Order, 0, len(f.orders))
+	for id := int64(1); id < f.nextOrderID; id++ {
+		if o, ok := f.orders[id]; ok {
+			orders = append(orders, *o)
+		}
+	}
+	return orders
+}
+
+// PlaceOrder records the order; market orders fill at the current price
+func (f *FakeExchange) PlaceOrder(ctx context.Context, req OrderRequest) (*Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if req.Quantity <= 0 {
+		return nil, fmt.Errorf("invalid quantity: %.8f", req.Quantity)
+	}
+	if price, ok := f.prices[req.Symbol]; ok && req.Type == OrderTypeLimitMaker &&
+		((req.Side == SideBuy && price <= req.Price) || (req.Side == SideSell && price >= req.Price)) {
+		return nil, fmt.Errorf("%w: %s %s at %.8f, market at %.8f", ErrWouldTakeLiquidity, req.Side, req.Symbol, req.Price, price)
+	}
+
+	order := &Order{
+		OrderID:       f.nextOrderID,
+		ClientOrderID: req.ClientOrderID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		Status:        OrderStatusNew,
+		Price:         req.Price,
+		OrigQuantity:  req.Quantity,
+		TransactTime:  time.Now(),
+	}
+	f.nextOrderID++
+
+	if req.Type == OrderTypeMarket {
+		price, ok := f.prices[req.Symbol]
+		if !ok {
+			return nil, fmt.Errorf("no price set for %s", req.Symbol)
+		}
+		order.Status = OrderStatusFilled
+		order.ExecutedQuantity = req.Quantity
+		order.QuoteQuantity = req.Quantity * price
+		order.Fills = []Fill{{
+			TradeID:  f.nextTradeID,
+			Price:    price,
+			Quantity: req.Quantity,
+		}}
+		f.nextTradeID++
+	}
+
+	f.orders[order.OrderID] = order
+	copied := *order
+	return &copied, nil
+}
+
+// FillOrder fills quantity of a resting order at price, as if the market traded against it.
+// The order is FILLED once its whole quantity has filled, PARTIALLY_FILLED before.
+func (f *FakeExchange) FillOrder(orderID int64, quantity, price float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	order, ok := f.orders[orderID]
+	if !ok {
+		return fmt.Errorf("order %d not found", orderID)
+	}
+	if !order.IsOpen() {
+		return fmt.Errorf("order %d is %s", orderID, order.Status)
+	}
+	if remaining := order.OrigQuantity - order.ExecutedQuantity; quantity > remaining {
+		return fmt.Errorf("order %d has only %.8f left to fill", orderID, remaining)
+	}
+
+	order.ExecutedQuantity += quantity
+	order.QuoteQuantity += quantity * price
+	order.Fills = append(order.Fills, Fill{TradeID: f.nextTradeID, Price: price, Quantity: quantity})
+	f.nextTradeID++
+
+	order.Status = OrderStatusPartiallyFilled
+	if order.ExecutedQuantity >= order.OrigQuantity {
+		order.Status = OrderStatusFilled
+	}
+	return nil
+}
+
+// CancelOrder cancels an order that has not fully filled
+func (f *FakeExchange) CancelOrder(ctx context.Context, symbol string, orderID int64) (*Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	order, ok := f.orders[orderID]
+	if !ok || order.Symbol != symbol {
+		return nil, fmt.Errorf("order %d not found", orderID)
+	}
+	if order.Status == OrderStatusFilled || order.Status == OrderStatusCanceled {
+		return nil, fmt.Errorf("order %d is %s", orderID, order.Status)
+	}
+
+	order.Status = OrderStatusCanceled
+	copied := *order
+	return &copied, nil
+}
+
+// GetOrder returns a previously placed order
+func (f *FakeExchange) GetOrder(ctx context.Context, symbol string, orderID int64) (*Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	order, ok := f.orders[orderID]
+	if !ok || order.Symbol != symbol {
+		return nil, fmt.Errorf("order %d not found", orderID)
+	}
+	copied := *order
+	return &copied, nil
+}
+
+// GetBalances returns the configured balances
+func (f *FakeExchange) GetBalances(ctx context.Context) ([]Balance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	balances := make([]Balance, 0, len(f.balances))
+	for _, b := range f.balances {
+		balances = append(balances, b)
+	}
+	return balances, nil
+}
+
+// GetOrderBook returns the configured depth, truncated to limit
+func (f *FakeExchange) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	book, ok := f.books[symbol]
+	if !ok {
+		return &OrderBook{}, nil
+	}
+
+	result := &OrderBook{Bids: book.Bids, Asks: book.Asks}
+	if limit > 0 && len(result.Bids) > limit {
+		result.Bids = result.Bids[:limit]
+	}
+	if limit > 0 && len(result.Asks) > limit {
+		result.Asks = result.Asks[:limit]
+	}
+	return result, nil
+}
+
+// GetPrice returns the configured price
+func (f *FakeExchange) GetPrice(ctx context.Context, symbol string) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return 0, f.Err
+	}
+
+	price, ok := f.prices[symbol]
+	if !ok {
+		return 0, fmt.Errorf("no price set for %s", symbol)
+	}
+	return price, nil
+}
+
+// GetServerTime returns the local clock
+func (f *FakeExchange) GetServerTime(ctx context.Context) (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return time.Time{}, f.Err
+	}
+	return time.Now(), nil
+}
+
+// GetSymbolFilters returns the configured filters, or permissive defaults
+func (f *FakeExchange) GetSymbolFilters(ctx context.Context, symbol string) (*SymbolFilters, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	if filters, ok := f.filters[symbol]; ok {
+		copied := *filters
+		return &copied, nil
+	}
+	return &SymbolFilters{Symbol: symbol}, nil
+}
+
+// GetKlines returns the most recent configured candles
+func (f *FakeExchange) GetKlines(ctx context.Context, symbol, interval string, limit int) ([]Kline, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	klines := f.klines[symbol+"@"+interval]
+	if limit > 0 && len(klines) > limit {
+		klines = klines[len(klines)-limit:]
+	}
+	return append([]Kline(nil), klines...), nil
+}
+
+// GetKlinesSince returns the first configured candles opening at or after since
+func (f *FakeExchange) GetKlinesSince(ctx context.Context, symbol, interval string, since time.Time, limit int) ([]Kline, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	var klines []Kline
+	for _, k := range f.klines[symbol+"@"+interval] {
+		if !k.OpenTime.Before(since) {
+			klines = append(klines, k)
+		}
+	}
+	if limit > 0 && len(klines) > limit {
+		klines = klines[:limit]
+	}
+	return klines, nil
+}
+
+// GetAccountTrades returns the fills of the orders placed so far
+func (f *FakeExchange) GetAccountTrades(ctx context.Context, symbol string, since time.Time, limit int) ([]AccountTrade, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	var trades []AccountTrade
+	for id := int64(1); id < f.nextOrderID; id++ {
+		if o, ok := f.orders[id]; ok && o.Symbol == symbol && !o.TransactTime.Before(since) {
+			trades = append(trades, o.AccountTrades()...)
+		}
+	}
+	if limit > 0 && len(trades) > limit {
+		trades = trades[:limit]
+	}
+	return trades, nil
+}
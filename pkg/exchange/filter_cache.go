@@ -0,0 +1,72 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultFilterCacheTTL is how long symbol filters are reused before exchangeInfo is queried again
+const DefaultFilterCacheTTL = time.Hour
+
+// FilterCache memoizes GetSymbolFilters so orders are not preceded by an exchangeInfo request
+type FilterCache struct {
+	exchange Exchange
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedFilters
+}
+
+type cachedFilters struct {
+	filters  *SymbolFilters
+	loadedAt time.Time
+}
+
+// NewFilterCache creates a cache over an exchange (ttl <= 0 uses DefaultFilterCacheTTL)
+func NewFilterCache(ex Exchange, ttl time.Duration) *FilterCache {
+	if ttl <= 0 {
+		ttl = DefaultFilterCacheTTL
+	}
+	return &FilterCache{
+		exchange: ex,
+		ttl:      ttl,
+		entries:  make(map[string]cachedFilters),
+	}
+}
+
+// Get returns the filters of a symbol, loading them when missing or stale.
+// If a refresh fails, the stale filters are returned rather than an error.
+func (c *FilterCache) Get(ctx context.Context, symbol string) (*SymbolFilters, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[symbol]
+	c.mu.Unlock()
+
+	if ok && time.Since(entry.loadedAt) < c.ttl {
+		return entry.filters, nil
+	}
+
+	filters, err := c.exchange.GetSymbolFilters(ctx, symbol)
+	if err != nil {
+		if ok {
+			return entry.filters, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[symbol] = cachedFilters{filters: filters, loadedAt: time.Now()}
+	c.mu.Unlock()
+	return filters, nil
+}
+
+// Load fetches the filters of every symbol up front
+func (c *FilterCache) Load(ctx context.Context, symbols ...string) error {
+	for _, symbol := range symbols {
+		if _, err := c.Get(ctx, symbol); err != nil {
+			return fmt.Errorf("failed to load %s filters: %w", symbol, err)
+		}
+	}
+	return nil
+}
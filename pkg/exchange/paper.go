@@ -0,0 +1,648 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInsufficientBalance is returned when an order needs more funds than are free
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// ErrWouldTakeLiquidity is returned for a LIMIT_MAKER order that would match immediately
+var ErrWouldTakeLiquidity = errors.New("order would immediately match and take")
+
+// PaperConfig configures the paper-trading simulator
+type PaperConfig struct {
+	InitialBalances map[string]float64 `mapstructure:"initial_balances"`  // e.g. {"USDT": 1000}
+	MakerFeePercent float64            `mapstructure:"maker_fee_percent"` // Fee for resting limit orders (0.1 = 0.1%)
+	TakerFeePercent float64            `mapstructure:"taker_fee_percent"` // Fee for market and marketable orders
+
+	// Let sells exceed the base balance; a negative balance is a short (no margin or borrow fees are simulated)
+	AllowShort bool `mapstructure:"allow_short"`
+
+	// Synthetic order book, used when no market data or replayed book is available
+	SpreadPercent float64 `mapstructure:"spread_percent"` // Distance between best bid and best ask
+	DepthLevels   int     `mapstructure:"depth_levels"`   // Price levels on each side
+	LevelNotional float64 `mapstructure:"level_notional"` // Quote value available at each level
+}
+
+// DefaultPaperConfig returns Binance spot-like defaults with 1000 USDT to trade
+func DefaultPaperConfig() PaperConfig {
+	return PaperConfig{
+		InitialBalances: map[string]float64{"USDT": 1000},
+		MakerFeePercent: 0.1,
+		TakerFeePercent: 0.1,
+		SpreadPercent:   0.1,
+		DepthLevels:     20,
+		LevelNotional:   10000,
+	}
+}
+
+// PaperExchange simulates an exchange account locally.
+// It keeps virtual balances per asset, fills market orders by walking an order book
+// (a replayed book, the live book from the market exchange, or a synthetic one built
+// around the last price), charges maker/taker fees and rejects orders it cannot fund.
+// Market data reads (price, depth, server time, filters) are delegated to the market exchange.
+type PaperExchange struct {
+	mu sync.Mutex
+
+	market Exchange // Source of real market data (may be nil)
+	config PaperConfig
+
+	balances map[string]*Balance
+	prices   map[string]float64
+	books    map[string]*OrderBook
+	orders   map[int64]*Order
+	open     []int64              // Resting limit orders, in placement order
+	symbols  map[string][2]string // Cached base/quote asset per symbol
+
+	nextOrderID int64
+	nextTradeID int64
+}
+
+// NewPaperExchange creates a simulator. market may be nil, in which case prices must be
+// supplied with UpdatePrice (or books with SetOrderBook) before orders can fill.
+func NewPaperExchange(market Exchange, config PaperConfig) *PaperExchange {
+	defaults := DefaultPaperConfig()
+	if config.InitialBalances == nil {
+		config.InitialBalances = defaults.InitialBalances
+	}
+	if config.SpreadPercent <= 0 {
+		config.SpreadPercent = defaults.SpreadPercent
+	}
+	if config.DepthLevels <= 0 {
+		config.DepthLevels = defaults.DepthLevels
+	}
+	if config.LevelNotional <= 0 {
+		config.LevelNotional = defaults.LevelNotional
+	}
+
+	p := &PaperExchange{
+		market:      market,
+		config:      config,
+		balances:    make(map[string]*Balance),
+		prices:      make(map[string]float64),
+		books:       make(map[string]*OrderBook),
+		orders:      make(map[int64]*Order),
+		symbols:     make(map[string][2]string),
+		nextOrderID: 1,
+		nextTradeID: 1,
+	}
+	for asset, amount := range config.InitialBalances {
+		p.balances[strings.ToUpper(asset)] = &Balance{Asset: strings.ToUpper(asset), Free: amount}
+	}
+	return p
+}
+
+// UpdatePrice records the latest price for a symbol and fills any resting limit orders it crosses
+func (p *PaperExchange) UpdatePrice(symbol string, price float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prices[symbol] = price
+	p.matchRestingOrders(symbol, price, p.config.MakerFeePercent)
+}
+
+// CanShort reports whether sells may exceed the base balance (PaperConfig.AllowShort)
+func (p *PaperExchange) CanShort() bool {
+	return p.config.AllowShort
+}
+
+// Deposit credits free balance of an asset (e.g. to seed a position restored from the database);
+// a negative amount is owed, like a short
+func (p *PaperExchange) Deposit(asset string, amount float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.balance(strings.ToUpper(asset)).Free += amount
+}
+
+// SetOrderBook replays a depth snapshot; it takes precedence over market and synthetic books
+func (p *PaperExchange) SetOrderBook(symbol string, book *OrderBook) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if book == nil {
+		delete(p.books, symbol)
+		return
+	}
+	p.books[symbol] = book
+}
+
+// PlaceOrder simulates an order against the virtual account
+func (p *PaperExchange) PlaceOrder(ctx context.Context, req OrderRequest) (*Order, error) {
+	if req.Quantity <= 0 {
+		return nil, fmt.Errorf("invalid quantity: %.8f", req.Quantity)
+	}
+	if req.Side != SideBuy && req.Side != SideSell {
+		return nil, fmt.Errorf("invalid side: %s", req.Side)
+	}
+
+	base, quote := p.assets(ctx, req.Symbol)
+
+	switch req.Type {
+	case OrderTypeMarket:
+		book, err := p.orderBook(ctx, req.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		return p.placeMarket(req, base, quote, book)
+	case OrderTypeLimit, OrderTypeLimitMaker:
+		if req.Price <= 0 {
+			return nil, fmt.Errorf("limit order requires a price")
+		}
+		// Without a book (no price yet) the order can only rest
+		book, _ := p.orderBook(ctx, req.Symbol)
+		return p.placeLimit(req, base, quote, book)
+	default:
+		return nil, fmt.Errorf("unsupported order type: %s", req.Type)
+	}
+}
+
+// placeMarket walks the book and settles the fills as a taker
+func (p *PaperExchange) placeMarket(req OrderRequest, base, quote string, book *OrderBook) (*Order, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	levels := book.Asks
+	if req.Side == SideSell {
+		levels = book.Bids
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("no liquidity in order book for %s", req.Symbol)
+	}
+
+	// Walk the book to price the order before touching balances
+	remaining := req.Quantity
+	var fills []Fill
+	var filledQty, filledQuote float64
+	for _, level := range levels {
+		if remaining <= 0 {
+			break
+		}
+		qty := level.Quantity
+		if qty > remaining {
+			qty = remaining
+		}
+		fills = append(fills, Fill{Price: level.Price, Quantity: qty})
+		filledQty += qty
+		filledQuote += qty * level.Price
+		remaining -= qty
+	}
+
+	if req.Side == SideBuy {
+		if free := p.balance(quote).Free; free < filledQuote {
+			return nil, fmt.Errorf("%w: need %.8f %s, have %.8f", ErrInsufficientBalance, filledQuote, quote, free)
+		}
+	} else if !p.config.AllowShort {
+		if free := p.balance(base).Free; free < filledQty {
+			return nil, fmt.Errorf("%w: need %.8f %s, have %.8f", ErrInsufficientBalance, filledQty, base, free)
+		}
+	}
+
+	order := p.newOrder(req)
+	for i := range fills {
+		p.settleFill(order, &fills[i], base, quote, p.config.TakerFeePercent)
+	}
+
+	// Like Binance, a market order that exhausts the book expires instead of resting
+	order.Status = OrderStatusFilled
+	if remaining > 0 {
+		order.Status = OrderStatusExpired
+	}
+
+	p.orders[order.OrderID] = order
+	return copyOrder(order), nil
+}
+
+// placeLimit locks funds, takes whatever the book offers at the limit price or better
+// and rests the rest until the price crosses it
+func (p *PaperExchange) placeLimit(req OrderRequest, base, quote string, book *OrderBook) (*Order, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	levels, available := crossedLevels(req, book)
+	if req.Type == OrderTypeLimitMaker && len(levels) > 0 {
+		return nil, fmt.Errorf("%w: %s %s at %.8f, book at %.8f", ErrWouldTakeLiquidity, req.Side, req.Symbol, req.Price, levels[0].Price)
+	}
+
+	// FOK orders fill completely or not at all
+	if req.TimeInForce == "FOK" && available < req.Quantity {
+		order := p.newOrder(req)
+		order.Status = OrderStatusExpired
+		p.orders[order.OrderID] = order
+		return copyOrder(order), nil
+	}
+
+	if req.Side == SideBuy {
+		cost := req.Quantity * req.Price
+		b := p.balance(quote)
+		if b.Free < cost {
+			return nil, fmt.Errorf("%w: need %.8f %s, have %.8f", ErrInsufficientBalance, cost, quote, b.Free)
+		}
+		b.Free -= cost
+		b.Locked += cost
+	} else {
+		b := p.balance(base)
+		if b.Free < req.Quantity && !p.config.AllowShort {
+			return nil, fmt.Errorf("%w: need %.8f %s, have %.8f", ErrInsufficientBalance, req.Quantity, base, b.Free)
+		}
+		b.Free -= req.Quantity
+		b.Locked += req.Quantity
+	}
+
+	order := p.newOrder(req)
+	p.orders[order.OrderID] = order
+
+	// The marketable part fills immediately as a taker, at the book's prices
+	remaining := req.Quantity
+	for _, level := range levels {
+		if remaining <= 0 {
+			break
+		}
+		qty := level.Quantity
+		if qty > remaining {
+			qty = remaining
+		}
+		p.fillLocked(order, qty, level.Price, p.config.TakerFeePercent)
+		remaining -= qty
+	}
+	if remaining <= 0 {
+		order.Status = OrderStatusFilled
+	}
+
+	if order.IsOpen() {
+		// IOC and FOK orders never rest: whatever did not fill immediately expires
+		if req.TimeInForce == "IOC" || req.TimeInForce == "FOK" {
+			p.release(order)
+			order.Status = OrderStatusExpired
+		} else {
+			p.open = append(p.open, order.OrderID)
+		}
+	}
+
+	return copyOrder(order), nil
+}
+
+// crossedLevels returns the book levels a limit order would take (asks at or below a buy's
+// price, bids at or above a sell's), best first, and the quantity they offer
+func crossedLevels(req OrderRequest, book *OrderBook) ([]PriceLevel, float64) {
+	if book == nil {
+		return nil, 0
+	}
+
+	levels := book.Asks
+	if req.Side == SideSell {
+		levels = book.Bids
+	}
+
+	var available float64
+	for i, level := range levels {
+		if (req.Side == SideBuy && level.Price > req.Price) || (req.Side == SideSell && level.Price < req.Price) {
+			return levels[:i], available
+		}
+		available += level.Quantity
+	}
+	return levels, available
+}
+
+// matchRestingOrders fills resting limit orders crossed by price at their limit price (caller holds mu)
+func (p *PaperExchange) matchRestingOrders(symbol string, price, feePercent float64) {
+	still := p.open[:0]
+	for _, id := range p.open {
+		order := p.orders[id]
+		crossed := order.Symbol == symbol &&
+			((order.Side == SideBuy && price <= order.Price) || (order.Side == SideSell && price >= order.Price))
+		if !crossed {
+			still = append(still, id)
+			continue
+		}
+
+		p.fillLocked(order, order.OrigQuantity-order.ExecutedQuantity, order.Price, feePercent)
+	}
+	p.open = still
+}
+
+// fillLocked fills qty of a limit order at price, unlocking the funds reserved for it
+// at the limit price (caller holds mu)
+func (p *PaperExchange) fillLocked(order *Order, qty, price, feePercent float64) {
+	base, quote := p.cachedAssets(order.Symbol)
+	if order.Side == SideBuy {
+		p.balance(quote).Locked -= qty * order.Price
+		p.balance(quote).Free += qty * order.Price
+	} else {
+		p.balance(base).Locked -= qty
+		p.balance(base).Free += qty
+	}
+
+	fill := Fill{Price: price, Quantity: qty}
+	p.settleFill(order, &fill, base, quote, feePercent)
+	if order.ExecutedQuantity >= order.OrigQuantity {
+		order.Status = OrderStatusFilled
+	}
+}
+
+// settleFill moves balances for one fill and charges the fee in the asset received (caller holds mu)
+func (p *PaperExchange) settleFill(order *Order, fill *Fill, base, quote string, feePercent float64) {
+	fill.TradeID = p.nextTradeID
+	p.nextTradeID++
+
+	notional := fill.Quantity * fill.Price
+	if order.Side == SideBuy {
+		fill.Commission = fill.Quantity * feePercent / 100.0
+		fill.CommissionAsset = base
+		p.balance(quote).Free -= notional
+		p.balance(base).Free += fill.Quantity - fill.Commission
+	} else {
+		fill.Commission = notional * feePercent / 100.0
+		fill.CommissionAsset = quote
+		p.balance(base).Free -= fill.Quantity
+		p.balance(quote).Free += notional - fill.Commission
+	}
+
+	order.ExecutedQuantity += fill.Quantity
+	order.QuoteQuantity += notional
+	order.Fills = append(order.Fills, *fill)
+	order.Status = OrderStatusPartiallyFilled
+}
+
+// CancelOrder cancels a resting limit order and releases its locked funds
+func (p *PaperExchange) CancelOrder(ctx context.Context, symbol string, orderID int64) (*Order, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	order, ok := p.orders[orderID]
+	if !ok || order.Symbol != symbol {
+		return nil, fmt.Errorf("order %d not found", orderID)
+	}
+	if !order.IsOpen() {
+		return nil, fmt.Errorf("order %d is %s", orderID, order.Status)
+	}
+
+	p.release(order)
+	order.Status = OrderStatusCanceled
+	return copyOrder(order), nil
+}
+
+// release unlocks the funds of an open order's unfilled rest and removes it from the book (caller holds mu)
+func (p *PaperExchange) release(order *Order) {
+	base, quote := p.cachedAssets(order.Symbol)
+
+	qty := order.OrigQuantity - order.ExecutedQuantity
+	if order.Side == SideBuy {
+		p.balance(quote).Locked -= qty * order.Price
+		p.balance(quote).Free += qty * order.Price
+	} else {
+		p.balance(base).Locked -= qty
+		p.balance(base).Free += qty
+	}
+
+	for i, id := range p.open {
+		if id == order.OrderID {
+			p.open = append(p.open[:i], p.open[i+1:]...)
+			break
+		}
+	}
+}
+
+// GetOrder returns a previously placed order
+func (p *PaperExchange) GetOrder(ctx context.Context, symbol string, orderID int64) (*Order, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	order, ok := p.orders[orderID]
+	if !ok || order.Symbol != symbol {
+		return nil, fmt.Errorf("order %d not found", orderID)
+	}
+	return copyOrder(order), nil
+}
+
+// GetBalances returns the virtual balances, sorted by asset
+func (p *PaperExchange) GetBalances(ctx context.Context) ([]Balance, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	balances := make([]Balance, 0, len(p.balances))
+	for _, b := range p.balances {
+		balances = append(balances, *b)
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Asset < balances[j].Asset
+	})
+	return balances, nil
+}
+
+// GetOrderBook returns the book orders would fill against
+func (p *PaperExchange) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	book, err := p.orderBook(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OrderBook{Bids: book.Bids, Asks: book.Asks}
+	if limit > 0 && len(result.Bids) > limit {
+		result.Bids = result.Bids[:limit]
+	}
+	if limit > 0 && len(result.Asks) > limit {
+		result.Asks = result.Asks[:limit]
+	}
+	return result, nil
+}
+
+// GetPrice returns the market price, falling back to the last price seen by UpdatePrice
+func (p *PaperExchange) GetPrice(ctx context.Context, symbol string) (float64, error) {
+	if p.market != nil {
+		if price, err := p.market.GetPrice(ctx, symbol); err == nil {
+			return price, nil
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	price, ok := p.prices[symbol]
+	if !ok {
+		return 0, fmt.Errorf("no price available for %s", symbol)
+	}
+	return price, nil
+}
+
+// GetServerTime returns the market's clock, or the local clock without a market
+func (p *PaperExchange) GetServerTime(ctx context.Context) (time.Time, error) {
+	if p.market != nil {
+		return p.market.GetServerTime(ctx)
+	}
+	return time.Now(), nil
+}
+
+// GetSymbolFilters returns the market's trading rules, or rules derived from the symbol name
+func (p *PaperExchange) GetSymbolFilters(ctx context.Context, symbol string) (*SymbolFilters, error) {
+	if p.market != nil {
+		if filters, err := p.market.GetSymbolFilters(ctx, symbol); err == nil {
+			return filters, nil
+		}
+	}
+
+	base, quote := splitSymbol(symbol)
+	return &SymbolFilters{Symbol: symbol, BaseAsset: base, QuoteAsset: quote}, nil
+}
+
+// GetKlines returns candle history from the market exchange
+func (p *PaperExchange) GetKlines(ctx context.Context, symbol, interval string, limit int) ([]Kline, error) {
+	if p.market == nil {
+		return nil, fmt.Errorf("no market data source for %s klines", symbol)
+	}
+	return p.market.GetKlines(ctx, symbol, interval, limit)
+}
+
+// GetKlinesSince returns candle history from the market exchange
+func (p *PaperExchange) GetKlinesSince(ctx context.Context, symbol, interval string, since time.Time, limit int) ([]Kline, error) {
+	if p.market == nil {
+		return nil, fmt.Errorf("no market data source for %s klines", symbol)
+	}
+	return p.market.GetKlinesSince(ctx, symbol, interval, since, limit)
+}
+
+// GetAccountTrades returns the simulated fills of this session (history is not kept across restarts)
+func (p *PaperExchange) GetAccountTrades(ctx context.Context, symbol string, since time.Time, limit int) ([]AccountTrade, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var trades []AccountTrade
+	for id := int64(1); id < p.nextOrderID; id++ {
+		if o, ok := p.orders[id]; ok && o.Symbol == symbol && !o.TransactTime.Before(since) {
+			trades = append(trades, o.AccountTrades()...)
+		}
+	}
+	if limit > 0 && len(trades) > limit {
+		trades = trades[:limit]
+	}
+	return trades, nil
+}
+
+// orderBook picks the book to fill against: replayed, then live market, then synthetic
+func (p *PaperExchange) orderBook(ctx context.Context, symbol string) (*OrderBook, error) {
+	p.mu.Lock()
+	book, replayed := p.books[symbol]
+	p.mu.Unlock()
+	if replayed {
+		return book, nil
+	}
+
+	if p.market != nil {
+		if book, err := p.market.GetOrderBook(ctx, symbol, 100); err == nil && len(book.Bids) > 0 && len(book.Asks) > 0 {
+			return book, nil
+		}
+	}
+
+	price, err := p.GetPrice(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return p.syntheticBook(price), nil
+}
+
+// syntheticBook builds an evenly spaced book around price
+func (p *PaperExchange) syntheticBook(price float64) *OrderBook {
+	halfSpread := price * p.config.SpreadPercent / 200.0
+	step := price * p.config.SpreadPercent / 100.0
+
+	book := &OrderBook{
+		Bids: make([]PriceLevel, 0, p.config.DepthLevels),
+		Asks: make([]PriceLevel, 0, p.config.DepthLevels),
+	}
+	for i := 0; i < p.config.DepthLevels; i++ {
+		bid := price - halfSpread - float64(i)*step
+		ask := price + halfSpread + float64(i)*step
+		if bid > 0 {
+			book.Bids = append(book.Bids, PriceLevel{Price: bid, Quantity: p.config.LevelNotional / bid})
+		}
+		book.Asks = append(book.Asks, PriceLevel{Price: ask, Quantity: p.config.LevelNotional / ask})
+	}
+	return book
+}
+
+// assets resolves the base and quote asset of a symbol and caches the answer
+func (p *PaperExchange) assets(ctx context.Context, symbol string) (string, string) {
+	p.mu.Lock()
+	assets, ok := p.symbols[symbol]
+	p.mu.Unlock()
+	if ok {
+		return assets[0], assets[1]
+	}
+
+	filters, _ := p.GetSymbolFilters(ctx, symbol)
+	base, quote := filters.BaseAsset, filters.QuoteAsset
+	if base == "" || quote == "" {
+		base, quote = splitSymbol(symbol)
+	}
+
+	p.mu.Lock()
+	p.symbols[symbol] = [2]string{base, quote}
+	p.mu.Unlock()
+
+	return base, quote
+}
+
+// cachedAssets returns the assets resolved when the order was placed (caller holds mu)
+func (p *PaperExchange) cachedAssets(symbol string) (string, string) {
+	if assets, ok := p.symbols[symbol]; ok {
+		return assets[0], assets[1]
+	}
+	return splitSymbol(symbol)
+}
+
+// balance returns the mutable balance of an asset, creating it at zero (caller holds mu)
+func (p *PaperExchange) balance(asset string) *Balance {
+	b, ok := p.balances[asset]
+	if !ok {
+		b = &Balance{Asset: asset}
+		p.balances[asset] = b
+	}
+	return b
+}
+
+// newOrder allocates an order ID for a request (caller holds mu)
+func (p *PaperExchange) newOrder(req OrderRequest) *Order {
+	order := &Order{
+		OrderID:       p.nextOrderID,
+		ClientOrderID: req.ClientOrderID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		Status:        OrderStatusNew,
+		Price:         req.Price,
+		OrigQuantity:  req.Quantity,
+		TransactTime:  time.Now(),
+	}
+	if req.Type == OrderTypeMarket {
+		order.Price = 0
+	}
+	p.nextOrderID++
+	return order
+}
+
+func copyOrder(o *Order) *Order {
+	copied := *o
+	copied.Fills = append([]Fill(nil), o.Fills...)
+	return &copied
+}
+
+// knownQuoteAssets are checked longest first when splitting a symbol without exchange info
+var knownQuoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "BTC", "ETH", "BNB", "EUR", "TRY"}
+
+// splitSymbol guesses base and quote from a symbol like "BTCUSDT"
+func splitSymbol(symbol string) (string, string) {
+	upper := strings.ToUpper(symbol)
+	for _, quote := range knownQuoteAssets {
+		if strings.HasSuffix(upper, quote) && len(upper) > len(quote) {
+			return strings.TrimSuffix(upper, quote), quote
+		}
+	}
+	return upper, ""
+}
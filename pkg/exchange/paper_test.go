@@ -0,0 +1,281 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+// newTestPaper returns a simulator holding 10000 USDT and 1 BTC, trading against a
+// replayed BTCUSDT book with 0.1% maker and 0.2% taker fees
+func newTestPaper() *PaperExchange {
+	p := NewPaperExchange(nil, PaperConfig{
+		InitialBalances: map[string]float64{"USDT": 10000, "BTC": 1},
+		MakerFeePercent: 0.1,
+		TakerFeePercent: 0.2,
+	})
+	p.SetOrderBook("BTCUSDT", &OrderBook{
+		Bids: []PriceLevel{{Price: 99, Quantity: 1}, {Price: 98, Quantity: 1}, {Price: 97, Quantity: 5}},
+		Asks: []PriceLevel{{Price: 100, Quantity: 1}, {Price: 101, Quantity: 1}, {Price: 102, Quantity: 5}},
+	})
+	return p
+}
+
+// paperBalance returns the balance of one asset
+func paperBalance(t *testing.T, p *PaperExchange, asset string) Balance {
+	t.Helper()
+	balances, err := p.GetBalances(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range balances {
+		if b.Asset == asset {
+			return b
+		}
+	}
+	return Balance{Asset: asset}
+}
+
+func checkBalance(t *testing.T, p *PaperExchange, asset string, free, locked float64) {
+	t.Helper()
+	b := paperBalance(t, p, asset)
+	if math.Abs(b.Free-free) > 1e-9 || math.Abs(b.Locked-locked) > 1e-9 {
+		t.Errorf("%s balance = %.8f free, %.8f locked; want %.8f free, %.8f locked", asset, b.Free, b.Locked, free, locked)
+	}
+}
+
+func TestPaperExchange_MarketOrderWalksBookAsTaker(t *testing.T) {
+	p := newTestPaper()
+	order, err := p.PlaceOrder(context.Background(), OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeMarket, Quantity: 1.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if order.Status != OrderStatusFilled || len(order.Fills) != 2 {
+		t.Fatalf("order = %s with %d fills, want FILLED with 2", order.Status, len(order.Fills))
+	}
+	if got := order.AveragePrice(); math.Abs(got-150.5/1.5) > 1e-9 {
+		t.Errorf("average price = %.8f, want %.8f", got, 150.5/1.5)
+	}
+	// Taker fee, charged in the asset received
+	if got := order.CommissionIn("BTC"); math.Abs(got-0.003) > 1e-12 {
+		t.Errorf("commission = %.8f BTC, want 0.003", got)
+	}
+	checkBalance(t, p, "USDT", 10000-150.5, 0)
+	checkBalance(t, p, "BTC", 1+1.5-0.003, 0)
+}
+
+func TestPaperExchange_LimitFees(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         OrderRequest
+		updatePrice float64 // Price update after placing (0 = none)
+		wantPrice   float64
+		wantFee     float64 // In the asset received
+		feeAsset    string
+	}{
+		{
+			name:      "marketable buy fills at the ask as taker",
+			req:       OrderRequest{Side: SideBuy, Quantity: 1, Price: 105},
+			wantPrice: 100,
+			wantFee:   0.002,
+			feeAsset:  "BTC",
+		},
+		{
+			name:      "marketable sell fills at the bid as taker",
+			req:       OrderRequest{Side: SideSell, Quantity: 1, Price: 95},
+			wantPrice: 99,
+			wantFee:   99 * 0.002,
+			feeAsset:  "USDT",
+		},
+		{
+			name:        "resting buy fills at its limit as maker",
+			req:         OrderRequest{Side: SideBuy, Quantity: 1, Price: 95},
+			updatePrice: 94,
+			wantPrice:   95,
+			wantFee:     0.001,
+			feeAsset:    "BTC",
+		},
+		{
+			name:        "resting sell fills at its limit as maker",
+			req:         OrderRequest{Side: SideSell, Quantity: 1, Price: 105},
+			updatePrice: 106,
+			wantPrice:   105,
+			wantFee:     105 * 0.001,
+			feeAsset:    "USDT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPaper()
+			req := tt.req
+			req.Symbol = "BTCUSDT"
+			req.Type = OrderTypeLimit
+			order, err := p.PlaceOrder(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.updatePrice > 0 {
+				if order.Status != OrderStatusNew {
+					t.Fatalf("status before the price update = %s, want NEW", order.Status)
+				}
+				p.UpdatePrice("BTCUSDT", tt.updatePrice)
+				order, _ = p.GetOrder(context.Background(), "BTCUSDT", order.OrderID)
+			}
+
+			if order.Status != OrderStatusFilled {
+				t.Fatalf("status = %s, want FILLED", order.Status)
+			}
+			if got := order.AveragePrice(); got != tt.wantPrice {
+				t.Errorf("fill price = %.8f, want %.8f", got, tt.wantPrice)
+			}
+			if got := order.CommissionIn(tt.feeAsset); math.Abs(got-tt.wantFee) > 1e-12 {
+				t.Errorf("commission = %.8f %s, want %.8f", got, tt.feeAsset, tt.wantFee)
+			}
+			for _, asset := range []string{"USDT", "BTC"} {
+				if locked := paperBalance(t, p, asset).Locked; locked != 0 {
+					t.Errorf("%.8f %s still locked after the fill", locked, asset)
+				}
+			}
+		})
+	}
+}
+
+func TestPaperExchange_MarketableBuyIsChargedTheAsk(t *testing.T) {
+	p := newTestPaper()
+	if _, err := p.PlaceOrder(context.Background(), OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimit, Quantity: 1, Price: 105}); err != nil {
+		t.Fatal(err)
+	}
+	// Paid 100, not the 105 limit; nothing stays locked
+	checkBalance(t, p, "USDT", 9900, 0)
+	checkBalance(t, p, "BTC", 1.998, 0)
+}
+
+func TestPaperExchange_InsufficientBalance(t *testing.T) {
+	tests := []struct {
+		name string
+		req  OrderRequest
+	}{
+		{"market sell above holdings", OrderRequest{Side: SideSell, Type: OrderTypeMarket, Quantity: 2}},
+		{"market buy above cash", OrderRequest{Side: SideBuy, Type: OrderTypeMarket, Quantity: 7}},
+		{"limit buy above cash", OrderRequest{Side: SideBuy, Type: OrderTypeLimit, Quantity: 200, Price: 90}},
+		{"limit sell above holdings", OrderRequest{Side: SideSell, Type: OrderTypeLimit, Quantity: 2, Price: 110}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPaperExchange(nil, PaperConfig{InitialBalances: map[string]float64{"USDT": 500, "BTC": 1}})
+			p.SetOrderBook("BTCUSDT", newTestPaper().books["BTCUSDT"])
+
+			req := tt.req
+			req.Symbol = "BTCUSDT"
+			if _, err := p.PlaceOrder(context.Background(), req); !errors.Is(err, ErrInsufficientBalance) {
+				t.Errorf("err = %v, want ErrInsufficientBalance", err)
+			}
+			checkBalance(t, p, "USDT", 500, 0)
+			checkBalance(t, p, "BTC", 1, 0)
+		})
+	}
+
+	// Shorting lets sells run the base balance negative
+	p := NewPaperExchange(nil, PaperConfig{InitialBalances: map[string]float64{"USDT": 500}, AllowShort: true})
+	p.SetOrderBook("BTCUSDT", newTestPaper().books["BTCUSDT"])
+	if _, err := p.PlaceOrder(context.Background(), OrderRequest{Symbol: "BTCUSDT", Side: SideSell, Type: OrderTypeMarket, Quantity: 2}); err != nil {
+		t.Fatalf("short sell: %v", err)
+	}
+	checkBalance(t, p, "BTC", -2, 0)
+}
+
+func TestPaperExchange_TimeInForce(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeInForce  string
+		quantity     float64
+		wantStatus   OrderStatus
+		wantExecuted float64
+		wantLocked   float64 // USDT still reserved for the resting rest
+	}{
+		{"IOC fills what crosses and expires the rest", "IOC", 3, OrderStatusExpired, 2, 0},
+		{"IOC fills completely when the book allows", "IOC", 1.5, OrderStatusFilled, 1.5, 0},
+		{"FOK expires without fills when the book is short", "FOK", 3, OrderStatusExpired, 0, 0},
+		{"FOK fills completely when the book allows", "FOK", 2, OrderStatusFilled, 2, 0},
+		{"GTC rests the part that did not cross", "GTC", 3, OrderStatusPartiallyFilled, 2, 101},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPaper()
+			order, err := p.PlaceOrder(context.Background(), OrderRequest{
+				Symbol:      "BTCUSDT",
+				Side:        SideBuy,
+				Type:        OrderTypeLimit,
+				TimeInForce: tt.timeInForce,
+				Quantity:    tt.quantity,
+				Price:       101,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if order.Status != tt.wantStatus || math.Abs(order.ExecutedQuantity-tt.wantExecuted) > 1e-12 {
+				t.Errorf("order = %s, executed %.8f; want %s, executed %.8f", order.Status, order.ExecutedQuantity, tt.wantStatus, tt.wantExecuted)
+			}
+			b := paperBalance(t, p, "USDT")
+			if math.Abs(b.Locked-tt.wantLocked) > 1e-9 {
+				t.Errorf("locked USDT = %.8f, want %.8f", b.Locked, tt.wantLocked)
+			}
+			if math.Abs(b.Free+b.Locked+order.QuoteQuantity-10000) > 1e-9 {
+				t.Errorf("USDT not conserved: %.8f free + %.8f locked + %.8f spent", b.Free, b.Locked, order.QuoteQuantity)
+			}
+		})
+	}
+}
+
+func TestPaperExchange_LimitMakerWouldTake(t *testing.T) {
+	p := newTestPaper()
+	_, err := p.PlaceOrder(context.Background(), OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimitMaker, Quantity: 1, Price: 100})
+	if !errors.Is(err, ErrWouldTakeLiquidity) {
+		t.Errorf("err = %v, want ErrWouldTakeLiquidity", err)
+	}
+	checkBalance(t, p, "USDT", 10000, 0)
+
+	order, err := p.PlaceOrder(context.Background(), OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimitMaker, Quantity: 1, Price: 99.5})
+	if err != nil || order.Status != OrderStatusNew {
+		t.Errorf("LIMIT_MAKER inside the spread = %v, %v; want a resting order", order, err)
+	}
+}
+
+func TestPaperExchange_CancelReleasesLockedBalance(t *testing.T) {
+	p := newTestPaper()
+	ctx := context.Background()
+
+	buy, err := p.PlaceOrder(ctx, OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimit, Quantity: 2, Price: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sell, err := p.PlaceOrder(ctx, OrderRequest{Symbol: "BTCUSDT", Side: SideSell, Type: OrderTypeLimit, Quantity: 1, Price: 110})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, p, "USDT", 9820, 180)
+	checkBalance(t, p, "BTC", 0, 1)
+
+	for _, order := range []*Order{buy, sell} {
+		canceled, err := p.CancelOrder(ctx, "BTCUSDT", order.OrderID)
+		if err != nil || canceled.Status != OrderStatusCanceled {
+			t.Fatalf("cancel %d = %v, %v", order.OrderID, canceled, err)
+		}
+	}
+	checkBalance(t, p, "USDT", 10000, 0)
+	checkBalance(t, p, "BTC", 1, 0)
+
+	// Cancelled orders no longer fill or cancel
+	p.UpdatePrice("BTCUSDT", 80)
+	checkBalance(t, p, "USDT", 10000, 0)
+	if _, err := p.CancelOrder(ctx, "BTCUSDT", buy.OrderID); err == nil {
+		t.Error("second cancel succeeded")
+	}
+}
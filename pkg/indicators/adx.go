@@ -0,0 +1,214 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ADX (Average Directional Index) with the +DI/-DI lines of Wilder's DMI
+// +DM = high - previous high when it exceeds previous low - low (and is positive), else 0; -DM mirrors it
+// TR, +DM and -DM are Wilder-smoothed over period: the first value is their sum, then S = S - S/period + value
+// +DI = 100 * smoothed +DM / smoothed TR, -DI likewise
+// DX = 100 * |+DI - -DI| / (+DI + -DI)
+// ADX = mean of the first period DX values, then Wilder-smoothed
+// Needs real bars: fed closes only (Update), highs and lows are the closes.
+type ADX struct {
+	period int
+
+	prev  Candle
+	count int
+
+	// Wilder sums of TR, +DM and -DM
+	trSum, plusDMSum, minusDMSum float64
+	dmCount                      int
+
+	plusDI, minusDI float64
+	dxValues        []float64 // First period DX values, until the ADX is seeded
+	dxCount         int
+	adx             float64
+}
+
+// NewADX creates a new Average Directional Index indicator
+// Standard period: 14
+func NewADX(period int) (*ADX, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("ADX period must be positive, got %d", period)
+	}
+
+	return &ADX{
+		period:   period,
+		dxValues: make([]float64, 0, period),
+	}, nil
+}
+
+// adxDefinition registers the ADX with the indicator registry
+var adxDefinition = Definition{
+	Name:        "adx",
+	Aliases:     []string{"dmi"},
+	DisplayName: "ADX",
+	Description: "Average Directional Index with +DI/-DI: trend strength and direction",
+	CandleInput: true,
+	Params: []ParamSpec{
+		{Name: "period", Type: ParamInt, Default: 14, Min: 1, Description: "Number of candles in the lookback"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewADX(p.Int("period"))
+	},
+}
+
+// Name returns the indicator identifier
+func (a *ADX) Name() string {
+	return "ADX"
+}
+
+// Update adds a close as a bar with no range; prefer UpdateCandle
+func (a *ADX) Update(price float64, timestamp time.Time) error {
+	return a.UpdateCandle(Candle{Timestamp: timestamp, Open: price, High: price, Low: price, Close: price})
+}
+
+// UpdateCandle adds a bar and recalculates +DI, -DI and the ADX
+func (a *ADX) UpdateCandle(candle Candle) error {
+	if err := checkCandle(candle); err != nil {
+		return err
+	}
+
+	a.count++
+	prev := a.prev
+	a.prev = candle
+	if a.count == 1 {
+		return nil // Directional movement needs a previous bar
+	}
+
+	upMove := candle.High - prev.High
+	downMove := prev.Low - candle.Low
+	plusDM, minusDM := 0.0, 0.0
+	if upMove > downMove && upMove > 0 {
+		plusDM = upMove
+	}
+	if downMove > upMove && downMove > 0 {
+		minusDM = downMove
+	}
+	tr := trueRange(candle, prev.Close, true)
+
+	a.dmCount++
+	if a.dmCount <= a.period {
+		a.trSum += tr
+		a.plusDMSum += plusDM
+		a.minusDMSum += minusDM
+		if a.dmCount < a.period {
+			return nil
+		}
+	} else {
+		n := float64(a.period)
+		a.trSum = a.trSum - a.trSum/n + tr
+		a.plusDMSum = a.plusDMSum - a.plusDMSum/n + plusDM
+		a.minusDMSum = a.minusDMSum - a.minusDMSum/n + minusDM
+	}
+
+	a.plusDI, a.minusDI = 0, 0
+	if a.trSum > 0 {
+		a.plusDI = 100 * a.plusDMSum / a.trSum
+		a.minusDI = 100 * a.minusDMSum / a.trSum
+	}
+
+	dx := 0.0
+	if sum := a.plusDI + a.minusDI; sum > 0 {
+		dx = 100 * math.Abs(a.plusDI-a.minusDI) / sum
+	}
+
+	a.dxCount++
+	if a.dxCount < a.period {
+		a.dxValues = append(a.dxValues, dx)
+		return nil
+	}
+	if a.dxCount == a.period {
+		a.dxValues = append(a.dxValues, dx)
+		a.adx = average(a.dxValues)
+		a.dxValues = nil
+		return nil
+	}
+
+	a.adx = (a.adx*float64(a.period-1) + dx) / float64(a.period)
+	return nil
+}
+
+// GetValue returns the current ADX, +DI and -DI
+func (a *ADX) GetValue() (map[string]float64, bool) {
+	if !a.IsReady() {
+		return nil, false
+	}
+
+	return map[string]float64{
+		ValueKeyADX:     a.adx,
+		ValueKeyPlusDI:  a.plusDI,
+		ValueKeyMinusDI: a.minusDI,
+	}, true
+}
+
+// IsReady returns true once the ADX has been seeded (2 * period bars)
+func (a *ADX) IsReady() bool {
+	return a.dxCount >= a.period
+}
+
+// Reset clears all data
+func (a *ADX) Reset() {
+	a.prev = Candle{}
+	a.count = 0
+	a.trSum, a.plusDMSum, a.minusDMSum = 0, 0, 0
+	a.dmCount = 0
+	a.plusDI, a.minusDI = 0, 0
+	a.dxValues = make([]float64, 0, a.period)
+	a.dxCount = 0
+	a.adx = 0
+}
+
+// GetDataCount returns the number of bars received
+func (a *ADX) GetDataCount() int {
+	return a.count
+}
+
+// adxState is the saved form of an ADX's data
+type adxState struct {
+	Prev       Candle    `json:"prev"`
+	Count      int       `json:"count"`
+	TRSum      float64   `json:"tr_sum"`
+	PlusDMSum  float64   `json:"plus_dm_sum"`
+	MinusDMSum float64   `json:"minus_dm_sum"`
+	DMCount    int       `json:"dm_count"`
+	PlusDI     float64   `json:"plus_di"`
+	MinusDI    float64   `json:"minus_di"`
+	DXValues   []float64 `json:"dx_values,omitempty"`
+	DXCount    int       `json:"dx_count"`
+	ADX        float64   `json:"adx"`
+}
+
+// MarshalState saves the Wilder sums, directional indicators and ADX
+func (a *ADX) MarshalState() ([]byte, error) {
+	return encodeState(a.Name(), stateParams(a.period), adxState{
+		Prev:       a.prev,
+		Count:      a.count,
+		TRSum:      a.trSum,
+		PlusDMSum:  a.plusDMSum,
+		MinusDMSum: a.minusDMSum,
+		DMCount:    a.dmCount,
+		PlusDI:     a.plusDI,
+		MinusDI:    a.minusDI,
+		DXValues:   a.dxValues,
+		DXCount:    a.dxCount,
+		ADX:        a.adx,
+	})
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (a *ADX) UnmarshalState(data []byte) error {
+	var s adxState
+	if err := decodeState(data, a.Name(), stateParams(a.period), &s); err != nil {
+		return err
+	}
+	a.prev, a.count = s.Prev, s.Count
+	a.trSum, a.plusDMSum, a.minusDMSum, a.dmCount = s.TRSum, s.PlusDMSum, s.MinusDMSum, s.DMCount
+	a.plusDI, a.minusDI = s.PlusDI, s.MinusDI
+	a.dxValues, a.dxCount, a.adx = s.DXValues, s.DXCount, s.ADX
+	return nil
+}
@@ -0,0 +1,148 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ATR (Average True Range) with Wilder smoothing
+// True Range = max(high - low, |high - previous close|, |low - previous close|)
+// ATR = mean of the first period true ranges, then (previous ATR * (period-1) + TR) / period
+// Needs real bars: fed closes only (Update), the true range is just the close-to-close move.
+type ATR struct {
+	period int
+
+	prevClose  float64
+	trueRanges []float64 // First period true ranges, until the ATR is seeded
+	atr        float64
+	count      int
+}
+
+// NewATR creates a new Average True Range indicator
+// Standard period: 14
+func NewATR(period int) (*ATR, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("ATR period must be positive, got %d", period)
+	}
+
+	return &ATR{
+		period:     period,
+		trueRanges: make([]float64, 0, period),
+	}, nil
+}
+
+// atrDefinition registers the ATR with the indicator registry
+var atrDefinition = Definition{
+	Name:        "atr",
+	DisplayName: "ATR",
+	Description: "Average True Range: volatility in price units",
+	CandleInput: true,
+	Params: []ParamSpec{
+		{Name: "period", Type: ParamInt, Default: 14, Min: 1, Description: "Number of candles in the lookback"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewATR(p.Int("period"))
+	},
+}
+
+// Name returns the indicator identifier
+func (a *ATR) Name() string {
+	return "ATR"
+}
+
+// Update adds a close as a bar with no range; prefer UpdateCandle
+func (a *ATR) Update(price float64, timestamp time.Time) error {
+	return a.UpdateCandle(Candle{Timestamp: timestamp, Open: price, High: price, Low: price, Close: price})
+}
+
+// UpdateCandle adds a bar and recalculates the ATR
+func (a *ATR) UpdateCandle(candle Candle) error {
+	if err := checkCandle(candle); err != nil {
+		return err
+	}
+
+	tr := trueRange(candle, a.prevClose, a.count > 0)
+	a.prevClose = candle.Close
+	a.count++
+
+	if a.count < a.period {
+		a.trueRanges = append(a.trueRanges, tr)
+		return nil
+	}
+	if a.count == a.period {
+		a.trueRanges = append(a.trueRanges, tr)
+		a.atr = average(a.trueRanges)
+		a.trueRanges = nil
+		return nil
+	}
+
+	a.atr = (a.atr*float64(a.period-1) + tr) / float64(a.period)
+	return nil
+}
+
+// GetValue returns the current ATR
+func (a *ATR) GetValue() (map[string]float64, bool) {
+	if !a.IsReady() {
+		return nil, false
+	}
+
+	return map[string]float64{
+		ValueKeyATR: a.atr,
+	}, true
+}
+
+// IsReady returns true once period bars have been received
+func (a *ATR) IsReady() bool {
+	return a.count >= a.period
+}
+
+// Reset clears all data
+func (a *ATR) Reset() {
+	a.prevClose = 0
+	a.trueRanges = make([]float64, 0, a.period)
+	a.atr = 0
+	a.count = 0
+}
+
+// GetDataCount returns the number of bars received
+func (a *ATR) GetDataCount() int {
+	return a.count
+}
+
+// trueRange returns the bar's true range; the first bar (no previous close) uses high - low
+func trueRange(candle Candle, prevClose float64, hasPrev bool) float64 {
+	tr := candle.High - candle.Low
+	if hasPrev {
+		tr = math.Max(tr, math.Max(math.Abs(candle.High-prevClose), math.Abs(candle.Low-prevClose)))
+	}
+	return tr
+}
+
+// atrState is the saved form of an ATR's data
+type atrState struct {
+	PrevClose  float64   `json:"prev_close"`
+	TrueRanges []float64 `json:"true_ranges,omitempty"`
+	ATR        float64   `json:"atr"`
+	Count      int       `json:"count"`
+}
+
+// MarshalState saves the ATR and the previous close
+func (a *ATR) MarshalState() ([]byte, error) {
+	return encodeState(a.Name(), stateParams(a.period), atrState{
+		PrevClose:  a.prevClose,
+		TrueRanges: a.trueRanges,
+		ATR:        a.atr,
+		Count:      a.count,
+	})
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (a *ATR) UnmarshalState(data []byte) error {
+	var s atrState
+	if err := decodeState(data, a.Name(), stateParams(a.period), &s); err != nil {
+		return err
+	}
+	a.prevClose, a.trueRanges, a.atr, a.count = s.PrevClose, s.TrueRanges, s.ATR, s.Count
+	return nil
+}
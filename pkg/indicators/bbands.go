@@ -39,6 +39,27 @@ func NewBollingerBands(period int, stdDevMult float64) (*BollingerBands, error)
 	}, nil
 }
 
+// bbandsDefinition registers the Bollinger Bands with the indicator registry
+var bbandsDefinition = Definition{
+	Name:        "bbands",
+	Aliases:     []string{"bollinger_bands"},
+	DisplayName: "Bollinger Bands",
+	Description: "Bollinger Bands: SMA with standard deviation bands",
+	Params: []ParamSpec{
+		{Name: "period", Type: ParamInt, Default: 20, Min: 2, Description: "Moving average period"},
+		{Name: "std_dev", Type: ParamFloat, Default: 2.0, Min: 0, Description: "Standard deviation multiplier for the bands"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewBollingerBands(p.Int("period"), p.Float("std_dev"))
+	},
+	Validate: func(p Params) error {
+		if p.Float("std_dev") <= 0 {
+			return fmt.Errorf("Bollinger Bands std_dev must be positive, got %.2f", p.Float("std_dev"))
+		}
+		return nil
+	},
+}
+
 // Name returns the indicator identifier
 func (bb *BollingerBands) Name() string {
 	return "BBands"
@@ -67,15 +88,6 @@ func (bb *BollingerBands) Update(price float64, timestamp time.Time) error {
 	return nil
 }
 
-// calculateSMA calculates Simple Moving Average for the given slice
-func (bb *BollingerBands) calculateSMA(values []float64) float64 {
-	sum := 0.0
-	for _, v := range values {
-		sum += v
-	}
-	return sum / float64(len(values))
-}
-
 // calculateStdDev calculates standard deviation
 func (bb *BollingerBands) calculateStdDev(values []float64, mean float64) float64 {
 	sumSquares := 0.0
@@ -103,7 +115,7 @@ func (bb *BollingerBands) GetValue() (map[string]float64, bool) {
 	recentPrices := bb.prices[startIdx:]
 
 	// Calculate middle band (SMA)
-	middle := bb.calculateSMA(recentPrices)
+	middle := average(recentPrices)
 
 	// Calculate standard deviation
 	stdDev := bb.calculateStdDev(recentPrices, middle)
@@ -172,3 +184,34 @@ func (bb *BollingerBands) GetPercentB(currentPrice float64) (float64, bool) {
 	percentB := (currentPrice - lower) / (upper - lower)
 	return percentB, true
 }
+
+// bbandsState is the saved form of a Bollinger Bands' data
+type bbandsState struct {
+	Prices     []float64   `json:"prices"`
+	Timestamps []time.Time `json:"timestamps"`
+	IsReady    bool        `json:"is_ready"`
+}
+
+// MarshalState saves the price window
+func (bb *BollingerBands) MarshalState() ([]byte, error) {
+	return encodeState(bb.Name(), stateParams(bb.period, bb.stdDevMult), bbandsState{
+		Prices:     bb.prices,
+		Timestamps: bb.timestamps,
+		IsReady:    bb.isReady,
+	})
+}
+
+// UnmarshalState restores a price window saved by MarshalState
+func (bb *BollingerBands) UnmarshalState(data []byte) error {
+	var s bbandsState
+	if err := decodeState(data, bb.Name(), stateParams(bb.period, bb.stdDevMult), &s); err != nil {
+		return err
+	}
+	if len(s.Timestamps) != len(s.Prices) || (s.IsReady && len(s.Prices) < bb.period) {
+		return fmt.Errorf("invalid Bollinger Bands state: %d prices, %d timestamps", len(s.Prices), len(s.Timestamps))
+	}
+
+	bb.prices, bb.timestamps = s.Prices, s.Timestamps
+	bb.isReady = s.IsReady
+	return nil
+}
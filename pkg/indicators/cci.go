@@ -0,0 +1,131 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// CCI (Commodity Channel Index) of the typical price (high + low + close) / 3
+// CCI = (typical - SMA(typical)) / (0.015 * mean absolute deviation from the SMA) over period bars
+type CCI struct {
+	period   int
+	typicals []float64
+	cci      float64
+	count    int
+}
+
+// NewCCI creates a new Commodity Channel Index indicator
+// Standard period: 20
+func NewCCI(period int) (*CCI, error) {
+	if period < 2 {
+		return nil, fmt.Errorf("CCI period must be at least 2, got %d", period)
+	}
+
+	return &CCI{
+		period:   period,
+		typicals: make([]float64, 0, period),
+	}, nil
+}
+
+// cciDefinition registers the CCI with the indicator registry
+var cciDefinition = Definition{
+	Name:        "cci",
+	DisplayName: "CCI",
+	Description: "Commodity Channel Index: deviation of the typical price from its average",
+	CandleInput: true,
+	Params: []ParamSpec{
+		{Name: "period", Type: ParamInt, Default: 20, Min: 2, Description: "Number of candles in the lookback"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewCCI(p.Int("period"))
+	},
+}
+
+// Name returns the indicator identifier
+func (c *CCI) Name() string {
+	return "CCI"
+}
+
+// Update adds a close as a bar with no range; prefer UpdateCandle
+func (c *CCI) Update(price float64, timestamp time.Time) error {
+	return c.UpdateCandle(Candle{Timestamp: timestamp, Open: price, High: price, Low: price, Close: price})
+}
+
+// UpdateCandle adds a bar and recalculates the CCI
+func (c *CCI) UpdateCandle(candle Candle) error {
+	if err := checkCandle(candle); err != nil {
+		return err
+	}
+
+	typical := (candle.High + candle.Low + candle.Close) / 3
+	c.typicals = appendWindow(c.typicals, typical, c.period)
+	c.count++
+
+	if !c.IsReady() {
+		return nil
+	}
+
+	mean := average(c.typicals)
+	deviation := 0.0
+	for _, t := range c.typicals {
+		deviation += math.Abs(t - mean)
+	}
+	deviation /= float64(c.period)
+
+	c.cci = 0 // No deviation over the period: price sits on its average
+	if deviation > 0 {
+		c.cci = (typical - mean) / (0.015 * deviation)
+	}
+	return nil
+}
+
+// GetValue returns the current CCI
+func (c *CCI) GetValue() (map[string]float64, bool) {
+	if !c.IsReady() {
+		return nil, false
+	}
+
+	return map[string]float64{
+		ValueKeyCCI: c.cci,
+	}, true
+}
+
+// IsReady returns true once period bars have been received
+func (c *CCI) IsReady() bool {
+	return len(c.typicals) >= c.period
+}
+
+// Reset clears all data
+func (c *CCI) Reset() {
+	c.typicals = make([]float64, 0, c.period)
+	c.cci = 0
+	c.count = 0
+}
+
+// GetDataCount returns the number of bars received
+func (c *CCI) GetDataCount() int {
+	return c.count
+}
+
+// cciState is the saved form of a CCI's data
+type cciState struct {
+	Typicals []float64 `json:"typicals"`
+	CCI      float64   `json:"cci"`
+	Count    int       `json:"count"`
+}
+
+// MarshalState saves the typical price window
+func (c *CCI) MarshalState() ([]byte, error) {
+	return encodeState(c.Name(), stateParams(c.period), cciState{Typicals: c.typicals, CCI: c.cci, Count: c.count})
+}
+
+// UnmarshalState restores a window saved by MarshalState
+func (c *CCI) UnmarshalState(data []byte) error {
+	var s cciState
+	if err := decodeState(data, c.Name(), stateParams(c.period), &s); err != nil {
+		return err
+	}
+	c.typicals, c.cci, c.count = s.Typicals, s.CCI, s.Count
+	return nil
+}
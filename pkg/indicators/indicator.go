@@ -1,6 +1,9 @@
 package indicators
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Indicator represents a technical indicator calculator
 // This is the base interface that all indicators must implement
@@ -30,6 +33,45 @@ type Indicator interface {
 	GetDataCount() int
 }
 
+// Candle is one OHLCV bar. Trades and TakerBuyVolume are zero when the source does not report them.
+type Candle struct {
+	Timestamp      time.Time
+	Open           float64
+	High           float64
+	Low            float64
+	Close          float64
+	Volume         float64
+	Trades         int64   // Number of trades in the bar
+	TakerBuyVolume float64 // Base asset volume bought by takers (market buys)
+}
+
+// CandleUpdater is implemented by indicators that use the whole bar rather than
+// only the close (e.g. true range or stochastics)
+type CandleUpdater interface {
+	// UpdateCandle adds a completed bar and recalculates the indicator
+	UpdateCandle(candle Candle) error
+}
+
+// checkCandle rejects bars no candle-based indicator can use
+func checkCandle(candle Candle) error {
+	if candle.Close <= 0 {
+		return fmt.Errorf("price must be positive, got %.8f", candle.Close)
+	}
+	if candle.High < candle.Low {
+		return fmt.Errorf("high %.8f is below low %.8f", candle.High, candle.Low)
+	}
+	return nil
+}
+
+// UpdateWithCandle feeds a bar to an indicator: the whole bar if it is a
+// CandleUpdater, otherwise the close through Update
+func UpdateWithCandle(indicator Indicator, candle Candle) error {
+	if updater, ok := indicator.(CandleUpdater); ok {
+		return updater.UpdateCandle(candle)
+	}
+	return indicator.Update(candle.Close, candle.Timestamp)
+}
+
 // Common indicator value keys for consistency
 const (
 	ValueKeyRSI       = "rsi"
@@ -42,4 +84,16 @@ const (
 	ValueKeyStochRSI  = "stoch_rsi"
 	ValueKeyStochK    = "stoch_k"
 	ValueKeyStochD    = "stoch_d"
+	ValueKeySMA       = "sma"
+	ValueKeyEMA       = "ema"
+	ValueKeyWMA       = "wma"
+	ValueKeyHMA       = "hma"
+	ValueKeyATR       = "atr"
+	ValueKeyADX       = "adx"
+	ValueKeyPlusDI    = "plus_di"
+	ValueKeyMinusDI   = "minus_di"
+	ValueKeyOBV       = "obv"
+	ValueKeyVWAP      = "vwap"
+	ValueKeyCCI       = "cci"
+	ValueKeyWilliamsR = "williams_r"
 )
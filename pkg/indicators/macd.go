@@ -60,6 +60,27 @@ func NewMACD(fastPeriod, slowPeriod, signalPeriod int) (*MACD, error) {
 	}, nil
 }
 
+// macdDefinition registers the MACD with the indicator registry
+var macdDefinition = Definition{
+	Name:        "macd",
+	DisplayName: "MACD",
+	Description: "Moving Average Convergence Divergence: EMA spread with signal line",
+	Params: []ParamSpec{
+		{Name: "fast_period", Type: ParamInt, Default: 12, Min: 2, Description: "Fast EMA period"},
+		{Name: "slow_period", Type: ParamInt, Default: 26, Min: 2, Description: "Slow EMA period"},
+		{Name: "signal_period", Type: ParamInt, Default: 9, Min: 2, Description: "Signal line EMA period"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewMACD(p.Int("fast_period"), p.Int("slow_period"), p.Int("signal_period"))
+	},
+	Validate: func(p Params) error {
+		if p.Int("fast_period") >= p.Int("slow_period") {
+			return fmt.Errorf("MACD fast_period (%d) must be less than slow_period (%d)", p.Int("fast_period"), p.Int("slow_period"))
+		}
+		return nil
+	},
+}
+
 // Name returns the indicator identifier
 func (m *MACD) Name() string {
 	return "MACD"
@@ -79,8 +100,8 @@ func (m *MACD) Update(price float64, timestamp time.Time) error {
 	// Initialize EMAs when we have enough data for slow period
 	if dataCount == m.slowPeriod {
 		// Calculate initial SMA for both fast and slow
-		m.fastEMA = m.calculateSMA(m.prices[dataCount-m.fastPeriod:])
-		m.slowEMA = m.calculateSMA(m.prices)
+		m.fastEMA = average(m.prices[dataCount-m.fastPeriod:])
+		m.slowEMA = average(m.prices)
 	}
 
 	// Update EMAs if we have enough initial data
@@ -97,7 +118,7 @@ func (m *MACD) Update(price float64, timestamp time.Time) error {
 
 		// Initialize signal line when we have enough MACD values
 		if len(m.macdLine) == m.signalPeriod {
-			m.signalEMA = m.calculateSMA(m.macdLine)
+			m.signalEMA = average(m.macdLine)
 			m.isReady = true
 		}
 
@@ -130,15 +151,6 @@ func (m *MACD) Update(price float64, timestamp time.Time) error {
 	return nil
 }
 
-// calculateSMA calculates Simple Moving Average
-func (m *MACD) calculateSMA(values []float64) float64 {
-	sum := 0.0
-	for _, v := range values {
-		sum += v
-	}
-	return sum / float64(len(values))
-}
-
 // GetValue returns current MACD values
 func (m *MACD) GetValue() (map[string]float64, bool) {
 	if !m.isReady {
@@ -192,3 +204,48 @@ func (m *MACD) GetMACDDataCount() int {
 func (m *MACD) GetHistorySize() int {
 	return m.slowPeriod + m.signalPeriod
 }
+
+// macdState is the saved form of a MACD's data
+type macdState struct {
+	Prices     []float64   `json:"prices"`
+	Timestamps []time.Time `json:"timestamps"`
+	MACDLine   []float64   `json:"macd_line"`
+	SignalLine []float64   `json:"signal_line"`
+	Histogram  []float64   `json:"histogram"`
+	FastEMA    float64     `json:"fast_ema"`
+	SlowEMA    float64     `json:"slow_ema"`
+	SignalEMA  float64     `json:"signal_ema"`
+	IsReady    bool        `json:"is_ready"`
+}
+
+// MarshalState saves the MACD's EMAs and history
+func (m *MACD) MarshalState() ([]byte, error) {
+	return encodeState(m.Name(), stateParams(m.fastPeriod, m.slowPeriod, m.signalPeriod), macdState{
+		Prices:     m.prices,
+		Timestamps: m.timestamps,
+		MACDLine:   m.macdLine,
+		SignalLine: m.signalLine,
+		Histogram:  m.histogram,
+		FastEMA:    m.fastEMA,
+		SlowEMA:    m.slowEMA,
+		SignalEMA:  m.signalEMA,
+		IsReady:    m.isReady,
+	})
+}
+
+// UnmarshalState restores EMAs and history saved by MarshalState
+func (m *MACD) UnmarshalState(data []byte) error {
+	var s macdState
+	if err := decodeState(data, m.Name(), stateParams(m.fastPeriod, m.slowPeriod, m.signalPeriod), &s); err != nil {
+		return err
+	}
+	if len(s.Timestamps) != len(s.Prices) {
+		return fmt.Errorf("invalid MACD state: %d prices but %d timestamps", len(s.Prices), len(s.Timestamps))
+	}
+
+	m.prices, m.timestamps = s.Prices, s.Timestamps
+	m.macdLine, m.signalLine, m.histogram = s.MACDLine, s.SignalLine, s.Histogram
+	m.fastEMA, m.slowEMA, m.signalEMA = s.FastEMA, s.SlowEMA, s.SignalEMA
+	m.isReady = s.IsReady
+	return nil
+}
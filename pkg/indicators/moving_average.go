@@ -0,0 +1,474 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// SMA (Simple Moving Average): mean of the last period prices
+type SMA struct {
+	period int
+	prices []float64
+	count  int
+}
+
+// NewSMA creates a new Simple Moving Average
+func NewSMA(period int) (*SMA, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("SMA period must be positive, got %d", period)
+	}
+
+	return &SMA{
+		period: period,
+		prices: make([]float64, 0, period),
+	}, nil
+}
+
+// smaDefinition registers the SMA with the indicator registry
+var smaDefinition = Definition{
+	Name:        "sma",
+	DisplayName: "SMA",
+	Description: "Simple Moving Average",
+	Params: []ParamSpec{
+		{Name: "period", Type: ParamInt, Default: 20, Min: 1, Description: "Number of candles in the lookback"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewSMA(p.Int("period"))
+	},
+}
+
+// Name returns the indicator identifier
+func (s *SMA) Name() string {
+	return "SMA"
+}
+
+// Update adds new price data
+func (s *SMA) Update(price float64, timestamp time.Time) error {
+	if price <= 0 {
+		return fmt.Errorf("price must be positive, got %.8f", price)
+	}
+
+	s.prices = appendWindow(s.prices, price, s.period)
+	s.count++
+	return nil
+}
+
+// GetValue returns the current SMA
+func (s *SMA) GetValue() (map[string]float64, bool) {
+	if !s.IsReady() {
+		return nil, false
+	}
+
+	return map[string]float64{
+		ValueKeySMA: average(s.prices),
+	}, true
+}
+
+// IsReady returns true once period prices have been received
+func (s *SMA) IsReady() bool {
+	return len(s.prices) >= s.period
+}
+
+// Reset clears all data
+func (s *SMA) Reset() {
+	s.prices = make([]float64, 0, s.period)
+	s.count = 0
+}
+
+// GetDataCount returns the number of prices received
+func (s *SMA) GetDataCount() int {
+	return s.count
+}
+
+// EMA (Exponential Moving Average)
+// Seeded with the SMA of the first period prices, then
+// EMA = (price - previous EMA) * 2/(period+1) + previous EMA
+type EMA struct {
+	period     int
+	multiplier float64
+
+	seed  []float64 // First period prices, until the EMA is seeded
+	ema   float64
+	count int
+}
+
+// NewEMA creates a new Exponential Moving Average
+func NewEMA(period int) (*EMA, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("EMA period must be positive, got %d", period)
+	}
+
+	return &EMA{
+		period:     period,
+		multiplier: 2.0 / float64(period+1),
+		seed:       make([]float64, 0, period),
+	}, nil
+}
+
+// emaDefinition registers the EMA with the indicator registry
+var emaDefinition = Definition{
+	Name:        "ema",
+	DisplayName: "EMA",
+	Description: "Exponential Moving Average",
+	Params: []ParamSpec{
+		{Name: "period", Type: ParamInt, Default: 20, Min: 1, Description: "Number of candles in the lookback"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewEMA(p.Int("period"))
+	},
+}
+
+// Name returns the indicator identifier
+func (e *EMA) Name() string {
+	return "EMA"
+}
+
+// Update adds new price data and recalculates the EMA
+func (e *EMA) Update(price float64, timestamp time.Time) error {
+	if price <= 0 {
+		return fmt.Errorf("price must be positive, got %.8f", price)
+	}
+
+	e.count++
+	if e.count < e.period {
+		e.seed = append(e.seed, price)
+		return nil
+	}
+	if e.count == e.period {
+		e.seed = append(e.seed, price)
+		e.ema = average(e.seed)
+		e.seed = nil
+		return nil
+	}
+
+	e.ema = (price-e.ema)*e.multiplier + e.ema
+	return nil
+}
+
+// GetValue returns the current EMA
+func (e *EMA) GetValue() (map[string]float64, bool) {
+	if !e.IsReady() {
+		return nil, false
+	}
+
+	return map[string]float64{
+		ValueKeyEMA: e.ema,
+	}, true
+}
+
+// IsReady returns true once the EMA has been seeded
+func (e *EMA) IsReady() bool {
+	return e.count >= e.period
+}
+
+// Reset clears all data
+func (e *EMA) Reset() {
+	e.seed = make([]float64, 0, e.period)
+	e.ema = 0
+	e.count = 0
+}
+
+// GetDataCount returns the number of prices received
+func (e *EMA) GetDataCount() int {
+	return e.count
+}
+
+// WMA (Weighted Moving Average): linear weights 1..period, newest price weighted most
+type WMA struct {
+	period int
+	values []float64
+	count  int
+}
+
+// NewWMA creates a new Weighted Moving Average
+func NewWMA(period int) (*WMA, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("WMA period must be positive, got %d", period)
+	}
+
+	return &WMA{
+		period: period,
+		values: make([]float64, 0, period),
+	}, nil
+}
+
+// wmaDefinition registers the WMA with the indicator registry
+var wmaDefinition = Definition{
+	Name:        "wma",
+	DisplayName: "WMA",
+	Description: "Linearly Weighted Moving Average",
+	Params: []ParamSpec{
+		{Name: "period", Type: ParamInt, Default: 20, Min: 1, Description: "Number of candles in the lookback"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewWMA(p.Int("period"))
+	},
+}
+
+// Name returns the indicator identifier
+func (w *WMA) Name() string {
+	return "WMA"
+}
+
+// Update adds new price data
+func (w *WMA) Update(price float64, timestamp time.Time) error {
+	if price <= 0 {
+		return fmt.Errorf("price must be positive, got %.8f", price)
+	}
+
+	w.add(price)
+	return nil
+}
+
+// add appends a value without validation (HMA feeds it differences that may be negative)
+func (w *WMA) add(value float64) {
+	w.values = appendWindow(w.values, value, w.period)
+	w.count++
+}
+
+// value returns the weighted average of the window
+func (w *WMA) value() float64 {
+	sum, weights := 0.0, 0.0
+	for i, v := range w.values {
+		weight := float64(i + 1)
+		sum += v * weight
+		weights += weight
+	}
+	return sum / weights
+}
+
+// GetValue returns the current WMA
+func (w *WMA) GetValue() (map[string]float64, bool) {
+	if !w.IsReady() {
+		return nil, false
+	}
+
+	return map[string]float64{
+		ValueKeyWMA: w.value(),
+	}, true
+}
+
+// IsReady returns true once period values have been received
+func (w *WMA) IsReady() bool {
+	return len(w.values) >= w.period
+}
+
+// Reset clears all data
+func (w *WMA) Reset() {
+	w.values = make([]float64, 0, w.period)
+	w.count = 0
+}
+
+// GetDataCount returns the number of values received
+func (w *WMA) GetDataCount() int {
+	return w.count
+}
+
+// HMA (Hull Moving Average): a low-lag average
+// HMA = WMA(2 * WMA(period/2) - WMA(period), floor(sqrt(period)))
+type HMA struct {
+	period int
+
+	half   *WMA
+	full   *WMA
+	smooth *WMA
+	count  int
+}
+
+// NewHMA creates a new Hull Moving Average
+func NewHMA(period int) (*HMA, error) {
+	if period < 2 {
+		return nil, fmt.Errorf("HMA period must be at least 2, got %d", period)
+	}
+
+	h := &HMA{period: period}
+	h.Reset()
+	return h, nil
+}
+
+// hmaDefinition registers the HMA with the indicator registry
+var hmaDefinition = Definition{
+	Name:        "hma",
+	DisplayName: "HMA",
+	Description: "Hull Moving Average: low-lag smoothing",
+	Params: []ParamSpec{
+		{Name: "period", Type: ParamInt, Default: 20, Min: 2, Description: "Number of candles in the lookback"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewHMA(p.Int("period"))
+	},
+}
+
+// Name returns the indicator identifier
+func (h *HMA) Name() string {
+	return "HMA"
+}
+
+// Update adds new price data and recalculates the HMA
+func (h *HMA) Update(price float64, timestamp time.Time) error {
+	if price <= 0 {
+		return fmt.Errorf("price must be positive, got %.8f", price)
+	}
+
+	h.half.add(price)
+	h.full.add(price)
+	h.count++
+
+	if h.full.IsReady() {
+		h.smooth.add(2*h.half.value() - h.full.value())
+	}
+	return nil
+}
+
+// GetValue returns the current HMA
+func (h *HMA) GetValue() (map[string]float64, bool) {
+	if !h.IsReady() {
+		return nil, false
+	}
+
+	return map[string]float64{
+		ValueKeyHMA: h.smooth.value(),
+	}, true
+}
+
+// IsReady returns true once the smoothing WMA is full (period + floor(sqrt(period)) - 1 prices)
+func (h *HMA) IsReady() bool {
+	return h.smooth.IsReady()
+}
+
+// Reset clears all data
+func (h *HMA) Reset() {
+	h.half = &WMA{period: h.period / 2}
+	h.full = &WMA{period: h.period}
+	h.smooth = &WMA{period: int(math.Sqrt(float64(h.period)))}
+	h.count = 0
+}
+
+// GetDataCount returns the number of prices received
+func (h *HMA) GetDataCount() int {
+	return h.count
+}
+
+// appendWindow appends v and drops the oldest values beyond size
+func appendWindow(values []float64, v float64, size int) []float64 {
+	values = append(values, v)
+	if len(values) > size {
+		values = values[len(values)-size:]
+	}
+	return values
+}
+
+// average returns the arithmetic mean of values
+func average(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// smaState is the saved form of an SMA's data
+type smaState struct {
+	Prices []float64 `json:"prices"`
+	Count  int       `json:"count"`
+}
+
+// MarshalState saves the price window
+func (s *SMA) MarshalState() ([]byte, error) {
+	return encodeState(s.Name(), stateParams(s.period), smaState{Prices: s.prices, Count: s.count})
+}
+
+// UnmarshalState restores a price window saved by MarshalState
+func (s *SMA) UnmarshalState(data []byte) error {
+	var st smaState
+	if err := decodeState(data, s.Name(), stateParams(s.period), &st); err != nil {
+		return err
+	}
+	s.prices, s.count = st.Prices, st.Count
+	return nil
+}
+
+// emaState is the saved form of an EMA's data
+type emaState struct {
+	Seed  []float64 `json:"seed,omitempty"`
+	EMA   float64   `json:"ema"`
+	Count int       `json:"count"`
+}
+
+// MarshalState saves the EMA and, until it is seeded, the seed prices
+func (e *EMA) MarshalState() ([]byte, error) {
+	return encodeState(e.Name(), stateParams(e.period), emaState{Seed: e.seed, EMA: e.ema, Count: e.count})
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (e *EMA) UnmarshalState(data []byte) error {
+	var s emaState
+	if err := decodeState(data, e.Name(), stateParams(e.period), &s); err != nil {
+		return err
+	}
+	e.seed, e.ema, e.count = s.Seed, s.EMA, s.Count
+	return nil
+}
+
+// wmaState is the saved form of a WMA's data
+type wmaState struct {
+	Values []float64 `json:"values"`
+	Count  int       `json:"count"`
+}
+
+// MarshalState saves the value window
+func (w *WMA) MarshalState() ([]byte, error) {
+	return encodeState(w.Name(), stateParams(w.period), w.state())
+}
+
+// UnmarshalState restores a value window saved by MarshalState
+func (w *WMA) UnmarshalState(data []byte) error {
+	var s wmaState
+	if err := decodeState(data, w.Name(), stateParams(w.period), &s); err != nil {
+		return err
+	}
+	w.restore(s)
+	return nil
+}
+
+// state returns the WMA's data (HMA saves its three WMAs with it)
+func (w *WMA) state() wmaState {
+	return wmaState{Values: w.values, Count: w.count}
+}
+
+// restore replaces the WMA's data
+func (w *WMA) restore(s wmaState) {
+	w.values, w.count = s.Values, s.Count
+}
+
+// hmaState is the saved form of an HMA's data
+type hmaState struct {
+	Half   wmaState `json:"half"`
+	Full   wmaState `json:"full"`
+	Smooth wmaState `json:"smooth"`
+	Count  int      `json:"count"`
+}
+
+// MarshalState saves the three inner WMAs
+func (h *HMA) MarshalState() ([]byte, error) {
+	return encodeState(h.Name(), stateParams(h.period), hmaState{
+		Half:   h.half.state(),
+		Full:   h.full.state(),
+		Smooth: h.smooth.state(),
+		Count:  h.count,
+	})
+}
+
+// UnmarshalState restores inner WMAs saved by MarshalState
+func (h *HMA) UnmarshalState(data []byte) error {
+	var s hmaState
+	if err := decodeState(data, h.Name(), stateParams(h.period), &s); err != nil {
+		return err
+	}
+	h.half.restore(s.Half)
+	h.full.restore(s.Full)
+	h.smooth.restore(s.Smooth)
+	h.count = s.Count
+	return nil
+}
@@ -0,0 +1,113 @@
+package indicators
+
+import (
+	"fmt"
+	"time"
+)
+
+// OBV (On-Balance Volume): running total of volume, added on up closes and
+// subtracted on down closes. The first bar starts the total at 0.
+// Needs volume: fed closes only (Update), the total never moves.
+type OBV struct {
+	prevClose float64
+	obv       float64
+	count     int
+}
+
+// NewOBV creates a new On-Balance Volume indicator
+func NewOBV() (*OBV, error) {
+	return &OBV{}, nil
+}
+
+// obvDefinition registers the OBV with the indicator registry
+var obvDefinition = Definition{
+	Name:        "obv",
+	DisplayName: "OBV",
+	Description: "On-Balance Volume: running volume signed by the close direction",
+	CandleInput: true,
+	New: func(p Params) (Indicator, error) {
+		return NewOBV()
+	},
+}
+
+// Name returns the indicator identifier
+func (o *OBV) Name() string {
+	return "OBV"
+}
+
+// Update adds a close with no volume; prefer UpdateCandle
+func (o *OBV) Update(price float64, timestamp time.Time) error {
+	return o.UpdateCandle(Candle{Timestamp: timestamp, Open: price, High: price, Low: price, Close: price})
+}
+
+// UpdateCandle adds a bar and updates the running total
+func (o *OBV) UpdateCandle(candle Candle) error {
+	if err := checkCandle(candle); err != nil {
+		return err
+	}
+	if candle.Volume < 0 {
+		return fmt.Errorf("volume must not be negative, got %.8f", candle.Volume)
+	}
+
+	if o.count > 0 {
+		switch {
+		case candle.Close > o.prevClose:
+			o.obv += candle.Volume
+		case candle.Close < o.prevClose:
+			o.obv -= candle.Volume
+		}
+	}
+	o.prevClose = candle.Close
+	o.count++
+	return nil
+}
+
+// GetValue returns the current OBV
+func (o *OBV) GetValue() (map[string]float64, bool) {
+	if !o.IsReady() {
+		return nil, false
+	}
+
+	return map[string]float64{
+		ValueKeyOBV: o.obv,
+	}, true
+}
+
+// IsReady returns true once a bar has been received
+func (o *OBV) IsReady() bool {
+	return o.count > 0
+}
+
+// Reset clears all data
+func (o *OBV) Reset() {
+	o.prevClose = 0
+	o.obv = 0
+	o.count = 0
+}
+
+// GetDataCount returns the number of bars received
+func (o *OBV) GetDataCount() int {
+	return o.count
+}
+
+// obvState is the saved form of an OBV's data
+type obvState struct {
+	PrevClose float64 `json:"prev_close"`
+	OBV       float64 `json:"obv"`
+	Count     int     `json:"count"`
+}
+
+// MarshalState saves the running total and the previous close
+func (o *OBV) MarshalState() ([]byte, error) {
+	return encodeState(o.Name(), stateParams(), obvState{PrevClose: o.prevClose, OBV: o.obv, Count: o.count})
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (o *OBV) UnmarshalState(data []byte) error {
+	var s obvState
+	if err := decodeState(data, o.Name(), stateParams(), &s); err != nil {
+		return err
+	}
+	o.prevClose, o.obv, o.count = s.PrevClose, s.OBV, s.Count
+	return nil
+}
@@ -0,0 +1,171 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// referenceCandles is the shared test vector: 40 hourly bars (open, high, low, close, volume)
+// starting 2024-01-01 12:00 UTC, so the series crosses two UTC midnights (bars 12 and 36).
+// Expected values below were computed independently from the textbook formulas.
+var referenceCandles = [][5]float64{
+	{100.0, 104.0, 99.2, 103.0, 1000.0},
+	{103.0, 106.17, 101.9, 104.67, 1037.0},
+	{104.67, 107.39, 103.27, 105.39, 1074.0},
+	{105.39, 106.48, 103.69, 105.48, 1111.0},
+	{105.48, 106.98, 104.62, 105.42, 1148.0},
+	{105.42, 107.65, 104.32, 105.65, 1185.0},
+	{105.65, 107.4, 104.25, 106.4, 1222.0},
+	{106.4, 109.09, 104.7, 107.59, 1259.0},
+	{107.59, 110.85, 106.79, 108.85, 1296.0},
+	{108.85, 110.67, 107.75, 109.67, 1333.0},
+	{109.67, 111.17, 108.15, 109.55, 1370.0},
+	{109.55, 111.55, 106.5, 108.2, 1407.0},
+	{108.2, 109.2, 104.87, 105.67, 1444.0},
+	{105.67, 107.17, 101.25, 102.35, 1481.0},
+	{102.35, 104.35, 97.48, 98.88, 1018.0},
+	{98.88, 99.88, 94.25, 95.95, 1055.0},
+	{95.95, 97.45, 93.35, 94.15, 1092.0},
+	{94.15, 96.15, 92.62, 93.72, 1129.0},
+	{93.72, 95.59, 92.32, 94.59, 1166.0},
+	{94.59, 97.85, 92.89, 96.35, 1203.0},
+	{96.35, 100.42, 95.55, 98.42, 1240.0},
+	{98.42, 101.26, 97.32, 100.26, 1277.0},
+	{100.26, 103.05, 98.86, 101.55, 1314.0},
+	{101.55, 104.25, 99.85, 102.25, 1351.0},
+	{102.25, 103.62, 101.45, 102.62, 1388.0},
+	{102.62, 104.62, 101.52, 103.12, 1425.0},
+	{103.12, 106.17, 101.72, 104.17, 1462.0},
+	{104.17, 107.05, 102.47, 106.05, 1499.0},
+	{106.05, 110.19, 105.25, 108.69, 1036.0},
+	{108.69, 113.73, 107.59, 111.73, 1073.0},
+	{111.73, 115.58, 110.33, 114.58, 1110.0},
+	{114.58, 118.11, 112.88, 116.61, 1147.0},
+	{116.61, 119.31, 115.81, 117.31, 1184.0},
+	{117.31, 118.31, 115.42, 116.52, 1221.0},
+	{116.52, 118.02, 113.01, 114.41, 1258.0},
+	{114.41, 116.41, 109.8, 111.5, 1295.0},
+	{111.5, 112.5, 107.64, 108.44, 1332.0},
+	{108.44, 109.94, 104.77, 105.87, 1369.0},
+	{105.87, 107.87, 102.79, 104.19, 1406.0},
+	{104.19, 105.19, 101.8, 103.5, 1443.0},
+}
+
+// referenceSeries converts referenceCandles to hourly Candles
+func referenceSeries() []Candle {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	candles := make([]Candle, len(referenceCandles))
+	for i, r := range referenceCandles {
+		candles[i] = Candle{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      r[0],
+			High:      r[1],
+			Low:       r[2],
+			Close:     r[3],
+			Volume:    r[4],
+		}
+	}
+	return candles
+}
+
+// TestIndicatorsReference feeds the reference vector through every factory
+// indicator and checks when it becomes ready and its final values
+func TestIndicatorsReference(t *testing.T) {
+	tests := []struct {
+		config     IndicatorConfig
+		readyAfter int // Bars needed before IsReady
+		want       map[string]float64
+	}{
+		{IndicatorConfig{Type: "sma", Params: map[string]interface{}{"period": 5}}, 5,
+			map[string]float64{ValueKeySMA: 106.7}},
+		{IndicatorConfig{Type: "ema", Params: map[string]interface{}{"period": 10}}, 10,
+			map[string]float64{ValueKeyEMA: 108.0957876454192}},
+		{IndicatorConfig{Type: "wma", Params: map[string]interface{}{"period": 10}}, 10,
+			map[string]float64{ValueKeyWMA: 108.82927272727272}},
+		{IndicatorConfig{Type: "hma", Params: map[string]interface{}{"period": 9}}, 11,
+			map[string]float64{ValueKeyHMA: 102.29077777777779}},
+		{IndicatorConfig{Type: "atr", Params: map[string]interface{}{"period": 14}}, 14,
+			map[string]float64{ValueKeyATR: 4.488349288618428}},
+		{IndicatorConfig{Type: "adx", Params: map[string]interface{}{"period": 14}}, 28,
+			map[string]float64{ValueKeyADX: 26.002776812363216, ValueKeyPlusDI: 17.575232306162853, ValueKeyMinusDI: 22.22277597894858}},
+		{IndicatorConfig{Type: "obv"}, 1,
+			map[string]float64{ValueKeyOBV: 7924}},
+		{IndicatorConfig{Type: "vwap", Params: map[string]interface{}{"period": 10}}, 10,
+			map[string]float64{ValueKeyVWAP: 111.26074396135265}},
+		{IndicatorConfig{Type: "vwap"}, 1, // Anchored: only the bars since the last UTC midnight
+			map[string]float64{ValueKeyVWAP: 106.14166666666667}},
+		{IndicatorConfig{Type: "cci", Params: map[string]interface{}{"period": 20}}, 20,
+			map[string]float64{ValueKeyCCI: -52.10266420943791}},
+		{IndicatorConfig{Type: "williams_r", Params: map[string]interface{}{"period": 14}}, 14,
+			map[string]float64{ValueKeyWilliamsR: -89.88061398521887}},
+		{IndicatorConfig{Type: "rsi", Params: map[string]interface{}{"period": 14}}, 15,
+			map[string]float64{ValueKeyRSI: 43.006717892935896}},
+		{IndicatorConfig{Type: "rsi", Params: map[string]interface{}{"period": 14, "smoothing": "sma"}}, 15,
+			map[string]float64{ValueKeyRSI: 50.678571428571416}},
+		{IndicatorConfig{Type: "rsi", Params: map[string]interface{}{"period": 14, "smoothing": "ema"}}, 15,
+			map[string]float64{ValueKeyRSI: 31.243188732033843}},
+		{IndicatorConfig{Type: "stoch", Params: map[string]interface{}{"k_period": 14, "d_period": 3}}, 16,
+			map[string]float64{ValueKeyStochK: 10.11938601478113, ValueKeyStochD: 16.625286010511676}},
+		{IndicatorConfig{Type: "stoch_rsi"}, 32,
+			map[string]float64{ValueKeyStochRSI: 0, ValueKeyStochK: 0, ValueKeyStochD: 2.9557494934876534}},
+	}
+
+	factory := NewFactory()
+	for _, tt := range tests {
+		t.Run(tt.config.Type, func(t *testing.T) {
+			if err := factory.ValidateConfig(tt.config); err != nil {
+				t.Fatalf("ValidateConfig: %v", err)
+			}
+			indicator, err := factory.Create(tt.config)
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+
+			for i, candle := range referenceSeries() {
+				if err := UpdateWithCandle(indicator, candle); err != nil {
+					t.Fatalf("bar %d: %v", i, err)
+				}
+				if ready := indicator.IsReady(); ready != (i+1 >= tt.readyAfter) {
+					t.Fatalf("bar %d: IsReady = %v, want ready after %d bars", i, ready, tt.readyAfter)
+				}
+			}
+
+			values, ok := indicator.GetValue()
+			if !ok {
+				t.Fatal("GetValue not ready")
+			}
+			for key, want := range tt.want {
+				if got, ok := values[key]; !ok || math.Abs(got-want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", key, got, want)
+				}
+			}
+
+			indicator.Reset()
+			if indicator.IsReady() || indicator.GetDataCount() != 0 {
+				t.Errorf("after Reset: ready %v, data count %d", indicator.IsReady(), indicator.GetDataCount())
+			}
+		})
+	}
+}
+
+// TestValidateConfigRejectsShortPeriods checks the factory rejects periods below each indicator's minimum
+func TestValidateConfigRejectsShortPeriods(t *testing.T) {
+	factory := NewFactory()
+	for _, config := range []IndicatorConfig{
+		{Type: "sma", Params: map[string]interface{}{"period": 0}},
+		{Type: "ema", Params: map[string]interface{}{"period": 0}},
+		{Type: "wma", Params: map[string]interface{}{"period": 0}},
+		{Type: "hma", Params: map[string]interface{}{"period": 1}},
+		{Type: "atr", Params: map[string]interface{}{"period": 0}},
+		{Type: "adx", Params: map[string]interface{}{"period": 0}},
+		{Type: "vwap", Params: map[string]interface{}{"period": -1}},
+		{Type: "cci", Params: map[string]interface{}{"period": 1}},
+		{Type: "williams_r", Params: map[string]interface{}{"period": "14"}},
+		{Type: "stoch_rsi", Params: map[string]interface{}{"rsi_period": 1}},
+	} {
+		if err := factory.ValidateConfig(config); err == nil {
+			t.Errorf("ValidateConfig(%s %v) = nil, want error", config.Type, config.Params)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package indicators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ParamType is the kind of value an indicator parameter takes
+type ParamType string
+
+const (
+	ParamInt    ParamType = "int"
+	ParamFloat  ParamType = "float"
+	ParamBool   ParamType = "bool"
+	ParamString ParamType = "string"
+)
+
+// ParamSpec describes one indicator parameter
+type ParamSpec struct {
+	Name        string      `json:"name"`              // Key in IndicatorConfig.Params (e.g. "period")
+	Type        ParamType   `json:"type"`              // int values are truncated from numbers
+	Default     interface{} `json:"default"`           // Used when the parameter is missing
+	Min         float64     `json:"min"`               // Smallest accepted number (int and float only)
+	Max         float64     `json:"max"`               // Largest accepted number (0 = no limit; int and float only)
+	Options     []string    `json:"options,omitempty"` // Accepted values of a string parameter (empty = any)
+	Description string      `json:"description"`       // Shown in UIs
+}
+
+// Params holds an indicator's parameters with defaults applied: an int, float64,
+// bool or string per ParamSpec.Type
+type Params map[string]interface{}
+
+// Int returns a parameter as an int
+func (p Params) Int(name string) int {
+	switch v := p[name].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// Float returns a parameter as a float64
+func (p Params) Float(name string) float64 {
+	switch v := p[name].(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// Bool returns a parameter as a bool
+func (p Params) Bool(name string) bool {
+	b, _ := p[name].(bool)
+	return b
+}
+
+// String returns a parameter as a string
+func (p Params) String(name string) string {
+	s, _ := p[name].(string)
+	return s
+}
+
+// Definition describes an indicator to the registry: its names, parameter
+// schema and constructor. The factory derives creation, validation, defaults
+// and listing from it.
+type Definition struct {
+	Name        string      // Canonical type name (e.g. "rsi")
+	Aliases     []string    // Alternative type names (e.g. "bollinger_bands")
+	DisplayName string      // Used in validation errors (e.g. "Bollinger Bands")
+	Description string      // One-line summary
+	CandleInput bool        // Uses high/low/volume: feed whole candles (CandleUpdater)
+	Params      []ParamSpec // Parameter schema
+
+	// New builds the indicator from validated parameters
+	New func(p Params) (Indicator, error)
+
+	// Validate checks constraints between parameters (optional)
+	Validate func(p Params) error
+}
+
+// Registry holds indicator definitions by name and alias
+type Registry struct {
+	mu    sync.RWMutex
+	defs  map[string]*Definition // Keyed by lowercase name and aliases
+	names []string               // Canonical names, sorted
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		defs: make(map[string]*Definition),
+	}
+}
+
+// Register adds an indicator definition. Names and aliases must be unique.
+func (r *Registry) Register(def Definition) error {
+	if def.Name == "" {
+		return fmt.Errorf("indicator name cannot be empty")
+	}
+	if def.New == nil {
+		return fmt.Errorf("indicator %s has no constructor", def.Name)
+	}
+	def.Name = strings.ToLower(def.Name)
+	if def.DisplayName == "" {
+		def.DisplayName = def.Name
+	}
+
+	if err := CheckParamSpecs(def.Params); err != nil {
+		return fmt.Errorf("indicator %s: %w", def.Name, err)
+	}
+
+	keys := append([]string{def.Name}, def.Aliases...)
+	for i, key := range keys {
+		keys[i] = strings.ToLower(key)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		if _, exists := r.defs[key]; exists {
+			return fmt.Errorf("indicator %s is already registered", key)
+		}
+	}
+
+	d := def
+	for _, key := range keys {
+		r.defs[key] = &d
+	}
+	r.names = append(r.names, keys[0])
+	sort.Strings(r.names)
+	return nil
+}
+
+// Lookup finds a definition by name or alias (case-insensitive)
+func (r *Registry) Lookup(name string) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	def, ok := r.defs[strings.ToLower(name)]
+	if !ok {
+		return Definition{}, false
+	}
+	return *def, true
+}
+
+// Names returns the canonical names of all registered indicators, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.names...)
+}
+
+// defaultRegistry holds the built-in indicators and anything added with Register
+var defaultRegistry = newBuiltinRegistry()
+
+// newBuiltinRegistry creates a registry holding the indicators of this package
+func newBuiltinRegistry() *Registry {
+	r := NewRegistry()
+	for _, def := range []Definition{
+		rsiDefinition,
+		macdDefinition,
+		bbandsDefinition,
+		stochasticDefinition,
+		stochRSIDefinition,
+		smaDefinition,
+		emaDefinition,
+		wmaDefinition,
+		hmaDefinition,
+		atrDefinition,
+		adxDefinition,
+		obvDefinition,
+		vwapDefinition,
+		cciDefinition,
+		williamsRDefinition,
+	} {
+		if err := r.Register(def); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// DefaultRegistry returns the registry used by NewFactory
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register adds an indicator to the default registry, making it available to
+// every factory created with NewFactory
+func Register(def Definition) error {
+	return defaultRegistry.Register(def)
+}
+
+// resolveParams validates raw config parameters against the schema and fills in defaults
+func (def Definition) resolveParams(raw map[string]interface{}) (Params, error) {
+	params, err := ResolveParams(def.DisplayName, def.Params, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if def.Validate != nil {
+		if err := def.Validate(params); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// CheckParamSpecs rejects schemas with unknown types, duplicate names or
+// defaults of the wrong type
+func CheckParamSpecs(specs []ParamSpec) error {
+	seen := make(map[string]bool)
+	for _, p := range specs {
+		if p.Type != ParamInt && p.Type != ParamFloat && p.Type != ParamBool && p.Type != ParamString {
+			return fmt.Errorf("parameter %s has unknown type %q", p.Name, p.Type)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("parameter %s is declared twice", p.Name)
+		}
+		seen[p.Name] = true
+
+		if _, err := convertParam(p, p.Default); err != nil {
+			return fmt.Errorf("parameter %s default: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// ResolveParams validates raw config parameters against a schema and fills in
+// defaults. Keys not in the schema are ignored. name prefixes error messages.
+func ResolveParams(name string, specs []ParamSpec, raw map[string]interface{}) (Params, error) {
+	params := make(Params, len(specs))
+	for _, spec := range specs {
+		p, ok := raw[spec.Name]
+		if !ok {
+			p = spec.Default
+		}
+
+		value, err := convertParam(spec, p)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s %w", name, spec.Name, err)
+		}
+
+		if spec.Type == ParamInt || spec.Type == ParamFloat {
+			number := Params{spec.Name: value}.Float(spec.Name)
+			if number < spec.Min {
+				return nil, fmt.Errorf("%s %s must be at least %s, got %s",
+					name, spec.Name, formatParam(spec, spec.Min), formatParam(spec, number))
+			}
+			if spec.Max != 0 && number > spec.Max {
+				return nil, fmt.Errorf("%s %s too large: %s (max %s)",
+					name, spec.Name, formatParam(spec, number), formatParam(spec, spec.Max))
+			}
+		}
+		params[spec.Name] = value
+	}
+
+	return params, nil
+}
+
+// convertParam converts a config value to the spec's type (int, float64, bool or string).
+// Errors complete the sentence "<indicator> <param> ...".
+func convertParam(spec ParamSpec, p interface{}) (interface{}, error) {
+	switch spec.Type {
+	case ParamInt, ParamFloat:
+		var number float64
+		switch v := p.(type) {
+		case int:
+			number = float64(v)
+		case int64:
+			number = float64(v)
+		case float64:
+			number = v
+		default:
+			return nil, fmt.Errorf("must be a number, got %T", p)
+		}
+		if spec.Type == ParamInt {
+			return int(number), nil
+		}
+		return number, nil
+
+	case ParamBool:
+		b, ok := p.(bool)
+		if !ok {
+			return nil, fmt.Errorf("must be true or false, got %T", p)
+		}
+		return b, nil
+
+	default:
+		s, ok := p.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string, got %T", p)
+		}
+		s = strings.ToLower(strings.TrimSpace(s))
+		if len(spec.Options) == 0 {
+			return s, nil
+		}
+		for _, option := range spec.Options {
+			if s == option {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of %v, got %q", spec.Options, s)
+	}
+}
+
+// DefaultParams returns the schema defaults as config values (int, float64, bool or string)
+func DefaultParams(specs []ParamSpec) map[string]interface{} {
+	params := make(map[string]interface{}, len(specs))
+	for _, spec := range specs {
+		value, err := convertParam(spec, spec.Default)
+		if err != nil {
+			continue // Registered specs have valid defaults (CheckParamSpecs)
+		}
+		params[spec.Name] = value
+	}
+	return params
+}
+
+// formatParam formats a parameter value for error messages
+func formatParam(spec ParamSpec, value float64) string {
+	if spec.Type == ParamInt {
+		return fmt.Sprintf("%d", int(value))
+	}
+	return fmt.Sprintf("%.2f", value)
+}
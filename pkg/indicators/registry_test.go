@@ -0,0 +1,77 @@
+package indicators
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRegistryCustomIndicator registers an indicator in a private registry and
+// checks the factory derives creation, validation, defaults and listing from it
+func TestRegistryCustomIndicator(t *testing.T) {
+	registry := NewRegistry()
+	err := registry.Register(Definition{
+		Name:        "Double_SMA",
+		Aliases:     []string{"dsma"},
+		DisplayName: "Double SMA",
+		Params: []ParamSpec{
+			{Name: "period", Type: ParamInt, Default: 10, Min: 2, Max: 50},
+		},
+		New: func(p Params) (Indicator, error) {
+			return NewSMA(p.Int("period") * 2)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	if err := registry.Register(Definition{Name: "dsma", New: smaDefinition.New}); err == nil {
+		t.Error("registering a taken alias succeeded")
+	}
+
+	factory := NewFactoryWithRegistry(registry)
+	if got := factory.GetAvailableIndicators(); len(got) != 1 || got[0] != "double_sma" {
+		t.Errorf("GetAvailableIndicators = %v, want [double_sma]", got)
+	}
+
+	config := factory.GetDefaultConfig("DSMA")
+	if config.Type != "double_sma" || config.Params["period"] != 10 {
+		t.Errorf("GetDefaultConfig = %+v, want double_sma with period 10", config)
+	}
+
+	indicator, err := factory.Create(IndicatorConfig{Type: "dsma", Params: map[string]interface{}{"period": 3.0}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if sma := indicator.(*SMA); sma.period != 6 {
+		t.Errorf("period = %d, want 6", sma.period)
+	}
+
+	for raw, want := range map[interface{}]string{
+		1:      "Double SMA period must be at least 2, got 1",
+		51:     "Double SMA period too large: 51 (max 50)",
+		"many": "Double SMA period must be a number, got string",
+	} {
+		err := factory.ValidateConfig(IndicatorConfig{Type: "double_sma", Params: map[string]interface{}{"period": raw}})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidateConfig(period %v) = %v, want %q", raw, err, want)
+		}
+	}
+
+	if _, err := NewFactory().Create(IndicatorConfig{Type: "dsma"}); err == nil {
+		t.Error("private registry leaked into the default factory")
+	}
+}
+
+// TestDefaultRegistryCrossParamValidation checks constraints between parameters still apply
+func TestDefaultRegistryCrossParamValidation(t *testing.T) {
+	factory := NewFactory()
+	for _, config := range []IndicatorConfig{
+		{Type: "macd", Params: map[string]interface{}{"fast_period": 26, "slow_period": 12}},
+		{Type: "bollinger_bands", Params: map[string]interface{}{"std_dev": 0}},
+		{Type: "rsi", Params: map[string]interface{}{"period": 101}},
+	} {
+		if err := factory.ValidateConfig(config); err == nil {
+			t.Errorf("ValidateConfig(%s %v) = nil, want error", config.Type, config.Params)
+		}
+	}
+}
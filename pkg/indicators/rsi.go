@@ -2,33 +2,85 @@ package indicators
 
 import (
 	"fmt"
-	"math"
 	"time"
 )
 
-// RSI implements the Relative Strength Index indicator
+// RSISmoothing selects how average gains and losses are smoothed
+type RSISmoothing string
+
+const (
+	RSISmoothingWilder RSISmoothing = "wilder" // avg = (avg*(period-1) + change) / period (default, as in Wilder's original)
+	RSISmoothingSMA    RSISmoothing = "sma"    // Simple average of the last period changes
+	RSISmoothingEMA    RSISmoothing = "ema"    // Exponential average with alpha = 2/(period+1)
+)
+
+// RSI implements the Relative Strength Index indicator.
+// Averages are seeded with the simple average of the first period changes and
+// then updated in O(1) per price.
 type RSI struct {
-	period     int
-	closes     []float64
-	timestamps []time.Time
-	lastRSI    float64
-	isReady    bool
+	period    int
+	smoothing RSISmoothing
+
+	count         int // Prices received
+	prevClose     float64
+	lastTimestamp time.Time
+
+	gainSum float64 // Sums of the changes in the seed (or SMA) window
+	lossSum float64
+	avgGain float64
+	avgLoss float64
+
+	// SMA smoothing keeps the last period gains and losses in a ring
+	gains  []float64
+	losses []float64
+	pos    int
+
+	lastRSI float64
+	isReady bool
 }
 
-// NewRSI creates a new RSI indicator with the specified period
+// NewRSI creates a new RSI indicator with the specified period and Wilder smoothing
 // Typical periods: 14 (default), 9, 25
 func NewRSI(period int) (*RSI, error) {
+	return NewRSIWithSmoothing(period, RSISmoothingWilder)
+}
+
+// NewRSIWithSmoothing creates a new RSI indicator with the specified period and smoothing ("" = Wilder)
+func NewRSIWithSmoothing(period int, smoothing RSISmoothing) (*RSI, error) {
 	if period < 2 {
 		return nil, fmt.Errorf("RSI period must be at least 2, got %d", period)
 	}
 
-	return &RSI{
-		period:     period,
-		closes:     make([]float64, 0),
-		timestamps: make([]time.Time, 0),
-		lastRSI:    50.0, // Neutral value when not ready
-		isReady:    false,
-	}, nil
+	switch smoothing {
+	case "":
+		smoothing = RSISmoothingWilder
+	case RSISmoothingWilder, RSISmoothingSMA, RSISmoothingEMA:
+	default:
+		return nil, fmt.Errorf("unknown RSI smoothing %q (use wilder, sma or ema)", smoothing)
+	}
+
+	r := &RSI{
+		period:    period,
+		smoothing: smoothing,
+	}
+	r.Reset()
+	return r, nil
+}
+
+// rsiDefinition registers the RSI with the indicator registry
+var rsiDefinition = Definition{
+	Name:        "rsi",
+	DisplayName: "RSI",
+	Description: "Relative Strength Index: momentum from 0 to 100",
+	Params: []ParamSpec{
+		{Name: "period", Type: ParamInt, Default: 14, Min: 2, Max: 100, Description: "Number of price changes averaged"},
+		{Name: "smoothing", Type: ParamString, Default: string(RSISmoothingWilder),
+			Options:     []string{string(RSISmoothingWilder), string(RSISmoothingSMA), string(RSISmoothingEMA)},
+			Description: "How gains and losses are averaged"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewRSIWithSmoothing(p.Int("period"), RSISmoothing(p.String("smoothing")))
+	},
 }
 
 // Name returns the indicator identifier
@@ -42,20 +94,45 @@ func (r *RSI) Update(price float64, timestamp time.Time) error {
 		return fmt.Errorf("price must be positive, got %.8f", price)
 	}
 
-	// Add new price data
-	r.closes = append(r.closes, price)
-	r.timestamps = append(r.timestamps, timestamp)
+	r.count++
+	r.lastTimestamp = timestamp
+	if r.count == 1 {
+		r.prevClose = price
+		return nil
+	}
+
+	gain, loss := 0.0, 0.0
+	if change := price - r.prevClose; change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+	r.prevClose = price
 
-	// Keep only what we need (period + buffer for accuracy)
-	// Buffer of 20 helps with smoothing and accuracy
-	maxKeep := r.period + 20
-	if len(r.closes) > maxKeep {
-		r.closes = r.closes[len(r.closes)-maxKeep:]
-		r.timestamps = r.timestamps[len(r.timestamps)-maxKeep:]
+	changes := r.count - 1
+	period := float64(r.period)
+	switch {
+	case r.smoothing == RSISmoothingSMA:
+		r.addToWindow(gain, loss)
+		r.avgGain = r.gainSum / period
+		r.avgLoss = r.lossSum / period
+	case changes <= r.period:
+		// Seed with the simple average of the first period changes
+		r.gainSum += gain
+		r.lossSum += loss
+		r.avgGain = r.gainSum / period
+		r.avgLoss = r.lossSum / period
+	case r.smoothing == RSISmoothingWilder:
+		r.avgGain = (r.avgGain*(period-1) + gain) / period
+		r.avgLoss = (r.avgLoss*(period-1) + loss) / period
+	default:
+		alpha := 2 / (period + 1)
+		r.avgGain += (gain - r.avgGain) * alpha
+		r.avgLoss += (loss - r.avgLoss) * alpha
 	}
 
-	// Calculate RSI if we have enough data
-	if len(r.closes) >= r.period+1 {
+	// Calculate RSI once the averages are seeded
+	if changes >= r.period {
 		rsi, err := r.calculate()
 		if err != nil {
 			return fmt.Errorf("RSI calculation failed: %w", err)
@@ -67,6 +144,24 @@ func (r *RSI) Update(price float64, timestamp time.Time) error {
 	return nil
 }
 
+// addToWindow replaces the oldest gain and loss in the SMA ring, keeping the sums current
+func (r *RSI) addToWindow(gain, loss float64) {
+	r.gainSum += gain - r.gains[r.pos]
+	r.lossSum += loss - r.losses[r.pos]
+	r.gains[r.pos] = gain
+	r.losses[r.pos] = loss
+
+	r.pos = (r.pos + 1) % r.period
+	if r.pos == 0 {
+		// Re-sum once per lap so rounding errors don't accumulate
+		r.gainSum, r.lossSum = 0, 0
+		for i := range r.gains {
+			r.gainSum += r.gains[i]
+			r.lossSum += r.losses[i]
+		}
+	}
+}
+
 // GetValue returns the current RSI value
 // Returns (map with "rsi" key, true) if ready, (map with neutral value, false) if not ready
 func (r *RSI) GetValue() (map[string]float64, bool) {
@@ -82,55 +177,45 @@ func (r *RSI) IsReady() bool {
 
 // Reset clears all historical data
 func (r *RSI) Reset() {
-	r.closes = make([]float64, 0)
-	r.timestamps = make([]time.Time, 0)
-	r.lastRSI = 50.0
+	r.count = 0
+	r.prevClose = 0
+	r.lastTimestamp = time.Time{}
+	r.gainSum, r.lossSum = 0, 0
+	r.avgGain, r.avgLoss = 0, 0
+	r.gains, r.losses, r.pos = nil, nil, 0
+	if r.smoothing == RSISmoothingSMA {
+		r.gains = make([]float64, r.period)
+		r.losses = make([]float64, r.period)
+	}
+	r.lastRSI = 50.0 // Neutral value when not ready
 	r.isReady = false
 }
 
-// GetDataCount returns the number of price points currently stored
+// GetDataCount returns the number of prices received
 func (r *RSI) GetDataCount() int {
-	return len(r.closes)
+	return r.count
 }
 
-// calculate computes the RSI value using the standard formula
+// calculate computes the RSI value from the smoothed averages
 // RSI = 100 - (100 / (1 + RS))
-// where RS = Average Gain / Average Loss over the period
+// where RS = Average Gain / Average Loss
 func (r *RSI) calculate() (float64, error) {
-	if len(r.closes) < r.period+1 {
-		return 50.0, fmt.Errorf("insufficient data: need %d points, have %d", r.period+1, len(r.closes))
-	}
-
-	gains := 0.0
-	losses := 0.0
-
-	// Calculate gains and losses over the period
-	for i := len(r.closes) - r.period; i < len(r.closes); i++ {
-		change := r.closes[i] - r.closes[i-1]
-		if change > 0 {
-			gains += change
-		} else {
-			losses += math.Abs(change)
-		}
+	if r.count < r.period+1 {
+		return 50.0, fmt.Errorf("insufficient data: need %d points, have %d", r.period+1, r.count)
 	}
 
 	// Handle edge case: no losses means RSI = 100
-	if losses == 0 {
+	if r.avgLoss == 0 {
 		return 100.0, nil
 	}
 
 	// Handle edge case: no gains means RSI = 0
-	if gains == 0 {
+	if r.avgGain == 0 {
 		return 0.0, nil
 	}
 
-	// Standard RSI calculation
-	avgGain := gains / float64(r.period)
-	avgLoss := losses / float64(r.period)
-	rs := avgGain / avgLoss
-	rsi := 100 - (100 / (1 + rs))
-
-	return rsi, nil
+	rs := r.avgGain / r.avgLoss
+	return 100 - (100 / (1 + rs)), nil
 }
 
 // GetPeriod returns the RSI period setting
@@ -138,10 +223,15 @@ func (r *RSI) GetPeriod() int {
 	return r.period
 }
 
+// GetSmoothing returns how gains and losses are averaged
+func (r *RSI) GetSmoothing() RSISmoothing {
+	return r.smoothing
+}
+
 // GetRequiredDataPoints returns how many more data points are needed
 // Returns 0 if already ready
 func (r *RSI) GetRequiredDataPoints() int {
-	needed := (r.period + 1) - len(r.closes)
+	needed := (r.period + 1) - r.count
 	if needed < 0 {
 		return 0
 	}
@@ -150,8 +240,63 @@ func (r *RSI) GetRequiredDataPoints() int {
 
 // GetLastTimestamp returns the timestamp of the most recent data point
 func (r *RSI) GetLastTimestamp() (time.Time, bool) {
-	if len(r.timestamps) == 0 {
+	if r.count == 0 {
 		return time.Time{}, false
 	}
-	return r.timestamps[len(r.timestamps)-1], true
+	return r.lastTimestamp, true
+}
+
+// rsiState is the saved form of an RSI's data
+type rsiState struct {
+	Count         int       `json:"count"`
+	PrevClose     float64   `json:"prev_close"`
+	LastTimestamp time.Time `json:"last_timestamp"`
+	GainSum       float64   `json:"gain_sum"`
+	LossSum       float64   `json:"loss_sum"`
+	AvgGain       float64   `json:"avg_gain"`
+	AvgLoss       float64   `json:"avg_loss"`
+	Gains         []float64 `json:"gains,omitempty"`
+	Losses        []float64 `json:"losses,omitempty"`
+	Pos           int       `json:"pos"`
+	LastRSI       float64   `json:"last_rsi"`
+	IsReady       bool      `json:"is_ready"`
+}
+
+// MarshalState saves the RSI's running averages
+func (r *RSI) MarshalState() ([]byte, error) {
+	return encodeState(r.Name(), stateParams(r.period, r.smoothing), rsiState{
+		Count:         r.count,
+		PrevClose:     r.prevClose,
+		LastTimestamp: r.lastTimestamp,
+		GainSum:       r.gainSum,
+		LossSum:       r.lossSum,
+		AvgGain:       r.avgGain,
+		AvgLoss:       r.avgLoss,
+		Gains:         r.gains,
+		Losses:        r.losses,
+		Pos:           r.pos,
+		LastRSI:       r.lastRSI,
+		IsReady:       r.isReady,
+	})
+}
+
+// UnmarshalState restores running averages saved by MarshalState
+func (r *RSI) UnmarshalState(data []byte) error {
+	var s rsiState
+	if err := decodeState(data, r.Name(), stateParams(r.period, r.smoothing), &s); err != nil {
+		return err
+	}
+	if r.smoothing == RSISmoothingSMA && (len(s.Gains) != r.period || len(s.Losses) != r.period || s.Pos < 0 || s.Pos >= r.period) {
+		return fmt.Errorf("invalid RSI state: SMA window does not match period %d", r.period)
+	}
+
+	r.count = s.Count
+	r.prevClose = s.PrevClose
+	r.lastTimestamp = s.LastTimestamp
+	r.gainSum, r.lossSum = s.GainSum, s.LossSum
+	r.avgGain, r.avgLoss = s.AvgGain, s.AvgLoss
+	r.gains, r.losses, r.pos = s.Gains, s.Losses, s.Pos
+	r.lastRSI = s.LastRSI
+	r.isReady = s.IsReady
+	return nil
 }
@@ -0,0 +1,138 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// wilderCloses is the 14-period worked example from Wilder's "New Concepts in
+// Technical Trading Systems" as republished by StockCharts. Their table rounds
+// the averages at each step, so its RSI differs from the values below by up to 0.07.
+var wilderCloses = []float64{
+	44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08, 45.89,
+	46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64, 46.21, 46.25,
+	45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57, 43.42, 42.66, 43.13,
+}
+
+// TestRSISmoothingParity checks every RSI value of the worked example against
+// an independent implementation of each smoothing
+func TestRSISmoothingParity(t *testing.T) {
+	tests := []struct {
+		smoothing RSISmoothing
+		want      []float64 // RSI after each close from the 15th on
+	}{
+		{RSISmoothingWilder, []float64{
+			70.46413502109705, 66.24961855355505, 66.48094183471265, 69.34685316290866, 66.29471265892624,
+			57.91502067008556, 62.880718309962404, 63.20878871828778, 56.01158478954757, 62.33992931089789,
+			54.67097137765516, 50.386815195114224, 40.01942379131357, 41.49263540422282, 41.90242967845811,
+			45.499497238680405, 37.322778313379956, 33.09048257272339, 37.788771982057824,
+		}},
+		{RSISmoothingSMA, []float64{
+			70.46413502109705, 70.02096436058699, 69.831223628692, 80.56768558951963, 73.33333333333336,
+			59.80629539951578, 62.52821670428896, 60.0, 48.47775175644025, 53.87840670859544,
+			48.952380952380956, 43.86281588447653, 37.73291925465839, 32.26351351351349, 32.71812080536908,
+			38.14262023217247, 31.748251748251732, 25.099601593625508, 30.217669654289423,
+		}},
+		{RSISmoothingEMA, []float64{
+			70.46413502109705, 62.51079758134174, 63.02671530614472, 69.21800659460254, 63.12012530100334,
+			48.172259388887376, 59.14372961624991, 59.832244229918246, 47.39172207983883, 60.40822128016246,
+			48.00084433525033, 41.66383013853536, 28.388785311953598, 31.260545181278786, 32.09839038961823,
+			39.53870212794547, 27.93422848026168, 22.825748230810845, 31.73398518815202,
+		}},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(string(tt.smoothing), func(t *testing.T) {
+			rsi, err := NewRSIWithSmoothing(14, tt.smoothing)
+			if err != nil {
+				t.Fatalf("NewRSIWithSmoothing: %v", err)
+			}
+
+			// Run the series twice to check Reset restores the initial state
+			for pass := 0; pass < 2; pass++ {
+				for i, price := range wilderCloses {
+					if err := rsi.Update(price, start.AddDate(0, 0, i)); err != nil {
+						t.Fatalf("close %d: %v", i, err)
+					}
+					if i < 14 {
+						if rsi.IsReady() {
+							t.Fatalf("close %d: ready before 15 closes", i)
+						}
+						continue
+					}
+
+					values, ok := rsi.GetValue()
+					if !ok {
+						t.Fatalf("close %d: not ready", i)
+					}
+					if want := tt.want[i-14]; math.Abs(values[ValueKeyRSI]-want) > 1e-9 {
+						t.Errorf("pass %d close %d: RSI = %v, want %v", pass, i, values[ValueKeyRSI], want)
+					}
+				}
+				rsi.Reset()
+			}
+		})
+	}
+}
+
+// TestRSISMAMatchesFullRecalculation checks the running SMA sums against a
+// full re-sum over a long series, where rounding drift would show
+func TestRSISMAMatchesFullRecalculation(t *testing.T) {
+	const period = 9
+	rsi, _ := NewRSIWithSmoothing(period, RSISmoothingSMA)
+
+	closes := []float64{100}
+	for i := 1; i < 5000; i++ {
+		closes = append(closes, 100+10*math.Sin(float64(i)/7)+math.Cos(float64(i)*1.3))
+	}
+
+	start := time.Now()
+	for i, price := range closes {
+		if err := rsi.Update(price, start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("close %d: %v", i, err)
+		}
+	}
+
+	gains, losses := 0.0, 0.0
+	for i := len(closes) - period; i < len(closes); i++ {
+		if change := closes[i] - closes[i-1]; change > 0 {
+			gains += change
+		} else {
+			losses -= change
+		}
+	}
+	want := 100 - 100/(1+gains/losses)
+
+	values, _ := rsi.GetValue()
+	if math.Abs(values[ValueKeyRSI]-want) > 1e-9 {
+		t.Errorf("RSI = %v, want %v", values[ValueKeyRSI], want)
+	}
+}
+
+// TestRSIEdgeCases checks the one-directional series limits and parameter validation
+func TestRSIEdgeCases(t *testing.T) {
+	rising, _ := NewRSI(3)
+	falling, _ := NewRSI(3)
+	for i := 0; i < 10; i++ {
+		rising.Update(100+float64(i), time.Now())
+		falling.Update(100-float64(i), time.Now())
+	}
+	if v, _ := rising.GetValue(); v[ValueKeyRSI] != 100 {
+		t.Errorf("rising RSI = %v, want 100", v[ValueKeyRSI])
+	}
+	if v, _ := falling.GetValue(); v[ValueKeyRSI] != 0 {
+		t.Errorf("falling RSI = %v, want 0", v[ValueKeyRSI])
+	}
+
+	if _, err := NewRSIWithSmoothing(14, "hull"); err == nil {
+		t.Error("NewRSIWithSmoothing accepted unknown smoothing")
+	}
+	if err := NewFactory().ValidateConfig(IndicatorConfig{Type: "rsi", Params: map[string]interface{}{"smoothing": "hull"}}); err == nil {
+		t.Error("ValidateConfig accepted unknown smoothing")
+	}
+	if rsi, err := NewFactory().Create(IndicatorConfig{Type: "rsi", Params: map[string]interface{}{"smoothing": "EMA"}}); err != nil || rsi.(*RSI).GetSmoothing() != RSISmoothingEMA {
+		t.Errorf("Create with smoothing EMA: %v", err)
+	}
+}
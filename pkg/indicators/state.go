@@ -0,0 +1,75 @@
+package indicators
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// StateMarshaler is implemented by indicators that can save and restore their
+// data, so a restarted bot resumes with the same values instead of re-warming
+type StateMarshaler interface {
+	// MarshalState encodes the indicator's data along with the parameters it was built with
+	MarshalState() ([]byte, error)
+
+	// UnmarshalState replaces the indicator's data with state saved by MarshalState.
+	// It fails if the state belongs to another indicator or other parameters.
+	UnmarshalState(data []byte) error
+}
+
+// MarshalState saves an indicator's state; it fails for indicators that are not StateMarshalers
+func MarshalState(indicator Indicator) ([]byte, error) {
+	m, ok := indicator.(StateMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("%s indicator cannot save its state", indicator.Name())
+	}
+	return m.MarshalState()
+}
+
+// UnmarshalState restores an indicator's state saved by MarshalState
+func UnmarshalState(indicator Indicator, data []byte) error {
+	m, ok := indicator.(StateMarshaler)
+	if !ok {
+		return fmt.Errorf("%s indicator cannot restore its state", indicator.Name())
+	}
+	return m.UnmarshalState(data)
+}
+
+// savedState is the envelope every indicator's state is stored in
+type savedState struct {
+	Indicator string          `json:"indicator"`
+	Params    string          `json:"params"`
+	State     json.RawMessage `json:"state"`
+}
+
+// stateParams formats the parameters an indicator was built with
+func stateParams(values ...interface{}) string {
+	return fmt.Sprintf("%v", values)
+}
+
+// encodeState wraps an indicator's state with its name and parameters
+func encodeState(name, params string, state interface{}) ([]byte, error) {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s state: %w", name, err)
+	}
+	return json.Marshal(savedState{Indicator: name, Params: params, State: raw})
+}
+
+// decodeState unwraps state saved by encodeState into state, checking it was
+// saved by the same indicator with the same parameters
+func decodeState(data []byte, name, params string, state interface{}) error {
+	var saved savedState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("invalid %s state: %w", name, err)
+	}
+	if saved.Indicator != name {
+		return fmt.Errorf("saved state is for %s, not %s", saved.Indicator, name)
+	}
+	if saved.Params != params {
+		return fmt.Errorf("saved %s state has parameters %s, indicator has %s", name, saved.Params, params)
+	}
+	if err := json.Unmarshal(saved.State, state); err != nil {
+		return fmt.Errorf("invalid %s state: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package indicators
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestStateRoundTrip saves every registered indicator part-way through the
+// reference vector, restores the state into a fresh instance and checks both
+// produce identical values for the remaining bars
+func TestStateRoundTrip(t *testing.T) {
+	factory := NewFactory()
+	configs := []IndicatorConfig{
+		{Type: "rsi", Params: map[string]interface{}{"smoothing": "sma"}},
+		{Type: "vwap", Params: map[string]interface{}{"period": 10}},
+	}
+	for _, name := range factory.GetAvailableIndicators() {
+		configs = append(configs, factory.GetDefaultConfig(name))
+	}
+
+	series := referenceSeries()
+	for _, config := range configs {
+		for _, split := range []int{0, 5, 20, len(series)} {
+			original, err := factory.Create(config)
+			if err != nil {
+				t.Fatalf("%s: Create: %v", config.Type, err)
+			}
+			for _, candle := range series[:split] {
+				if err := UpdateWithCandle(original, candle); err != nil {
+					t.Fatalf("%s: %v", config.Type, err)
+				}
+			}
+
+			data, err := MarshalState(original)
+			if err != nil {
+				t.Fatalf("%s: MarshalState: %v", config.Type, err)
+			}
+			restored, _ := factory.Create(config)
+			if err := UnmarshalState(restored, data); err != nil {
+				t.Fatalf("%s: UnmarshalState: %v", config.Type, err)
+			}
+
+			for i := split; i <= len(series); i++ {
+				want, wantReady := original.GetValue()
+				got, gotReady := restored.GetValue()
+				if gotReady != wantReady || !reflect.DeepEqual(got, want) || restored.GetDataCount() != original.GetDataCount() {
+					t.Fatalf("%s saved after %d bars, bar %d: restored %v (ready %v), want %v (ready %v)",
+						config.Type, split, i, got, gotReady, want, wantReady)
+				}
+				if i == len(series) {
+					break
+				}
+				UpdateWithCandle(original, series[i])
+				UpdateWithCandle(restored, series[i])
+			}
+		}
+	}
+}
+
+// TestStateRejectsOtherIndicators checks state is only restored into an
+// indicator of the same kind and parameters
+func TestStateRejectsOtherIndicators(t *testing.T) {
+	rsi, _ := NewRSI(14)
+	for _, candle := range referenceSeries() {
+		rsi.Update(candle.Close, candle.Timestamp)
+	}
+	data, _ := rsi.MarshalState()
+
+	other, _ := NewRSI(9)
+	if err := other.UnmarshalState(data); err == nil {
+		t.Error("RSI(9) restored RSI(14) state")
+	}
+	sma, _ := NewRSIWithSmoothing(14, RSISmoothingSMA)
+	if err := sma.UnmarshalState(data); err == nil {
+		t.Error("SMA-smoothed RSI restored Wilder RSI state")
+	}
+	ema, _ := NewEMA(14)
+	if err := ema.UnmarshalState(data); err == nil {
+		t.Error("EMA restored RSI state")
+	}
+	if err := rsi.UnmarshalState([]byte("not json")); err == nil {
+		t.Error("RSI restored invalid data")
+	}
+}
@@ -0,0 +1,169 @@
+package indicators
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stochastic oscillator
+// %K = 100 * (close - lowest low) / (highest high - lowest low) over kPeriod bars
+// %D = SMA of %K over dPeriod bars
+// Needs real bars: fed closes only (Update), every bar has high = low = close.
+type Stochastic struct {
+	kPeriod int
+	dPeriod int
+
+	highs   []float64 // Last kPeriod bars
+	lows    []float64
+	kValues []float64 // Last dPeriod %K values
+	count   int
+}
+
+// NewStochastic creates a new Stochastic oscillator
+// Standard parameters: kPeriod=14, dPeriod=3
+func NewStochastic(kPeriod, dPeriod int) (*Stochastic, error) {
+	if kPeriod <= 0 {
+		return nil, fmt.Errorf("%%K period must be positive, got %d", kPeriod)
+	}
+	if dPeriod <= 0 {
+		return nil, fmt.Errorf("%%D period must be positive, got %d", dPeriod)
+	}
+
+	return &Stochastic{
+		kPeriod: kPeriod,
+		dPeriod: dPeriod,
+		highs:   make([]float64, 0, kPeriod),
+		lows:    make([]float64, 0, kPeriod),
+		kValues: make([]float64, 0, dPeriod),
+	}, nil
+}
+
+// stochasticDefinition registers the Stochastic oscillator with the indicator registry
+var stochasticDefinition = Definition{
+	Name:        "stoch",
+	Aliases:     []string{"stochastic"},
+	DisplayName: "Stochastic",
+	Description: "Stochastic oscillator: close within the high/low range, 0 to 100",
+	CandleInput: true,
+	Params: []ParamSpec{
+		{Name: "k_period", Type: ParamInt, Default: 14, Min: 1, Description: "%K lookback in candles"},
+		{Name: "d_period", Type: ParamInt, Default: 3, Min: 1, Description: "%D smoothing (SMA of %K)"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewStochastic(p.Int("k_period"), p.Int("d_period"))
+	},
+}
+
+// Name returns the indicator identifier
+func (s *Stochastic) Name() string {
+	return "Stoch"
+}
+
+// Update adds a close as a bar with no range; prefer UpdateCandle
+func (s *Stochastic) Update(price float64, timestamp time.Time) error {
+	return s.UpdateCandle(Candle{Timestamp: timestamp, Open: price, High: price, Low: price, Close: price})
+}
+
+// UpdateCandle adds a bar and recalculates %K and %D
+func (s *Stochastic) UpdateCandle(candle Candle) error {
+	if err := checkCandle(candle); err != nil {
+		return err
+	}
+
+	s.highs = append(s.highs, candle.High)
+	s.lows = append(s.lows, candle.Low)
+	if len(s.highs) > s.kPeriod {
+		s.highs = s.highs[1:]
+		s.lows = s.lows[1:]
+	}
+	s.count++
+
+	if len(s.highs) < s.kPeriod {
+		return nil
+	}
+
+	highest, lowest := s.highs[0], s.lows[0]
+	for i := 1; i < len(s.highs); i++ {
+		if s.highs[i] > highest {
+			highest = s.highs[i]
+		}
+		if s.lows[i] < lowest {
+			lowest = s.lows[i]
+		}
+	}
+
+	k := 50.0 // No range over the period: neither overbought nor oversold
+	if highest > lowest {
+		k = 100 * (candle.Close - lowest) / (highest - lowest)
+	}
+
+	s.kValues = append(s.kValues, k)
+	if len(s.kValues) > s.dPeriod {
+		s.kValues = s.kValues[1:]
+	}
+
+	return nil
+}
+
+// GetValue returns the current %K and %D
+func (s *Stochastic) GetValue() (map[string]float64, bool) {
+	if !s.IsReady() {
+		return nil, false
+	}
+
+	return map[string]float64{
+		ValueKeyStochK: s.kValues[len(s.kValues)-1],
+		ValueKeyStochD: average(s.kValues),
+	}, true
+}
+
+// IsReady returns true once %D covers dPeriod %K values (kPeriod + dPeriod - 1 bars)
+func (s *Stochastic) IsReady() bool {
+	return len(s.kValues) >= s.dPeriod
+}
+
+// Reset clears all data
+func (s *Stochastic) Reset() {
+	s.highs = make([]float64, 0, s.kPeriod)
+	s.lows = make([]float64, 0, s.kPeriod)
+	s.kValues = make([]float64, 0, s.dPeriod)
+	s.count = 0
+}
+
+// GetDataCount returns the number of bars received
+func (s *Stochastic) GetDataCount() int {
+	return s.count
+}
+
+// stochasticState is the saved form of a Stochastic's data
+type stochasticState struct {
+	Highs   []float64 `json:"highs"`
+	Lows    []float64 `json:"lows"`
+	KValues []float64 `json:"k_values"`
+	Count   int       `json:"count"`
+}
+
+// MarshalState saves the high/low and %K windows
+func (s *Stochastic) MarshalState() ([]byte, error) {
+	return encodeState(s.Name(), stateParams(s.kPeriod, s.dPeriod), stochasticState{
+		Highs:   s.highs,
+		Lows:    s.lows,
+		KValues: s.kValues,
+		Count:   s.count,
+	})
+}
+
+// UnmarshalState restores windows saved by MarshalState
+func (s *Stochastic) UnmarshalState(data []byte) error {
+	var st stochasticState
+	if err := decodeState(data, s.Name(), stateParams(s.kPeriod, s.dPeriod), &st); err != nil {
+		return err
+	}
+	if len(st.Highs) != len(st.Lows) {
+		return fmt.Errorf("invalid Stochastic state: %d highs but %d lows", len(st.Highs), len(st.Lows))
+	}
+
+	s.highs, s.lows, s.kValues = st.Highs, st.Lows, st.KValues
+	s.count = st.Count
+	return nil
+}
@@ -0,0 +1,193 @@
+package indicators
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StochRSI implements the Stochastic RSI oscillator: the stochastic formula applied
+// to RSI values instead of prices, scaled 0-100
+// StochRSI = 100 * (RSI - lowest RSI) / (highest RSI - lowest RSI) over stochPeriod
+// %K = SMA of StochRSI over kPeriod
+// %D = SMA of %K over dPeriod
+type StochRSI struct {
+	rsiPeriod   int
+	stochPeriod int
+	kPeriod     int
+	dPeriod     int
+
+	rsi       *RSI
+	rsiValues []float64 // Last stochPeriod RSI values
+	rawValues []float64 // Last kPeriod StochRSI values
+	kValues   []float64 // Last dPeriod %K values
+	count     int
+}
+
+// NewStochRSI creates a new Stochastic RSI indicator
+// Standard parameters: rsiPeriod=14, stochPeriod=14, kPeriod=3, dPeriod=3
+func NewStochRSI(rsiPeriod, stochPeriod, kPeriod, dPeriod int) (*StochRSI, error) {
+	rsi, err := NewRSI(rsiPeriod)
+	if err != nil {
+		return nil, err
+	}
+	if stochPeriod <= 0 {
+		return nil, fmt.Errorf("stoch period must be positive, got %d", stochPeriod)
+	}
+	if kPeriod <= 0 {
+		return nil, fmt.Errorf("%%K period must be positive, got %d", kPeriod)
+	}
+	if dPeriod <= 0 {
+		return nil, fmt.Errorf("%%D period must be positive, got %d", dPeriod)
+	}
+
+	return &StochRSI{
+		rsiPeriod:   rsiPeriod,
+		stochPeriod: stochPeriod,
+		kPeriod:     kPeriod,
+		dPeriod:     dPeriod,
+		rsi:         rsi,
+		rsiValues:   make([]float64, 0, stochPeriod),
+		rawValues:   make([]float64, 0, kPeriod),
+		kValues:     make([]float64, 0, dPeriod),
+	}, nil
+}
+
+// stochRSIDefinition registers the Stochastic RSI with the indicator registry
+var stochRSIDefinition = Definition{
+	Name:        "stoch_rsi",
+	Aliases:     []string{"stochastic_rsi"},
+	DisplayName: "Stochastic RSI",
+	Description: "Stochastic RSI: RSI within its own range, 0 to 100",
+	Params: []ParamSpec{
+		{Name: "rsi_period", Type: ParamInt, Default: 14, Min: 2, Max: 100, Description: "RSI period"},
+		{Name: "stoch_period", Type: ParamInt, Default: 14, Min: 1, Description: "Lookback for the RSI high/low range"},
+		{Name: "k_period", Type: ParamInt, Default: 3, Min: 1, Description: "%K smoothing (SMA of Stochastic RSI)"},
+		{Name: "d_period", Type: ParamInt, Default: 3, Min: 1, Description: "%D smoothing (SMA of %K)"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewStochRSI(p.Int("rsi_period"), p.Int("stoch_period"), p.Int("k_period"), p.Int("d_period"))
+	},
+}
+
+// Name returns the indicator identifier
+func (s *StochRSI) Name() string {
+	return "StochRSI"
+}
+
+// Update adds new price data and recalculates StochRSI, %K and %D
+func (s *StochRSI) Update(price float64, timestamp time.Time) error {
+	if err := s.rsi.Update(price, timestamp); err != nil {
+		return err
+	}
+	s.count++
+
+	if !s.rsi.IsReady() {
+		return nil
+	}
+
+	s.rsiValues = appendWindow(s.rsiValues, s.rsi.lastRSI, s.stochPeriod)
+	if len(s.rsiValues) < s.stochPeriod {
+		return nil
+	}
+
+	highest, lowest := s.rsiValues[0], s.rsiValues[0]
+	for _, v := range s.rsiValues[1:] {
+		if v > highest {
+			highest = v
+		}
+		if v < lowest {
+			lowest = v
+		}
+	}
+
+	raw := 50.0 // RSI flat over the period: neither overbought nor oversold
+	if highest > lowest {
+		raw = 100 * (s.rsi.lastRSI - lowest) / (highest - lowest)
+	}
+
+	s.rawValues = appendWindow(s.rawValues, raw, s.kPeriod)
+	if len(s.rawValues) < s.kPeriod {
+		return nil
+	}
+
+	s.kValues = appendWindow(s.kValues, average(s.rawValues), s.dPeriod)
+	return nil
+}
+
+// GetValue returns the current StochRSI, %K and %D
+func (s *StochRSI) GetValue() (map[string]float64, bool) {
+	if !s.IsReady() {
+		return nil, false
+	}
+
+	return map[string]float64{
+		ValueKeyStochRSI: s.rawValues[len(s.rawValues)-1],
+		ValueKeyStochK:   s.kValues[len(s.kValues)-1],
+		ValueKeyStochD:   average(s.kValues),
+	}, true
+}
+
+// IsReady returns true once %D covers dPeriod %K values
+// (rsiPeriod + stochPeriod + kPeriod + dPeriod - 2 prices)
+func (s *StochRSI) IsReady() bool {
+	return len(s.kValues) >= s.dPeriod
+}
+
+// Reset clears all data
+func (s *StochRSI) Reset() {
+	s.rsi.Reset()
+	s.rsiValues = make([]float64, 0, s.stochPeriod)
+	s.rawValues = make([]float64, 0, s.kPeriod)
+	s.kValues = make([]float64, 0, s.dPeriod)
+	s.count = 0
+}
+
+// GetDataCount returns the number of prices received
+func (s *StochRSI) GetDataCount() int {
+	return s.count
+}
+
+// stochRSIState is the saved form of a StochRSI's data
+type stochRSIState struct {
+	RSI       json.RawMessage `json:"rsi"`
+	RSIValues []float64       `json:"rsi_values"`
+	RawValues []float64       `json:"raw_values"`
+	KValues   []float64       `json:"k_values"`
+	Count     int             `json:"count"`
+}
+
+// MarshalState saves the inner RSI and the StochRSI, %K and %D windows
+func (s *StochRSI) MarshalState() ([]byte, error) {
+	rsi, err := s.rsi.MarshalState()
+	if err != nil {
+		return nil, err
+	}
+	return encodeState(s.Name(), s.stateParams(), stochRSIState{
+		RSI:       rsi,
+		RSIValues: s.rsiValues,
+		RawValues: s.rawValues,
+		KValues:   s.kValues,
+		Count:     s.count,
+	})
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (s *StochRSI) UnmarshalState(data []byte) error {
+	var st stochRSIState
+	if err := decodeState(data, s.Name(), s.stateParams(), &st); err != nil {
+		return err
+	}
+	if err := s.rsi.UnmarshalState(st.RSI); err != nil {
+		return err
+	}
+
+	s.rsiValues, s.rawValues, s.kValues = st.RSIValues, st.RawValues, st.KValues
+	s.count = st.Count
+	return nil
+}
+
+// stateParams formats the parameters saved with the state
+func (s *StochRSI) stateParams() string {
+	return stateParams(s.rsiPeriod, s.stochPeriod, s.kPeriod, s.dPeriod)
+}
@@ -0,0 +1,153 @@
+package indicators
+
+import (
+	"fmt"
+	"time"
+)
+
+// VWAP (Volume-Weighted Average Price) of the typical price (high + low + close) / 3
+// With period 0 it is anchored to the UTC day and restarts on each day's first bar;
+// otherwise it is a rolling VWAP over the last period bars.
+// Needs volume: fed closes only (Update), it is never ready.
+type VWAP struct {
+	period int
+
+	day     time.Time // UTC day of the anchored session
+	pv      []float64 // Typical price * volume per bar (rolling) or one running total (anchored)
+	volumes []float64
+	count   int
+}
+
+// NewVWAP creates a new VWAP indicator; period 0 anchors it to the UTC day
+func NewVWAP(period int) (*VWAP, error) {
+	if period < 0 {
+		return nil, fmt.Errorf("VWAP period must not be negative, got %d", period)
+	}
+
+	v := &VWAP{period: period}
+	v.Reset()
+	return v, nil
+}
+
+// vwapDefinition registers the VWAP with the indicator registry
+var vwapDefinition = Definition{
+	Name:        "vwap",
+	DisplayName: "VWAP",
+	Description: "Volume-Weighted Average Price, anchored to the UTC day or rolling",
+	CandleInput: true,
+	Params: []ParamSpec{
+		{Name: "period", Type: ParamInt, Default: 0, Min: 0, Description: "Rolling window in candles (0 = anchored to the UTC day)"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewVWAP(p.Int("period"))
+	},
+}
+
+// Name returns the indicator identifier
+func (v *VWAP) Name() string {
+	return "VWAP"
+}
+
+// Update adds a close with no volume; prefer UpdateCandle
+func (v *VWAP) Update(price float64, timestamp time.Time) error {
+	return v.UpdateCandle(Candle{Timestamp: timestamp, Open: price, High: price, Low: price, Close: price})
+}
+
+// UpdateCandle adds a bar and recalculates the VWAP
+func (v *VWAP) UpdateCandle(candle Candle) error {
+	if err := checkCandle(candle); err != nil {
+		return err
+	}
+	if candle.Volume < 0 {
+		return fmt.Errorf("volume must not be negative, got %.8f", candle.Volume)
+	}
+
+	typical := (candle.High + candle.Low + candle.Close) / 3
+	v.count++
+
+	if v.period > 0 {
+		v.pv = appendWindow(v.pv, typical*candle.Volume, v.period)
+		v.volumes = appendWindow(v.volumes, candle.Volume, v.period)
+		return nil
+	}
+
+	day := candle.Timestamp.UTC().Truncate(24 * time.Hour)
+	if !day.Equal(v.day) {
+		v.day = day
+		v.pv = []float64{0}
+		v.volumes = []float64{0}
+	}
+	v.pv[0] += typical * candle.Volume
+	v.volumes[0] += candle.Volume
+	return nil
+}
+
+// totals returns the summed price*volume and volume of the current window or session
+func (v *VWAP) totals() (pv, volume float64) {
+	for i := range v.pv {
+		pv += v.pv[i]
+		volume += v.volumes[i]
+	}
+	return pv, volume
+}
+
+// GetValue returns the current VWAP
+func (v *VWAP) GetValue() (map[string]float64, bool) {
+	if !v.IsReady() {
+		return nil, false
+	}
+
+	pv, volume := v.totals()
+	return map[string]float64{
+		ValueKeyVWAP: pv / volume,
+	}, true
+}
+
+// IsReady returns true once the window (or session) has traded volume,
+// and a rolling VWAP has period bars
+func (v *VWAP) IsReady() bool {
+	if v.period > 0 && len(v.volumes) < v.period {
+		return false
+	}
+	_, volume := v.totals()
+	return volume > 0
+}
+
+// Reset clears all data
+func (v *VWAP) Reset() {
+	v.day = time.Time{}
+	v.pv = make([]float64, 0, v.period)
+	v.volumes = make([]float64, 0, v.period)
+	v.count = 0
+}
+
+// GetDataCount returns the number of bars received
+func (v *VWAP) GetDataCount() int {
+	return v.count
+}
+
+// vwapState is the saved form of a VWAP's data
+type vwapState struct {
+	Day     time.Time `json:"day"`
+	PV      []float64 `json:"pv"`
+	Volumes []float64 `json:"volumes"`
+	Count   int       `json:"count"`
+}
+
+// MarshalState saves the session (or rolling window) totals
+func (v *VWAP) MarshalState() ([]byte, error) {
+	return encodeState(v.Name(), stateParams(v.period), vwapState{Day: v.day, PV: v.pv, Volumes: v.volumes, Count: v.count})
+}
+
+// UnmarshalState restores totals saved by MarshalState
+func (v *VWAP) UnmarshalState(data []byte) error {
+	var s vwapState
+	if err := decodeState(data, v.Name(), stateParams(v.period), &s); err != nil {
+		return err
+	}
+	if len(s.PV) != len(s.Volumes) {
+		return fmt.Errorf("invalid VWAP state: %d price*volume totals but %d volumes", len(s.PV), len(s.Volumes))
+	}
+	v.day, v.pv, v.volumes, v.count = s.Day, s.PV, s.Volumes, s.Count
+	return nil
+}
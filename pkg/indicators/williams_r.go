@@ -0,0 +1,139 @@
+package indicators
+
+import (
+	"fmt"
+	"time"
+)
+
+// WilliamsR (Williams %R): where the close sits in the period's range, from 0 (highest high) to -100 (lowest low)
+// %R = -100 * (highest high - close) / (highest high - lowest low)
+// Needs real bars: fed closes only (Update), highs and lows are the closes.
+type WilliamsR struct {
+	period int
+	highs  []float64
+	lows   []float64
+	close  float64
+	count  int
+}
+
+// NewWilliamsR creates a new Williams %R indicator
+// Standard period: 14
+func NewWilliamsR(period int) (*WilliamsR, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("Williams %%R period must be positive, got %d", period)
+	}
+
+	return &WilliamsR{
+		period: period,
+		highs:  make([]float64, 0, period),
+		lows:   make([]float64, 0, period),
+	}, nil
+}
+
+// williamsRDefinition registers the Williams %R with the indicator registry
+var williamsRDefinition = Definition{
+	Name:        "williams_r",
+	Aliases:     []string{"willr"},
+	DisplayName: "Williams %R",
+	Description: "Williams %R: close within the period range, 0 to -100",
+	CandleInput: true,
+	Params: []ParamSpec{
+		{Name: "period", Type: ParamInt, Default: 14, Min: 1, Description: "Number of candles in the lookback"},
+	},
+	New: func(p Params) (Indicator, error) {
+		return NewWilliamsR(p.Int("period"))
+	},
+}
+
+// Name returns the indicator identifier
+func (w *WilliamsR) Name() string {
+	return "WilliamsR"
+}
+
+// Update adds a close as a bar with no range; prefer UpdateCandle
+func (w *WilliamsR) Update(price float64, timestamp time.Time) error {
+	return w.UpdateCandle(Candle{Timestamp: timestamp, Open: price, High: price, Low: price, Close: price})
+}
+
+// UpdateCandle adds a bar
+func (w *WilliamsR) UpdateCandle(candle Candle) error {
+	if err := checkCandle(candle); err != nil {
+		return err
+	}
+
+	w.highs = appendWindow(w.highs, candle.High, w.period)
+	w.lows = appendWindow(w.lows, candle.Low, w.period)
+	w.close = candle.Close
+	w.count++
+	return nil
+}
+
+// GetValue returns the current %R
+func (w *WilliamsR) GetValue() (map[string]float64, bool) {
+	if !w.IsReady() {
+		return nil, false
+	}
+
+	highest, lowest := w.highs[0], w.lows[0]
+	for i := 1; i < len(w.highs); i++ {
+		if w.highs[i] > highest {
+			highest = w.highs[i]
+		}
+		if w.lows[i] < lowest {
+			lowest = w.lows[i]
+		}
+	}
+
+	r := -50.0 // No range over the period: neither overbought nor oversold
+	if highest > lowest {
+		r = -100 * (highest - w.close) / (highest - lowest)
+	}
+
+	return map[string]float64{
+		ValueKeyWilliamsR: r,
+	}, true
+}
+
+// IsReady returns true once period bars have been received
+func (w *WilliamsR) IsReady() bool {
+	return len(w.highs) >= w.period
+}
+
+// Reset clears all data
+func (w *WilliamsR) Reset() {
+	w.highs = make([]float64, 0, w.period)
+	w.lows = make([]float64, 0, w.period)
+	w.close = 0
+	w.count = 0
+}
+
+// GetDataCount returns the number of bars received
+func (w *WilliamsR) GetDataCount() int {
+	return w.count
+}
+
+// williamsRState is the saved form of a Williams %R's data
+type williamsRState struct {
+	Highs []float64 `json:"highs"`
+	Lows  []float64 `json:"lows"`
+	Close float64   `json:"close"`
+	Count int       `json:"count"`
+}
+
+// MarshalState saves the high/low window and the last close
+func (w *WilliamsR) MarshalState() ([]byte, error) {
+	return encodeState(w.Name(), stateParams(w.period), williamsRState{Highs: w.highs, Lows: w.lows, Close: w.close, Count: w.count})
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (w *WilliamsR) UnmarshalState(data []byte) error {
+	var s williamsRState
+	if err := decodeState(data, w.Name(), stateParams(w.period), &s); err != nil {
+		return err
+	}
+	if len(s.Highs) != len(s.Lows) {
+		return fmt.Errorf("invalid Williams %%R state: %d highs but %d lows", len(s.Highs), len(s.Lows))
+	}
+	w.highs, w.lows, w.close, w.count = s.Highs, s.Lows, s.Close, s.Count
+	return nil
+}
@@ -1,17 +1,24 @@
 package models
 
 import (
+	"strings"
 	"time"
+	"rsi-bot/pkg/exchange"
 	"rsi-bot/pkg/safety"
 )
 
 type Config struct {
 	Symbol          string  `mapstructure:"symbol"`
+	Symbols         []string `mapstructure:"symbols"` // Trade several pairs from one process (overrides Symbol)
+	Interval        string  `mapstructure:"interval"` // Kline interval: "1m", "5m", "1h", ...
+	WarmupCandles   int     `mapstructure:"warmup_candles"` // Historical candles fed to strategies at startup (0 = off)
 	RSIPeriod       int     `mapstructure:"rsi_period"` // Deprecated: use Strategy config instead
 	OverboughtLevel float64 `mapstructure:"overbought_level"` // Deprecated: use Strategy config instead
 	OversoldLevel   float64 `mapstructure:"oversold_level"` // Deprecated: use Strategy config instead
 	Quantity        float64 `mapstructure:"quantity"`
+	Quantities      map[string]float64 `mapstructure:"quantities"` // Per-symbol overrides of Quantity
 	TradingEnabled  bool    `mapstructure:"trading_enabled"`
+	AdoptExternalOrders bool `mapstructure:"adopt_external_orders"` // Apply fills of orders placed outside the bot to positions (default: report only)
 	APIKey          string
 	APISecret       string
 
@@ -23,14 +30,218 @@ type Config struct {
 
 	// Safety & Resilience (Phase 7.5)
 	Safety safety.Config `mapstructure:"safety"`
+
+	// Paper trading simulator (used when trading_enabled is false)
+	Paper exchange.PaperConfig `mapstructure:"paper"`
+
+	// Stop-loss / take-profit / trailing stop attached to every opened position
+	Risk RiskConfig `mapstructure:"risk"`
+
+	// How the BUY quantity is computed (default: fixed quantity)
+	Sizing SizingConfig `mapstructure:"sizing"`
+
+	// How signals are executed: market orders, or limit orders with a timeout
+	Orders OrderConfig `mapstructure:"orders"`
+
+	// Which strategy signals are acted on: short selling and a minimum signal strength
+	Signals SignalConfig `mapstructure:"signals"`
+
+	// Market data WebSocket endpoints, reconnect backoff and stale stream detection
+	Stream StreamConfig `mapstructure:"stream"`
+}
+
+// RiskConfig defines the exits enforced on open positions (see strategy.RiskConfig)
+type RiskConfig struct {
+	Enabled                   bool    `mapstructure:"enabled"`
+	StopLossPercent           float64 `mapstructure:"stop_loss_percent"`           // Exit when price falls this far below entry
+	TakeProfitPercent         float64 `mapstructure:"take_profit_percent"`         // Exit when price rises this far above entry
+	RiskRewardRatio           float64 `mapstructure:"risk_reward_ratio"`           // If set, take-profit = stop distance * ratio
+	TrailingStop              bool    `mapstructure:"trailing_stop"`               // Trail the stop behind the peak price
+	TrailingActivationPercent float64 `mapstructure:"trailing_activation_percent"` // Profit % at which trailing starts
+	TrailingDistancePercent   float64 `mapstructure:"trailing_distance_percent"`   // Distance of the stop from the peak
+}
+
+// Position sizing modes
+const (
+	SizingFixedQuantity = "fixed_quantity" // Quantity / Quantities from config
+	SizingQuoteAmount   = "quote_amount"   // Spend a fixed amount of the quote asset
+	SizingPercentEquity = "percent_equity" // Spend a percentage of account value
+	SizingRiskPerTrade  = "risk_per_trade" // Lose at most a percentage of account value if the ATR stop is hit
+)
+
+// SignalConfig decides which strategy signals the bot acts on
+type SignalConfig struct {
+	AllowShort  bool    `mapstructure:"allow_short"`  // Act on short signals (needs an exchange that can short; the paper simulator can)
+	MinStrength float64 `mapstructure:"min_strength"` // Skip entries whose strength (0-1) is below this (0 = take every entry)
+}
+
+// SizingConfig selects how the quantity of each BUY is computed.
+// Quantities are rounded down to the symbol's LOT_SIZE step.
+type SizingConfig struct {
+	Mode               string  `mapstructure:"mode"`                 // One of the Sizing* modes
+	QuoteAmount        float64 `mapstructure:"quote_amount"`         // quote_amount: quote asset spent per BUY
+	EquityPercent      float64 `mapstructure:"equity_percent"`       // percent_equity: % of account value per BUY
+	RiskPercent        float64 `mapstructure:"risk_percent"`         // risk_per_trade: % of account value risked per BUY
+	MaxPositionPercent float64 `mapstructure:"max_position_percent"` // risk_per_trade: cap on position value as % of account value
+	ATRPeriod          int     `mapstructure:"atr_period"`           // risk_per_trade: ATR lookback in candles
+	ATRMultiplier      float64 `mapstructure:"atr_multiplier"`       // risk_per_trade: stop distance = ATR * multiplier
 }
 
+// SizingMode returns the configured sizing mode, defaulting to a fixed quantity
+func (c *Config) SizingMode() string {
+	if c.Sizing.Mode == "" {
+		return SizingFixedQuantity
+	}
+	return c.Sizing.Mode
+}
+
+// Order types and timeout actions of OrderConfig
+const (
+	OrderTypeMarket = "market"
+	OrderTypeLimit  = "limit"
+	OnTimeoutCancel = "cancel" // Cancel the unfilled rest and keep any partial fill
+	OnTimeoutMarket = "market" // Cancel the unfilled rest and fill it with a market order
+)
+
+const defaultOrderTimeout = 2 * time.Minute
+
+// OrderConfig selects how signals are executed. Strategies can override it per
+// signal by implementing strategy.OrderRequester.
+type OrderConfig struct {
+	Type               string  `mapstructure:"type"`                 // "market" (default) or "limit"
+	TimeInForce        string  `mapstructure:"time_in_force"`        // Limit orders: "GTC" (default), "IOC" or "FOK"
+	PostOnly           bool    `mapstructure:"post_only"`            // Limit orders: send as LIMIT_MAKER, rejected rather than taking liquidity
+	PriceOffsetPercent float64 `mapstructure:"price_offset_percent"` // Limit price distance from the signal price: buys below, sells above
+	Timeout            string  `mapstructure:"timeout"`              // Unfilled limit orders are cancelled after this long, e.g. "2m"
+	OnTimeout          string  `mapstructure:"on_timeout"`           // "cancel" (default) or "market"
+}
+
+// IsLimit reports whether signals are executed with limit orders
+func (o OrderConfig) IsLimit() bool {
+	return strings.EqualFold(o.Type, OrderTypeLimit)
+}
+
+// TimeoutDuration returns how long a limit order may rest, defaulting to 2 minutes
+func (o OrderConfig) TimeoutDuration() time.Duration {
+	timeout, err := time.ParseDuration(o.Timeout)
+	if err != nil || timeout <= 0 {
+		return defaultOrderTimeout
+	}
+	return timeout
+}
+
+// DefaultStreamEndpoints are the Binance market data WebSocket base URLs, in order of preference
+var DefaultStreamEndpoints = []string{
+	"wss://stream.binance.com:9443",
+	"wss://stream.binance.com",
+	"wss://data-stream.binance.vision",
+}
+
+const (
+	defaultReconnectBaseDelay = time.Second
+	defaultReconnectMaxDelay  = time.Minute
+)
+
+// StreamConfig controls the kline WebSocket connection. Reconnects back off with a
+// safety.RecoveryManager strategy and go to the healthiest endpoint first.
+type StreamConfig struct {
+	Endpoints          []string `mapstructure:"endpoints"`            // Base URLs without the /stream path (default: DefaultStreamEndpoints)
+	ReconnectStrategy  string   `mapstructure:"reconnect_strategy"`   // "exponential" (default), "linear" or "immediate"
+	ReconnectBaseDelay string   `mapstructure:"reconnect_base_delay"` // Delay before the first reconnect, e.g. "1s"
+	ReconnectMaxDelay  string   `mapstructure:"reconnect_max_delay"`  // Cap on the reconnect delay, e.g. "1m"
+	StaleIntervals     int      `mapstructure:"stale_intervals"`      // Reconnect when no kline arrives for this many intervals (0 = never)
+}
+
+// StreamEndpoints returns the configured endpoints, or the Binance defaults
+func (s StreamConfig) StreamEndpoints() []string {
+	var endpoints []string
+	for _, endpoint := range s.Endpoints {
+		if endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/"); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	if len(endpoints) == 0 {
+		return DefaultStreamEndpoints
+	}
+	return endpoints
+}
+
+// RecoveryConfig returns the reconnect backoff as a safety.RecoveryConfig
+func (s StreamConfig) RecoveryConfig() safety.RecoveryConfig {
+	return safety.RecoveryConfig{
+		Strategy:  s.ReconnectStrategy,
+		BaseDelay: parseDelay(s.ReconnectBaseDelay, defaultReconnectBaseDelay),
+		MaxDelay:  parseDelay(s.ReconnectMaxDelay, defaultReconnectMaxDelay),
+	}
+}
+
+// parseDelay parses a positive duration, falling back to def
+func parseDelay(value string, def time.Duration) time.Duration {
+	delay, err := time.ParseDuration(value)
+	if err != nil || delay <= 0 {
+		return def
+	}
+	return delay
+}
+
+// TradingSymbols returns the upper-case symbols to trade: Symbols if set, otherwise Symbol
+func (c *Config) TradingSymbols() []string {
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, symbol := range c.Symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol != "" && !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	if len(symbols) == 0 && c.Symbol != "" {
+		symbols = append(symbols, strings.ToUpper(c.Symbol))
+	}
+	return symbols
+}
+
+// QuantityFor returns the order quantity for a symbol
+func (c *Config) QuantityFor(symbol string) float64 {
+	// Viper lower-cases map keys, so match case-insensitively
+	for key, quantity := range c.Quantities {
+		if strings.EqualFold(key, symbol) {
+			return quantity
+		}
+	}
+	return c.Quantity
+}
+
+// KlineInterval returns the configured kline interval, defaulting to 1m
+func (c *Config) KlineInterval() string {
+	if c.Interval == "" {
+		return "1m"
+	}
+	return c.Interval
+}
+
+// ValidIntervals are the kline intervals supported by Binance streams
+var ValidIntervals = []string{"1s", "1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w", "1M"}
+
 // StrategyConfig defines which strategy to use
 type StrategyConfig struct {
-	Type            string                 `mapstructure:"type"`   // "rsi", "macd", "bbands"
-	OverboughtLevel float64                `mapstructure:"overbought_level"` // For RSI strategy
-	OversoldLevel   float64                `mapstructure:"oversold_level"`   // For RSI strategy
+	Type            string                 `mapstructure:"type"`   // "rsi", "macd", "bbands", "stoch_rsi", "composite", "rules"
+	OverboughtLevel float64                `mapstructure:"overbought_level"` // For RSI and Stochastic RSI strategies
+	OversoldLevel   float64                `mapstructure:"oversold_level"`   // For RSI and Stochastic RSI strategies
 	Indicator       IndicatorConfig        `mapstructure:"indicator"` // Indicator configuration
+
+	// Composite strategies combine the signals of child strategies
+	Combiner  string           `mapstructure:"combiner"`  // "all", "any", "majority" or "weighted"
+	Threshold float64          `mapstructure:"threshold"` // Weighted score (0-1) needed to buy or sell
+	Children  []StrategyConfig `mapstructure:"children"`  // Child strategies, each configured like a top-level strategy
+	Weight    float64          `mapstructure:"weight"`    // This strategy's vote weight as a composite child (default 1)
+
+	// Rules strategies evaluate expressions over named indicators
+	Indicators map[string]IndicatorConfig `mapstructure:"indicators"` // Indicators by name; type defaults to the name
+	Entry      string                     `mapstructure:"entry"`      // Buy when this expression holds (e.g. "rsi < 30 && close < bbands.lower")
+	Exit       string                     `mapstructure:"exit"`       // Sell when this expression holds
+	ShortEntry string                     `mapstructure:"short_entry"` // Open a short when this expression holds (needs signals.allow_short)
+	ShortExit  string                     `mapstructure:"short_exit"`  // Cover the short when this expression holds
 }
 
 // IndicatorConfig defines which indicator to use and its parameters
@@ -39,22 +250,61 @@ type IndicatorConfig struct {
 	Params map[string]interface{} `mapstructure:"params"` // Indicator-specific parameters
 }
 
+// PositionSide is the direction of a position
+type PositionSide string
+
+const (
+	PositionLong  PositionSide = "long"  // Holds the base asset, profits when the price rises
+	PositionShort PositionSide = "short" // Owes the base asset, profits when the price falls
+)
+
 type Position struct {
 	InPosition bool
-	Quantity   float64
-	EntryPrice float64
+	Side       PositionSide // Empty = long
+	Quantity   float64      // Base held (long) or owed (short)
+	EntryPrice float64      // Average entry price, net of fees
 	LastUpdate time.Time
 }
 
+// IsShort reports whether the position is a short
+func (p *Position) IsShort() bool {
+	return p.Side == PositionShort
+}
+
+// ProfitPercent returns the unrealized profit of the open position at price, in percent
+func (p *Position) ProfitPercent(price float64) float64 {
+	if !p.InPosition || p.EntryPrice <= 0 {
+		return 0
+	}
+	if p.IsShort() {
+		return (p.EntryPrice - price) / p.EntryPrice * 100
+	}
+	return (price - p.EntryPrice) / p.EntryPrice * 100
+}
+
 type KlineEvent struct {
 	EventType string `json:"e"`
 	EventTime int64  `json:"E"`
 	Symbol    string `json:"s"`
 	Kline     struct {
-		Symbol   string `json:"s"`
-		OpenTime int64  `json:"t"`
-		Close    string `json:"c"`
-		Volume   string `json:"v"`
-		IsClosed bool   `json:"x"`
+		Symbol              string `json:"s"`
+		OpenTime            int64  `json:"t"`
+		CloseTime           int64  `json:"T"`
+		Open                string `json:"o"`
+		High                string `json:"h"`
+		Low                 string `json:"l"`
+		Close               string `json:"c"`
+		Volume              string `json:"v"` // Base asset volume
+		QuoteVolume         string `json:"q"`
+		Trades              int64  `json:"n"` // Number of trades
+		TakerBuyVolume      string `json:"V"` // Base asset volume bought by takers
+		TakerBuyQuoteVolume string `json:"Q"`
+		IsClosed            bool   `json:"x"`
 	} `json:"k"`
 }
+
+// CombinedStreamEvent wraps an event received on a combined stream (/stream?streams=...)
+type CombinedStreamEvent struct {
+	Stream string     `json:"stream"`
+	Data   KlineEvent `json:"data"`
+}
@@ -1,7 +1,10 @@
 package portfolio
 
 import (
+	"context"
+
 	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/exchange"
 )
 
 // Stats represents portfolio statistics
@@ -17,11 +20,16 @@ type Stats struct {
 	TotalBuys       int     `json:"total_buys"`
 	TotalSells      int     `json:"total_sells"`
 	RealizedGains   float64 `json:"realized_gains"`   // Profit from closed positions
+
+	// Account balances (only when the calculator has an exchange, e.g. the paper simulator)
+	Balances     map[string]float64 `json:"balances,omitempty"`      // Free + locked per asset
+	AccountValue float64            `json:"account_value,omitempty"` // Quote balance + base balance at current price
 }
 
 // Calculator calculates portfolio statistics
 type Calculator struct {
-	db *database.DB
+	db       *database.DB
+	exchange exchange.Exchange
 }
 
 // NewCalculator creates a new portfolio calculator
@@ -29,6 +37,11 @@ func NewCalculator(db *database.DB) *Calculator {
 	return &Calculator{db: db}
 }
 
+// NewCalculatorWithExchange creates a calculator that also reports the account's balances
+func NewCalculatorWithExchange(db *database.DB, ex exchange.Exchange) *Calculator {
+	return &Calculator{db: db, exchange: ex}
+}
+
 // CalculateStats calculates current portfolio statistics
 func (c *Calculator) CalculateStats(symbol string, currentPrice float64) (*Stats, error) {
 	stats := &Stats{
@@ -86,9 +99,40 @@ func (c *Calculator) CalculateStats(symbol string, currentPrice float64) (*Stats
 		stats.RealizedGains = totalUSDReceived - (totalUSDSpent * (totalBTCSold / totalBTCBought))
 	}
 
+	if c.exchange != nil {
+		if err := c.addBalances(stats); err != nil {
+			return nil, err
+		}
+	}
+
 	return stats, nil
 }
 
+// addBalances fills in account balances and their value at the current price
+func (c *Calculator) addBalances(stats *Stats) error {
+	ctx := context.Background()
+
+	balances, err := c.exchange.GetBalances(ctx)
+	if err != nil {
+		return err
+	}
+
+	stats.Balances = make(map[string]float64, len(balances))
+	for _, b := range balances {
+		if b.Total() != 0 {
+			stats.Balances[b.Asset] = b.Total()
+		}
+	}
+
+	filters, err := c.exchange.GetSymbolFilters(ctx, stats.Symbol)
+	if err != nil {
+		return err
+	}
+	stats.AccountValue = stats.Balances[filters.QuoteAsset] + stats.Balances[filters.BaseAsset]*stats.CurrentPrice
+
+	return nil
+}
+
 // GetWeeklyStats calculates stats for the past week
 func (c *Calculator) GetWeeklyStats(symbol string, currentPrice float64) (*WeeklyStats, error) {
 	// Implementation for weekly summary
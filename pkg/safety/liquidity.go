@@ -3,14 +3,13 @@ package safety
 import (
 	"context"
 	"fmt"
-	"strconv"
 
-	"github.com/adshao/go-binance/v2"
+	"rsi-bot/pkg/exchange"
 )
 
 // LiquidityChecker verifies market depth before executing trades
 type LiquidityChecker struct {
-	client               *binance.Client
+	exchange             exchange.Exchange
 	minOrderBookDepth    int     // Minimum number of orders on each side
 	minTotalVolume       float64 // Minimum total volume in order book
 	maxSpreadPercent     float64 // Maximum allowed bid-ask spread %
@@ -26,9 +25,9 @@ type LiquidityConfig struct {
 }
 
 // NewLiquidityChecker creates a new liquidity checker
-func NewLiquidityChecker(client *binance.Client, config LiquidityConfig) *LiquidityChecker {
+func NewLiquidityChecker(ex exchange.Exchange, config LiquidityConfig) *LiquidityChecker {
 	return &LiquidityChecker{
-		client:               client,
+		exchange:             ex,
 		minOrderBookDepth:    config.MinOrderBookDepth,
 		minTotalVolume:       config.MinTotalVolume,
 		maxSpreadPercent:     config.MaxSpreadPercent,
@@ -39,7 +38,7 @@ func NewLiquidityChecker(client *binance.Client, config LiquidityConfig) *Liquid
 // CheckLiquidity verifies if there's sufficient liquidity for a trade
 func (lc *LiquidityChecker) CheckLiquidity(ctx context.Context, symbol string, orderSize float64, side string) error {
 	// Get order book depth
-	depth, err := lc.client.NewDepthService().Symbol(symbol).Limit(100).Do(ctx)
+	depth, err := lc.exchange.GetOrderBook(ctx, symbol, 100)
 	if err != nil {
 		return fmt.Errorf("failed to get order book: %w", err)
 	}
@@ -55,14 +54,14 @@ func (lc *LiquidityChecker) CheckLiquidity(ctx context.Context, symbol string, o
 		return fmt.Errorf("empty order book")
 	}
 
-	bestBid, err := strconv.ParseFloat(depth.Bids[0].Price, 64)
-	if err != nil {
-		return fmt.Errorf("invalid bid price: %w", err)
+	bestBid := depth.Bids[0].Price
+	if bestBid <= 0 {
+		return fmt.Errorf("invalid bid price: %.8f", bestBid)
 	}
 
-	bestAsk, err := strconv.ParseFloat(depth.Asks[0].Price, 64)
-	if err != nil {
-		return fmt.Errorf("invalid ask price: %w", err)
+	bestAsk := depth.Asks[0].Price
+	if bestAsk <= 0 {
+		return fmt.Errorf("invalid ask price: %.8f", bestAsk)
 	}
 
 	spreadPercent := ((bestAsk - bestBid) / bestBid) * 100
@@ -72,7 +71,7 @@ func (lc *LiquidityChecker) CheckLiquidity(ctx context.Context, symbol string, o
 
 	// Check total volume availability
 	var totalVolume float64
-	var orders []binance.Bid
+	var orders []exchange.PriceLevel
 
 	if side == "BUY" {
 		orders = depth.Asks
@@ -81,11 +80,7 @@ func (lc *LiquidityChecker) CheckLiquidity(ctx context.Context, symbol string, o
 	}
 
 	for _, order := range orders {
-		qty, err := strconv.ParseFloat(order.Quantity, 64)
-		if err != nil {
-			continue
-		}
-		totalVolume += qty
+		totalVolume += order.Quantity
 	}
 
 	if totalVolume < lc.minTotalVolume {
@@ -103,7 +98,7 @@ func (lc *LiquidityChecker) CheckLiquidity(ctx context.Context, symbol string, o
 
 // GetMarketDepth returns current market depth information
 func (lc *LiquidityChecker) GetMarketDepth(ctx context.Context, symbol string) (bestBid, bestAsk, spreadPercent float64, err error) {
-	depth, err := lc.client.NewDepthService().Symbol(symbol).Limit(10).Do(ctx)
+	depth, err := lc.exchange.GetOrderBook(ctx, symbol, 10)
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("failed to get order book: %w", err)
 	}
@@ -112,8 +107,8 @@ func (lc *LiquidityChecker) GetMarketDepth(ctx context.Context, symbol string) (
 		return 0, 0, 0, fmt.Errorf("empty order book")
 	}
 
-	bestBid, _ = strconv.ParseFloat(depth.Bids[0].Price, 64)
-	bestAsk, _ = strconv.ParseFloat(depth.Asks[0].Price, 64)
+	bestBid = depth.Bids[0].Price
+	bestAsk = depth.Asks[0].Price
 	spreadPercent = ((bestAsk - bestBid) / bestBid) * 100
 
 	return bestBid, bestAsk, spreadPercent, nil
@@ -6,7 +6,7 @@ import (
 	"log"
 	"time"
 
-	"github.com/adshao/go-binance/v2"
+	"rsi-bot/pkg/exchange"
 )
 
 // SafetyManager coordinates all safety mechanisms
@@ -16,6 +16,7 @@ type SafetyManager struct {
 	liquidityChecker *LiquidityChecker
 	positionLimits   *PositionLimits
 	recoveryManager  *RecoveryManager
+	symbolFilters    *exchange.FilterCache
 	enabled          bool
 }
 
@@ -41,8 +42,9 @@ type RateLimitConfig struct {
 	Interval    string `yaml:"interval"` // e.g., "1m"
 }
 
-// NewSafetyManager creates a new safety manager
-func NewSafetyManager(client *binance.Client, config Config) (*SafetyManager, error) {
+// NewSafetyManager creates a new safety manager. filters is the symbol filter cache of
+// the caller's order path, shared so exchange info is fetched once (nil creates one).
+func NewSafetyManager(ex exchange.Exchange, config Config, filters *exchange.FilterCache) (*SafetyManager, error) {
 	sm := &SafetyManager{
 		enabled: config.Enabled,
 	}
@@ -69,10 +71,16 @@ func NewSafetyManager(client *binance.Client, config Config) (*SafetyManager, er
 	)
 
 	// Initialize liquidity checker
-	sm.liquidityChecker = NewLiquidityChecker(client, config.Liquidity)
+	sm.liquidityChecker = NewLiquidityChecker(ex, config.Liquidity)
 
 	// Initialize position limits
-	sm.positionLimits = NewPositionLimits(client, config.PositionLimits)
+	sm.positionLimits = NewPositionLimits(ex, config.PositionLimits)
+
+	// Initialize symbol filter checks (LOT_SIZE, PRICE_FILTER, MIN_NOTIONAL)
+	if filters == nil {
+		filters = exchange.NewFilterCache(ex, exchange.DefaultFilterCacheTTL)
+	}
+	sm.symbolFilters = filters
 
 	// Initialize recovery manager
 	sm.recoveryManager = NewRecoveryManager(config.Recovery)
@@ -87,8 +95,9 @@ func NewSafetyManager(client *binance.Client, config Config) (*SafetyManager, er
 	return sm, nil
 }
 
-// CheckTradeAllowed verifies if a trade is allowed by all safety checks
-func (sm *SafetyManager) CheckTradeAllowed(ctx context.Context, symbol string, quantity float64, price float64, side string) error {
+// CheckTradeAllowed verifies if a trade is allowed by all safety checks.
+// price is the limit price, or the expected fill price of a market order.
+func (sm *SafetyManager) CheckTradeAllowed(ctx context.Context, symbol string, quantity float64, price float64, side string, orderType exchange.OrderType) error {
 	if !sm.enabled {
 		return nil
 	}
@@ -98,6 +107,11 @@ func (sm *SafetyManager) CheckTradeAllowed(ctx context.Context, symbol string, q
 		return fmt.Errorf("circuit breaker is open - trading paused")
 	}
 
+	// Check symbol filters - an order the exchange rejects must not count as a circuit breaker failure
+	if err := sm.CheckSymbolFilters(ctx, symbol, quantity, price, orderType); err != nil {
+		return fmt.Errorf("symbol filter check failed: %w", err)
+	}
+
 	// Check rate limit
 	if err := sm.rateLimiter.TryAllow(); err != nil {
 		return err
@@ -121,6 +135,20 @@ func (sm *SafetyManager) CheckTradeAllowed(ctx context.Context, symbol string, q
 	return nil
 }
 
+// CheckSymbolFilters verifies an order against the symbol's LOT_SIZE and MIN_NOTIONAL
+// rules, and limit orders against PRICE_FILTER too. Violations are returned as *exchange.FilterError.
+func (sm *SafetyManager) CheckSymbolFilters(ctx context.Context, symbol string, quantity float64, price float64, orderType exchange.OrderType) error {
+	if !sm.enabled {
+		return nil
+	}
+
+	filters, err := sm.symbolFilters.Get(ctx, symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get symbol filters: %w", err)
+	}
+	return filters.Validate(quantity, price, orderType == exchange.OrderTypeMarket)
+}
+
 // ExecuteWithSafety executes a function with all safety mechanisms
 func (sm *SafetyManager) ExecuteWithSafety(fn func() error) error {
 	if !sm.enabled {
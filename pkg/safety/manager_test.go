@@ -0,0 +1,49 @@
+package safety
+
+import (
+	"context"
+	"testing"
+
+	"rsi-bot/pkg/exchange"
+)
+
+func TestCheckSymbolFilters_OrderType(t *testing.T) {
+	fake := exchange.NewFakeExchange()
+	fake.SetSymbolFilters(&exchange.SymbolFilters{Symbol: "BTCUSDT", StepSize: 0.0001, TickSize: 0.01, MinNotional: 5})
+	filters := exchange.NewFilterCache(fake, exchange.DefaultFilterCacheTTL)
+
+	sm, err := NewSafetyManager(fake, Config{Enabled: true}, filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sm.symbolFilters != filters {
+		t.Error("safety manager built its own filter cache instead of sharing the caller's")
+	}
+
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		quantity   float64
+		price      float64
+		orderType  exchange.OrderType
+		wantFilter string // "" = allowed
+	}{
+		{"market order ignores the tick", 0.001, 45000.123, exchange.OrderTypeMarket, ""},
+		{"limit order off the tick", 0.001, 45000.123, exchange.OrderTypeLimit, exchange.FilterPrice},
+		{"limit maker order off the tick", 0.001, 45000.123, exchange.OrderTypeLimitMaker, exchange.FilterPrice},
+		{"limit order on the tick", 0.001, 45000.12, exchange.OrderTypeLimit, ""},
+		{"limit order below min notional", 0.0001, 45000, exchange.OrderTypeLimit, exchange.FilterMinNotional},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sm.CheckSymbolFilters(ctx, "BTCUSDT", tt.quantity, tt.price, tt.orderType)
+			if tt.wantFilter == "" && err != nil {
+				t.Errorf("CheckSymbolFilters = %v, want nil", err)
+			}
+			if tt.wantFilter != "" && !exchange.IsFilterError(err, tt.wantFilter) {
+				t.Errorf("CheckSymbolFilters = %v, want a %s failure", err, tt.wantFilter)
+			}
+		})
+	}
+}
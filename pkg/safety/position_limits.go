@@ -3,14 +3,13 @@ package safety
 import (
 	"context"
 	"fmt"
-	"strconv"
 
-	"github.com/adshao/go-binance/v2"
+	"rsi-bot/pkg/exchange"
 )
 
 // PositionLimits enforces position sizing rules
 type PositionLimits struct {
-	client                *binance.Client
+	exchange              exchange.Exchange
 	maxPositionSizeUSD    float64 // Maximum position size in USD
 	maxPortfolioPercent   float64 // Maximum % of portfolio in single position
 	maxDailyLossUSD       float64 // Maximum daily loss limit
@@ -28,9 +27,9 @@ type PositionLimitsConfig struct {
 }
 
 // NewPositionLimits creates a new position limits enforcer
-func NewPositionLimits(client *binance.Client, config PositionLimitsConfig) *PositionLimits {
+func NewPositionLimits(ex exchange.Exchange, config PositionLimitsConfig) *PositionLimits {
 	return &PositionLimits{
-		client:              client,
+		exchange:            ex,
 		maxPositionSizeUSD:  config.MaxPositionSizeUSD,
 		maxPortfolioPercent: config.MaxPortfolioPercent,
 		maxDailyLossUSD:     config.MaxDailyLossUSD,
@@ -51,17 +50,15 @@ func (pl *PositionLimits) CheckPositionSize(ctx context.Context, symbol string,
 	}
 
 	// Get account balance to check portfolio percentage
-	account, err := pl.client.NewGetAccountService().Do(ctx)
+	balances, err := pl.exchange.GetBalances(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get account info: %w", err)
 	}
 
 	// Calculate total portfolio value in USD
 	totalPortfolioUSD := 0.0
-	for _, balance := range account.Balances {
-		free, _ := strconv.ParseFloat(balance.Free, 64)
-		locked, _ := strconv.ParseFloat(balance.Locked, 64)
-		total := free + locked
+	for _, balance := range balances {
+		total := balance.Total()
 
 		if total > 0 {
 			// For USD-based assets
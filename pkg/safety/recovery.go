@@ -114,7 +114,11 @@ func (rm *RecoveryManager) calculateDelay(attempt int) time.Duration {
 		delay = rm.baseDelay * time.Duration(attempt+1)
 
 	case RecoveryExponential:
-		delay = rm.baseDelay * (1 << attempt) // 2^attempt
+		// 2^attempt, doubling only up to the cap so long outages cannot overflow
+		delay = rm.baseDelay
+		for i := 0; i < attempt && delay < rm.maxDelay; i++ {
+			delay *= 2
+		}
 	}
 
 	// Cap at maximum delay
@@ -125,6 +129,12 @@ func (rm *RecoveryManager) calculateDelay(attempt int) time.Duration {
 	return delay
 }
 
+// Delay returns the backoff before retry number attempt+1 (attempt counts from 0),
+// for callers that run their own retry loop
+func (rm *RecoveryManager) Delay(attempt int) time.Duration {
+	return rm.calculateDelay(attempt)
+}
+
 // RetryWithContext retries with context cancellation support
 func (rm *RecoveryManager) RetryWithContext(fn func() error, stopChan <-chan struct{}) error {
 	var lastErr error
@@ -33,6 +33,18 @@ func NewBollingerBandsStrategy(indicator indicators.Indicator) (*BollingerBandsS
 	}, nil
 }
 
+// bbandsDefinition registers the Bollinger Bands strategy with the strategy registry
+var bbandsDefinition = Definition{
+	Name:        "bbands",
+	Aliases:     []string{"bollinger_bands"},
+	DisplayName: "Bollinger Bands",
+	Description: "Bollinger Bands - Volatility-based trading",
+	Indicator:   "bbands",
+	New: func(in BuildInput) (Strategy, error) {
+		return NewBollingerBandsStrategy(in.Indicator)
+	},
+}
+
 // Name returns the strategy identifier
 func (s *BollingerBandsStrategy) Name() string {
 	return "BBands"
@@ -48,6 +60,11 @@ func (s *BollingerBandsStrategy) Update(price float64, volume float64, timestamp
 	return s.indicator.Update(price, timestamp)
 }
 
+// UpdateCandle processes a completed bar, passing it whole to candle-aware indicators
+func (s *BollingerBandsStrategy) UpdateCandle(candle OHLCV) error {
+	return indicators.UpdateWithCandle(s.indicator, candle)
+}
+
 // IsReady returns true when the strategy has enough data
 func (s *BollingerBandsStrategy) IsReady() bool {
 	return s.indicator.IsReady()
@@ -130,8 +147,9 @@ func (s *BollingerBandsStrategy) GetSignalReason() string {
 	return s.lastSignalReason
 }
 
-// Reset resets the strategy state
+// Reset resets the strategy state and its indicator
 func (s *BollingerBandsStrategy) Reset() {
+	s.indicator.Reset()
 	s.lastSignalReason = ""
 	s.prevPrice = 0
 	s.prevLower = 0
@@ -143,3 +161,31 @@ func (s *BollingerBandsStrategy) Reset() {
 func (s *BollingerBandsStrategy) GetCurrentBands() (upper, middle, lower float64) {
 	return s.prevUpper, 0, s.prevLower // middle not tracked, can be added if needed
 }
+
+// bbandsStrategyState is the saved form of the band touch detection state
+type bbandsStrategyState struct {
+	PrevPrice   float64 `json:"prev_price"`
+	PrevLower   float64 `json:"prev_lower"`
+	PrevUpper   float64 `json:"prev_upper"`
+	Initialized bool    `json:"initialized"`
+}
+
+// MarshalState saves the Bollinger Bands indicator and the previous price and bands
+func (s *BollingerBandsStrategy) MarshalState() ([]byte, error) {
+	return encodeState(s.Name(), s.indicator, bbandsStrategyState{
+		PrevPrice:   s.prevPrice,
+		PrevLower:   s.prevLower,
+		PrevUpper:   s.prevUpper,
+		Initialized: s.initialized,
+	})
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (s *BollingerBandsStrategy) UnmarshalState(data []byte) error {
+	var st bbandsStrategyState
+	if err := decodeState(data, s.Name(), s.indicator, &st); err != nil {
+		return err
+	}
+	s.prevPrice, s.prevLower, s.prevUpper, s.initialized = st.PrevPrice, st.PrevLower, st.PrevUpper, st.Initialized
+	return nil
+}
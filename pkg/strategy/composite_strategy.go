@@ -0,0 +1,291 @@
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"rsi-bot/pkg/indicators"
+)
+
+// Combiner decides how a composite strategy turns its children's votes into a signal
+type Combiner string
+
+const (
+	CombineAll      Combiner = "all"      // Every child gives the same signal
+	CombineAny      Combiner = "any"      // At least one child gives the signal and none the opposite
+	CombineMajority Combiner = "majority" // More than half of the children give the signal
+	CombineWeighted Combiner = "weighted" // The weighted vote score reaches the threshold
+)
+
+// Composite strategy parameters
+const (
+	ParamCombiner  = "combiner"
+	ParamThreshold = "threshold"
+)
+
+// CompositeStrategy combines the signals of child strategies. Every child is
+// updated and asked for a signal on each candle, so crossover-style children
+// keep their own state; the combiner turns their votes into one signal, whose
+// strength is the share of the vote behind it.
+type CompositeStrategy struct {
+	children         []Child
+	combiner         Combiner
+	threshold        float64
+	lastSignalReason string
+	lastDetail       SignalDetail
+}
+
+// NewCompositeStrategy creates a strategy combining children with the given combiner.
+// threshold is the weighted score (0-1) needed to buy or sell; only CombineWeighted uses it.
+func NewCompositeStrategy(children []Child, combiner Combiner, threshold float64) (*CompositeStrategy, error) {
+	if len(children) == 0 {
+		return nil, fmt.Errorf("composite strategy needs at least one child strategy")
+	}
+	for i, child := range children {
+		if child.Strategy == nil {
+			return nil, fmt.Errorf("composite child %d is nil", i+1)
+		}
+		if child.Weight <= 0 {
+			return nil, fmt.Errorf("composite child %d (%s) weight must be positive, got %.2f", i+1, child.Strategy.Name(), child.Weight)
+		}
+	}
+
+	switch combiner {
+	case CombineAll, CombineAny, CombineMajority:
+	case CombineWeighted:
+		if threshold <= 0 || threshold > 1 {
+			return nil, fmt.Errorf("weighted threshold must be above 0 and at most 1, got %.2f", threshold)
+		}
+	default:
+		return nil, fmt.Errorf("unknown combiner %q (use all, any, majority or weighted)", combiner)
+	}
+
+	return &CompositeStrategy{
+		children:  children,
+		combiner:  combiner,
+		threshold: threshold,
+	}, nil
+}
+
+// compositeDefinition registers the composite strategy with the strategy registry
+var compositeDefinition = Definition{
+	Name:        "composite",
+	DisplayName: "Composite",
+	Description: "Composite - Combines child strategies' signals by vote or weighted score",
+	Composite:   true,
+	Params: []indicators.ParamSpec{
+		{Name: ParamCombiner, Type: indicators.ParamString, Default: string(CombineAll),
+			Options:     []string{string(CombineAll), string(CombineAny), string(CombineMajority), string(CombineWeighted)},
+			Description: "How child signals are combined"},
+		{Name: ParamThreshold, Type: indicators.ParamFloat, Default: 0.5, Min: 0, Max: 1, Description: "Weighted score needed to buy or sell (weighted combiner)"},
+	},
+	New: func(in BuildInput) (Strategy, error) {
+		return NewCompositeStrategy(in.Children, Combiner(in.Params.String(ParamCombiner)), in.Params.Float(ParamThreshold))
+	},
+	Validate: func(p indicators.Params) error {
+		threshold := p.Float(ParamThreshold)
+		if Combiner(p.String(ParamCombiner)) == CombineWeighted && (threshold <= 0 || threshold > 1) {
+			return fmt.Errorf("weighted threshold must be above 0 and at most 1, got %.2f", threshold)
+		}
+		return nil
+	},
+}
+
+// Name returns the strategy identifier
+func (s *CompositeStrategy) Name() string {
+	return "Composite"
+}
+
+// GetIndicator returns nil: each child has its own indicator, which GenerateSignal reads itself
+func (s *CompositeStrategy) GetIndicator() indicators.Indicator {
+	return nil
+}
+
+// Update forwards new price data to every child
+func (s *CompositeStrategy) Update(price float64, volume float64, timestamp time.Time) error {
+	var firstErr error
+	for _, child := range s.children {
+		if err := child.Strategy.Update(price, volume, timestamp); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", child.Strategy.Name(), err)
+		}
+	}
+	return firstErr
+}
+
+// UpdateCandle forwards a completed bar to every child
+func (s *CompositeStrategy) UpdateCandle(candle OHLCV) error {
+	var firstErr error
+	for _, child := range s.children {
+		if err := UpdateWithCandle(child.Strategy, candle); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", child.Strategy.Name(), err)
+		}
+	}
+	return firstErr
+}
+
+// IsReady returns true when every child is ready
+func (s *CompositeStrategy) IsReady() bool {
+	for _, child := range s.children {
+		if !child.Strategy.IsReady() {
+			return false
+		}
+	}
+	return true
+}
+
+// voteSignals are the signals children can vote for, in the order ties are broken
+var voteSignals = []Signal{SignalBuy, SignalSell, SignalOpenShort, SignalCloseShort}
+
+// GenerateSignal asks every child for a signal and combines the votes
+func (s *CompositeStrategy) GenerateSignal(ctx SignalContext) Signal {
+	votes := make([]Signal, len(s.children))
+	counts := make(map[Signal]int)
+	weights := make(map[Signal]float64)
+	totalWeight := 0.0
+	for i, child := range s.children {
+		votes[i] = child.Strategy.GenerateSignal(childContext(child.Strategy, ctx))
+		totalWeight += child.Weight
+		if votes[i] != SignalNone {
+			counts[votes[i]]++
+			weights[votes[i]] += child.Weight
+		}
+	}
+
+	n := len(s.children)
+	signal := SignalNone
+	strength := 0.0
+	var summary string
+	switch s.combiner {
+	case CombineAll:
+		for _, candidate := range voteSignals {
+			if counts[candidate] == n {
+				signal, strength = candidate, 1
+			}
+		}
+	case CombineAny:
+		// Only one kind of signal may be voted for
+		if len(counts) == 1 {
+			for candidate, count := range counts {
+				signal, strength = candidate, float64(count)/float64(n)
+			}
+		}
+	case CombineMajority:
+		for _, candidate := range voteSignals {
+			if counts[candidate]*2 > n {
+				signal, strength = candidate, float64(counts[candidate])/float64(n)
+			}
+		}
+	case CombineWeighted:
+		// Each side is scored on its own: entries count for it, exits against it
+		longScore := (weights[SignalBuy] - weights[SignalSell]) / totalWeight
+		shortScore := (weights[SignalOpenShort] - weights[SignalCloseShort]) / totalWeight
+		switch {
+		case longScore >= s.threshold:
+			signal, strength = SignalBuy, longScore
+		case longScore <= -s.threshold:
+			signal, strength = SignalSell, -longScore
+		case shortScore >= s.threshold:
+			signal, strength = SignalOpenShort, shortScore
+		case shortScore <= -s.threshold:
+			signal, strength = SignalCloseShort, -shortScore
+		}
+		summary = fmt.Sprintf("weighted score %.2f, threshold ±%.2f", longScore, s.threshold)
+		if counts[SignalOpenShort]+counts[SignalCloseShort] > 0 {
+			summary += fmt.Sprintf(", short score %.2f", shortScore)
+		}
+	}
+	if summary == "" {
+		summary = fmt.Sprintf("%s: %d BUY, %d SELL", s.combiner, counts[SignalBuy], counts[SignalSell])
+		if counts[SignalOpenShort]+counts[SignalCloseShort] > 0 {
+			summary += fmt.Sprintf(", %d SHORT, %d COVER", counts[SignalOpenShort], counts[SignalCloseShort])
+		}
+		summary += fmt.Sprintf(" of %d", n)
+	}
+	s.lastDetail = SignalDetail{Strength: strength}
+
+	parts := make([]string, 0, n+1)
+	parts = append(parts, fmt.Sprintf("COMPOSITE %s (%s)", signal, summary))
+	for i, child := range s.children {
+		parts = append(parts, fmt.Sprintf("%s voted %s: %s", child.Strategy.Name(), votes[i], child.Strategy.GetSignalReason()))
+	}
+	s.lastSignalReason = strings.Join(parts, " | ")
+
+	return signal
+}
+
+// SignalDetail returns the strength of the last signal: the share of the vote (or weighted score) behind it
+func (s *CompositeStrategy) SignalDetail() SignalDetail {
+	return s.lastDetail
+}
+
+// childContext returns ctx with the child's own indicator values
+func childContext(child Strategy, ctx SignalContext) SignalContext {
+	ctx.IndicatorData = make(map[string]float64)
+	if indicator := child.GetIndicator(); indicator != nil {
+		if values, ok := indicator.GetValue(); ok {
+			ctx.IndicatorData = values
+		}
+	}
+	return ctx
+}
+
+// GetSignalReason returns the combined vote and each child's vote and reason
+func (s *CompositeStrategy) GetSignalReason() string {
+	return s.lastSignalReason
+}
+
+// Reset resets the strategy state and every child
+func (s *CompositeStrategy) Reset() {
+	s.lastSignalReason = ""
+	s.lastDetail = SignalDetail{}
+	for _, child := range s.children {
+		child.Strategy.Reset()
+	}
+}
+
+// Children returns the child strategies with their weights
+func (s *CompositeStrategy) Children() []Child {
+	return s.children
+}
+
+// GetCombiner returns how child signals are combined
+func (s *CompositeStrategy) GetCombiner() Combiner {
+	return s.combiner
+}
+
+// compositeState is the saved form of the children's states, in config order
+type compositeState struct {
+	Children []json.RawMessage `json:"children"`
+}
+
+// MarshalState saves every child's state; it fails if a child cannot save its state
+func (s *CompositeStrategy) MarshalState() ([]byte, error) {
+	st := compositeState{Children: make([]json.RawMessage, len(s.children))}
+	for i, child := range s.children {
+		data, err := MarshalState(child.Strategy)
+		if err != nil {
+			return nil, err
+		}
+		st.Children[i] = data
+	}
+	return encodeState(s.Name(), nil, st)
+}
+
+// UnmarshalState restores children's states saved by MarshalState
+func (s *CompositeStrategy) UnmarshalState(data []byte) error {
+	var st compositeState
+	if err := decodeState(data, s.Name(), nil, &st); err != nil {
+		return err
+	}
+	if len(st.Children) != len(s.children) {
+		return fmt.Errorf("saved state has %d composite children, strategy has %d", len(st.Children), len(s.children))
+	}
+	for i, child := range s.children {
+		if err := UnmarshalState(child.Strategy, st.Children[i]); err != nil {
+			return fmt.Errorf("composite child %d (%s): %w", i+1, child.Strategy.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package strategy
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"rsi-bot/pkg/indicators"
+	"rsi-bot/pkg/models"
+)
+
+// fixedChildren returns children voting the given signals, weighted 1 unless weights are given
+func fixedChildren(votes []Signal, weights []float64) []Child {
+	children := make([]Child, len(votes))
+	for i, vote := range votes {
+		children[i] = Child{Strategy: &fixedStrategy{name: "Child" + string(rune('A'+i)), signal: vote}, Weight: 1}
+		if weights != nil {
+			children[i].Weight = weights[i]
+		}
+	}
+	return children
+}
+
+func TestCompositeStrategy_Combiners(t *testing.T) {
+	const (
+		none  = SignalNone
+		buy   = SignalBuy
+		sell  = SignalSell
+		short = SignalOpenShort
+		cover = SignalCloseShort
+	)
+
+	tests := []struct {
+		name         string
+		combiner     Combiner
+		threshold    float64
+		votes        []Signal
+		weights      []float64 // nil = all 1
+		want         Signal
+		wantStrength float64
+	}{
+		{"all agree", CombineAll, 0, []Signal{buy, buy, buy}, nil, buy, 1},
+		{"all agree on a short", CombineAll, 0, []Signal{short, short}, nil, short, 1},
+		{"all with an abstention", CombineAll, 0, []Signal{buy, buy, none}, nil, none, 0},
+		{"all split", CombineAll, 0, []Signal{sell, buy}, nil, none, 0},
+
+		{"any single vote", CombineAny, 0, []Signal{buy, none, none}, nil, buy, 1.0 / 3},
+		{"any agreeing votes", CombineAny, 0, []Signal{sell, none, sell, none}, nil, sell, 0.5},
+		{"any conflicting sides", CombineAny, 0, []Signal{buy, none, sell}, nil, none, 0},
+		{"any long and short entries conflict", CombineAny, 0, []Signal{buy, short}, nil, none, 0},
+		{"any without votes", CombineAny, 0, []Signal{none, none}, nil, none, 0},
+
+		{"majority", CombineMajority, 0, []Signal{buy, buy, sell}, nil, buy, 2.0 / 3},
+		{"majority counts abstentions", CombineMajority, 0, []Signal{cover, cover, none, none, sell}, nil, none, 0},
+		{"majority of covers", CombineMajority, 0, []Signal{cover, cover, none}, nil, cover, 2.0 / 3},
+		{"majority needs more than half", CombineMajority, 0, []Signal{buy, buy, sell, sell}, nil, none, 0},
+
+		{"weighted buy", CombineWeighted, 0.2, []Signal{buy, sell, none}, []float64{2, 1, 1}, buy, 0.25},
+		{"weighted sell", CombineWeighted, 0.25, []Signal{buy, sell, none}, []float64{1, 2, 1}, sell, 0.25},
+		{"weighted below threshold", CombineWeighted, 0.5, []Signal{buy, none, none}, nil, none, 0},
+		{"weighted weight outvotes count", CombineWeighted, 0.1, []Signal{sell, sell, buy}, []float64{1, 1, 3}, buy, 0.2},
+		{"weighted short", CombineWeighted, 0.5, []Signal{short, cover}, []float64{3, 1}, short, 0.5},
+		{"weighted cover", CombineWeighted, 0.5, []Signal{cover, none}, []float64{2, 1}, cover, 2.0 / 3},
+		{"weighted long and short sides score apart", CombineWeighted, 0.5, []Signal{buy, short, sell}, []float64{1, 2, 1}, short, 0.5},
+		{"weighted long entry before short entry", CombineWeighted, 0.5, []Signal{buy, short}, nil, buy, 0.5},
+		{"weighted long exit before short entry", CombineWeighted, 0.5, []Signal{sell, short}, nil, sell, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			composite, err := NewCompositeStrategy(fixedChildren(tt.votes, tt.weights), tt.combiner, tt.threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := composite.GenerateSignal(SignalContext{CurrentPrice: 100, Position: &models.Position{}})
+			if got != tt.want {
+				t.Errorf("GenerateSignal = %s, want %s (%s)", got, tt.want, composite.GetSignalReason())
+			}
+			if strength := composite.SignalDetail().Strength; math.Abs(strength-tt.wantStrength) > 1e-9 {
+				t.Errorf("strength = %.4f, want %.4f", strength, tt.wantStrength)
+			}
+
+			reason := composite.GetSignalReason()
+			if !strings.HasPrefix(reason, "COMPOSITE "+tt.want.String()+" (") {
+				t.Errorf("reason = %q, want the combined signal first", reason)
+			}
+			for i, vote := range tt.votes {
+				if want := "Child" + string(rune('A'+i)) + " voted " + vote.String(); !strings.Contains(reason, want) {
+					t.Errorf("reason = %q, missing %q", reason, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompositeStrategy_VoteOrder(t *testing.T) {
+	// Ties between sides are broken in this order; the summaries list votes the same way
+	want := []Signal{SignalBuy, SignalSell, SignalOpenShort, SignalCloseShort}
+	if len(voteSignals) != len(want) {
+		t.Fatalf("voteSignals = %v, want %v", voteSignals, want)
+	}
+	for i := range want {
+		if voteSignals[i] != want[i] {
+			t.Errorf("voteSignals = %v, want %v", voteSignals, want)
+		}
+	}
+
+	composite, err := NewCompositeStrategy(fixedChildren([]Signal{SignalCloseShort, SignalBuy, SignalOpenShort, SignalSell}, nil), CombineMajority, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	composite.GenerateSignal(SignalContext{Position: &models.Position{}})
+	if reason := composite.GetSignalReason(); !strings.HasPrefix(reason, "COMPOSITE NONE (majority: 1 BUY, 1 SELL, 1 SHORT, 1 COVER of 4)") {
+		t.Errorf("reason = %q", reason)
+	}
+}
+
+func TestNewCompositeStrategy_Errors(t *testing.T) {
+	children := fixedChildren([]Signal{SignalBuy}, nil)
+	tests := []struct {
+		name      string
+		children  []Child
+		combiner  Combiner
+		threshold float64
+		want      string
+	}{
+		{"no children", nil, CombineAll, 0, "needs at least one child strategy"},
+		{"nil child", []Child{{Weight: 1}}, CombineAll, 0, "composite child 1 is nil"},
+		{"zero weight", fixedChildren([]Signal{SignalBuy}, []float64{0}), CombineAll, 0, "weight must be positive"},
+		{"unknown combiner", children, "most", 0, `unknown combiner "most"`},
+		{"weighted zero threshold", children, CombineWeighted, 0, "weighted threshold must be above 0 and at most 1"},
+		{"weighted threshold above 1", children, CombineWeighted, 1.5, "weighted threshold must be above 0 and at most 1"},
+	}
+	for _, tt := range tests {
+		if _, err := NewCompositeStrategy(tt.children, tt.combiner, tt.threshold); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: NewCompositeStrategy = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+	if _, err := NewCompositeStrategy(children, CombineWeighted, 1); err != nil {
+		t.Errorf("weighted threshold 1: %v", err)
+	}
+}
+
+func TestCompositeDefinition_ValidateThreshold(t *testing.T) {
+	for _, tt := range []struct {
+		combiner  Combiner
+		threshold float64
+		valid     bool
+	}{
+		{CombineWeighted, 0.5, true},
+		{CombineWeighted, 1, true},
+		{CombineWeighted, 0, false},
+		{CombineWeighted, 1.5, false},
+		{CombineWeighted, -0.5, false},
+		{CombineAll, 0, true}, // Only the weighted combiner reads the threshold
+	} {
+		err := compositeDefinition.Validate(indicators.Params{ParamCombiner: string(tt.combiner), ParamThreshold: tt.threshold})
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%s, %.2f) = %v, want valid %v", tt.combiner, tt.threshold, err, tt.valid)
+		}
+	}
+}
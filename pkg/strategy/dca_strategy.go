@@ -1,6 +1,7 @@
 package strategy
 
 import (
+	"fmt"
 	"time"
 
 	"rsi-bot/pkg/indicators"
@@ -20,6 +21,7 @@ type DCAStrategy struct {
 	last24hHigh    float64  // Track 24h high for dip detection
 	last24hReset   time.Time
 	lastDipBuy     time.Time // Prevent multiple dip buys per day
+	lastSignalDip  bool      // Whether the last BUY was a dip buy (sized by dipMultiplier)
 }
 
 // NewDCAStrategy creates a new DCA strategy
@@ -46,6 +48,28 @@ func NewDCAStrategyWithDip(dayOfWeek time.Weekday, hourOfDay int, dipThreshold,
 	return s
 }
 
+// dcaDefinition registers the DCA strategy with the strategy registry
+var dcaDefinition = Definition{
+	Name:        "dca",
+	DisplayName: "DCA",
+	Description: "DCA (Dollar Cost Averaging) - Scheduled weekly buys, optionally more on dips",
+	Params: []indicators.ParamSpec{
+		{Name: "day_of_week", Type: indicators.ParamInt, Default: 1, Min: 0, Max: 6, Description: "Day of the weekly buy (0 = Sunday)"},
+		{Name: "hour_of_day", Type: indicators.ParamInt, Default: 9, Min: 0, Max: 23, Description: "Hour of the weekly buy"},
+		{Name: "buy_the_dip", Type: indicators.ParamBool, Default: false, Description: "Also buy on daily dips"},
+		{Name: "dip_threshold", Type: indicators.ParamFloat, Default: 5.0, Min: 0, Description: "Drop from the 24h high (%) that counts as a dip"},
+		{Name: "dip_multiplier", Type: indicators.ParamFloat, Default: 1.5, Min: 0, Description: "Order size of dip buys relative to the weekly buy"},
+	},
+	New: func(in BuildInput) (Strategy, error) {
+		dayOfWeek := time.Weekday(in.Params.Int("day_of_week"))
+		hourOfDay := in.Params.Int("hour_of_day")
+		if in.Params.Bool("buy_the_dip") {
+			return NewDCAStrategyWithDip(dayOfWeek, hourOfDay, in.Params.Float("dip_threshold"), in.Params.Float("dip_multiplier")), nil
+		}
+		return NewDCAStrategy(dayOfWeek, hourOfDay), nil
+	},
+}
+
 // Name returns the strategy name
 func (s *DCAStrategy) Name() string {
 	return s.name
@@ -58,10 +82,18 @@ func (s *DCAStrategy) GetIndicator() indicators.Indicator {
 
 // Update tracks price for buy-the-dip logic
 func (s *DCAStrategy) Update(price float64, volume float64, timestamp time.Time) error {
+	// When replaying history the schedule was anchored to the wall clock, which is
+	// in the future relative to the data - re-anchor it to the candle time
+	if timestamp.Before(s.nextBuyTime.Add(-8 * 24 * time.Hour)) {
+		s.nextBuyTime = s.calculateNextBuyTime(timestamp)
+		s.last24hReset = timestamp
+		s.last24hHigh = 0
+	}
+
 	// Reset 24h high every 24 hours
-	if time.Since(s.last24hReset) > 24*time.Hour {
+	if timestamp.Sub(s.last24hReset) > 24*time.Hour {
 		s.last24hHigh = price
-		s.last24hReset = time.Now()
+		s.last24hReset = timestamp
 	}
 
 	// Track 24h high
@@ -79,16 +111,21 @@ func (s *DCAStrategy) IsReady() bool {
 
 // GenerateSignal returns BUY when it's time or on dips
 func (s *DCAStrategy) GenerateSignal(ctx SignalContext) Signal {
-	now := time.Now()
+	now := ctx.Timestamp
+	if now.IsZero() {
+		now = time.Now()
+	}
 
 	// Regular scheduled buy
 	if now.After(s.nextBuyTime) {
 		s.nextBuyTime = s.calculateNextBuyTime(now)
+		s.lastSignalDip = false
 		return SignalBuy
 	}
 
 	// Buy-the-dip logic
-	if s.buyTheDip && s.isDipDay(ctx.CurrentPrice) {
+	if s.buyTheDip && s.isDipDay(ctx.CurrentPrice, now) {
+		s.lastSignalDip = true
 		return SignalBuy
 	}
 
@@ -97,17 +134,28 @@ func (s *DCAStrategy) GenerateSignal(ctx SignalContext) Signal {
 
 // GetSignalReason returns the reason for the signal
 func (s *DCAStrategy) GetSignalReason() string {
+	if s.lastSignalDip {
+		return fmt.Sprintf("DCA buy-the-dip (%.1fx amount)", s.dipMultiplier)
+	}
 	return "DCA scheduled buy"
 }
 
+// SizeMultiplier scales dip buys by dipMultiplier
+func (s *DCAStrategy) SizeMultiplier() float64 {
+	if s.lastSignalDip {
+		return s.dipMultiplier
+	}
+	return 1.0
+}
+
 // isDipDay checks if current price represents a dip worth buying
-func (s *DCAStrategy) isDipDay(currentPrice float64) bool {
+func (s *DCAStrategy) isDipDay(currentPrice float64, now time.Time) bool {
 	if s.last24hHigh == 0 {
 		return false
 	}
 
 	// Prevent multiple dip buys in same day
-	if time.Since(s.lastDipBuy) < 24*time.Hour {
+	if now.Sub(s.lastDipBuy) < 24*time.Hour {
 		return false
 	}
 
@@ -115,7 +163,7 @@ func (s *DCAStrategy) isDipDay(currentPrice float64) bool {
 	percentDown := ((s.last24hHigh - currentPrice) / s.last24hHigh) * 100
 
 	if percentDown >= s.dipThreshold {
-		s.lastDipBuy = time.Now()
+		s.lastDipBuy = now
 		return true
 	}
 
@@ -156,3 +204,46 @@ func (s *DCAStrategy) calculateNextBuyTime(from time.Time) time.Time {
 
 	return next
 }
+
+// dcaState is the saved form of the DCA schedule and dip tracking
+type dcaState struct {
+	DayOfWeek     time.Weekday `json:"day_of_week"`
+	HourOfDay     int          `json:"hour_of_day"`
+	NextBuyTime   time.Time    `json:"next_buy_time"`
+	Last24hHigh   float64      `json:"last_24h_high"`
+	Last24hReset  time.Time    `json:"last_24h_reset"`
+	LastDipBuy    time.Time    `json:"last_dip_buy"`
+	LastSignalDip bool         `json:"last_signal_dip"`
+}
+
+// MarshalState saves the next scheduled buy and the dip tracking state
+func (s *DCAStrategy) MarshalState() ([]byte, error) {
+	return encodeState(s.Name(), nil, dcaState{
+		DayOfWeek:     s.dayOfWeek,
+		HourOfDay:     s.hourOfDay,
+		NextBuyTime:   s.nextBuyTime,
+		Last24hHigh:   s.last24hHigh,
+		Last24hReset:  s.last24hReset,
+		LastDipBuy:    s.lastDipBuy,
+		LastSignalDip: s.lastSignalDip,
+	})
+}
+
+// UnmarshalState restores state saved by MarshalState. If the schedule was
+// changed since, the next buy is recalculated from now (never today).
+func (s *DCAStrategy) UnmarshalState(data []byte) error {
+	var st dcaState
+	if err := decodeState(data, s.Name(), nil, &st); err != nil {
+		return err
+	}
+
+	s.nextBuyTime = st.NextBuyTime
+	if st.DayOfWeek != s.dayOfWeek || st.HourOfDay != s.hourOfDay {
+		s.nextBuyTime = s.calculateNextBuyTime(time.Now())
+	}
+	s.last24hHigh = st.Last24hHigh
+	s.last24hReset = st.Last24hReset
+	s.lastDipBuy = st.LastDipBuy
+	s.lastSignalDip = st.LastSignalDip
+	return nil
+}
@@ -2,108 +2,117 @@ package strategy
 
 import (
 	"fmt"
+	"sort"
 	"strings"
-	"time"
 
 	"rsi-bot/pkg/indicators"
+	"rsi-bot/pkg/models"
 )
 
 // StrategyConfig represents configuration for creating a strategy
 type StrategyConfig struct {
-	Type              string                 // "rsi", "macd", "bbands"
-	IndicatorConfig   indicators.IndicatorConfig
-	OverboughtLevel   float64 // For RSI strategy
-	OversoldLevel     float64 // For RSI strategy
+	Type              string                 // Registered strategy name or alias: "rsi", "macd", "bbands", "stoch_rsi", "dca", "multitimeframe", "composite", "rules"
+	IndicatorConfig   indicators.IndicatorConfig // Indicator type and parameters; strategy parameters (e.g. DCA's day_of_week) go in Params too
+	OverboughtLevel   float64 // For overbought/oversold strategies; overrides Params["overbought_level"] when set
+	OversoldLevel     float64 // For overbought/oversold strategies; overrides Params["oversold_level"] when set
+	Combiner          string           // For composite strategies; overrides Params["combiner"] when set
+	Threshold         float64          // For composite strategies; overrides Params["threshold"] when set
+	Children          []StrategyConfig // Child strategies of a composite strategy
+	Weight            float64          // Vote weight as a composite child (0 = 1)
+	Indicators        map[string]indicators.IndicatorConfig // Named indicators of a rules strategy; type defaults to the name
+	Entry             string                                // For rules strategies; overrides Params["entry"] when set
+	Exit              string                                // For rules strategies; overrides Params["exit"] when set
+	ShortEntry        string                                // For rules strategies; overrides Params["short_entry"] when set
+	ShortExit         string                                // For rules strategies; overrides Params["short_exit"] when set
 }
 
-// Factory creates trading strategies
+// ConfigFromModel converts a strategy section of the bot config, children included
+func ConfigFromModel(c models.StrategyConfig) StrategyConfig {
+	config := StrategyConfig{
+		Type: c.Type,
+		IndicatorConfig: indicators.IndicatorConfig{
+			Type:   c.Indicator.Type,
+			Params: c.Indicator.Params,
+		},
+		OverboughtLevel: c.OverboughtLevel,
+		OversoldLevel:   c.OversoldLevel,
+		Combiner:        c.Combiner,
+		Threshold:       c.Threshold,
+		Weight:          c.Weight,
+		Entry:           c.Entry,
+		Exit:            c.Exit,
+		ShortEntry:      c.ShortEntry,
+		ShortExit:       c.ShortExit,
+	}
+	for _, child := range c.Children {
+		config.Children = append(config.Children, ConfigFromModel(child))
+	}
+	if len(c.Indicators) > 0 {
+		config.Indicators = make(map[string]indicators.IndicatorConfig, len(c.Indicators))
+		for name, ic := range c.Indicators {
+			config.Indicators[name] = indicators.IndicatorConfig{Type: ic.Type, Params: ic.Params}
+		}
+	}
+	return config
+}
+
+// Factory creates trading strategies from the definitions in a Registry
 type Factory struct {
 	indicatorFactory *indicators.Factory
+	registry         *Registry
 }
 
-// NewFactory creates a new strategy factory
+// NewFactory creates a new strategy factory backed by the default registries
 func NewFactory() *Factory {
+	return NewFactoryWithRegistry(defaultRegistry, indicators.NewFactory())
+}
+
+// NewFactoryWithRegistry creates a strategy factory backed by the given registry and indicator factory
+func NewFactoryWithRegistry(registry *Registry, indicatorFactory *indicators.Factory) *Factory {
 	return &Factory{
-		indicatorFactory: indicators.NewFactory(),
+		indicatorFactory: indicatorFactory,
+		registry:         registry,
 	}
 }
 
+// Registry returns the registry the factory creates strategies from
+func (f *Factory) Registry() *Registry {
+	return f.registry
+}
+
 // Create builds a strategy based on the provided configuration
 func (f *Factory) Create(config StrategyConfig) (Strategy, error) {
-	strategyType := strings.ToLower(config.Type)
-
-	// Handle multitimeframe strategy separately (it doesn't use a single indicator)
-	if strategyType == "multitimeframe" || strategyType == "multi_timeframe" {
-		strategyConfig := DefaultMultiTimeframeStrategyConfig()
-		if config.OverboughtLevel != 0 {
-			strategyConfig.RSIOverbought = config.OverboughtLevel
-		}
-		if config.OversoldLevel != 0 {
-			strategyConfig.RSIOversold = config.OversoldLevel
-		}
-		return NewMultiTimeframeStrategy(strategyConfig)
-	}
-
-	// Handle DCA strategy (doesn't use indicators)
-	if strategyType == "dca" {
-		// Default: Weekly on Monday at 9am
-		dayOfWeek := time.Monday
-		hourOfDay := 9
-		buyTheDip := false
-		dipThreshold := 5.0
-		dipMultiplier := 1.5
-
-		// Parse params if provided
-		if params := config.IndicatorConfig.Params; params != nil {
-			if dow, ok := params["day_of_week"].(float64); ok {
-				dayOfWeek = time.Weekday(int(dow))
-			}
-			if hour, ok := params["hour_of_day"].(float64); ok {
-				hourOfDay = int(hour)
-			}
-			if dip, ok := params["buy_the_dip"].(bool); ok {
-				buyTheDip = dip
-			}
-			if threshold, ok := params["dip_threshold"].(float64); ok {
-				dipThreshold = threshold
-			}
-			if multiplier, ok := params["dip_multiplier"].(float64); ok {
-				dipMultiplier = multiplier
-			}
-		}
-
-		if buyTheDip {
-			return NewDCAStrategyWithDip(dayOfWeek, hourOfDay, dipThreshold, dipMultiplier), nil
-		}
-		return NewDCAStrategy(dayOfWeek, hourOfDay), nil
-	}
-
-	// Create the indicator for other strategies
-	indicator, err := f.indicatorFactory.Create(config.IndicatorConfig)
+	def, ok := f.registry.Lookup(config.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy type: %s", config.Type)
+	}
+
+	params, err := f.resolveParams(def, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create indicator: %w", err)
+		return nil, err
 	}
 
-	// Create the appropriate strategy
-	switch strategyType {
-	case "rsi":
-		if config.OverboughtLevel == 0 {
-			config.OverboughtLevel = 70.0 // default
+	in := BuildInput{Params: params}
+	if def.Composite {
+		in.Children, err = f.createChildren(config)
+		if err != nil {
+			return nil, err
 		}
-		if config.OversoldLevel == 0 {
-			config.OversoldLevel = 30.0 // default
+	}
+	if def.NamedIndicators {
+		in.Indicators, err = f.createNamedIndicators(config)
+		if err != nil {
+			return nil, err
 		}
-		return NewRSIStrategy(indicator, config.OverboughtLevel, config.OversoldLevel)
-
-	case "macd":
-		return NewMACDStrategy(indicator)
-
-	case "bbands", "bollinger_bands":
-		return NewBollingerBandsStrategy(indicator)
-
-	default:
-		return nil, fmt.Errorf("unknown strategy type: %s", config.Type)
 	}
+	if def.Indicator != "" {
+		in.Indicator, err = f.indicatorFactory.Create(indicatorConfig(def, config))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create indicator: %w", err)
+		}
+	}
+
+	return def.New(in)
 }
 
 // ValidateConfig checks if a strategy configuration is valid
@@ -112,134 +121,239 @@ func (f *Factory) ValidateConfig(config StrategyConfig) error {
 		return fmt.Errorf("strategy type cannot be empty")
 	}
 
-	strategyType := strings.ToLower(config.Type)
+	def, ok := f.registry.Lookup(config.Type)
+	if !ok {
+		return fmt.Errorf("unknown strategy type: %s (available: %v)", config.Type, f.GetAvailableStrategies())
+	}
 
-	// Skip indicator validation for strategies that don't use indicators
-	if strategyType != "multitimeframe" && strategyType != "multi_timeframe" && strategyType != "dca" {
-		// Validate indicator config for strategies that use indicators
-		if err := f.indicatorFactory.ValidateConfig(config.IndicatorConfig); err != nil {
+	// Validate indicator config for strategies that use indicators
+	if def.Indicator != "" {
+		if err := f.indicatorFactory.ValidateConfig(indicatorConfig(def, config)); err != nil {
 			return fmt.Errorf("invalid indicator config: %w", err)
 		}
 	}
 
-	// Validate strategy-specific parameters
-	switch strategyType {
-	case "rsi":
-		if config.OverboughtLevel != 0 && config.OversoldLevel != 0 {
-			if config.OverboughtLevel <= config.OversoldLevel {
-				return fmt.Errorf("overbought level (%.1f) must be greater than oversold level (%.1f)",
-					config.OverboughtLevel, config.OversoldLevel)
-			}
+	if err := f.validateChildren(def, config); err != nil {
+		return err
+	}
+	if err := f.validateNamedIndicators(def, config); err != nil {
+		return err
+	}
+
+	_, err := f.resolveParams(def, config)
+	return err
+}
+
+// validateChildren checks a composite strategy's children; other strategies must have none
+func (f *Factory) validateChildren(def Definition, config StrategyConfig) error {
+	if !def.Composite {
+		if len(config.Children) > 0 {
+			return fmt.Errorf("%s strategy does not take child strategies", def.DisplayName)
+		}
+		return nil
+	}
+
+	if len(config.Children) == 0 {
+		return fmt.Errorf("%s strategy needs at least one child strategy", def.DisplayName)
+	}
+	for i, child := range config.Children {
+		if child.Weight < 0 {
+			return fmt.Errorf("child %d (%s): weight cannot be negative, got %.2f", i+1, child.Type, child.Weight)
 		}
-	case "macd":
-		// No additional validation needed
-	case "bbands", "bollinger_bands":
-		// No additional validation needed
-	case "multitimeframe", "multi_timeframe":
-		// Multi-timeframe strategy has its own validation
-		if config.OverboughtLevel != 0 && config.OversoldLevel != 0 {
-			if config.OverboughtLevel <= config.OversoldLevel {
-				return fmt.Errorf("overbought level (%.1f) must be greater than oversold level (%.1f)",
-					config.OverboughtLevel, config.OversoldLevel)
-			}
+		if err := f.ValidateConfig(child); err != nil {
+			return fmt.Errorf("child %d (%s): %w", i+1, child.Type, err)
 		}
-	case "dca":
-		// DCA strategy has no specific validation requirements
-	default:
-		return fmt.Errorf("unknown strategy type: %s", config.Type)
 	}
+	return nil
+}
+
+// createChildren builds a composite strategy's children, defaulting their weights to 1
+func (f *Factory) createChildren(config StrategyConfig) ([]Child, error) {
+	children := make([]Child, 0, len(config.Children))
+	for i, childConfig := range config.Children {
+		child, err := f.Create(childConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create child %d (%s): %w", i+1, childConfig.Type, err)
+		}
 
+		weight := childConfig.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		children = append(children, Child{Strategy: child, Weight: weight})
+	}
+	return children, nil
+}
+
+// validateNamedIndicators checks a rules strategy's named indicators; other strategies must have none
+func (f *Factory) validateNamedIndicators(def Definition, config StrategyConfig) error {
+	if !def.NamedIndicators {
+		if len(config.Indicators) > 0 {
+			return fmt.Errorf("%s strategy does not take named indicators", def.DisplayName)
+		}
+		return nil
+	}
+
+	for _, name := range sortedIndicatorNames(config.Indicators) {
+		if err := f.indicatorFactory.ValidateConfig(namedIndicatorConfig(name, config.Indicators[name])); err != nil {
+			return fmt.Errorf("invalid indicator %s: %w", name, err)
+		}
+	}
 	return nil
 }
 
+// createNamedIndicators builds a rules strategy's named indicators
+func (f *Factory) createNamedIndicators(config StrategyConfig) (map[string]indicators.Indicator, error) {
+	named := make(map[string]indicators.Indicator, len(config.Indicators))
+	for _, name := range sortedIndicatorNames(config.Indicators) {
+		indicator, err := f.indicatorFactory.Create(namedIndicatorConfig(name, config.Indicators[name]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create indicator %s: %w", name, err)
+		}
+		named[name] = indicator
+	}
+	return named, nil
+}
+
+// namedIndicatorConfig returns a named indicator's settings, defaulting the type to the name
+func namedIndicatorConfig(name string, ic indicators.IndicatorConfig) indicators.IndicatorConfig {
+	if ic.Type == "" {
+		ic.Type = name
+	}
+	return ic
+}
+
+// sortedIndicatorNames returns the names of named indicators in a stable order for error messages
+func sortedIndicatorNames(named map[string]indicators.IndicatorConfig) []string {
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveParams validates the strategy parameters in config against the definition's schema
+func (f *Factory) resolveParams(def Definition, config StrategyConfig) (indicators.Params, error) {
+	raw := make(map[string]interface{}, len(config.IndicatorConfig.Params)+6)
+	for k, v := range config.IndicatorConfig.Params {
+		raw[k] = v
+	}
+	if config.OverboughtLevel != 0 {
+		raw[ParamOverboughtLevel] = config.OverboughtLevel
+	}
+	if config.OversoldLevel != 0 {
+		raw[ParamOversoldLevel] = config.OversoldLevel
+	}
+	if config.Combiner != "" {
+		raw[ParamCombiner] = config.Combiner
+	}
+	if config.Threshold != 0 {
+		raw[ParamThreshold] = config.Threshold
+	}
+	if config.Entry != "" {
+		raw[ParamEntry] = config.Entry
+	}
+	if config.Exit != "" {
+		raw[ParamExit] = config.Exit
+	}
+	if config.ShortEntry != "" {
+		raw[ParamShortEntry] = config.ShortEntry
+	}
+	if config.ShortExit != "" {
+		raw[ParamShortExit] = config.ShortExit
+	}
+
+	params, err := indicators.ResolveParams(def.DisplayName, def.Params, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if def.Validate != nil {
+		if err := def.Validate(params); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// indicatorConfig returns the config's indicator settings, defaulting the type to the strategy's indicator
+func indicatorConfig(def Definition, config StrategyConfig) indicators.IndicatorConfig {
+	ic := config.IndicatorConfig
+	if ic.Type == "" {
+		ic.Type = def.Indicator
+	}
+	return ic
+}
+
 // GetAvailableStrategies returns a list of all available strategy types
 func (f *Factory) GetAvailableStrategies() []string {
-	return []string{
-		"dca",
-		"rsi",
-		"macd",
-		"bbands",
-		"multitimeframe",
+	return f.registry.Names()
+}
+
+// GetParamSchema returns the parameters of a strategy type: its indicator's followed by its own
+func (f *Factory) GetParamSchema(strategyType string) ([]indicators.ParamSpec, error) {
+	def, ok := f.registry.Lookup(strategyType)
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy type: %s", strategyType)
+	}
+
+	var schema []indicators.ParamSpec
+	if def.Indicator != "" {
+		indicatorSchema, err := f.indicatorFactory.GetParamSchema(def.Indicator)
+		if err != nil {
+			return nil, err
+		}
+		schema = append(schema, indicatorSchema...)
 	}
+	return append(schema, def.Params...), nil
 }
 
 // GetDefaultConfig returns default configuration for a strategy type
 func (f *Factory) GetDefaultConfig(strategyType string) StrategyConfig {
-	strategyType = strings.ToLower(strategyType)
-
-	switch strategyType {
-	case "dca":
+	def, ok := f.registry.Lookup(strategyType)
+	if !ok {
 		return StrategyConfig{
-			Type: "dca",
-			IndicatorConfig: indicators.IndicatorConfig{
-				Type: "dca",
-				Params: map[string]interface{}{
-					"day_of_week": 1,   // Monday
-					"hour_of_day": 9,   // 9am
-				},
-			},
+			Type:            strings.ToLower(strategyType),
+			IndicatorConfig: indicators.IndicatorConfig{},
 		}
+	}
 
-	case "rsi":
-		return StrategyConfig{
-			Type: "rsi",
-			IndicatorConfig: indicators.IndicatorConfig{
-				Type: "rsi",
-				Params: map[string]interface{}{
-					"period": 14,
-				},
-			},
-			OverboughtLevel: 70.0,
-			OversoldLevel:   30.0,
-		}
-
-	case "macd":
-		return StrategyConfig{
-			Type: "macd",
-			IndicatorConfig: indicators.IndicatorConfig{
-				Type: "macd",
-				Params: map[string]interface{}{
-					"fast_period":   12,
-					"slow_period":   26,
-					"signal_period": 9,
-				},
-			},
-		}
-
-	case "bbands", "bollinger_bands":
-		return StrategyConfig{
-			Type: "bbands",
-			IndicatorConfig: indicators.IndicatorConfig{
-				Type: "bbands",
-				Params: map[string]interface{}{
-					"period":  20,
-					"std_dev": 2.0,
-				},
-			},
-		}
+	config := StrategyConfig{
+		Type: def.Name,
+		IndicatorConfig: indicators.IndicatorConfig{
+			Type:   def.Name, // Strategies without an indicator keep their own type here
+			Params: map[string]interface{}{},
+		},
+	}
+	if def.Indicator != "" {
+		config.IndicatorConfig = f.indicatorFactory.GetDefaultConfig(def.Indicator)
+	}
 
-	case "multitimeframe", "multi_timeframe":
-		return StrategyConfig{
-			Type: "multitimeframe",
-			IndicatorConfig: indicators.IndicatorConfig{
-				Type: "multitimeframe",
-				Params: map[string]interface{}{
-					"rsi_period":    14,
-					"macd_fast":     12,
-					"macd_slow":     26,
-					"macd_signal":   9,
-					"bbands_period": 20,
-					"bbands_stddev": 2.0,
-				},
-			},
-			OverboughtLevel: 70.0,
-			OversoldLevel:   30.0,
-		}
-
-	default:
-		return StrategyConfig{
-			Type:            strategyType,
-			IndicatorConfig: indicators.IndicatorConfig{},
+	// Zone levels, combiner settings and rules have their own fields; other strategy parameters travel with the indicator's
+	defaults := indicators.Params(indicators.DefaultParams(def.Params))
+	for name, value := range defaults {
+		switch name {
+		case ParamOverboughtLevel:
+			config.OverboughtLevel = defaults.Float(name)
+		case ParamOversoldLevel:
+			config.OversoldLevel = defaults.Float(name)
+		case ParamCombiner:
+			config.Combiner = defaults.String(name)
+		case ParamThreshold:
+			config.Threshold = defaults.Float(name)
+		case ParamEntry:
+			config.Entry = defaults.String(name)
+		case ParamExit:
+			config.Exit = defaults.String(name)
+		case ParamShortEntry:
+			config.ShortEntry = defaults.String(name)
+		case ParamShortExit:
+			config.ShortExit = defaults.String(name)
+		default:
+			config.IndicatorConfig.Params[name] = value
 		}
 	}
+
+	return config
 }
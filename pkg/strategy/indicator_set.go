@@ -0,0 +1,190 @@
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"rsi-bot/pkg/indicators"
+)
+
+// Candle value keys published by an indicatorSet next to its indicators' values
+const (
+	ValueKeyOpen   = "open"
+	ValueKeyHigh   = "high"
+	ValueKeyLow    = "low"
+	ValueKeyClose  = "close"
+	ValueKeyVolume = "volume"
+)
+
+// indicatorNamePattern is what an indicatorSet accepts as an indicator name
+var indicatorNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// indicatorSet is a group of named indicators fed the same bars. Its values are
+// each indicator's values under "<name>.<key>" (and "<name>" alone when the
+// indicator has a single value or a value keyed like its name, e.g. rsi or
+// macd), plus the last bar's open, high, low, close and volume.
+type indicatorSet struct {
+	names      []string // Sorted
+	indicators map[string]indicators.Indicator
+	last       OHLCV
+	count      int
+}
+
+// newIndicatorSet creates a set of named indicators; reserved lists names that
+// cannot be used besides the candle keys
+func newIndicatorSet(named map[string]indicators.Indicator, reserved ...string) (*indicatorSet, error) {
+	taken := map[string]bool{ValueKeyOpen: true, ValueKeyHigh: true, ValueKeyLow: true, ValueKeyClose: true, ValueKeyVolume: true}
+	for _, name := range reserved {
+		taken[name] = true
+	}
+
+	s := &indicatorSet{indicators: make(map[string]indicators.Indicator, len(named))}
+	for name, indicator := range named {
+		if !indicatorNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid indicator name %q (use lowercase letters, digits and _)", name)
+		}
+		if taken[name] {
+			return nil, fmt.Errorf("indicator name %q is reserved", name)
+		}
+		if indicator == nil {
+			return nil, fmt.Errorf("indicator %s is nil", name)
+		}
+		s.names = append(s.names, name)
+		s.indicators[name] = indicator
+	}
+	sort.Strings(s.names)
+	return s, nil
+}
+
+// Name returns the indicator identifier
+func (s *indicatorSet) Name() string {
+	return "IndicatorSet"
+}
+
+// Update adds a price to every indicator
+func (s *indicatorSet) Update(price float64, timestamp time.Time) error {
+	return s.UpdateCandle(OHLCV{Timestamp: timestamp, Open: price, High: price, Low: price, Close: price})
+}
+
+// UpdateCandle adds a bar to every indicator, whole to candle-aware ones
+func (s *indicatorSet) UpdateCandle(candle OHLCV) error {
+	var firstErr error
+	for _, name := range s.names {
+		if err := indicators.UpdateWithCandle(s.indicators[name], candle); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	s.last = candle
+	s.count++
+	return firstErr
+}
+
+// GetValue returns the values of the ready indicators and the last bar;
+// the bool is true once every indicator is ready
+func (s *indicatorSet) GetValue() (map[string]float64, bool) {
+	values := make(map[string]float64)
+	if s.count > 0 {
+		values[ValueKeyOpen] = s.last.Open
+		values[ValueKeyHigh] = s.last.High
+		values[ValueKeyLow] = s.last.Low
+		values[ValueKeyClose] = s.last.Close
+		values[ValueKeyVolume] = s.last.Volume
+	}
+
+	ready := true
+	for _, name := range s.names {
+		v, ok := s.indicators[name].GetValue()
+		if !ok {
+			ready = false
+			continue
+		}
+		for key, value := range v {
+			values[name+"."+key] = value
+		}
+		if value, ok := v[name]; ok {
+			values[name] = value
+		} else if len(v) == 1 {
+			for _, value := range v {
+				values[name] = value
+			}
+		}
+	}
+	return values, ready
+}
+
+// IsReady returns true once a bar has been received and every indicator is ready
+func (s *indicatorSet) IsReady() bool {
+	if s.count == 0 {
+		return false
+	}
+	for _, indicator := range s.indicators {
+		if !indicator.IsReady() {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset clears every indicator
+func (s *indicatorSet) Reset() {
+	for _, indicator := range s.indicators {
+		indicator.Reset()
+	}
+	s.last = OHLCV{}
+	s.count = 0
+}
+
+// GetDataCount returns the number of bars received
+func (s *indicatorSet) GetDataCount() int {
+	return s.count
+}
+
+// Names returns the indicator names, sorted
+func (s *indicatorSet) Names() []string {
+	return s.names
+}
+
+// indicatorSetState is the saved form of an indicatorSet
+type indicatorSetState struct {
+	Indicators map[string]json.RawMessage `json:"indicators"`
+	Last       OHLCV                      `json:"last"`
+	Count      int                        `json:"count"`
+}
+
+// MarshalState saves every indicator and the last bar
+func (s *indicatorSet) MarshalState() ([]byte, error) {
+	st := indicatorSetState{Indicators: make(map[string]json.RawMessage, len(s.names)), Last: s.last, Count: s.count}
+	for _, name := range s.names {
+		data, err := indicators.MarshalState(s.indicators[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		st.Indicators[name] = data
+	}
+	return json.Marshal(st)
+}
+
+// UnmarshalState restores state saved by MarshalState; the set must have the same indicator names
+func (s *indicatorSet) UnmarshalState(data []byte) error {
+	var st indicatorSetState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("invalid indicator set state: %w", err)
+	}
+	if len(st.Indicators) != len(s.names) {
+		return fmt.Errorf("saved state has %d indicators, set has %d", len(st.Indicators), len(s.names))
+	}
+	for _, name := range s.names {
+		saved, ok := st.Indicators[name]
+		if !ok {
+			return fmt.Errorf("saved state has no %s indicator", name)
+		}
+		if err := indicators.UnmarshalState(s.indicators[name], saved); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	s.last, s.count = st.Last, st.Count
+	return nil
+}
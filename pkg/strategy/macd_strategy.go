@@ -30,6 +30,17 @@ func NewMACDStrategy(indicator indicators.Indicator) (*MACDStrategy, error) {
 	}, nil
 }
 
+// macdDefinition registers the MACD strategy with the strategy registry
+var macdDefinition = Definition{
+	Name:        "macd",
+	DisplayName: "MACD",
+	Description: "MACD (Moving Average Convergence Divergence) - Trend following",
+	Indicator:   "macd",
+	New: func(in BuildInput) (Strategy, error) {
+		return NewMACDStrategy(in.Indicator)
+	},
+}
+
 // Name returns the strategy identifier
 func (s *MACDStrategy) Name() string {
 	return "MACD"
@@ -45,6 +56,11 @@ func (s *MACDStrategy) Update(price float64, volume float64, timestamp time.Time
 	return s.indicator.Update(price, timestamp)
 }
 
+// UpdateCandle processes a completed bar, passing it whole to candle-aware indicators
+func (s *MACDStrategy) UpdateCandle(candle OHLCV) error {
+	return indicators.UpdateWithCandle(s.indicator, candle)
+}
+
 // IsReady returns true when the strategy has enough data
 func (s *MACDStrategy) IsReady() bool {
 	return s.indicator.IsReady()
@@ -112,8 +128,9 @@ func (s *MACDStrategy) GetSignalReason() string {
 	return s.lastSignalReason
 }
 
-// Reset resets the strategy state
+// Reset resets the strategy state and its indicator
 func (s *MACDStrategy) Reset() {
+	s.indicator.Reset()
 	s.lastSignalReason = ""
 	s.prevMACD = 0
 	s.prevSignal = 0
@@ -129,3 +146,29 @@ func (s *MACDStrategy) GetCurrentMACD() float64 {
 func (s *MACDStrategy) GetCurrentSignal() float64 {
 	return s.prevSignal
 }
+
+// macdStrategyState is the saved form of the crossover detection state
+type macdStrategyState struct {
+	PrevMACD    float64 `json:"prev_macd"`
+	PrevSignal  float64 `json:"prev_signal"`
+	Initialized bool    `json:"initialized"`
+}
+
+// MarshalState saves the MACD indicator and the previous MACD/signal values
+func (s *MACDStrategy) MarshalState() ([]byte, error) {
+	return encodeState(s.Name(), s.indicator, macdStrategyState{
+		PrevMACD:    s.prevMACD,
+		PrevSignal:  s.prevSignal,
+		Initialized: s.initialized,
+	})
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (s *MACDStrategy) UnmarshalState(data []byte) error {
+	var st macdStrategyState
+	if err := decodeState(data, s.Name(), s.indicator, &st); err != nil {
+		return err
+	}
+	s.prevMACD, s.prevSignal, s.initialized = st.PrevMACD, st.PrevSignal, st.Initialized
+	return nil
+}
@@ -1,6 +1,7 @@
 package strategy
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -108,12 +109,25 @@ func NewMultiTimeframeManager(config MultiTimeframeConfig) (*MultiTimeframeManag
 // Update processes new price data and updates all timeframes
 // This should be called with each new price tick (e.g., from 1-minute klines)
 func (mtf *MultiTimeframeManager) Update(price float64, volume float64, timestamp time.Time) error {
+	return mtf.UpdateCandle(OHLCV{
+		Timestamp: timestamp,
+		Open:      price,
+		High:      price,
+		Low:       price,
+		Close:     price,
+		Volume:    volume,
+	})
+}
+
+// UpdateCandle aggregates a bar (e.g. a 1-minute kline) into every timeframe
+// and updates the indicators of completed candles
+func (mtf *MultiTimeframeManager) UpdateCandle(bar OHLCV) error {
 	mtf.mu.Lock()
 	defer mtf.mu.Unlock()
 
 	// Update each timeframe's data
 	for tf, tfData := range mtf.TimeframeData {
-		if err := tfData.Update(price, volume, timestamp); err != nil {
+		if err := tfData.UpdateCandle(bar); err != nil {
 			return fmt.Errorf("failed to update %s timeframe: %w", tf, err)
 		}
 
@@ -121,16 +135,16 @@ func (mtf *MultiTimeframeManager) Update(price float64, volume float64, timestam
 		if candle, ok := tfData.GetLatestCandle(); ok {
 			tfIndicators := mtf.Indicators[tf]
 
-			// Update all indicators with the close price
-			if err := tfIndicators.RSI.Update(candle.Close, candle.Timestamp); err != nil {
+			// Update all indicators with the candle (close-based indicators use the close)
+			if err := indicators.UpdateWithCandle(tfIndicators.RSI, *candle); err != nil {
 				return fmt.Errorf("failed to update RSI for %s: %w", tf, err)
 			}
 
-			if err := tfIndicators.MACD.Update(candle.Close, candle.Timestamp); err != nil {
+			if err := indicators.UpdateWithCandle(tfIndicators.MACD, *candle); err != nil {
 				return fmt.Errorf("failed to update MACD for %s: %w", tf, err)
 			}
 
-			if err := tfIndicators.BBands.Update(candle.Close, candle.Timestamp); err != nil {
+			if err := indicators.UpdateWithCandle(tfIndicators.BBands, *candle); err != nil {
 				return fmt.Errorf("failed to update BBands for %s: %w", tf, err)
 			}
 		}
@@ -139,6 +153,48 @@ func (mtf *MultiTimeframeManager) Update(price float64, volume float64, timestam
 	return nil
 }
 
+// Preload seeds a timeframe with historical candles and recomputes its indicators.
+// current is the still-open bar, if known.
+func (mtf *MultiTimeframeManager) Preload(tf Timeframe, candles []OHLCV, current *OHLCV) error {
+	mtf.mu.Lock()
+	defer mtf.mu.Unlock()
+
+	tfData, ok := mtf.TimeframeData[tf]
+	if !ok {
+		return fmt.Errorf("timeframe %s is not tracked", tf)
+	}
+	tfData.Preload(candles, current)
+
+	tfIndicators := mtf.Indicators[tf]
+	tfIndicators.RSI.Reset()
+	tfIndicators.MACD.Reset()
+	tfIndicators.BBands.Reset()
+
+	for _, candle := range tfData.GetCandles() {
+		if err := tfIndicators.RSI.Update(candle.Close, candle.Timestamp); err != nil {
+			return fmt.Errorf("failed to update RSI for %s: %w", tf, err)
+		}
+		if err := tfIndicators.MACD.Update(candle.Close, candle.Timestamp); err != nil {
+			return fmt.Errorf("failed to update MACD for %s: %w", tf, err)
+		}
+		if err := tfIndicators.BBands.Update(candle.Close, candle.Timestamp); err != nil {
+			return fmt.Errorf("failed to update BBands for %s: %w", tf, err)
+		}
+	}
+
+	return nil
+}
+
+// Timeframes returns the tracked timeframes
+func (mtf *MultiTimeframeManager) Timeframes() []Timeframe {
+	return mtf.config.Timeframes
+}
+
+// MaxCandles returns how many candles are kept per timeframe
+func (mtf *MultiTimeframeManager) MaxCandles() int {
+	return mtf.config.MaxCandles
+}
+
 // GetIndicatorValues returns all indicator values for a specific timeframe
 func (mtf *MultiTimeframeManager) GetIndicatorValues(tf Timeframe) (IndicatorSnapshot, bool) {
 	mtf.mu.RLock()
@@ -273,3 +329,79 @@ func (is IndicatorSnapshot) String() string {
 		is.BBandsWidth,
 	)
 }
+
+// timeframeState is the saved form of one timeframe's candles and indicators
+type timeframeState struct {
+	Candles      []OHLCV         `json:"candles"`
+	Current      *OHLCV          `json:"current,omitempty"`
+	BarStartTime time.Time       `json:"bar_start_time"`
+	RSI          json.RawMessage `json:"rsi"`
+	MACD         json.RawMessage `json:"macd"`
+	BBands       json.RawMessage `json:"bbands"`
+}
+
+// MarshalState saves the candles and indicators of every timeframe
+func (mtf *MultiTimeframeManager) MarshalState() ([]byte, error) {
+	mtf.mu.RLock()
+	defer mtf.mu.RUnlock()
+
+	state := make(map[Timeframe]timeframeState, len(mtf.TimeframeData))
+	for tf, tfData := range mtf.TimeframeData {
+		tfIndicators := mtf.Indicators[tf]
+		s := timeframeState{
+			Candles:      tfData.Candles,
+			Current:      tfData.currentBar,
+			BarStartTime: tfData.barStartTime,
+		}
+
+		var err error
+		if s.RSI, err = tfIndicators.RSI.MarshalState(); err != nil {
+			return nil, fmt.Errorf("failed to save RSI for %s: %w", tf, err)
+		}
+		if s.MACD, err = tfIndicators.MACD.MarshalState(); err != nil {
+			return nil, fmt.Errorf("failed to save MACD for %s: %w", tf, err)
+		}
+		if s.BBands, err = tfIndicators.BBands.MarshalState(); err != nil {
+			return nil, fmt.Errorf("failed to save BBands for %s: %w", tf, err)
+		}
+		state[tf] = s
+	}
+
+	return json.Marshal(state)
+}
+
+// UnmarshalState restores state saved by MarshalState. The state must cover every
+// tracked timeframe; if restoring an indicator fails, call Reset before reuse.
+func (mtf *MultiTimeframeManager) UnmarshalState(data []byte) error {
+	var state map[Timeframe]timeframeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid multi-timeframe state: %w", err)
+	}
+
+	mtf.mu.Lock()
+	defer mtf.mu.Unlock()
+
+	for tf := range mtf.TimeframeData {
+		if _, ok := state[tf]; !ok {
+			return fmt.Errorf("saved multi-timeframe state has no %s timeframe", tf)
+		}
+	}
+
+	for tf, tfData := range mtf.TimeframeData {
+		s := state[tf]
+		tfIndicators := mtf.Indicators[tf]
+
+		if err := tfIndicators.RSI.UnmarshalState(s.RSI); err != nil {
+			return fmt.Errorf("failed to restore RSI for %s: %w", tf, err)
+		}
+		if err := tfIndicators.MACD.UnmarshalState(s.MACD); err != nil {
+			return fmt.Errorf("failed to restore MACD for %s: %w", tf, err)
+		}
+		if err := tfIndicators.BBands.UnmarshalState(s.BBands); err != nil {
+			return fmt.Errorf("failed to restore BBands for %s: %w", tf, err)
+		}
+		tfData.restore(s.Candles, s.Current, s.BarStartTime)
+	}
+
+	return nil
+}
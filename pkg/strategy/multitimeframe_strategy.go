@@ -1,6 +1,7 @@
 package strategy
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -81,6 +82,22 @@ func NewMultiTimeframeStrategy(config MultiTimeframeStrategyConfig) (*MultiTimef
 	}, nil
 }
 
+// multiTimeframeDefinition registers the multi-timeframe strategy with the strategy registry
+var multiTimeframeDefinition = Definition{
+	Name:        "multitimeframe",
+	Aliases:     []string{"multi_timeframe"},
+	DisplayName: "Multi-Timeframe",
+	Description: "Multi-Timeframe - Advanced strategy using Daily/1h/5m timeframes with RSI, MACD, and Bollinger Bands",
+	Params:      levelParams(70.0, 30.0),
+	New: func(in BuildInput) (Strategy, error) {
+		config := DefaultMultiTimeframeStrategyConfig()
+		config.RSIOverbought = in.Params.Float(ParamOverboughtLevel)
+		config.RSIOversold = in.Params.Float(ParamOversoldLevel)
+		return NewMultiTimeframeStrategy(config)
+	},
+	Validate: validateLevels,
+}
+
 // Name returns the strategy identifier
 func (mts *MultiTimeframeStrategy) Name() string {
 	return mts.name
@@ -97,7 +114,19 @@ func (mts *MultiTimeframeStrategy) GetIndicator() indicators.Indicator {
 
 // Update processes new price data across all timeframes
 func (mts *MultiTimeframeStrategy) Update(price float64, volume float64, timestamp time.Time) error {
-	err := mts.mtfManager.Update(price, volume, timestamp)
+	return mts.UpdateCandle(OHLCV{
+		Timestamp: timestamp,
+		Open:      price,
+		High:      price,
+		Low:       price,
+		Close:     price,
+		Volume:    volume,
+	})
+}
+
+// UpdateCandle aggregates a bar into every timeframe with its real high and low
+func (mts *MultiTimeframeStrategy) UpdateCandle(candle OHLCV) error {
+	err := mts.mtfManager.UpdateCandle(candle)
 	if err != nil {
 		return err
 	}
@@ -377,3 +406,21 @@ func (mts *MultiTimeframeStrategy) GetMultiTimeframeManager() *MultiTimeframeMan
 func (mts *MultiTimeframeStrategy) IsReady() bool {
 	return mts.mtfManager.IsReady()
 }
+
+// MarshalState saves the candles and indicators of every timeframe
+func (mts *MultiTimeframeStrategy) MarshalState() ([]byte, error) {
+	data, err := mts.mtfManager.MarshalState()
+	if err != nil {
+		return nil, err
+	}
+	return encodeState(mts.Name(), nil, json.RawMessage(data))
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (mts *MultiTimeframeStrategy) UnmarshalState(data []byte) error {
+	var st json.RawMessage
+	if err := decodeState(data, mts.Name(), nil, &st); err != nil {
+		return err
+	}
+	return mts.mtfManager.UnmarshalState(st)
+}
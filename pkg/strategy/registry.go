@@ -0,0 +1,174 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"rsi-bot/pkg/indicators"
+)
+
+// Definition describes a strategy to the registry: its names, description,
+// indicator, parameter schema and constructor. The factory derives creation,
+// validation, defaults and listing from it.
+type Definition struct {
+	Name            string                 // Canonical type name (e.g. "rsi")
+	Aliases         []string               // Alternative type names (e.g. "bollinger_bands")
+	DisplayName     string                 // Used in validation errors (e.g. "Bollinger Bands")
+	Description     string                 // One-line summary shown in UIs
+	Indicator       string                 // Default indicator type; "" for strategies that need none or build their own
+	Params          []indicators.ParamSpec // Strategy parameters (indicator parameters come from the indicator's schema)
+	Composite       bool                   // Built from child strategies (StrategyConfig.Children)
+	NamedIndicators bool                   // Built from named indicators (StrategyConfig.Indicators)
+
+	// New builds the strategy from its indicator and validated parameters
+	New func(in BuildInput) (Strategy, error)
+
+	// Validate checks constraints between parameters (optional)
+	Validate func(p indicators.Params) error
+}
+
+// BuildInput is what a strategy constructor receives
+type BuildInput struct {
+	Indicator  indicators.Indicator            // Built from the config; nil when Definition.Indicator is ""
+	Params     indicators.Params               // Strategy parameters with defaults applied
+	Children   []Child                         // Built child strategies; only for composite definitions
+	Indicators map[string]indicators.Indicator // Built named indicators; only for NamedIndicators definitions
+}
+
+// Child is a child strategy of a composite strategy with its vote weight
+type Child struct {
+	Strategy Strategy
+	Weight   float64
+}
+
+// Registry holds strategy definitions by name and alias
+type Registry struct {
+	mu    sync.RWMutex
+	defs  map[string]*Definition // Keyed by lowercase name and aliases
+	names []string               // Canonical names, sorted
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		defs: make(map[string]*Definition),
+	}
+}
+
+// Register adds a strategy definition. Names and aliases must be unique.
+func (r *Registry) Register(def Definition) error {
+	if def.Name == "" {
+		return fmt.Errorf("strategy name cannot be empty")
+	}
+	if def.New == nil {
+		return fmt.Errorf("strategy %s has no constructor", def.Name)
+	}
+	def.Name = strings.ToLower(def.Name)
+	if def.DisplayName == "" {
+		def.DisplayName = def.Name
+	}
+	if err := indicators.CheckParamSpecs(def.Params); err != nil {
+		return fmt.Errorf("strategy %s: %w", def.Name, err)
+	}
+
+	keys := append([]string{def.Name}, def.Aliases...)
+	for i, key := range keys {
+		keys[i] = strings.ToLower(key)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		if _, exists := r.defs[key]; exists {
+			return fmt.Errorf("strategy %s is already registered", key)
+		}
+	}
+
+	d := def
+	for _, key := range keys {
+		r.defs[key] = &d
+	}
+	r.names = append(r.names, keys[0])
+	sort.Strings(r.names)
+	return nil
+}
+
+// Lookup finds a definition by name or alias (case-insensitive)
+func (r *Registry) Lookup(name string) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	def, ok := r.defs[strings.ToLower(name)]
+	if !ok {
+		return Definition{}, false
+	}
+	return *def, true
+}
+
+// Names returns the canonical names of all registered strategies, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.names...)
+}
+
+// defaultRegistry holds the built-in strategies and anything added with Register
+var defaultRegistry = newBuiltinRegistry()
+
+// newBuiltinRegistry creates a registry holding the strategies of this package
+func newBuiltinRegistry() *Registry {
+	r := NewRegistry()
+	for _, def := range []Definition{
+		dcaDefinition,
+		rsiDefinition,
+		macdDefinition,
+		bbandsDefinition,
+		stochRSIDefinition,
+		multiTimeframeDefinition,
+		compositeDefinition,
+		rulesDefinition,
+	} {
+		if err := r.Register(def); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// DefaultRegistry returns the registry used by NewFactory
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register adds a strategy to the default registry, making it available to
+// every factory created with NewFactory
+func Register(def Definition) error {
+	return defaultRegistry.Register(def)
+}
+
+// Zone level parameters shared by the overbought/oversold strategies
+const (
+	ParamOverboughtLevel = "overbought_level"
+	ParamOversoldLevel   = "oversold_level"
+)
+
+// levelParams returns the overbought/oversold parameter specs with the given defaults
+func levelParams(overbought, oversold float64) []indicators.ParamSpec {
+	return []indicators.ParamSpec{
+		{Name: ParamOverboughtLevel, Type: indicators.ParamFloat, Default: overbought, Min: 0, Max: 100, Description: "Sell zone threshold"},
+		{Name: ParamOversoldLevel, Type: indicators.ParamFloat, Default: oversold, Min: 0, Max: 100, Description: "Buy zone threshold"},
+	}
+}
+
+// validateLevels checks the overbought level is above the oversold level
+func validateLevels(p indicators.Params) error {
+	if p.Float(ParamOverboughtLevel) <= p.Float(ParamOversoldLevel) {
+		return fmt.Errorf("overbought level (%.1f) must be greater than oversold level (%.1f)",
+			p.Float(ParamOverboughtLevel), p.Float(ParamOversoldLevel))
+	}
+	return nil
+}
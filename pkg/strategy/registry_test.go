@@ -0,0 +1,271 @@
+package strategy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/indicators"
+)
+
+// fixedStrategy always gives the same signal; it stands in for real strategies
+// where only the signal matters
+type fixedStrategy struct {
+	name      string
+	signal    Signal
+	indicator indicators.Indicator
+}
+
+func (s *fixedStrategy) Name() string                                    { return s.name }
+func (s *fixedStrategy) GetIndicator() indicators.Indicator              { return s.indicator }
+func (s *fixedStrategy) Update(price, volume float64, _ time.Time) error { return nil }
+func (s *fixedStrategy) IsReady() bool                                   { return true }
+func (s *fixedStrategy) GenerateSignal(ctx SignalContext) Signal         { return s.signal }
+func (s *fixedStrategy) GetSignalReason() string                         { return "always " + s.signal.String() }
+func (s *fixedStrategy) Reset()                                          {}
+
+// readyAfter resets indicator and returns the number of prices it needs to become ready
+func readyAfter(indicator indicators.Indicator) int {
+	indicator.Reset()
+	defer indicator.Reset()
+	start := time.Now()
+	for i := 1; i <= 1000; i++ {
+		indicator.Update(100+float64(i%7), start.Add(time.Duration(i)*time.Minute))
+		if indicator.IsReady() {
+			return i
+		}
+	}
+	return -1
+}
+
+// fixedDefinition is a strategy with an indicator and its own parameters, for private registries
+var fixedDefinition = Definition{
+	Name:        "Fixed",
+	Aliases:     []string{"constant"},
+	DisplayName: "Fixed Signal",
+	Indicator:   "sma",
+	Params: []indicators.ParamSpec{
+		{Name: "signal", Type: indicators.ParamString, Default: "none", Options: []string{"none", "buy", "sell"}},
+		{Name: "delay", Type: indicators.ParamInt, Default: 0, Min: 0, Max: 10},
+	},
+	New: func(in BuildInput) (Strategy, error) {
+		signals := map[string]Signal{"none": SignalNone, "buy": SignalBuy, "sell": SignalSell}
+		return &fixedStrategy{name: "Fixed", signal: signals[in.Params.String("signal")], indicator: in.Indicator}, nil
+	},
+}
+
+// TestRegistryCustomStrategy registers a strategy in a private registry and
+// checks the factory derives creation, validation, defaults and listing from it
+func TestRegistryCustomStrategy(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register(fixedDefinition); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	for _, def := range []Definition{
+		{Name: "constant", New: fixedDefinition.New},
+		{Name: "", New: fixedDefinition.New},
+		{Name: "other"},
+	} {
+		if err := registry.Register(def); err == nil {
+			t.Errorf("Register(%q) succeeded", def.Name)
+		}
+	}
+
+	for _, name := range []string{"fixed", "FIXED", "Constant"} {
+		def, ok := registry.Lookup(name)
+		if !ok || def.Name != "fixed" {
+			t.Errorf("Lookup(%q) = %q, %v; want fixed", name, def.Name, ok)
+		}
+	}
+	if _, ok := registry.Lookup("rsi"); ok {
+		t.Error("built-in strategy found in a private registry")
+	}
+
+	factory := NewFactoryWithRegistry(registry, indicators.NewFactory())
+	if got := factory.GetAvailableStrategies(); len(got) != 1 || got[0] != "fixed" {
+		t.Errorf("GetAvailableStrategies = %v, want [fixed]", got)
+	}
+
+	// Indicator parameters come first, then the strategy's own, both with defaults
+	schema, err := factory.GetParamSchema("constant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schema) != 3 || schema[0].Name != "period" || schema[1].Name != "signal" || schema[2].Name != "delay" {
+		t.Errorf("GetParamSchema = %+v, want period, signal, delay", schema)
+	}
+	config := factory.GetDefaultConfig("Fixed")
+	params := config.IndicatorConfig.Params
+	if config.Type != "fixed" || config.IndicatorConfig.Type != "sma" ||
+		params["period"] != 20 || params["signal"] != "none" || params["delay"] != 0 {
+		t.Errorf("GetDefaultConfig = %+v, want fixed over sma with period 20, signal none, delay 0", config)
+	}
+
+	built, err := factory.Create(StrategyConfig{
+		Type:            "constant",
+		IndicatorConfig: indicators.IndicatorConfig{Params: map[string]interface{}{"period": 5.0, "signal": "buy"}},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	fixed := built.(*fixedStrategy)
+	if fixed.signal != SignalBuy {
+		t.Errorf("signal = %s, want BUY", fixed.signal)
+	}
+	if _, ok := fixed.indicator.(*indicators.SMA); !ok || readyAfter(fixed.indicator) != 5 {
+		t.Errorf("indicator = %T ready after %d prices, want a 5-period SMA", fixed.indicator, readyAfter(fixed.indicator))
+	}
+
+	for _, tt := range []struct {
+		params map[string]interface{}
+		want   string
+	}{
+		{map[string]interface{}{"delay": -1}, "Fixed Signal delay must be at least 0, got -1"},
+		{map[string]interface{}{"delay": 11}, "Fixed Signal delay too large: 11 (max 10)"},
+		{map[string]interface{}{"delay": true}, "Fixed Signal delay must be a number, got bool"},
+		{map[string]interface{}{"signal": "short"}, "Fixed Signal signal must be one of"},
+		{map[string]interface{}{"period": 0}, "invalid indicator config"},
+	} {
+		err := factory.ValidateConfig(StrategyConfig{Type: "fixed", IndicatorConfig: indicators.IndicatorConfig{Params: tt.params}})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ValidateConfig(%v) = %v, want %q", tt.params, err, tt.want)
+		}
+	}
+
+	if _, err := NewFactory().Create(StrategyConfig{Type: "fixed"}); err == nil {
+		t.Error("private registry leaked into the default factory")
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	want := []string{"bbands", "composite", "dca", "macd", "multitimeframe", "rsi", "rules", "stoch_rsi"}
+	if got := DefaultRegistry().Names(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Names = %v, want %v", got, want)
+	}
+	for alias, name := range map[string]string{"bollinger_bands": "bbands", "multi_timeframe": "multitimeframe", "RSI": "rsi"} {
+		if def, ok := DefaultRegistry().Lookup(alias); !ok || def.Name != name {
+			t.Errorf("Lookup(%q) = %q, %v; want %s", alias, def.Name, ok, name)
+		}
+	}
+
+	// Zone levels default from the schema and are checked against each other
+	factory := NewFactory()
+	config := factory.GetDefaultConfig("rsi")
+	if config.OverboughtLevel != 70 || config.OversoldLevel != 30 || config.IndicatorConfig.Params["period"] != 14 {
+		t.Errorf("GetDefaultConfig(rsi) = %+v, want levels 70/30 and period 14", config)
+	}
+	if err := factory.ValidateConfig(StrategyConfig{Type: "rsi", OverboughtLevel: 30, OversoldLevel: 40}); err == nil ||
+		!strings.Contains(err.Error(), "must be greater than oversold level") {
+		t.Errorf("ValidateConfig(inverted levels) = %v, want a level error", err)
+	}
+	if err := factory.ValidateConfig(StrategyConfig{Type: "rsi", OverboughtLevel: 101}); err == nil {
+		t.Error("ValidateConfig(overbought 101) = nil, want error")
+	}
+	if err := factory.ValidateConfig(StrategyConfig{Type: "unknown"}); err == nil || !strings.Contains(err.Error(), "available") {
+		t.Errorf("ValidateConfig(unknown) = %v, want the available types", err)
+	}
+}
+
+func TestFactoryCompositeBuild(t *testing.T) {
+	factory := NewFactory()
+	config := StrategyConfig{
+		Type:     "composite",
+		Combiner: "weighted",
+		Children: []StrategyConfig{
+			{Type: "rsi"},
+			{Type: "macd", Weight: 2},
+		},
+	}
+	if err := factory.ValidateConfig(config); err != nil {
+		t.Fatalf("ValidateConfig: %v", err)
+	}
+	built, err := factory.Create(config)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	composite := built.(*CompositeStrategy)
+	children := composite.Children()
+	if composite.GetCombiner() != CombineWeighted || composite.threshold != 0.5 || len(children) != 2 {
+		t.Fatalf("composite = %s threshold %.2f with %d children, want weighted 0.5 with 2", composite.GetCombiner(), composite.threshold, len(children))
+	}
+	if children[0].Strategy.Name() != "RSI" || children[0].Weight != 1 || children[1].Strategy.Name() != "MACD" || children[1].Weight != 2 {
+		t.Errorf("children = %s×%.0f, %s×%.0f; want RSI×1 (defaulted), MACD×2",
+			children[0].Strategy.Name(), children[0].Weight, children[1].Strategy.Name(), children[1].Weight)
+	}
+
+	for name, tt := range map[string]struct {
+		config StrategyConfig
+		want   string
+	}{
+		"no children":               {StrategyConfig{Type: "composite"}, "needs at least one child strategy"},
+		"negative weight":           {StrategyConfig{Type: "composite", Children: []StrategyConfig{{Type: "rsi", Weight: -1}}}, "child 1 (rsi): weight cannot be negative"},
+		"invalid child":             {StrategyConfig{Type: "composite", Children: []StrategyConfig{{Type: "rsi"}, {Type: "nope"}}}, "child 2 (nope): unknown strategy type"},
+		"unknown combiner":          {StrategyConfig{Type: "composite", Combiner: "most", Children: []StrategyConfig{{Type: "rsi"}}}, "combiner must be one of"},
+		"children of non-composite": {StrategyConfig{Type: "rsi", Children: []StrategyConfig{{Type: "macd"}}}, "RSI strategy does not take child strategies"},
+	} {
+		if err := factory.ValidateConfig(tt.config); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ValidateConfig = %v, want %q", name, err, tt.want)
+		}
+	}
+}
+
+func TestFactoryNamedIndicatorsBuild(t *testing.T) {
+	factory := NewFactory()
+	config := StrategyConfig{
+		Type: "rules",
+		Indicators: map[string]indicators.IndicatorConfig{
+			"rsi":  {},
+			"fast": {Type: "sma", Params: map[string]interface{}{"period": 5}},
+		},
+		Entry: "rsi < 30 && close > fast",
+		Exit:  "rsi > 70",
+	}
+	if err := factory.ValidateConfig(config); err != nil {
+		t.Fatalf("ValidateConfig: %v", err)
+	}
+	built, err := factory.Create(config)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	rules := built.(*RulesStrategy)
+	if names := rules.set.Names(); len(names) != 2 || names[0] != "fast" || names[1] != "rsi" {
+		t.Errorf("indicator names = %v, want [fast rsi]", names)
+	}
+	if fast, ok := rules.set.indicators["fast"].(*indicators.SMA); !ok || readyAfter(fast) != 5 {
+		t.Errorf("fast = %T, want a 5-period SMA", rules.set.indicators["fast"])
+	}
+	if rsi, ok := rules.set.indicators["rsi"].(*indicators.RSI); !ok || rsi.GetPeriod() != 14 {
+		t.Errorf("rsi = %T, want RSI(14) (type defaulted from the name)", rules.set.indicators["rsi"])
+	}
+	if rules.entry == nil || rules.exit == nil || rules.shortEntry != nil || rules.shortExit != nil {
+		t.Errorf("rules = entry %v, exit %v, short entry %v, short exit %v; want only entry and exit",
+			rules.entry != nil, rules.exit != nil, rules.shortEntry != nil, rules.shortExit != nil)
+	}
+
+	for name, tt := range map[string]struct {
+		config StrategyConfig
+		want   string
+	}{
+		"no entry": {StrategyConfig{Type: "rules", Exit: "close > 1"}, "needs an entry or short_entry rule"},
+		"bad rule": {StrategyConfig{Type: "rules", Entry: "close >"}, "invalid entry"},
+		"bad indicator": {StrategyConfig{Type: "rules", Entry: "close > 1",
+			Indicators: map[string]indicators.IndicatorConfig{"slow": {Type: "sma", Params: map[string]interface{}{"period": 0}}}}, "invalid indicator slow"},
+		"unknown indicator type": {StrategyConfig{Type: "rules", Entry: "close > 1",
+			Indicators: map[string]indicators.IndicatorConfig{"trend": {}}}, "invalid indicator trend"},
+		"indicators of another strategy": {StrategyConfig{Type: "macd",
+			Indicators: map[string]indicators.IndicatorConfig{"rsi": {}}}, "MACD strategy does not take named indicators"},
+	} {
+		if err := factory.ValidateConfig(tt.config); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ValidateConfig = %v, want %q", name, err, tt.want)
+		}
+	}
+
+	// Names the rules read are only known once the indicators are built
+	config.Entry = "slow < 30"
+	if _, err := factory.Create(config); err == nil || !strings.Contains(err.Error(), `"slow" is not a candle value`) {
+		t.Errorf("Create(unknown name) = %v, want an unknown name error", err)
+	}
+}
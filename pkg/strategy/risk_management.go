@@ -93,27 +93,7 @@ func (rm *RiskManager) CalculatePositionSize(
 	result := PositionSizeResult{
 		EntryPrice: entryPrice,
 	}
-
-	// Calculate stop-loss price
-	if rm.config.UseATRStopLoss && volatility > 0 {
-		// Dynamic ATR-based stop-loss
-		stopDistance := volatility * rm.config.ATRMultiplier
-		result.StopLossPrice = entryPrice - stopDistance
-	} else {
-		// Fixed percentage stop-loss
-		result.StopLossPrice = entryPrice * (1 - rm.config.StopLossPercent/100.0)
-	}
-
-	// Calculate take-profit price
-	if rm.config.UseRiskRewardRatio {
-		// Based on risk/reward ratio
-		riskPerUnit := entryPrice - result.StopLossPrice
-		rewardPerUnit := riskPerUnit * rm.config.RiskRewardRatio
-		result.TakeProfitPrice = entryPrice + rewardPerUnit
-	} else {
-		// Fixed percentage take-profit
-		result.TakeProfitPrice = entryPrice * (1 + rm.config.TakeProfitPercent/100.0)
-	}
+	result.StopLossPrice, result.TakeProfitPrice = rm.ExitLevels(entryPrice, volatility)
 
 	// Calculate maximum position value based on portfolio percentage
 	maxPositionValue := portfolioValue * (rm.config.MaxPositionSizePercent / 100.0)
@@ -146,6 +126,37 @@ func (rm *RiskManager) CalculatePositionSize(
 	return result, nil
 }
 
+// ExitLevels returns the stop-loss and take-profit prices for an entry
+func (rm *RiskManager) ExitLevels(entryPrice float64, volatility float64) (stopLoss float64, takeProfit float64) {
+	// Calculate stop-loss price
+	if rm.config.UseATRStopLoss && volatility > 0 {
+		// Dynamic ATR-based stop-loss
+		stopDistance := volatility * rm.config.ATRMultiplier
+		stopLoss = entryPrice - stopDistance
+	} else {
+		// Fixed percentage stop-loss
+		stopLoss = entryPrice * (1 - rm.config.StopLossPercent/100.0)
+	}
+
+	// Calculate take-profit price
+	if rm.config.UseRiskRewardRatio {
+		// Based on risk/reward ratio
+		riskPerUnit := entryPrice - stopLoss
+		rewardPerUnit := riskPerUnit * rm.config.RiskRewardRatio
+		takeProfit = entryPrice + rewardPerUnit
+	} else {
+		// Fixed percentage take-profit
+		takeProfit = entryPrice * (1 + rm.config.TakeProfitPercent/100.0)
+	}
+
+	return stopLoss, takeProfit
+}
+
+// Config returns the risk parameters
+func (rm *RiskManager) Config() RiskConfig {
+	return rm.config
+}
+
 // TrailingStopTracker tracks the trailing stop for an open position
 type TrailingStopTracker struct {
 	EntryPrice       float64
@@ -33,6 +33,19 @@ func NewRSIStrategy(indicator indicators.Indicator, overboughtLevel, oversoldLev
 	}, nil
 }
 
+// rsiDefinition registers the RSI strategy with the strategy registry
+var rsiDefinition = Definition{
+	Name:        "rsi",
+	DisplayName: "RSI",
+	Description: "RSI (Relative Strength Index) - Mean reversion strategy",
+	Indicator:   "rsi",
+	Params:      levelParams(70.0, 30.0),
+	New: func(in BuildInput) (Strategy, error) {
+		return NewRSIStrategy(in.Indicator, in.Params.Float(ParamOverboughtLevel), in.Params.Float(ParamOversoldLevel))
+	},
+	Validate: validateLevels,
+}
+
 // Name returns the strategy identifier
 func (s *RSIStrategy) Name() string {
 	return "RSI"
@@ -48,6 +61,11 @@ func (s *RSIStrategy) Update(price float64, volume float64, timestamp time.Time)
 	return s.indicator.Update(price, timestamp)
 }
 
+// UpdateCandle processes a completed bar, passing it whole to candle-aware indicators
+func (s *RSIStrategy) UpdateCandle(candle OHLCV) error {
+	return indicators.UpdateWithCandle(s.indicator, candle)
+}
+
 // IsReady returns true when the strategy has enough data
 func (s *RSIStrategy) IsReady() bool {
 	return s.indicator.IsReady()
@@ -93,8 +111,9 @@ func (s *RSIStrategy) GetSignalReason() string {
 	return s.lastSignalReason
 }
 
-// Reset resets the strategy state
+// Reset resets the strategy state and its indicator
 func (s *RSIStrategy) Reset() {
+	s.indicator.Reset()
 	s.lastSignalReason = ""
 }
 
@@ -127,3 +146,13 @@ func (s *RSIStrategy) SetOversoldLevel(level float64) error {
 	s.oversoldLevel = level
 	return nil
 }
+
+// MarshalState saves the RSI indicator's state
+func (s *RSIStrategy) MarshalState() ([]byte, error) {
+	return encodeState(s.Name(), s.indicator, nil)
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (s *RSIStrategy) UnmarshalState(data []byte) error {
+	return decodeState(data, s.Name(), s.indicator, nil)
+}
@@ -0,0 +1,556 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rule expressions compare indicator values, candle prices and position state:
+//
+//	rsi < 30 && macd.histogram > 0 && close < bbands.lower
+//	crossover(macd.macd, macd.signal) || rsi[1] < 30 && rsi >= 30
+//
+// Operators, loosest first: || (or), && (and), ! (not), comparisons
+// (< <= > >= == !=), + -, * /, unary minus. name[n] is a value n bars ago.
+// Functions: crossover(a, b), crossunder(a, b), prev(x) or prev(x, n), abs(x),
+// min(a, b), max(a, b). A missing value (before an indicator is ready, or more
+// bars back than have been seen) makes any comparison using it false.
+
+// rule is a compiled rule expression
+type rule struct {
+	source   string
+	root     ruleNode
+	idents   []string // Values the expression reads, sorted
+	lookback int      // Bars of history needed besides the current one
+}
+
+// ruleEnv supplies values to a rule: the current bar's and those of earlier bars
+type ruleEnv struct {
+	current map[string]float64   // Current bar
+	history []map[string]float64 // Earlier bars, oldest first
+}
+
+// value returns a value offset bars back, or NaN if it is unknown
+func (e *ruleEnv) value(name string, offset int) float64 {
+	values := e.current
+	if offset > 0 {
+		i := len(e.history) - offset
+		if i < 0 {
+			return math.NaN()
+		}
+		values = e.history[i]
+	}
+	if v, ok := values[name]; ok {
+		return v
+	}
+	return math.NaN()
+}
+
+// ruleNode is a node of a parsed rule expression
+type ruleNode interface {
+	// eval returns the node's value offset bars back (booleans are 1 or 0; NaN = unknown)
+	eval(env *ruleEnv, offset int) float64
+
+	// lookback returns the bars of history the node needs besides the current one
+	lookback() int
+}
+
+// evalBool evaluates a rule; unknown values count as false
+func (r *rule) evalBool(env *ruleEnv) bool {
+	return truthy(r.root.eval(env, 0))
+}
+
+func truthy(v float64) bool {
+	return v != 0 && !math.IsNaN(v)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type numberNode float64
+
+func (n numberNode) eval(env *ruleEnv, offset int) float64 { return float64(n) }
+func (n numberNode) lookback() int                         { return 0 }
+
+type identNode struct {
+	name   string
+	offset int // name[offset]
+}
+
+func (n identNode) eval(env *ruleEnv, offset int) float64 { return env.value(n.name, offset+n.offset) }
+func (n identNode) lookback() int                         { return n.offset }
+
+type unaryNode struct {
+	op string
+	x  ruleNode
+}
+
+func (n unaryNode) eval(env *ruleEnv, offset int) float64 {
+	x := n.x.eval(env, offset)
+	if n.op == "-" {
+		return -x
+	}
+	if math.IsNaN(x) {
+		return x
+	}
+	return boolValue(!truthy(x))
+}
+
+func (n unaryNode) lookback() int { return n.x.lookback() }
+
+type binaryNode struct {
+	op   string
+	l, r ruleNode
+}
+
+func (n binaryNode) eval(env *ruleEnv, offset int) float64 {
+	l := n.l.eval(env, offset)
+	switch n.op {
+	case "&&":
+		return boolValue(truthy(l) && truthy(n.r.eval(env, offset)))
+	case "||":
+		return boolValue(truthy(l) || truthy(n.r.eval(env, offset)))
+	}
+
+	r := n.r.eval(env, offset)
+	switch n.op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		return l / r
+	}
+
+	if math.IsNaN(l) || math.IsNaN(r) {
+		return math.NaN()
+	}
+	switch n.op {
+	case "<":
+		return boolValue(l < r)
+	case "<=":
+		return boolValue(l <= r)
+	case ">":
+		return boolValue(l > r)
+	case ">=":
+		return boolValue(l >= r)
+	case "==":
+		return boolValue(l == r)
+	default: // "!="
+		return boolValue(l != r)
+	}
+}
+
+func (n binaryNode) lookback() int { return max(n.l.lookback(), n.r.lookback()) }
+
+type callNode struct {
+	fn   string
+	args []ruleNode
+	bars int // prev's bar count
+}
+
+func (n callNode) eval(env *ruleEnv, offset int) float64 {
+	switch n.fn {
+	case "crossover", "crossunder":
+		a, b := n.args[0].eval(env, offset), n.args[1].eval(env, offset)
+		prevA, prevB := n.args[0].eval(env, offset+1), n.args[1].eval(env, offset+1)
+		if math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(prevA) || math.IsNaN(prevB) {
+			return math.NaN()
+		}
+		if n.fn == "crossover" {
+			return boolValue(prevA <= prevB && a > b)
+		}
+		return boolValue(prevA >= prevB && a < b)
+	case "prev":
+		return n.args[0].eval(env, offset+n.bars)
+	case "abs":
+		return math.Abs(n.args[0].eval(env, offset))
+	case "min":
+		return math.Min(n.args[0].eval(env, offset), n.args[1].eval(env, offset))
+	default: // "max"
+		return math.Max(n.args[0].eval(env, offset), n.args[1].eval(env, offset))
+	}
+}
+
+func (n callNode) lookback() int {
+	bars := 0
+	for _, arg := range n.args {
+		bars = max(bars, arg.lookback())
+	}
+	switch n.fn {
+	case "crossover", "crossunder":
+		return bars + 1
+	case "prev":
+		return bars + n.bars
+	}
+	return bars
+}
+
+// ruleFuncs maps each function to its number of arguments (prev also takes an optional bar count)
+var ruleFuncs = map[string]int{
+	"crossover":  2,
+	"crossunder": 2,
+	"prev":       1,
+	"abs":        1,
+	"min":        2,
+	"max":        2,
+}
+
+// compileRule parses a rule expression
+func compileRule(source string) (*rule, error) {
+	tokens, err := lexRule(source)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", source, err)
+	}
+
+	p := &ruleParser{tokens: tokens, idents: make(map[string]bool)}
+	root, err := p.parseOr()
+	if err == nil && p.peek().kind != tokenEOF {
+		err = p.unexpected()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", source, err)
+	}
+
+	idents := make([]string, 0, len(p.idents))
+	for name := range p.idents {
+		idents = append(idents, name)
+	}
+	sort.Strings(idents)
+
+	return &rule{source: source, root: root, idents: idents, lookback: root.lookback()}, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOp
+)
+
+type ruleToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// ruleOps holds the operators and punctuation of rule expressions
+var ruleOps = map[string]bool{
+	"&&": true, "||": true, "<=": true, ">=": true, "==": true, "!=": true,
+	"<": true, ">": true, "!": true, "+": true, "-": true, "*": true, "/": true,
+	"(": true, ")": true, "[": true, "]": true, ",": true,
+}
+
+// lexRule splits a rule expression into tokens
+func lexRule(source string) ([]ruleToken, error) {
+	var tokens []ruleToken
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c >= '0' && c <= '9' || c == '.':
+			start := i
+			for i < len(source) && (source[i] >= '0' && source[i] <= '9' || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, ruleToken{tokenNumber, source[start:i], start})
+
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_':
+			start := i
+			for i < len(source) && isIdentByte(source[i]) {
+				i++
+			}
+			name := strings.ToLower(source[start:i])
+			if strings.HasSuffix(name, ".") || strings.Contains(name, "..") {
+				return nil, fmt.Errorf("invalid name %q at position %d", name, start+1)
+			}
+			tokens = append(tokens, ruleToken{tokenIdent, name, start})
+
+		default:
+			op := source[i : i+1]
+			if i+1 < len(source) && ruleOps[source[i:i+2]] {
+				op = source[i : i+2]
+			}
+			if !ruleOps[op] {
+				return nil, fmt.Errorf("unexpected %q at position %d", op, i+1)
+			}
+			tokens = append(tokens, ruleToken{tokenOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, ruleToken{kind: tokenEOF, pos: len(source)}), nil
+}
+
+func isIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.'
+}
+
+// ruleParser is a recursive descent parser over rule tokens
+type ruleParser struct {
+	tokens []ruleToken
+	pos    int
+	idents map[string]bool
+}
+
+func (p *ruleParser) peek() ruleToken {
+	return p.tokens[p.pos]
+}
+
+func (p *ruleParser) next() ruleToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of ops (operators or keywords)
+func (p *ruleParser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOp && t.kind != tokenIdent {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *ruleParser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		return fmt.Errorf("expected %q, got %s", op, describeToken(p.peek()))
+	}
+	return nil
+}
+
+func (p *ruleParser) unexpected() error {
+	return fmt.Errorf("unexpected %s", describeToken(p.peek()))
+}
+
+func describeToken(t ruleToken) string {
+	if t.kind == tokenEOF {
+		return "end of rule"
+	}
+	return fmt.Sprintf("%q at position %d", t.text, t.pos+1)
+}
+
+func (p *ruleParser) parseOr() (ruleNode, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return l, nil
+		}
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: "||", l: l, r: r}
+	}
+}
+
+func (p *ruleParser) parseAnd() (ruleNode, error) {
+	l, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return l, nil
+		}
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: "&&", l: l, r: r}
+	}
+}
+
+func (p *ruleParser) parseNot() (ruleNode, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "!", x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *ruleParser) parseComparison() (ruleNode, error) {
+	l, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("<", "<=", ">", ">=", "==", "!=")
+	if !ok {
+		return l, nil
+	}
+	r, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{op: op, l: l, r: r}, nil
+}
+
+func (p *ruleParser) parseSum() (ruleNode, error) {
+	l, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: op, l: l, r: r}
+	}
+}
+
+func (p *ruleParser) parseProduct() (ruleNode, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: op, l: l, r: r}
+	}
+}
+
+func (p *ruleParser) parseUnary() (ruleNode, error) {
+	if _, ok := p.accept("-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "-", x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *ruleParser) parsePrimary() (ruleNode, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.next()
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.text, t.pos+1)
+		}
+		return numberNode(v), nil
+
+	case tokenIdent:
+		p.next()
+		switch t.text {
+		case "true":
+			return numberNode(1), nil
+		case "false":
+			return numberNode(0), nil
+		case "and", "or", "not":
+			return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos+1)
+		}
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t)
+		}
+
+		node := identNode{name: t.text}
+		if _, ok := p.accept("["); ok {
+			bars, err := p.parseBars()
+			if err != nil {
+				return nil, err
+			}
+			node.offset = bars
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		}
+		p.idents[node.name] = true
+		return node, nil
+
+	case tokenOp:
+		if t.text == "(" {
+			p.next()
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		}
+	}
+	return nil, p.unexpected()
+}
+
+// parseBars parses a whole number of bars
+func (p *ruleParser) parseBars() (int, error) {
+	t := p.next()
+	bars, err := strconv.Atoi(t.text)
+	if t.kind != tokenNumber || err != nil || bars < 0 {
+		return 0, fmt.Errorf("expected a number of bars, got %s", describeToken(t))
+	}
+	return bars, nil
+}
+
+// parseCall parses a function call's arguments after "("
+func (p *ruleParser) parseCall(name ruleToken) (ruleNode, error) {
+	arity, ok := ruleFuncs[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos+1)
+	}
+
+	node := callNode{fn: name.text, bars: 1}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		node.args = append(node.args, arg)
+
+		if _, ok := p.accept(","); !ok {
+			break
+		}
+		if node.fn == "prev" && len(node.args) == 1 {
+			if node.bars, err = p.parseBars(); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	if len(node.args) != arity {
+		return nil, fmt.Errorf("%s takes %d arguments, got %d", node.fn, arity, len(node.args))
+	}
+	return node, nil
+}
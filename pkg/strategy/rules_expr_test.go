@@ -0,0 +1,158 @@
+package strategy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRule_Eval(t *testing.T) {
+	// Bars oldest first; the last one is the current bar
+	bars := []map[string]float64{
+		{"close": 100, "rsi": 25, "fast": 9, "slow": 10},
+		{"close": 101, "rsi": 28, "fast": 10, "slow": 10},
+		{"close": 103, "rsi": 35, "fast": 11, "slow": 10},
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   bool
+	}{
+		// Precedence: && binds tighter than ||, comparisons tighter than !, * tighter than +
+		{"and before or", "1 > 0 || 1 > 2 && 1 > 2", true},
+		{"parentheses", "(1 > 0 || 1 > 2) && 1 > 2", false},
+		{"keywords", "true or false and false", true},
+		{"not of a comparison", "!rsi > 40", true},
+		{"not before and", "!rsi > 30 && close > 100", false},
+		{"double not", "!!(rsi > 30)", true},
+		{"unary minus", "-rsi + 40 == 5", true},
+		{"unary minus before product", "-2 * 3 == -6", true},
+		{"product before sum", "1 + 2 * 3 == 7", true},
+		{"left to right", "10 - 4 - 3 == 3 && 12 / 3 / 2 == 2", true},
+		{"functions", "abs(-rsi) == 35 && min(rsi, close) == 35 && max(fast, slow) == 11", true},
+		{"case insensitive", "RSI > 30 AND Close > 100", true},
+
+		// A missing value makes every comparison using it false, negated or not
+		{"missing value", "volume > 0", false},
+		{"negated missing value", "!(volume > 0)", false},
+		{"missing value in arithmetic", "volume * 0 == 0", false},
+		{"missing value in one branch", "volume > 0 || rsi > 30", true},
+		{"missing value and", "rsi > 30 && !(volume < 0)", false},
+		{"missing indicator key", "macd.histogram != 0", false},
+
+		// Lookback
+		{"one bar ago", "rsi[1] == 28", true},
+		{"two bars ago", "rsi[2] == 25 && close[2] == 100", true},
+		{"zero bars ago", "rsi[0] == rsi", true},
+		{"prev", "prev(rsi) == 28", true},
+		{"prev of an expression", "prev(rsi - fast, 2) == 16", true},
+		{"nested lookback", "prev(rsi[1], 1) == 25", true},
+		{"before the first bar", "rsi[3] < 100", false},
+		{"nested before the first bar", "prev(rsi[1], 2) < 100", false},
+
+		// Crossings compare this bar with the previous one
+		{"crossover from equal", "crossover(fast, slow)", true},
+		{"no crossunder", "crossunder(fast, slow)", false},
+		{"touching is no crossover", "prev(crossover(fast, slow))", false},
+		{"crossunder of inverted lines", "crossunder(slow, fast)", true},
+		{"crossover needs a previous bar", "prev(crossover(fast, slow), 2)", false},
+		{"crossover of a constant", "crossover(rsi, 30)", true},
+		{"crossover of a constant one bar ago", "crossover(rsi[1], 30)", false},
+	}
+
+	env := &ruleEnv{current: bars[len(bars)-1], history: bars[:len(bars)-1]}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := compileRule(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.evalBool(env); got != tt.want {
+				t.Errorf("%s = %v, want %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRule_Crossings(t *testing.T) {
+	crossover, _ := compileRule("crossover(fast, slow)")
+	crossunder, _ := compileRule("crossunder(fast, slow)")
+
+	// fast starts below slow, touches it, crosses above, stays, then crosses back under
+	fast := []float64{8, 9, 10, 11, 12, 9}
+	slow := []float64{10, 10, 10, 10, 10, 10}
+	wantOver := []bool{false, false, false, true, false, false}
+	wantUnder := []bool{false, false, false, false, false, true}
+
+	var history []map[string]float64
+	for i := range fast {
+		env := &ruleEnv{current: map[string]float64{"fast": fast[i], "slow": slow[i]}, history: history}
+		if got := crossover.evalBool(env); got != wantOver[i] {
+			t.Errorf("bar %d: crossover = %v, want %v", i, got, wantOver[i])
+		}
+		if got := crossunder.evalBool(env); got != wantUnder[i] {
+			t.Errorf("bar %d: crossunder = %v, want %v", i, got, wantUnder[i])
+		}
+		history = append(history, env.current)
+	}
+}
+
+func TestCompileRule_Lookback(t *testing.T) {
+	tests := []struct {
+		source   string
+		lookback int
+		idents   string
+	}{
+		{"rsi < 30", 0, "rsi"},
+		{"rsi[1] < 30 && rsi >= 30", 1, "rsi"},
+		{"prev(close) < close", 1, "close"},
+		{"prev(close, 3) < close", 3, "close"},
+		{"prev(rsi[1], 2) < 30", 3, "rsi"},
+		{"crossover(macd.macd, macd.signal)", 1, "macd.macd,macd.signal"},
+		{"crossover(prev(fast, 2), slow[1])", 3, "fast,slow"},
+		{"abs(bbands.lower[4] - close) > 1", 4, "bbands.lower,close"},
+		{"prev(crossunder(a, b), 2) || c[5] > 0", 5, "a,b,c"},
+	}
+	for _, tt := range tests {
+		r, err := compileRule(tt.source)
+		if err != nil {
+			t.Errorf("%s: %v", tt.source, err)
+			continue
+		}
+		if r.lookback != tt.lookback || strings.Join(r.idents, ",") != tt.idents {
+			t.Errorf("%s: lookback %d, idents %v; want %d, %s", tt.source, r.lookback, r.idents, tt.lookback, tt.idents)
+		}
+	}
+}
+
+func TestCompileRule_Errors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"crossover(fast) > 0", "crossover takes 2 arguments, got 1"},
+		{"abs(a, b) > 0", "abs takes 1 arguments, got 2"},
+		{"min(a) > 0", "min takes 2 arguments, got 1"},
+		{"prev(a, 1, 2) > 0", `expected ")", got ","`},
+		{"prev(a, b) > 0", `expected a number of bars, got "b"`},
+		{"close > 1.2.3", `invalid number "1.2.3"`},
+		{"rsi. > 30", `invalid name "rsi."`},
+		{"macd..signal > 0", `invalid name "macd..signal"`},
+		{"rsi[1.5] < 30", `expected a number of bars, got "1.5"`},
+		{"rsi[-1] < 30", `expected a number of bars, got "-"`},
+		{"rsi[1 < 30", `expected "]"`},
+		{"ema(close) > 0", `unknown function "ema"`},
+		{"close >", "unexpected end of rule"},
+		{"close > 1 1", `unexpected "1" at position 11`},
+		{"(close > 1", `expected ")", got end of rule`},
+		{"1 < close < 2", `unexpected "<"`},
+		{"close # 1", `unexpected "#" at position 7`},
+		{"rsi < 30 and or close > 1", `unexpected "or"`},
+		{"", "unexpected end of rule"},
+	}
+	for _, tt := range tests {
+		if _, err := compileRule(tt.source); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("compileRule(%q) = %v, want %q", tt.source, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"rsi-bot/pkg/indicators"
+	"rsi-bot/pkg/models"
+)
+
+// Rules strategy parameters
+const (
+	ParamEntry      = "entry"
+	ParamExit       = "exit"
+	ParamShortEntry = "short_entry"
+	ParamShortExit  = "short_exit"
+)
+
+// Position value keys rule expressions can read besides indicator and candle values
+const (
+	ValueKeyInPosition   = "in_position"   // 1 while holding a position, otherwise 0
+	ValueKeyPositionSide = "position_side" // 1 for a long position, -1 for a short one, 0 when flat
+	ValueKeyEntryPrice   = "entry_price"   // Entry price of the open position
+	ValueKeyProfitPct    = "profit_pct"    // Unrealized profit of the open position in percent
+)
+
+// RulesStrategy buys when its entry expression holds and sells when its exit
+// expression holds; the optional short_entry and short_exit expressions open
+// and cover shorts the same way. The expressions read the values of a set of
+// named indicators (see indicatorSet) and the position; see rule for the syntax.
+type RulesStrategy struct {
+	set              *indicatorSet
+	entry            *rule // nil = never go long
+	exit             *rule // nil = never sell on a rule (risk exits still apply)
+	shortEntry       *rule // nil = never go short
+	shortExit        *rule // nil = never cover on a rule
+	history          []map[string]float64
+	historySize      int // Bars kept: the current one and as many earlier ones as the rules read
+	lastSignalReason string
+}
+
+// NewRulesStrategy creates a strategy from expressions over the named indicators.
+// At least one of entry and shortEntry is needed; the others may be empty. Every
+// name the expressions read must be a candle or position value or belong to one of the indicators.
+func NewRulesStrategy(named map[string]indicators.Indicator, entry, exit, shortEntry, shortExit string) (*RulesStrategy, error) {
+	set, err := newIndicatorSet(named, ValueKeyInPosition, ValueKeyPositionSide, ValueKeyEntryPrice, ValueKeyProfitPct)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(entry) == "" && strings.TrimSpace(shortEntry) == "" {
+		return nil, fmt.Errorf("rules strategy needs an entry or short_entry rule")
+	}
+	s := &RulesStrategy{set: set, historySize: 1}
+	for _, r := range []struct {
+		name, source string
+		compiled     **rule
+	}{
+		{ParamEntry, entry, &s.entry},
+		{ParamExit, exit, &s.exit},
+		{ParamShortEntry, shortEntry, &s.shortEntry},
+		{ParamShortExit, shortExit, &s.shortExit},
+	} {
+		if strings.TrimSpace(r.source) == "" {
+			continue
+		}
+		compiled, err := compileRule(r.source)
+		if err == nil {
+			err = s.checkNames(compiled)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", r.name, err)
+		}
+		*r.compiled = compiled
+		if compiled.lookback+1 > s.historySize {
+			s.historySize = compiled.lookback + 1
+		}
+	}
+	return s, nil
+}
+
+// rulesDefinition registers the rules strategy with the strategy registry
+var rulesDefinition = Definition{
+	Name:            "rules",
+	DisplayName:     "Rules",
+	Description:     "Rules - Entry and exit expressions over named indicators",
+	NamedIndicators: true,
+	Params: []indicators.ParamSpec{
+		{Name: ParamEntry, Type: indicators.ParamString, Default: "", Description: "Buy when this expression holds (e.g. rsi < 30 && close < bbands.lower)"},
+		{Name: ParamExit, Type: indicators.ParamString, Default: "", Description: "Sell when this expression holds (empty = only risk exits)"},
+		{Name: ParamShortEntry, Type: indicators.ParamString, Default: "", Description: "Open a short when this expression holds (empty = long only)"},
+		{Name: ParamShortExit, Type: indicators.ParamString, Default: "", Description: "Cover the short when this expression holds (empty = only risk exits)"},
+	},
+	New: func(in BuildInput) (Strategy, error) {
+		return NewRulesStrategy(in.Indicators, in.Params.String(ParamEntry), in.Params.String(ParamExit),
+			in.Params.String(ParamShortEntry), in.Params.String(ParamShortExit))
+	},
+	Validate: func(p indicators.Params) error {
+		if p.String(ParamEntry) == "" && p.String(ParamShortEntry) == "" {
+			return fmt.Errorf("rules strategy needs an entry or short_entry rule")
+		}
+		for _, name := range []string{ParamEntry, ParamExit, ParamShortEntry, ParamShortExit} {
+			if p.String(name) == "" {
+				continue
+			}
+			if _, err := compileRule(p.String(name)); err != nil {
+				return fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+		return nil
+	},
+}
+
+// checkNames rejects rules reading values no candle, position or configured indicator provides
+func (s *RulesStrategy) checkNames(r *rule) error {
+	for _, name := range r.idents {
+		switch name {
+		case ValueKeyOpen, ValueKeyHigh, ValueKeyLow, ValueKeyClose, ValueKeyVolume,
+			ValueKeyInPosition, ValueKeyPositionSide, ValueKeyEntryPrice, ValueKeyProfitPct:
+			continue
+		}
+		indicator, _, _ := strings.Cut(name, ".")
+		if _, ok := s.set.indicators[indicator]; !ok {
+			return fmt.Errorf("%q is not a candle value, position value or configured indicator (indicators: %v)",
+				name, s.set.Names())
+		}
+	}
+	return nil
+}
+
+// Name returns the strategy identifier
+func (s *RulesStrategy) Name() string {
+	return "Rules"
+}
+
+// GetIndicator returns the indicator set whose values the rules read
+func (s *RulesStrategy) GetIndicator() indicators.Indicator {
+	return s.set
+}
+
+// Update processes new price data
+func (s *RulesStrategy) Update(price float64, volume float64, timestamp time.Time) error {
+	return s.UpdateCandle(OHLCV{Timestamp: timestamp, Open: price, High: price, Low: price, Close: price, Volume: volume})
+}
+
+// UpdateCandle processes a completed bar and remembers its values for previous-bar references
+func (s *RulesStrategy) UpdateCandle(candle OHLCV) error {
+	err := s.set.UpdateCandle(candle)
+
+	values, _ := s.set.GetValue()
+	s.history = append(s.history, values)
+	if len(s.history) > s.historySize {
+		s.history = s.history[len(s.history)-s.historySize:]
+	}
+	return err
+}
+
+// IsReady returns true when every indicator is ready
+func (s *RulesStrategy) IsReady() bool {
+	return s.set.IsReady()
+}
+
+// ruleCheck is a rule GenerateSignal evaluates and the signal it gives when it holds
+type ruleCheck struct {
+	name   string
+	rule   *rule
+	signal Signal
+}
+
+// checks returns the rules that apply to the position: the entries when flat,
+// otherwise the exit of the position's side
+func (s *RulesStrategy) checks(position *models.Position) []ruleCheck {
+	var checks []ruleCheck
+	switch {
+	case !position.InPosition:
+		if s.entry != nil {
+			checks = append(checks, ruleCheck{ParamEntry, s.entry, SignalBuy})
+		}
+		if s.shortEntry != nil {
+			checks = append(checks, ruleCheck{ParamShortEntry, s.shortEntry, SignalOpenShort})
+		}
+	case position.IsShort():
+		if s.shortExit != nil {
+			checks = append(checks, ruleCheck{ParamShortExit, s.shortExit, SignalCloseShort})
+		}
+	default:
+		if s.exit != nil {
+			checks = append(checks, ruleCheck{ParamExit, s.exit, SignalSell})
+		}
+	}
+	return checks
+}
+
+// GenerateSignal evaluates the entry rules without a position and the exit rule of its side with one
+func (s *RulesStrategy) GenerateSignal(ctx SignalContext) Signal {
+	values := make(map[string]float64, len(ctx.IndicatorData)+4)
+	for k, v := range ctx.IndicatorData {
+		values[k] = v
+	}
+	values[ValueKeyInPosition] = 0
+	values[ValueKeyPositionSide] = 0
+	if ctx.Position.InPosition {
+		values[ValueKeyInPosition] = 1
+		values[ValueKeyPositionSide] = 1
+		if ctx.Position.IsShort() {
+			values[ValueKeyPositionSide] = -1
+		}
+		values[ValueKeyEntryPrice] = ctx.Position.EntryPrice
+		if ctx.Position.EntryPrice > 0 {
+			values[ValueKeyProfitPct] = ctx.Position.ProfitPercent(ctx.CurrentPrice)
+		}
+	}
+
+	// The last history entry is the current bar; earlier ones back previous-bar references
+	env := &ruleEnv{current: values}
+	if len(s.history) > 0 {
+		env.history = s.history[:len(s.history)-1]
+	}
+
+	checks := s.checks(ctx.Position)
+	if len(checks) == 0 {
+		s.lastSignalReason = fmt.Sprintf("RULES HOLDING: no exit rule (%.2f%% profit)", values[ValueKeyProfitPct])
+		return SignalNone
+	}
+
+	unmet := make([]string, 0, len(checks))
+	for _, c := range checks {
+		if missing := missingValues(c.rule, values); len(missing) > 0 {
+			s.lastSignalReason = fmt.Sprintf("RULES: %s rule reads unknown values %v (available: %v)", c.name, missing, sortedKeys(values))
+			return SignalNone
+		}
+
+		if c.rule.evalBool(env) {
+			s.lastSignalReason = fmt.Sprintf("RULES %s: %s `%s` matched (%s)", c.signal, c.name, c.rule.source, formatRuleValues(c.rule, values))
+			if c.signal.IsExit() {
+				s.lastSignalReason += fmt.Sprintf(", Profit: %.2f%%", values[ValueKeyProfitPct])
+			}
+			return c.signal
+		}
+		unmet = append(unmet, fmt.Sprintf("%s `%s` not met (%s)", c.name, c.rule.source, formatRuleValues(c.rule, values)))
+	}
+
+	if ctx.Position.InPosition {
+		s.lastSignalReason = fmt.Sprintf("HOLDING: %s (%.2f%% profit)", strings.Join(unmet, "; "), values[ValueKeyProfitPct])
+	} else {
+		s.lastSignalReason = fmt.Sprintf("WAITING: %s (no position)", strings.Join(unmet, "; "))
+	}
+	return SignalNone
+}
+
+// missingValues returns the names a rule reads that values lacks; position
+// values only exist while holding, so they never count as missing
+func missingValues(r *rule, values map[string]float64) []string {
+	var missing []string
+	for _, name := range r.idents {
+		if name == ValueKeyEntryPrice || name == ValueKeyProfitPct {
+			continue
+		}
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// formatRuleValues lists the current values a rule reads
+func formatRuleValues(r *rule, values map[string]float64) string {
+	parts := make([]string, 0, len(r.idents))
+	for _, name := range r.idents {
+		if v, ok := values[name]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%.6g", name, v))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetSignalReason returns the explanation for the last signal
+func (s *RulesStrategy) GetSignalReason() string {
+	return s.lastSignalReason
+}
+
+// Reset resets the strategy state and its indicators
+func (s *RulesStrategy) Reset() {
+	s.set.Reset()
+	s.history = nil
+	s.lastSignalReason = ""
+}
+
+// rulesState is the saved form of the previous bars' values
+type rulesState struct {
+	History []map[string]float64 `json:"history"`
+}
+
+// MarshalState saves the indicators and the values kept for previous-bar references
+func (s *RulesStrategy) MarshalState() ([]byte, error) {
+	return encodeState(s.Name(), s.set, rulesState{History: s.history})
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (s *RulesStrategy) UnmarshalState(data []byte) error {
+	var st rulesState
+	if err := decodeState(data, s.Name(), s.set, &st); err != nil {
+		return err
+	}
+	s.history = st.History
+	if len(s.history) > s.historySize {
+		s.history = s.history[len(s.history)-s.historySize:]
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package strategy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"rsi-bot/pkg/indicators"
+	"rsi-bot/pkg/models"
+)
+
+// newSMARules creates a rules strategy over a 2-period SMA named sma
+func newSMARules(t *testing.T, entry, exit, shortEntry, shortExit string) *RulesStrategy {
+	t.Helper()
+	sma, err := indicators.NewSMA(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewRulesStrategy(map[string]indicators.Indicator{"sma": sma}, entry, exit, shortEntry, shortExit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// feedRules updates s with a bar closing at close and returns its signal for position
+func feedRules(t *testing.T, s *RulesStrategy, bar int, close float64, position *models.Position) Signal {
+	t.Helper()
+	timestamp := time.Date(2024, 1, 1, 0, bar, 0, 0, time.UTC)
+	if err := s.UpdateCandle(OHLCV{Timestamp: timestamp, Open: close, High: close, Low: close, Close: close}); err != nil {
+		t.Fatal(err)
+	}
+	values, _ := s.GetIndicator().GetValue()
+	return s.GenerateSignal(SignalContext{CurrentPrice: close, Position: position, IndicatorData: values, Timestamp: timestamp})
+}
+
+func TestRulesStrategy_EntryAndExit(t *testing.T) {
+	s := newSMARules(t, "crossover(close, sma)", "close < sma", "", "")
+
+	closes := []float64{10, 10, 9, 12, 13, 11}
+	want := []Signal{SignalNone, SignalNone, SignalNone, SignalBuy, SignalNone, SignalSell}
+	reasons := []string{
+		"RULES: entry rule reads unknown values [sma]", // SMA not ready
+		"WAITING: entry `crossover(close, sma)` not met",
+		"WAITING: entry `crossover(close, sma)` not met",
+		"RULES BUY: entry `crossover(close, sma)` matched (close=12, sma=10.5)",
+		"HOLDING: exit `close < sma` not met (close=13, sma=12.5) (8.33% profit)",
+		"RULES SELL: exit `close < sma` matched (close=11, sma=12), Profit: -8.33%",
+	}
+
+	position := &models.Position{}
+	for i, close := range closes {
+		got := feedRules(t, s, i, close, position)
+		if got != want[i] {
+			t.Errorf("bar %d (%.0f): signal = %s, want %s (%s)", i, close, got, want[i], s.GetSignalReason())
+		}
+		if reason := s.GetSignalReason(); !strings.HasPrefix(reason, reasons[i]) {
+			t.Errorf("bar %d: reason = %q, want %q", i, reason, reasons[i])
+		}
+
+		switch got {
+		case SignalBuy:
+			*position = models.Position{InPosition: true, Quantity: 1, EntryPrice: close}
+		case SignalSell:
+			*position = models.Position{}
+		}
+	}
+}
+
+func TestRulesStrategy_PositionSide(t *testing.T) {
+	s := newSMARules(t, "position_side == 0", "position_side == 1", "", "position_side == -1")
+	for i, tt := range []struct {
+		position models.Position
+		want     Signal
+	}{
+		{models.Position{}, SignalBuy},
+		{models.Position{InPosition: true, Quantity: 1, EntryPrice: 10}, SignalSell},
+		{models.Position{InPosition: true, Side: models.PositionShort, Quantity: 1, EntryPrice: 10}, SignalCloseShort},
+	} {
+		if got := feedRules(t, s, i, 10, &tt.position); got != tt.want {
+			t.Errorf("%+v: signal = %s, want %s (%s)", tt.position, got, tt.want, s.GetSignalReason())
+		}
+	}
+}
+
+func TestRulesStrategy_History(t *testing.T) {
+	entry, exit := "prev(close[1], 2) < close", "close[1] > close"
+	s := newSMARules(t, entry, exit, "", "")
+	if s.historySize != 4 {
+		t.Fatalf("historySize = %d, want 4 (the current bar and 3 earlier ones)", s.historySize)
+	}
+
+	flat := &models.Position{}
+	closes := []float64{10, 11, 12, 13, 9, 14}
+	for i, close := range closes[:5] {
+		feedRules(t, s, i, close, flat)
+	}
+	if len(s.history) != 4 || s.history[0][ValueKeyClose] != 11 {
+		t.Fatalf("history = %v, want the last 4 bars from 11", s.history)
+	}
+
+	data, err := MarshalState(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := newSMARules(t, entry, exit, "", "")
+	if err := UnmarshalState(restored, data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored.history, s.history) {
+		t.Errorf("restored history = %v, want %v", restored.history, s.history)
+	}
+
+	// Both read the close 3 bars back (12 < 14) and give the same signal
+	for _, strategy := range []*RulesStrategy{s, restored} {
+		if got := feedRules(t, strategy, 5, closes[5], flat); got != SignalBuy {
+			t.Errorf("signal = %s, want BUY (%s)", got, strategy.GetSignalReason())
+		}
+	}
+	if !reflect.DeepEqual(restored.history, s.history) || restored.history[0][ValueKeyClose] != 12 {
+		t.Errorf("history after restore = %v, want %v", restored.history, s.history)
+	}
+
+	// A strategy reading fewer bars keeps only the ones it needs
+	short := newSMARules(t, "close > sma", "", "", "")
+	if err := UnmarshalState(short, data); err != nil {
+		t.Fatal(err)
+	}
+	if len(short.history) != 1 || short.history[0][ValueKeyClose] != 9 {
+		t.Errorf("history = %v, want only the last bar", short.history)
+	}
+}
+
+func TestNewRulesStrategy_Errors(t *testing.T) {
+	sma, _ := indicators.NewSMA(2)
+	named := map[string]indicators.Indicator{"sma": sma}
+	tests := []struct {
+		name                           string
+		named                          map[string]indicators.Indicator
+		entry, exit, shortEntry, short string
+		want                           string
+	}{
+		{"no entry", named, "", "close < sma", "", "", "needs an entry or short_entry rule"},
+		{"unknown name", named, "ema > close", "", "", "", `invalid entry: "ema" is not a candle value, position value or configured indicator (indicators: [sma])`},
+		{"unknown indicator key", named, "close > 0", "", "", "ema.fast < 1", `invalid short_exit: "ema.fast" is not`},
+		{"parse error", named, "close > 0", "close >", "", "", "invalid exit: rule"},
+		{"reserved name", map[string]indicators.Indicator{"close": sma}, "close > 0", "", "", "", `indicator name "close" is reserved`},
+		{"position name", map[string]indicators.Indicator{"position_side": sma}, "close > 0", "", "", "", `indicator name "position_side" is reserved`},
+		{"invalid name", map[string]indicators.Indicator{"Fast SMA": sma}, "close > 0", "", "", "", `invalid indicator name "Fast SMA"`},
+	}
+	for _, tt := range tests {
+		if _, err := NewRulesStrategy(tt.named, tt.entry, tt.exit, tt.shortEntry, tt.short); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: NewRulesStrategy = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+
+	// Short-only rule sets and position values need no indicators
+	if _, err := NewRulesStrategy(nil, "", "", "close > 0", "profit_pct > 5 || entry_price < close || in_position == 0"); err != nil {
+		t.Errorf("short-only rules: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"rsi-bot/pkg/indicators"
+)
+
+// StateMarshaler is implemented by strategies that can save and restore their
+// state, including their indicator's, so a restarted bot resumes with the same
+// indicator values and schedule instead of re-warming
+type StateMarshaler interface {
+	// MarshalState encodes the strategy's state
+	MarshalState() ([]byte, error)
+
+	// UnmarshalState replaces the strategy's state with state saved by MarshalState.
+	// It fails if the state belongs to another strategy or indicator configuration.
+	UnmarshalState(data []byte) error
+}
+
+// MarshalState saves a strategy's state; it fails for strategies that are not StateMarshalers
+func MarshalState(s Strategy) ([]byte, error) {
+	m, ok := s.(StateMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("%s strategy cannot save its state", s.Name())
+	}
+	return m.MarshalState()
+}
+
+// UnmarshalState restores a strategy's state saved by MarshalState
+func UnmarshalState(s Strategy, data []byte) error {
+	m, ok := s.(StateMarshaler)
+	if !ok {
+		return fmt.Errorf("%s strategy cannot restore its state", s.Name())
+	}
+	return m.UnmarshalState(data)
+}
+
+// savedState is the envelope every strategy's state is stored in
+type savedState struct {
+	Strategy  string          `json:"strategy"`
+	Indicator json.RawMessage `json:"indicator,omitempty"`
+	State     json.RawMessage `json:"state,omitempty"`
+}
+
+// encodeState wraps a strategy's own state (nil for none) with its name and
+// its indicator's state (indicator may be nil)
+func encodeState(name string, indicator indicators.Indicator, state interface{}) ([]byte, error) {
+	saved := savedState{Strategy: name}
+	if indicator != nil {
+		data, err := indicators.MarshalState(indicator)
+		if err != nil {
+			return nil, err
+		}
+		saved.Indicator = data
+	}
+	if state != nil {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s state: %w", name, err)
+		}
+		saved.State = data
+	}
+	return json.Marshal(saved)
+}
+
+// decodeState unwraps state saved by encodeState: it decodes the strategy's own
+// state into state (nil for none), then restores the indicator
+func decodeState(data []byte, name string, indicator indicators.Indicator, state interface{}) error {
+	var saved savedState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("invalid %s state: %w", name, err)
+	}
+	if saved.Strategy != name {
+		return fmt.Errorf("saved state is for the %s strategy, not %s", saved.Strategy, name)
+	}
+	if state != nil {
+		if err := json.Unmarshal(saved.State, state); err != nil {
+			return fmt.Errorf("invalid %s state: %w", name, err)
+		}
+	}
+	if indicator != nil {
+		if err := indicators.UnmarshalState(indicator, saved.Indicator); err != nil {
+			return err
+		}
+	}
+	return nil
+}
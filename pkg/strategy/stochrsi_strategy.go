@@ -0,0 +1,183 @@
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"rsi-bot/pkg/indicators"
+)
+
+// StochRSIStrategy implements a trading strategy based on Stochastic RSI %K/%D
+// crossovers inside the overbought/oversold zones
+type StochRSIStrategy struct {
+	indicator        indicators.Indicator
+	overboughtLevel  float64
+	oversoldLevel    float64
+	lastSignalReason string
+
+	// Track previous %K/%D values for crossover detection
+	prevK       float64
+	prevD       float64
+	initialized bool
+}
+
+// NewStochRSIStrategy creates a new Stochastic RSI-based trading strategy
+// Typical levels: overbought 80, oversold 20
+func NewStochRSIStrategy(indicator indicators.Indicator, overboughtLevel, oversoldLevel float64) (*StochRSIStrategy, error) {
+	if indicator.Name() != "StochRSI" {
+		return nil, fmt.Errorf("StochRSIStrategy requires StochRSI indicator, got %s", indicator.Name())
+	}
+
+	if overboughtLevel <= oversoldLevel {
+		return nil, fmt.Errorf("overbought level (%.1f) must be greater than oversold level (%.1f)",
+			overboughtLevel, oversoldLevel)
+	}
+
+	return &StochRSIStrategy{
+		indicator:       indicator,
+		overboughtLevel: overboughtLevel,
+		oversoldLevel:   oversoldLevel,
+	}, nil
+}
+
+// stochRSIDefinition registers the Stochastic RSI strategy with the strategy registry
+var stochRSIDefinition = Definition{
+	Name:        "stoch_rsi",
+	Aliases:     []string{"stochastic_rsi"},
+	DisplayName: "Stochastic RSI",
+	Description: "Stochastic RSI - %K/%D crossovers in overbought/oversold zones",
+	Indicator:   "stoch_rsi",
+	Params:      levelParams(80.0, 20.0),
+	New: func(in BuildInput) (Strategy, error) {
+		return NewStochRSIStrategy(in.Indicator, in.Params.Float(ParamOverboughtLevel), in.Params.Float(ParamOversoldLevel))
+	},
+	Validate: validateLevels,
+}
+
+// Name returns the strategy identifier
+func (s *StochRSIStrategy) Name() string {
+	return "StochRSI"
+}
+
+// GetIndicator returns the underlying indicator
+func (s *StochRSIStrategy) GetIndicator() indicators.Indicator {
+	return s.indicator
+}
+
+// Update processes new price data
+func (s *StochRSIStrategy) Update(price float64, volume float64, timestamp time.Time) error {
+	return s.indicator.Update(price, timestamp)
+}
+
+// UpdateCandle processes a completed bar, passing it whole to candle-aware indicators
+func (s *StochRSIStrategy) UpdateCandle(candle OHLCV) error {
+	return indicators.UpdateWithCandle(s.indicator, candle)
+}
+
+// IsReady returns true when the strategy has enough data
+func (s *StochRSIStrategy) IsReady() bool {
+	return s.indicator.IsReady()
+}
+
+// GenerateSignal analyzes %K/%D crossovers and generates trading signals.
+// A crossover only counts when %D (the slower line) is inside the matching zone.
+func (s *StochRSIStrategy) GenerateSignal(ctx SignalContext) Signal {
+	k, hasK := ctx.IndicatorData[indicators.ValueKeyStochK]
+	d, hasD := ctx.IndicatorData[indicators.ValueKeyStochD]
+
+	if !hasK || !hasD {
+		s.lastSignalReason = "Stochastic RSI values not available"
+		return SignalNone
+	}
+
+	// Need at least 2 data points to detect crossover
+	if !s.initialized {
+		s.prevK = k
+		s.prevD = d
+		s.initialized = true
+		s.lastSignalReason = "Initializing Stochastic RSI crossover detection"
+		return SignalNone
+	}
+
+	bullishCrossover := s.prevK <= s.prevD && k > d
+	bearishCrossover := s.prevK >= s.prevD && k < d
+
+	var signal Signal = SignalNone
+
+	// BUY signal: %K crosses above %D in the oversold zone AND no position
+	if bullishCrossover && d <= s.oversoldLevel && !ctx.Position.InPosition {
+		s.lastSignalReason = fmt.Sprintf("StochRSI BULLISH CROSSOVER: %%K %.2f crossed above %%D %.2f (OVERSOLD <= %.1f)",
+			k, d, s.oversoldLevel)
+		signal = SignalBuy
+	} else if bearishCrossover && d >= s.overboughtLevel && ctx.Position.InPosition {
+		// SELL signal: %K crosses below %D in the overbought zone AND holding position
+		profitPercent := ((ctx.CurrentPrice - ctx.Position.EntryPrice) / ctx.Position.EntryPrice) * 100
+		s.lastSignalReason = fmt.Sprintf("StochRSI BEARISH CROSSOVER: %%K %.2f crossed below %%D %.2f (OVERBOUGHT >= %.1f), Profit: %.2f%%",
+			k, d, s.overboughtLevel, profitPercent)
+		signal = SignalSell
+	} else {
+		// No crossover in the zone or wrong position state
+		if ctx.Position.InPosition {
+			profitPercent := ((ctx.CurrentPrice - ctx.Position.EntryPrice) / ctx.Position.EntryPrice) * 100
+			s.lastSignalReason = fmt.Sprintf("HOLDING: StochRSI %%K %.2f, %%D %.2f (%.2f%% profit)", k, d, profitPercent)
+		} else {
+			s.lastSignalReason = fmt.Sprintf("WAITING: StochRSI %%K %.2f, %%D %.2f (no position)", k, d)
+		}
+	}
+
+	// Update previous values for next crossover detection
+	s.prevK = k
+	s.prevD = d
+
+	return signal
+}
+
+// GetSignalReason returns the explanation for the last signal
+func (s *StochRSIStrategy) GetSignalReason() string {
+	return s.lastSignalReason
+}
+
+// Reset resets the strategy state and its indicator
+func (s *StochRSIStrategy) Reset() {
+	s.indicator.Reset()
+	s.lastSignalReason = ""
+	s.prevK = 0
+	s.prevD = 0
+	s.initialized = false
+}
+
+// GetOverboughtLevel returns the overbought threshold
+func (s *StochRSIStrategy) GetOverboughtLevel() float64 {
+	return s.overboughtLevel
+}
+
+// GetOversoldLevel returns the oversold threshold
+func (s *StochRSIStrategy) GetOversoldLevel() float64 {
+	return s.oversoldLevel
+}
+
+// stochRSIStrategyState is the saved form of the crossover detection state
+type stochRSIStrategyState struct {
+	PrevK       float64 `json:"prev_k"`
+	PrevD       float64 `json:"prev_d"`
+	Initialized bool    `json:"initialized"`
+}
+
+// MarshalState saves the Stochastic RSI indicator and the previous %K/%D values
+func (s *StochRSIStrategy) MarshalState() ([]byte, error) {
+	return encodeState(s.Name(), s.indicator, stochRSIStrategyState{
+		PrevK:       s.prevK,
+		PrevD:       s.prevD,
+		Initialized: s.initialized,
+	})
+}
+
+// UnmarshalState restores state saved by MarshalState
+func (s *StochRSIStrategy) UnmarshalState(data []byte) error {
+	var st stochRSIStrategyState
+	if err := decodeState(data, s.Name(), s.indicator, &st); err != nil {
+		return err
+	}
+	s.prevK, s.prevD, s.initialized = st.PrevK, st.PrevD, st.Initialized
+	return nil
+}
@@ -0,0 +1,100 @@
+package strategy
+
+import (
+	"strings"
+	"testing"
+
+	"rsi-bot/pkg/indicators"
+	"rsi-bot/pkg/models"
+)
+
+// newTestStochRSIStrategy creates a Stochastic RSI strategy with 80/20 zones
+func newTestStochRSIStrategy(t *testing.T) *StochRSIStrategy {
+	t.Helper()
+	stoch, err := indicators.NewStochRSI(14, 14, 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewStochRSIStrategy(stoch, 80, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// stochSignal returns the strategy's signal for %K and %D at 110, long from 100 when inPosition
+func stochSignal(s *StochRSIStrategy, k, d float64, inPosition bool) Signal {
+	position := &models.Position{}
+	if inPosition {
+		*position = models.Position{InPosition: true, Quantity: 1, EntryPrice: 100}
+	}
+	values := map[string]float64{indicators.ValueKeyStochK: k, indicators.ValueKeyStochD: d}
+	return s.GenerateSignal(SignalContext{CurrentPrice: 110, Position: position, IndicatorData: values})
+}
+
+func TestStochRSIStrategy_CrossoversInZone(t *testing.T) {
+	s := newTestStochRSIStrategy(t)
+	bars := []struct {
+		k, d       float64
+		inPosition bool
+		want       Signal
+		reason     string
+	}{
+		{10, 15, false, SignalNone, "Initializing"},
+		// %K crosses above %D with %D oversold
+		{18, 15, false, SignalBuy, "StochRSI BULLISH CROSSOVER: %K 18.00 crossed above %D 15.00 (OVERSOLD <= 20.0)"},
+		{25, 21, true, SignalNone, "HOLDING: StochRSI %K 25.00, %D 21.00 (10.00% profit)"},
+		{85, 82, true, SignalNone, "HOLDING"},
+		// %K crosses below %D with %D overbought
+		{80, 83, true, SignalSell, "StochRSI BEARISH CROSSOVER: %K 80.00 crossed below %D 83.00 (OVERBOUGHT >= 80.0), Profit: 10.00%"},
+		// Crossovers outside their zone are ignored
+		{30, 35, false, SignalNone, "WAITING: StochRSI %K 30.00, %D 35.00 (no position)"},
+		{40, 35, false, SignalNone, "WAITING"},
+		{38, 39, false, SignalNone, "WAITING"},
+		// A bearish crossover without a position and a bullish one while holding do nothing
+		{90, 85, false, SignalNone, "WAITING"},
+		{84, 86, false, SignalNone, "WAITING"},
+		{10, 15, true, SignalNone, "HOLDING"},
+		{16, 15, true, SignalNone, "HOLDING"},
+		// From touching lines, crossing counts
+		{12, 12, false, SignalNone, "WAITING"},
+		{14, 12, false, SignalBuy, "StochRSI BULLISH CROSSOVER"},
+	}
+
+	for i, bar := range bars {
+		if got := stochSignal(s, bar.k, bar.d, bar.inPosition); got != bar.want {
+			t.Errorf("bar %d (%%K %.0f, %%D %.0f): signal = %s, want %s (%s)", i, bar.k, bar.d, got, bar.want, s.GetSignalReason())
+		}
+		if reason := s.GetSignalReason(); !strings.HasPrefix(reason, bar.reason) {
+			t.Errorf("bar %d: reason = %q, want %q", i, reason, bar.reason)
+		}
+	}
+}
+
+func TestStochRSIStrategy_ResetAndState(t *testing.T) {
+	s := newTestStochRSIStrategy(t)
+	stochSignal(s, 10, 15, false)
+
+	// The previous %K/%D survive a save and restore, so the next bar can cross
+	data, err := MarshalState(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := newTestStochRSIStrategy(t)
+	if err := UnmarshalState(restored, data); err != nil {
+		t.Fatal(err)
+	}
+	if got := stochSignal(restored, 18, 15, false); got != SignalBuy {
+		t.Errorf("restored: signal = %s, want BUY (%s)", got, restored.GetSignalReason())
+	}
+
+	// After a reset the first bar only initializes again
+	s.Reset()
+	if got := stochSignal(s, 18, 15, false); got != SignalNone || !strings.HasPrefix(s.GetSignalReason(), "Initializing") {
+		t.Errorf("after Reset: signal = %s (%s), want initialization", got, s.GetSignalReason())
+	}
+
+	if got := s.GenerateSignal(SignalContext{Position: &models.Position{}, IndicatorData: map[string]float64{indicators.ValueKeyStochK: 18}}); got != SignalNone || s.GetSignalReason() != "Stochastic RSI values not available" {
+		t.Errorf("without %%D: signal = %s (%s), want none", got, s.GetSignalReason())
+	}
+}
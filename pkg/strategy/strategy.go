@@ -11,9 +11,17 @@ import (
 type Signal int
 
 const (
-	SignalNone Signal = iota // No action
-	SignalBuy                // Buy signal
-	SignalSell               // Sell signal
+	SignalNone       Signal = iota // No action
+	SignalBuy                      // Open or add to a long position
+	SignalSell                     // Close or reduce a long position
+	SignalOpenShort                // Open or add to a short position
+	SignalCloseShort               // Close or reduce a short position
+)
+
+// Long-side names of SignalBuy and SignalSell
+const (
+	SignalOpenLong  = SignalBuy
+	SignalCloseLong = SignalSell
 )
 
 func (s Signal) String() string {
@@ -22,16 +30,47 @@ func (s Signal) String() string {
 		return "BUY"
 	case SignalSell:
 		return "SELL"
+	case SignalOpenShort:
+		return "SHORT"
+	case SignalCloseShort:
+		return "COVER"
 	default:
 		return "NONE"
 	}
 }
 
+// IsEntry reports whether the signal opens or adds to a position
+func (s Signal) IsEntry() bool {
+	return s == SignalBuy || s == SignalOpenShort
+}
+
+// IsExit reports whether the signal closes or reduces a position
+func (s Signal) IsExit() bool {
+	return s == SignalSell || s == SignalCloseShort
+}
+
+// Side returns the side of the position the signal opens or closes
+func (s Signal) Side() models.PositionSide {
+	if s == SignalOpenShort || s == SignalCloseShort {
+		return models.PositionShort
+	}
+	return models.PositionLong
+}
+
+// CloseSignal returns the signal that closes a position on the given side
+func CloseSignal(side models.PositionSide) Signal {
+	if side == models.PositionShort {
+		return SignalCloseShort
+	}
+	return SignalSell
+}
+
 // SignalContext provides context for signal generation
 type SignalContext struct {
 	CurrentPrice  float64
 	Position      *models.Position
 	IndicatorData map[string]float64
+	Timestamp     time.Time // Time of the candle being evaluated (zero = wall clock)
 }
 
 // Strategy defines the interface for trading strategies
@@ -54,6 +93,69 @@ type Strategy interface {
 	// GetSignalReason returns a human-readable explanation of the last signal
 	GetSignalReason() string
 
-	// Reset resets the strategy state
+	// Reset resets the strategy state, including its indicators
 	Reset()
 }
+
+// CandleUpdater is implemented by strategies that use whole bars (open, high, low,
+// trade counts) rather than only closes. Update remains the price-only path.
+type CandleUpdater interface {
+	// UpdateCandle processes a completed bar (updates all indicators)
+	UpdateCandle(candle OHLCV) error
+}
+
+// UpdateWithCandle feeds a bar to a strategy: the whole bar if it is a
+// CandleUpdater, otherwise the close and volume through Update
+func UpdateWithCandle(s Strategy, candle OHLCV) error {
+	if updater, ok := s.(CandleUpdater); ok {
+		return updater.UpdateCandle(candle)
+	}
+	return s.Update(candle.Close, candle.Volume, candle.Timestamp)
+}
+
+// OrderRequester is implemented by strategies that choose how their signals are executed
+type OrderRequester interface {
+	// OrderConfig returns the execution settings for a signal; ok=false uses the bot's orders config
+	OrderConfig(signal Signal) (config models.OrderConfig, ok bool)
+}
+
+// SizeScaler is implemented by strategies that vary the order amount of their signals
+type SizeScaler interface {
+	// SizeMultiplier returns the factor applied to the base order amount of the last signal (1 = normal)
+	SizeMultiplier() float64
+}
+
+// SignalDetail qualifies the last signal of a strategy
+type SignalDetail struct {
+	// Strength is the strategy's confidence in the signal, from 0 to 1 (0 = not reported, treated as 1)
+	Strength float64
+
+	// Size is the suggested amount: for entries a factor of the base order amount,
+	// for exits the fraction of the position to close (0 = normal amount / whole position)
+	Size float64
+}
+
+// SignalDetailer is implemented by strategies that report the strength and size of their signals
+type SignalDetailer interface {
+	// SignalDetail returns the detail of the last signal
+	SignalDetail() SignalDetail
+}
+
+// DetailOf returns the detail of a strategy's last signal, falling back to the
+// SizeScaler multiplier for entries, with Strength clamped to 0-1 (1 when not reported)
+func DetailOf(s Strategy, signal Signal) SignalDetail {
+	var detail SignalDetail
+	if detailer, ok := s.(SignalDetailer); ok {
+		detail = detailer.SignalDetail()
+	} else if scaler, ok := s.(SizeScaler); ok && signal.IsEntry() {
+		detail.Size = scaler.SizeMultiplier()
+	}
+
+	if detail.Strength <= 0 || detail.Strength > 1 {
+		detail.Strength = 1
+	}
+	if detail.Size < 0 {
+		detail.Size = 0
+	}
+	return detail
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"time"
+
+	"rsi-bot/pkg/indicators"
 )
 
 // Timeframe represents a chart timeframe
@@ -40,15 +42,9 @@ func (tf Timeframe) String() string {
 	return string(tf)
 }
 
-// OHLCV represents a candlestick with Open, High, Low, Close, Volume
-type OHLCV struct {
-	Timestamp time.Time
-	Open      float64
-	High      float64
-	Low       float64
-	Close     float64
-	Volume    float64
-}
+// OHLCV represents a candlestick with Open, High, Low, Close, Volume.
+// It is the indicators package's bar type, so bars pass to indicators unchanged.
+type OHLCV = indicators.Candle
 
 // TimeframeData stores candlestick data for a specific timeframe
 type TimeframeData struct {
@@ -71,13 +67,26 @@ func NewTimeframeData(tf Timeframe, maxCandles int) *TimeframeData {
 // Update aggregates tick data into the appropriate timeframe candle
 // This is called for every price update (e.g., from 1-minute klines)
 func (td *TimeframeData) Update(price float64, volume float64, timestamp time.Time) error {
+	return td.UpdateCandle(OHLCV{
+		Timestamp: timestamp,
+		Open:      price,
+		High:      price,
+		Low:       price,
+		Close:     price,
+		Volume:    volume,
+	})
+}
+
+// UpdateCandle aggregates a smaller bar (e.g. a 1-minute kline) into the current
+// timeframe candle, keeping its real open, high and low
+func (td *TimeframeData) UpdateCandle(candle OHLCV) error {
 	duration, err := td.Timeframe.GetDuration()
 	if err != nil {
 		return err
 	}
 
 	// Calculate the start time of the current bar
-	barStart := timestamp.Truncate(duration)
+	barStart := candle.Timestamp.Truncate(duration)
 
 	// If this is a new bar or first update
 	if td.currentBar == nil || barStart.After(td.barStartTime) {
@@ -93,31 +102,57 @@ func (td *TimeframeData) Update(price float64, volume float64, timestamp time.Ti
 		}
 
 		// Start a new bar
-		td.currentBar = &OHLCV{
-			Timestamp: barStart,
-			Open:      price,
-			High:      price,
-			Low:       price,
-			Close:     price,
-			Volume:    volume,
-		}
+		bar := candle
+		bar.Timestamp = barStart
+		td.currentBar = &bar
 		td.barStartTime = barStart
-		log.Printf("[%s] New bar started at %s, price=%.2f", td.Timeframe, barStart.Format("15:04:05"), price)
+		log.Printf("[%s] New bar started at %s, price=%.2f", td.Timeframe, barStart.Format("15:04:05"), candle.Close)
 	} else {
 		// Update the current bar
-		if price > td.currentBar.High {
-			td.currentBar.High = price
+		if candle.High > td.currentBar.High {
+			td.currentBar.High = candle.High
 		}
-		if price < td.currentBar.Low {
-			td.currentBar.Low = price
+		if candle.Low < td.currentBar.Low {
+			td.currentBar.Low = candle.Low
 		}
-		td.currentBar.Close = price
-		td.currentBar.Volume += volume
+		td.currentBar.Close = candle.Close
+		td.currentBar.Volume += candle.Volume
+		td.currentBar.Trades += candle.Trades
+		td.currentBar.TakerBuyVolume += candle.TakerBuyVolume
 	}
 
 	return nil
 }
 
+// Preload replaces the stored history with completed candles (oldest first) and
+// an optional in-progress bar, so analysis can start without waiting for live data
+func (td *TimeframeData) Preload(candles []OHLCV, current *OHLCV) {
+	if len(candles) > td.MaxCandles {
+		candles = candles[len(candles)-td.MaxCandles:]
+	}
+	td.Candles = append(make([]OHLCV, 0, td.MaxCandles), candles...)
+
+	td.currentBar = nil
+	td.barStartTime = time.Time{}
+	if current != nil {
+		bar := *current
+		td.currentBar = &bar
+		td.barStartTime = bar.Timestamp
+	} else if len(td.Candles) > 0 {
+		td.barStartTime = td.Candles[len(td.Candles)-1].Timestamp
+	}
+}
+
+// restore replaces the stored candles, in-progress bar and bar start with saved ones
+func (td *TimeframeData) restore(candles []OHLCV, current *OHLCV, barStartTime time.Time) {
+	if len(candles) > td.MaxCandles {
+		candles = candles[len(candles)-td.MaxCandles:]
+	}
+	td.Candles = append(make([]OHLCV, 0, td.MaxCandles), candles...)
+	td.currentBar = current
+	td.barStartTime = barStartTime
+}
+
 // GetLatestCandle returns the most recent completed candle
 func (td *TimeframeData) GetLatestCandle() (*OHLCV, bool) {
 	if len(td.Candles) == 0 {
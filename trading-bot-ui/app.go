@@ -12,6 +12,7 @@ import (
 
 	"rsi-bot/pkg/bot"
 	"rsi-bot/pkg/database"
+	"rsi-bot/pkg/exchange"
 	"rsi-bot/pkg/indicators"
 	"rsi-bot/pkg/models"
 	"rsi-bot/pkg/portfolio"
@@ -103,24 +104,20 @@ func (a *App) beforeClose(ctx context.Context) (prevent bool) {
 
 // GetAvailableStrategies returns list of available trading strategies
 func (a *App) GetAvailableStrategies() []StrategyInfo {
-	return []StrategyInfo{
-		{
-			Name:        "rsi",
-			Description: "RSI (Relative Strength Index) - Mean reversion strategy",
-		},
-		{
-			Name:        "macd",
-			Description: "MACD (Moving Average Convergence Divergence) - Trend following",
-		},
-		{
-			Name:        "bbands",
-			Description: "Bollinger Bands - Volatility-based trading",
-		},
-		{
-			Name:        "multitimeframe",
-			Description: "Multi-Timeframe - Advanced strategy using Daily/1h/5m timeframes with RSI, MACD, and Bollinger Bands",
-		},
+	registry := strategy.NewFactory().Registry()
+
+	var infos []StrategyInfo
+	for _, name := range registry.Names() {
+		def, _ := registry.Lookup(name)
+		if def.Composite || def.NamedIndicators {
+			continue // Child strategies and named indicators are configured in YAML only
+		}
+		infos = append(infos, StrategyInfo{
+			Name:        def.Name,
+			Description: def.Description,
+		})
 	}
+	return infos
 }
 
 // GetBotStatus returns current bot status
@@ -394,19 +391,22 @@ func (a *App) GetPortfolioStats() (*portfolio.Stats, error) {
 	symbol := a.config.Symbol
 	currentPrice := 0.0
 
-	// Use bot's client to get current price
-	prices, err := a.bot.GetClient().NewListPricesService().Symbol(symbol).Do(context.Background())
-	if err != nil {
-		log.Printf("⚠️  Failed to fetch current price for %s: %v", symbol, err)
-	} else if len(prices) > 0 {
-		priceFloat, parseErr := strconv.ParseFloat(prices[0].Price, 64)
-		if parseErr == nil {
-			currentPrice = priceFloat
+	// Use bot's exchange to get current price
+	if ex := a.bot.GetExchange(); ex != nil {
+		price, err := ex.GetPrice(context.Background(), symbol)
+		if err != nil {
+			log.Printf("⚠️  Failed to fetch current price for %s: %v", symbol, err)
+		} else {
+			currentPrice = price
 		}
 	}
 
 	// Calculate portfolio stats using the portfolio calculator
 	calculator := portfolio.NewCalculator(a.bot.GetDB())
+	if paper := a.bot.GetPaperExchange(); paper != nil {
+		// Paper trading: include the simulated account's balances
+		calculator = portfolio.NewCalculatorWithExchange(a.bot.GetDB(), paper)
+	}
 	stats, err := calculator.CalculateStats(symbol, currentPrice)
 	if err != nil {
 		log.Printf("❌ GetPortfolioStats error: %v", err)
@@ -453,7 +453,7 @@ func (a *App) GetDefaultStrategyParams(strategyType string) map[string]interface
 	}
 
 	// Add strategy-specific params
-	if strategyType == "rsi" {
+	if config.OverboughtLevel != 0 {
 		params["overbought_level"] = config.OverboughtLevel
 		params["oversold_level"] = config.OversoldLevel
 	}
@@ -461,12 +461,18 @@ func (a *App) GetDefaultStrategyParams(strategyType string) map[string]interface
 	return params
 }
 
+// GetStrategyParamSchema returns the parameter schema of a strategy (its indicator's
+// parameters followed by its own) so the UI can render a form for it
+func (a *App) GetStrategyParamSchema(strategyType string) ([]indicators.ParamSpec, error) {
+	return strategy.NewFactory().GetParamSchema(strategyType)
+}
+
 // ValidateConfig validates strategy configuration
 func (a *App) ValidateConfig(strategyType string, params map[string]interface{}) error {
 	factory := strategy.NewFactory()
 
+	// The factory fills in the strategy's own indicator type
 	indicatorConfig := indicators.IndicatorConfig{
-		Type:   strategyType,
 		Params: params,
 	}
 
@@ -629,35 +635,17 @@ func (a *App) GetWalletBalance() ([]WalletBalance, error) {
 	client.BaseURL = apiEndpoint
 	log.Printf("Using Binance API endpoint: %s", apiEndpoint)
 
-	// Enable debug mode to see the actual request
-	client.Debug = true
-
-	// Synchronize time with Binance server to avoid timestamp errors
-	log.Printf("GetWalletBalance: Synchronizing time with Binance server...")
-	serverTime, err := client.NewServerTimeService().Do(context.Background())
-	if err != nil {
-		log.Printf("Warning: Failed to get server time: %v", err)
-	} else {
-		localTime := time.Now().UnixMilli()
-		// Calculate offset: positive means server is ahead, negative means we're ahead
-		timeOffset := serverTime - localTime
-		log.Printf("Time sync: Server=%d, Local=%d, Offset=%d ms", serverTime, localTime, timeOffset)
-
-		// Set TimeOffset on client to adjust all future requests
-		// Subtract an additional 1000ms buffer to ensure we're always behind server time
-		client.TimeOffset = timeOffset - 1000
-		log.Printf("Set client TimeOffset to %d ms (includes 1s safety buffer)", client.TimeOffset)
+	// A live bot keeps balances current from the user data stream; REST is the fallback
+	var accountBalances []exchange.Balance
+	if a.bot != nil {
+		accountBalances = a.bot.Balances()
 	}
-
-	// Now make the account request with synchronized time
-	// Note: TimeOffset set above handles timestamp sync automatically
-	log.Printf("Calling Binance GetAccountService...")
-	account, err := client.NewGetAccountService().Do(context.Background())
-	if err != nil {
-		log.Printf("ERROR: GetAccountService failed: %v", err)
-		return nil, fmt.Errorf("failed to get account info: %w", err)
+	if accountBalances == nil {
+		accountBalances, err = fetchAccountBalances(client)
+		if err != nil {
+			return nil, err
+		}
 	}
-	log.Printf("SUCCESS: Got account info with %d balances", len(account.Balances))
 
 	// Get current prices for all trading pairs
 	prices, err := client.NewListPricesService().Do(context.Background())
@@ -677,11 +665,9 @@ func (a *App) GetWalletBalance() ([]WalletBalance, error) {
 	}
 
 	// Convert to our format with USD values
-	balances := make([]WalletBalance, 0, len(account.Balances))
-	for _, balance := range account.Balances {
-		freeAmount, _ := strconv.ParseFloat(balance.Free, 64)
-		lockedAmount, _ := strconv.ParseFloat(balance.Locked, 64)
-		totalAmount := freeAmount + lockedAmount
+	balances := make([]WalletBalance, 0, len(accountBalances))
+	for _, balance := range accountBalances {
+		totalAmount := balance.Total()
 
 		usdValue := 0.0
 		asset := balance.Asset
@@ -705,8 +691,8 @@ func (a *App) GetWalletBalance() ([]WalletBalance, error) {
 
 		balances = append(balances, WalletBalance{
 			Asset:    balance.Asset,
-			Free:     balance.Free,
-			Locked:   balance.Locked,
+			Free:     strconv.FormatFloat(balance.Free, 'f', -1, 64),
+			Locked:   strconv.FormatFloat(balance.Locked, 'f', -1, 64),
 			USDValue: usdValue,
 		})
 	}
@@ -714,6 +700,27 @@ func (a *App) GetWalletBalance() ([]WalletBalance, error) {
 	return balances, nil
 }
 
+// fetchAccountBalances requests the account balances over REST
+func fetchAccountBalances(client *binance.Client) ([]exchange.Balance, error) {
+	binanceExchange := exchange.NewBinanceExchange(client)
+
+	// Synchronize time with Binance server to avoid timestamp errors
+	if offset, err := binanceExchange.SyncTime(context.Background()); err != nil {
+		log.Printf("Warning: Failed to sync time with Binance server: %v", err)
+	} else {
+		log.Printf("Set client TimeOffset to %d ms (includes 1s safety buffer)", offset)
+	}
+
+	accountBalances, err := binanceExchange.GetBalances(context.Background())
+	if err != nil {
+		log.Printf("ERROR: GetAccountService failed: %v", err)
+		return nil, fmt.Errorf("failed to get account info: %w", err)
+	}
+	log.Printf("SUCCESS: Got account info with %d balances", len(accountBalances))
+
+	return accountBalances, nil
+}
+
 // ============= Email Settings Methods =============
 
 // EmailSettings represents email notification configuration
@@ -1228,6 +1235,35 @@ func (a *App) GetMultiTimeframeData() (map[string]TimeframeChartData, error) {
 	return result, nil
 }
 
+// GetStoredCandles returns the latest candles kept in the local candle store, oldest first,
+// so charts can show history without calling the exchange
+func (a *App) GetStoredCandles(symbol string, interval string, limit int) ([]CandleData, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.bot == nil || a.bot.GetDB() == nil {
+		return nil, fmt.Errorf("bot not initialized")
+	}
+
+	stored, err := a.bot.GetDB().GetLatestCandles(strings.ToUpper(symbol), interval, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	candles := make([]CandleData, 0, len(stored))
+	for _, c := range stored {
+		candles = append(candles, CandleData{
+			Timestamp: c.OpenTime.UnixMilli(),
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+			Volume:    c.Volume,
+		})
+	}
+	return candles, nil
+}
+
 // GetTimeframeData returns chart data for a specific timeframe
 func (a *App) GetTimeframeData(timeframe string) (*TimeframeChartData, error) {
 	a.mu.Lock()
@@ -3,6 +3,7 @@
 import {main} from '../models';
 import {database} from '../models';
 import {portfolio} from '../models';
+import {indicators} from '../models';
 
 export function ChangePIN(arg1:string,arg2:string):Promise<void>;
 
@@ -32,6 +33,10 @@ export function GetPortfolioStats():Promise<portfolio.Stats>;
 
 export function GetSetupInstructions():Promise<string>;
 
+export function GetStoredCandles(arg1:string,arg2:string,arg3:number):Promise<Array<main.CandleData>>;
+
+export function GetStrategyParamSchema(arg1:string):Promise<Array<indicators.ParamSpec>>;
+
 export function GetTimeframeData(arg1:string):Promise<main.TimeframeChartData>;
 
 export function GetTradeHistory(arg1:number):Promise<Array<database.Trade>>;
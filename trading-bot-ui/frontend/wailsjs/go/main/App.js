@@ -58,6 +58,14 @@ export function GetSetupInstructions() {
   return window['go']['main']['App']['GetSetupInstructions']();
 }
 
+export function GetStoredCandles(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetStoredCandles'](arg1, arg2, arg3);
+}
+
+export function GetStrategyParamSchema(arg1) {
+  return window['go']['main']['App']['GetStrategyParamSchema'](arg1);
+}
+
 export function GetTimeframeData(arg1) {
   return window['go']['main']['App']['GetTimeframeData'](arg1);
 }
@@ -16,6 +16,9 @@ export namespace database {
 	    profit_loss_percent?: number;
 	    buy_trade_id: number;
 	    sell_trade_id?: number;
+	    side: string;
+	    realized_pnl?: number;
+	    closed_quantity?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new Position(source);
@@ -36,6 +39,9 @@ export namespace database {
 	        this.profit_loss_percent = source["profit_loss_percent"];
 	        this.buy_trade_id = source["buy_trade_id"];
 	        this.sell_trade_id = source["sell_trade_id"];
+	        this.side = source["side"];
+	        this.realized_pnl = source["realized_pnl"];
+	        this.closed_quantity = source["closed_quantity"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -73,6 +79,8 @@ export namespace database {
 	    profit_loss?: number;
 	    profit_loss_percent?: number;
 	    related_buy_id?: number;
+	    position_side?: string;
+	    signal_strength?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new Trade(source);
@@ -95,6 +103,8 @@ export namespace database {
 	        this.profit_loss = source["profit_loss"];
 	        this.profit_loss_percent = source["profit_loss_percent"];
 	        this.related_buy_id = source["related_buy_id"];
+	        this.position_side = source["position_side"];
+	        this.signal_strength = source["signal_strength"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -120,6 +130,8 @@ export namespace database {
 	    total_buys: number;
 	    total_sells: number;
 	    total_profit_loss: number;
+	    total_fees: number;
+	    unconverted_fees?: {[key: string]: number};
 	    win_rate: number;
 	    average_profit_loss: number;
 	    largest_win: number;
@@ -139,6 +151,8 @@ export namespace database {
 	        this.total_buys = source["total_buys"];
 	        this.total_sells = source["total_sells"];
 	        this.total_profit_loss = source["total_profit_loss"];
+	        this.total_fees = source["total_fees"];
+	        this.unconverted_fees = source["unconverted_fees"];
 	        this.win_rate = source["win_rate"];
 	        this.average_profit_loss = source["average_profit_loss"];
 	        this.largest_win = source["largest_win"];
@@ -168,6 +182,35 @@ export namespace database {
 
 }
 
+export namespace indicators {
+	
+	export class ParamSpec {
+	    name: string;
+	    type: string;
+	    default: any;
+	    min: number;
+	    max: number;
+	    options?: string[];
+	    description: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ParamSpec(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.default = source["default"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.options = source["options"];
+	        this.description = source["description"];
+	    }
+	}
+
+}
+
 export namespace main {
 	
 	export class BotStatus {